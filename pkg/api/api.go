@@ -472,6 +472,7 @@ func (hs *HTTPServer) registerRoutes() {
 				dashUidRoute.Get("/versions", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersions))
 				dashUidRoute.Post("/restore", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.RestoreDashboardVersion))
 				dashUidRoute.Get("/versions/:id", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersion))
+				dashUidRoute.Get("/versions/:id/diff/:newId", authorize(ac.EvalPermission(dashboards.ActionDashboardsWrite)), routing.Wrap(hs.GetDashboardVersionDiff))
 				dashUidRoute.Group("/permissions", func(dashboardPermissionRoute routing.RouteRegister) {
 					dashboardPermissionRoute.Get("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsRead)), routing.Wrap(hs.GetDashboardPermissionList))
 					dashboardPermissionRoute.Post("/", authorize(ac.EvalPermission(dashboards.ActionDashboardsPermissionsWrite)), routing.Wrap(hs.UpdateDashboardPermissions))
@@ -507,6 +508,14 @@ func (hs *HTTPServer) registerRoutes() {
 			dashboardRoute.Get("/", routing.Wrap(hs.SearchDashboardSnapshots))
 		})
 
+		// Dashboard snapshot schedules
+		apiRoute.Group("/snapshot-schedules", func(scheduleRoute routing.RouteRegister) {
+			scheduleRoute.Get("/", routing.Wrap(hs.GetSnapshotSchedules))
+			scheduleRoute.Get("/:uid", routing.Wrap(hs.GetSnapshotSchedule))
+			scheduleRoute.Post("/", routing.Wrap(hs.CreateSnapshotSchedule))
+			scheduleRoute.Delete("/:uid", routing.Wrap(hs.DeleteSnapshotSchedule))
+		}, reqEditorRole)
+
 		// Playlist
 		apiRoute.Group("/playlists", func(playlistRoute routing.RouteRegister) {
 			playlistRoute.Get("/", routing.Wrap(hs.SearchPlaylists))
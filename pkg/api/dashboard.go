@@ -445,6 +445,17 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		allowUiUpdate = hs.ProvisioningService.GetAllowUIUpdatesFromConfig(provisioningData.Name)
 	}
 
+	// Provisioned dashboards that are configured to do so have their changes written back to their source. Keep the
+	// dashboard as it was before the change to restore it if the change cannot be committed.
+	var previous *dashboards.Dashboard
+	writeBack := provisioningData != nil && allowUiUpdate
+	if writeBack {
+		previous, err = hs.DashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{ID: provisioningData.DashboardID, OrgID: c.OrgID})
+		if err != nil {
+			return response.Error(500, "Error while getting the provisioned dashboard", err)
+		}
+	}
+
 	dashItem := &dashboards.SaveDashboardDTO{
 		Dashboard: dash,
 		Message:   cmd.Message,
@@ -483,6 +494,25 @@ func (hs *HTTPServer) postDashboard(c *contextmodel.ReqContext, cmd dashboards.S
 		return apierrors.ToDashboardErrorResponse(ctx, hs.pluginStore, err)
 	}
 
+	// The changes are committed only after they are saved, so that the permission and version checks of the save apply
+	// to the commit too. A change that cannot be committed is reverted, as it would be lost on the next sync.
+	if writeBack {
+		if _, err := hs.ProvisioningService.CommitProvisionedDashboard(ctx, provisioningData, dashboard, cmd.Message, c.SignedInUser); err != nil {
+			revert := &dashboards.SaveDashboardDTO{
+				Dashboard: previous,
+				Message:   fmt.Sprintf("Restored from version %d", previous.Version),
+				OrgID:     c.OrgID,
+				User:      c.SignedInUser,
+				Overwrite: true,
+			}
+			if _, rerr := hs.DashboardService.SaveDashboard(ctx, revert, allowUiUpdate); rerr != nil {
+				hs.log.Error("Failed to revert a dashboard change that could not be committed", "uid", dashboard.UID, "error", rerr)
+				return response.Error(500, "Dashboard saved, but failed to commit it to the provisioning repository", err)
+			}
+			return response.Error(500, "Failed to commit dashboard to the provisioning repository", err)
+		}
+	}
+
 	// Clear permission cache for the user who's created the dashboard, so that new permissions are fetched for their next call
 	// Required for cases when caller wants to immediately interact with the newly created object
 	if newDashboard {
@@ -795,6 +825,74 @@ func (hs *HTTPServer) GetDashboardVersion(c *contextmodel.ReqContext) response.R
 	return response.JSON(http.StatusOK, dashVersionMeta)
 }
 
+// swagger:route GET /dashboards/uid/{uid}/versions/{DashboardVersionID}/diff/{NewDashboardVersionID} dashboard_versions getDashboardVersionDiffByUID
+//
+// Get the semantic diff of two versions of a dashboard.
+//
+// Returns the panels, queries, variables and time settings that changed between the versions, and the JSON patch
+// from the first version to the second one.
+//
+// Responses:
+// 200: dashboardVersionDiffResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetDashboardVersionDiff(c *contextmodel.ReqContext) response.Response {
+	dash, rsp := hs.getDashboardHelper(c.Req.Context(), c.OrgID, 0, web.Params(c.Req)[":uid"])
+	if rsp != nil {
+		return rsp
+	}
+
+	guardian, err := guardian.NewByDashboard(c.Req.Context(), dash, c.OrgID, c.SignedInUser)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	if canSave, err := guardian.CanSave(); err != nil || !canSave {
+		return dashboardGuardianResponse(err)
+	}
+
+	baseVersion, err := strconv.ParseInt(web.Params(c.Req)[":id"], 10, 32)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "base version is invalid", err)
+	}
+	newVersion, err := strconv.ParseInt(web.Params(c.Req)[":newId"], 10, 32)
+	if err != nil {
+		return response.Error(http.StatusBadRequest, "new version is invalid", err)
+	}
+
+	versions := make([]*dashver.DashboardVersionDTO, 0, 2)
+	for _, version := range []int64{baseVersion, newVersion} {
+		res, err := hs.dashboardVersionService.Get(c.Req.Context(), &dashver.GetDashboardVersionQuery{
+			OrgID:        c.OrgID,
+			DashboardID:  dash.ID,
+			DashboardUID: dash.UID,
+			Version:      int(version),
+		})
+		if err != nil {
+			if errors.Is(err, dashver.ErrDashboardVersionNotFound) {
+				return response.Error(http.StatusNotFound, fmt.Sprintf("Dashboard version %d not found", version), err)
+			}
+			return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+		}
+		versions = append(versions, res)
+	}
+
+	diff, err := dashdiffs.CalculateSemanticDiff(versions[0].Data, versions[1].Data)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Unable to compute diff", err)
+	}
+
+	return response.JSON(http.StatusOK, dtos.DashboardVersionDiff{
+		DashboardUID: dash.UID,
+		BaseVersion:  versions[0].Version,
+		NewVersion:   versions[1].Version,
+		SemanticDiff: *diff,
+	})
+}
+
 // swagger:route POST /dashboards/validate dashboards alpha validateDashboard
 //
 // Validates a dashboard JSON against the schema.
@@ -1255,6 +1353,27 @@ type PostDashboardResponse struct {
 	} `json:"body"`
 }
 
+// swagger:parameters getDashboardVersionDiffByUID
+type GetDashboardVersionDiffByUIDParams struct {
+	// in:path
+	// required:true
+	UID string `json:"uid"`
+	// The version to compare from.
+	// in:path
+	// required:true
+	DashboardVersionID int64
+	// The version to compare to.
+	// in:path
+	// required:true
+	NewDashboardVersionID int64
+}
+
+// swagger:response dashboardVersionDiffResponse
+type DashboardVersionDiffResponse struct {
+	// in: body
+	Body dtos.DashboardVersionDiff `json:"body"`
+}
+
 // swagger:response calculateDashboardDiffResponse
 type CalculateDashboardDiffResponse struct {
 	// in: body
@@ -1,8 +1,6 @@
 package api
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -21,11 +19,6 @@ import (
 	"github.com/grafana/grafana/pkg/web"
 )
 
-var client = &http.Client{
-	Timeout:   time.Second * 5,
-	Transport: &http.Transport{Proxy: http.ProxyFromEnvironment},
-}
-
 // swagger:route GET /snapshot/shared-options snapshots getSharingOptions
 //
 // Get snapshot sharing settings.
@@ -42,49 +35,6 @@ func (hs *HTTPServer) GetSharingOptions(c *contextmodel.ReqContext) {
 	})
 }
 
-type CreateExternalSnapshotResponse struct {
-	Key       string `json:"key"`
-	DeleteKey string `json:"deleteKey"`
-	Url       string `json:"url"`
-	DeleteUrl string `json:"deleteUrl"`
-}
-
-func createExternalDashboardSnapshot(cmd dashboardsnapshots.CreateDashboardSnapshotCommand, externalSnapshotUrl string) (*CreateExternalSnapshotResponse, error) {
-	var createSnapshotResponse CreateExternalSnapshotResponse
-	message := map[string]interface{}{
-		"name":      cmd.Name,
-		"expires":   cmd.Expires,
-		"dashboard": cmd.Dashboard,
-		"key":       cmd.Key,
-		"deleteKey": cmd.DeleteKey,
-	}
-
-	messageBytes, err := simplejson.NewFromAny(message).Encode()
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := client.Post(externalSnapshotUrl+"/api/snapshots", "application/json", bytes.NewBuffer(messageBytes))
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			plog.Warn("Failed to close response body", "err", err)
-		}
-	}()
-
-	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("create external snapshot response status code %d", resp.StatusCode)
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&createSnapshotResponse); err != nil {
-		return nil, err
-	}
-
-	return &createSnapshotResponse, nil
-}
-
 func createOriginalDashboardURL(cmd *dashboardsnapshots.CreateDashboardSnapshotCommand) (string, error) {
 	dashUID := cmd.Dashboard.Get("uid").MustString("")
 	if ok := util.IsValidShortUID(dashUID); !ok {
@@ -134,7 +84,7 @@ func (hs *HTTPServer) CreateDashboardSnapshot(c *contextmodel.ReqContext) respon
 			return nil
 		}
 
-		resp, err := createExternalDashboardSnapshot(cmd, hs.Cfg.ExternalSnapshotUrl)
+		resp, err := dashboardsnapshots.CreateExternalSnapshot(&cmd, hs.Cfg.ExternalSnapshotUrl)
 		if err != nil {
 			c.JsonApiErr(http.StatusInternalServerError, "Failed to create external snapshot", err)
 			return nil
@@ -240,38 +190,6 @@ func (hs *HTTPServer) GetDashboardSnapshot(c *contextmodel.ReqContext) response.
 	return response.JSON(http.StatusOK, dto).SetHeader("Cache-Control", "public, max-age=3600")
 }
 
-func deleteExternalDashboardSnapshot(externalUrl string) error {
-	resp, err := client.Get(externalUrl)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			plog.Warn("Failed to close response body", "err", err)
-		}
-	}()
-
-	if resp.StatusCode == 200 {
-		return nil
-	}
-
-	// Gracefully ignore "snapshot not found" errors as they could have already
-	// been removed either via the cleanup script or by request.
-	if resp.StatusCode == 500 {
-		var respJson map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&respJson); err != nil {
-			return err
-		}
-
-		if respJson["message"] == "Failed to get dashboard snapshot" {
-			return nil
-		}
-	}
-
-	return fmt.Errorf("unexpected response when deleting external snapshot, status code: %d", resp.StatusCode)
-}
-
 // swagger:route GET /snapshots-delete/{deleteKey} snapshots deleteDashboardSnapshotByDeleteKey
 //
 // Delete Snapshot by deleteKey.
@@ -302,7 +220,7 @@ func (hs *HTTPServer) DeleteDashboardSnapshotByDeleteKey(c *contextmodel.ReqCont
 	}
 
 	if queryResult.External {
-		err := deleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL)
+		err := dashboardsnapshots.DeleteExternalSnapshot(queryResult.ExternalDeleteURL)
 		if err != nil {
 			return response.Error(500, "Failed to delete external dashboard", err)
 		}
@@ -351,7 +269,7 @@ func (hs *HTTPServer) DeleteDashboardSnapshot(c *contextmodel.ReqContext) respon
 	}
 
 	if queryResult.External {
-		err := deleteExternalDashboardSnapshot(queryResult.ExternalDeleteURL)
+		err := dashboardsnapshots.DeleteExternalSnapshot(queryResult.ExternalDeleteURL)
 		if err != nil {
 			return response.Error(http.StatusInternalServerError, "Failed to delete external dashboard", err)
 		}
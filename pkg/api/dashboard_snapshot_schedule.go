@@ -0,0 +1,204 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	snapshotschedule "github.com/grafana/grafana/pkg/services/dashboardsnapshots/schedule"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// swagger:route GET /snapshot-schedules snapshots getSnapshotSchedules
+//
+// List the snapshot schedules of the organization for the dashboards the user can view.
+//
+// Responses:
+// 200: getSnapshotSchedulesResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) GetSnapshotSchedules(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	schedules, err := hs.snapshotScheduleService.List(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.Error(http.StatusInternalServerError, "Failed to get snapshot schedules", err)
+	}
+
+	visible := make([]*snapshotschedule.SnapshotSchedule, 0, len(schedules))
+	for _, schedule := range schedules {
+		canView, err := hs.canViewSnapshotScheduleDashboard(c, schedule.DashboardUID)
+		if err != nil {
+			return response.Error(http.StatusInternalServerError, "Failed to check the permissions of the snapshot schedules", err)
+		}
+		if canView {
+			visible = append(visible, schedule)
+		}
+	}
+	return response.JSON(http.StatusOK, visible)
+}
+
+// swagger:route GET /snapshot-schedules/{uid} snapshots getSnapshotSchedule
+//
+// Get a snapshot schedule by UID.
+//
+// Responses:
+// 200: getSnapshotScheduleResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) GetSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	schedule, err := hs.snapshotScheduleService.Get(c.Req.Context(), c.OrgID, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return response.Err(err)
+	}
+	if canView, err := hs.canViewSnapshotScheduleDashboard(c, schedule.DashboardUID); err != nil || !canView {
+		return dashboardGuardianResponse(err)
+	}
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route POST /snapshot-schedules snapshots createSnapshotSchedule
+//
+// Create a schedule that periodically takes a snapshot of a dashboard.
+//
+// The data of the snapshots is queried by the server, with the permissions of the user who created the schedule.
+// Creating a schedule requires permission to edit the dashboard.
+//
+// Responses:
+// 200: getSnapshotScheduleResponse
+// 400: badRequestError
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) CreateSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	cmd := snapshotschedule.CreateSnapshotScheduleCommand{}
+	if err := web.Bind(c.Req, &cmd); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	cmd.OrgID = c.OrgID
+	cmd.UserID = c.UserID
+
+	if rsp := hs.checkCanEditSnapshotScheduleDashboard(c, cmd.DashboardUID); rsp != nil {
+		return rsp
+	}
+
+	schedule, err := hs.snapshotScheduleService.Create(c.Req.Context(), &cmd)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, schedule)
+}
+
+// swagger:route DELETE /snapshot-schedules/{uid} snapshots deleteSnapshotSchedule
+//
+// Delete a snapshot schedule by UID.
+//
+// The snapshots already taken by the schedule are kept until they expire.
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 404: notFoundError
+// 500: internalServerError
+func (hs *HTTPServer) DeleteSnapshotSchedule(c *contextmodel.ReqContext) response.Response {
+	if !hs.Cfg.SnapshotEnabled {
+		return response.Error(http.StatusForbidden, "Dashboard Snapshots are disabled", nil)
+	}
+
+	uid := web.Params(c.Req)[":uid"]
+	schedule, err := hs.snapshotScheduleService.Get(c.Req.Context(), c.OrgID, uid)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	// The schedule of a deleted dashboard can be deleted by anyone who can manage schedules.
+	if rsp := hs.checkCanEditSnapshotScheduleDashboard(c, schedule.DashboardUID); rsp != nil && rsp.Status() != http.StatusNotFound {
+		return rsp
+	}
+
+	if err := hs.snapshotScheduleService.Delete(c.Req.Context(), c.OrgID, uid); err != nil {
+		return response.Err(err)
+	}
+	return response.Success("Snapshot schedule deleted")
+}
+
+// checkCanEditSnapshotScheduleDashboard returns an error response if the user cannot edit the dashboard.
+func (hs *HTTPServer) checkCanEditSnapshotScheduleDashboard(c *contextmodel.ReqContext, dashboardUID string) response.Response {
+	if dashboardUID == "" {
+		return response.Error(http.StatusBadRequest, "Dashboard UID is required", nil)
+	}
+
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.OrgID, c.SignedInUser)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return response.Error(http.StatusNotFound, "Dashboard not found", err)
+		}
+		return response.Err(err)
+	}
+	if canEdit, err := g.CanEdit(); err != nil || !canEdit {
+		return dashboardGuardianResponse(err)
+	}
+	return nil
+}
+
+// canViewSnapshotScheduleDashboard returns whether the user can view the dashboard of a snapshot schedule. The schedules
+// of deleted dashboards can be viewed by anyone who can manage schedules, so that they can be deleted.
+func (hs *HTTPServer) canViewSnapshotScheduleDashboard(c *contextmodel.ReqContext, dashboardUID string) (bool, error) {
+	g, err := guardian.NewByUID(c.Req.Context(), dashboardUID, c.OrgID, c.SignedInUser)
+	if err != nil {
+		if errors.Is(err, dashboards.ErrDashboardNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	return g.CanView()
+}
+
+// swagger:parameters createSnapshotSchedule
+type CreateSnapshotScheduleParams struct {
+	// in:body
+	// required:true
+	Body snapshotschedule.CreateSnapshotScheduleCommand `json:"body"`
+}
+
+// swagger:parameters getSnapshotSchedule
+type GetSnapshotScheduleParams struct {
+	// in:path
+	UID string `json:"uid"`
+}
+
+// swagger:parameters deleteSnapshotSchedule
+type DeleteSnapshotScheduleParams struct {
+	// in:path
+	UID string `json:"uid"`
+}
+
+// swagger:response getSnapshotSchedulesResponse
+type GetSnapshotSchedulesResponse struct {
+	// in:body
+	Body []*snapshotschedule.SnapshotSchedule `json:"body"`
+}
+
+// swagger:response getSnapshotScheduleResponse
+type GetSnapshotScheduleResponse struct {
+	// in:body
+	Body *snapshotschedule.SnapshotSchedule `json:"body"`
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/db"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	snapshotschedule "github.com/grafana/grafana/pkg/services/dashboardsnapshots/schedule"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSnapshotScheduleAPIEndpoint(t *testing.T) {
+	const (
+		visibleDashboardUID = "visible"
+		hiddenDashboardUID  = "hidden"
+		deletedDashboardUID = "deleted"
+	)
+
+	origNewByUID := guardian.NewByUID
+	t.Cleanup(func() {
+		guardian.NewByUID = origNewByUID
+	})
+	guardian.NewByUID = func(_ context.Context, dashUID string, _ int64, _ *user.SignedInUser) (guardian.DashboardGuardian, error) {
+		if dashUID == deletedDashboardUID {
+			return nil, dashboards.ErrDashboardNotFound
+		}
+		return &guardian.FakeDashboardGuardian{DashUID: dashUID, CanViewValue: dashUID == visibleDashboardUID}, nil
+	}
+
+	cfg := setting.NewCfg()
+	cfg.SnapshotEnabled = true
+	sqlStore := db.InitTestDB(t)
+	hs := &HTTPServer{
+		Cfg:                     cfg,
+		snapshotScheduleService: snapshotschedule.ProvideService(cfg, sqlStore, nil, nil, nil, nil, nil, nil, nil),
+	}
+	call := func(t *testing.T, url, pattern string, handler func(*contextmodel.ReqContext) response.Response) *scenarioContext {
+		sc := setupScenarioContext(t, url)
+		// The scenario context initializes the test database again, so the schedules are inserted afterwards.
+		now := time.Now()
+		err := sqlStore.WithDbSession(context.Background(), func(sess *db.Session) error {
+			for _, dashboardUID := range []string{visibleDashboardUID, hiddenDashboardUID, deletedDashboardUID} {
+				if _, err := sess.Insert(&snapshotschedule.SnapshotSchedule{UID: dashboardUID, OrgID: 1, DashboardUID: dashboardUID, Name: dashboardUID, Schedule: "@daily", NextRun: now, Created: now, Updated: now}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+			sc.context = c
+			sc.context.SignedInUser = &user.SignedInUser{OrgID: 1, UserID: 1}
+			return handler(c)
+		})
+		sc.m.Get(pattern, sc.defaultHandler)
+		sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+		return sc
+	}
+
+	t.Run("list only includes the schedules of dashboards the user can view", func(t *testing.T) {
+		sc := call(t, "/api/snapshot-schedules", "/api/snapshot-schedules", hs.GetSnapshotSchedules)
+		require.Equal(t, http.StatusOK, sc.resp.Code)
+
+		var schedules []*snapshotschedule.SnapshotSchedule
+		require.NoError(t, json.Unmarshal(sc.resp.Body.Bytes(), &schedules))
+		uids := make([]string, 0, len(schedules))
+		for _, schedule := range schedules {
+			uids = append(uids, schedule.UID)
+		}
+		assert.ElementsMatch(t, []string{visibleDashboardUID, deletedDashboardUID}, uids)
+	})
+
+	testCases := []struct {
+		uid          string
+		expectedCode int
+	}{
+		{uid: visibleDashboardUID, expectedCode: http.StatusOK},
+		{uid: hiddenDashboardUID, expectedCode: http.StatusForbidden},
+		{uid: deletedDashboardUID, expectedCode: http.StatusOK},
+		{uid: "unknown", expectedCode: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run("get the schedule of the "+tc.uid+" dashboard", func(t *testing.T) {
+			sc := call(t, "/api/snapshot-schedules/"+tc.uid, "/api/snapshot-schedules/:uid", hs.GetSnapshotSchedule)
+			assert.Equal(t, tc.expectedCode, sc.resp.Code)
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/api/routing"
 	"github.com/grafana/grafana/pkg/bus"
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/db/dbtest"
@@ -246,6 +247,28 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 
 					assert.Equal(t, 200, sc.resp.Code)
 				}, mockSQLStore)
+
+			loggedInUserScenarioWithRole(t, "When calling GET on", "GET", "/api/dashboards/uid/test-uid-one/versions/1/diff/2",
+				"/api/dashboards/uid/:uid/versions/:id/diff/:newId", role, func(sc *scenarioContext) {
+					setUp()
+					fakeDashboardVersionService.ExpectedDashboardVersions = []*dashver.DashboardVersionDTO{
+						{Version: 1, Data: simplejson.NewFromAny(map[string]any{"refresh": "1m"})},
+						{Version: 2, Data: simplejson.NewFromAny(map[string]any{"refresh": "5m"})},
+					}
+					t.Cleanup(func() {
+						fakeDashboardVersionService.ExpectedDashboardVersions = nil
+					})
+					sc.handlerFunc = hs.GetDashboardVersionDiff
+					sc.fakeReqWithParams("GET", sc.url, map[string]string{}).exec()
+
+					assert.Equal(t, 200, sc.resp.Code)
+					var diff dtos.DashboardVersionDiff
+					require.NoError(t, json.NewDecoder(sc.resp.Body).Decode(&diff))
+					assert.Equal(t, 1, diff.BaseVersion)
+					assert.Equal(t, 2, diff.NewVersion)
+					assert.Equal(t, []dashdiffs.SettingChange{{Name: "refresh", Old: "1m", New: "5m"}}, diff.Time)
+					assert.Len(t, diff.Patch, 1)
+				}, mockSQLStore)
 		})
 	})
 
@@ -701,6 +724,98 @@ func TestDashboardAPIEndpoint(t *testing.T) {
 		})
 	})
 
+	t.Run("Post provisioned dashboard that is written back to its repository", func(t *testing.T) {
+		const dashID int64 = 2
+		cmd := dashboards.SaveDashboardCommand{
+			OrgID:  1,
+			UserID: 5,
+			Dashboard: simplejson.NewFromAny(map[string]interface{}{
+				"id":      dashID,
+				"uid":     "uid",
+				"title":   "Dash",
+				"version": 1,
+			}),
+			Message: "msg",
+		}
+		previous := &dashboards.Dashboard{ID: dashID, UID: "uid", Title: "Dash", Version: 2, Data: simplejson.New()}
+
+		testCases := []struct {
+			Name               string
+			SaveError          error
+			CommitError        error
+			ExpectedStatusCode int
+			ExpectedCommits    int
+			ExpectedSaves      int
+		}{
+			{Name: "is committed after it is saved", ExpectedStatusCode: 200, ExpectedCommits: 1, ExpectedSaves: 1},
+			{Name: "is not committed without permission", SaveError: dashboards.ErrDashboardUpdateAccessDenied, ExpectedStatusCode: 403, ExpectedSaves: 1},
+			{Name: "is not committed if the version does not match", SaveError: dashboards.ErrDashboardVersionMismatch, ExpectedStatusCode: 412, ExpectedSaves: 1},
+			{Name: "is reverted if it cannot be committed", CommitError: errors.New("push rejected"), ExpectedStatusCode: 500, ExpectedCommits: 1, ExpectedSaves: 2},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.Name, func(t *testing.T) {
+				dashboardService := dashboards.NewFakeDashboardService(t)
+				dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(previous, nil)
+				var saved []*dashboards.SaveDashboardDTO
+				dashboardService.On("SaveDashboard", mock.Anything, mock.AnythingOfType("*dashboards.SaveDashboardDTO"), true).
+					Run(func(args mock.Arguments) {
+						saved = append(saved, args.Get(1).(*dashboards.SaveDashboardDTO))
+					}).
+					Return(func(_ context.Context, dto *dashboards.SaveDashboardDTO, _ bool) *dashboards.Dashboard {
+						if tc.SaveError != nil {
+							return nil
+						}
+						return &dashboards.Dashboard{ID: dashID, UID: "uid", Title: "Dash", Slug: "dash", Version: 3}
+					}, tc.SaveError)
+
+				dashboardProvisioningService := dashboards.NewFakeDashboardProvisioning(t)
+				dashboardProvisioningService.On("GetProvisionedDashboardDataByDashboardID", mock.Anything, dashID).
+					Return(&dashboards.DashboardProvisioning{DashboardID: dashID, Name: "git"}, nil)
+
+				provisioningService := provisioning.NewProvisioningServiceMock(context.Background())
+				provisioningService.GetAllowUIUpdatesFromConfigFunc = func(string) bool { return true }
+				provisioningService.CommitProvisionedDashboardFunc = func(*dashboards.DashboardProvisioning, *dashboards.Dashboard) (string, error) {
+					return "sha", tc.CommitError
+				}
+
+				hs := HTTPServer{
+					Cfg:                          setting.NewCfg(),
+					ProvisioningService:          provisioningService,
+					QuotaService:                 quotatest.New(false, nil),
+					pluginStore:                  &fakes.FakePluginStore{},
+					LibraryPanelService:          &mockLibraryPanelService{},
+					LibraryElementService:        &mockLibraryElementService{},
+					DashboardService:             dashboardService,
+					dashboardProvisioningService: dashboardProvisioningService,
+					Features:                     featuremgmt.WithFeatures(),
+					Kinds:                        corekind.NewBase(nil),
+					accesscontrolService:         actest.FakeService{},
+					log:                          log.New("test"),
+				}
+
+				sc := setupScenarioContext(t, "/api/dashboards")
+				sc.defaultHandler = routing.Wrap(func(c *contextmodel.ReqContext) response.Response {
+					c.Req.Body = mockRequestBody(cmd)
+					c.Req.Header.Add("Content-Type", "application/json")
+					sc.context = c
+					sc.context.SignedInUser = &user.SignedInUser{OrgID: cmd.OrgID, UserID: cmd.UserID}
+					return hs.PostDashboard(c)
+				})
+				sc.m.Post("/api/dashboards", sc.defaultHandler)
+
+				callPostDashboard(sc)
+				assert.Equal(t, tc.ExpectedStatusCode, sc.resp.Code)
+				assert.Len(t, provisioningService.Calls.CommitProvisionedDashboard, tc.ExpectedCommits)
+				require.Len(t, saved, tc.ExpectedSaves)
+				if tc.ExpectedSaves > 1 {
+					assert.Same(t, previous, saved[1].Dashboard)
+					assert.True(t, saved[1].Overwrite)
+				}
+			})
+		}
+	})
+
 	t.Run("Given a dashboard to validate", func(t *testing.T) {
 		sqlmock := dbtest.NewFakeDB()
 
@@ -1152,7 +1267,7 @@ func (hs *HTTPServer) callGetDashboardVersions(sc *scenarioContext) {
 func (hs *HTTPServer) callDeleteDashboardByUID(t *testing.T,
 	sc *scenarioContext, mockDashboard *dashboards.FakeDashboardService, mockPubdashService *publicdashboards.FakePublicDashboardService) {
 	hs.DashboardService = mockDashboard
-	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures())
+	pubdashApi := api.ProvideApi(mockPubdashService, nil, nil, featuremgmt.WithFeatures(), nil)
 	hs.PublicDashboardsApi = pubdashApi
 	sc.handlerFunc = hs.DeleteDashboardByUID
 	sc.fakeReqWithParams("DELETE", sc.url, map[string]string{}).exec()
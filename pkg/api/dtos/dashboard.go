@@ -3,6 +3,7 @@ package dtos
 import (
 	"time"
 
+	"github.com/grafana/grafana/pkg/components/dashdiffs"
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -73,6 +74,14 @@ type CalculateDiffTarget struct {
 	UnsavedDashboard *simplejson.Json `json:"unsavedDashboard"`
 }
 
+// DashboardVersionDiff is the semantic diff of two versions of a dashboard.
+type DashboardVersionDiff struct {
+	DashboardUID string `json:"dashboardUid"`
+	BaseVersion  int    `json:"baseVersion"`
+	NewVersion   int    `json:"newVersion"`
+	dashdiffs.SemanticDiff
+}
+
 type RestoreDashboardVersionCommand struct {
 	Version int `json:"version" binding:"Required"`
 }
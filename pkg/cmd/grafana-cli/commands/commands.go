@@ -161,6 +161,57 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "alerting",
+		Usage: "Runs alerting commands",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "convert-prometheus-rules",
+				Usage:     "Converts a Prometheus or Mimir rule file to a file that provisions the alerting rules as Grafana-managed alert rules",
+				ArgsUsage: "<rule file>",
+				Action:    runPluginCommand(convertPrometheusRulesCommand),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "datasource-uid",
+						Usage: "The UID of the Prometheus-compatible data source that the rules query",
+					},
+					&cli.StringFlag{
+						Name:  "folder",
+						Usage: "The title of the folder of the rules",
+					},
+					&cli.IntFlag{
+						Name:  "org-id",
+						Usage: "The ID of the organization of the rules",
+						Value: 1,
+					},
+					&cli.StringFlag{
+						Name:  "output",
+						Usage: "The path of the provisioning file. The file is written to stdout if not set",
+					},
+				},
+			},
+			{
+				Name:      "lint-rules",
+				Usage:     "Lints the alert rules of a provisioning file and prints the configurations that are likely to be mistakes",
+				ArgsUsage: "<provisioning file>",
+				Action:    runPluginCommand(lintRulesCommand),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "scrape-interval",
+						Usage: "The interval at which the data sources of the rules scrape their targets",
+					},
+					&cli.StringSliceFlag{
+						Name:  "block",
+						Usage: "The codes of the warnings that make the command fail",
+					},
+					&cli.StringSliceFlag{
+						Name:  "ignore",
+						Usage: "The codes of the warnings that are not printed",
+					},
+				},
+			},
+		},
+	},
 	{
 		Name:  "user-manager",
 		Usage: "Runs different helpful user commands",
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/ngalert/api"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// convertPrometheusRulesCommand converts a Prometheus rule file to a file that provisions the rules as Grafana-managed alert rules.
+// The rules that cannot be converted are reported and left out of the provisioning file.
+func convertPrometheusRulesCommand(c utils.CommandLine) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("the path to the Prometheus rule file is required")
+	}
+	datasourceUID := c.String("datasource-uid")
+	if datasourceUID == "" {
+		return fmt.Errorf("the --datasource-uid flag is required")
+	}
+	folderTitle := c.String("folder")
+	if folderTitle == "" {
+		return fmt.Errorf("the --folder flag is required")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read Prometheus rule file: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if output := c.String("output"); output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				logger.Errorf("failed to close output file: %s\n", err)
+			}
+		}()
+		out = f
+	}
+
+	orgID := int64(c.Int("org-id"))
+	if orgID == 0 {
+		orgID = 1
+	}
+	failures, err := convertPrometheusRules(b, orgID, folderTitle, datasourceUID, out)
+	if err != nil {
+		return err
+	}
+	// The provisioning file can be written to stdout, so the failures are reported on stderr.
+	for _, failure := range failures {
+		fmt.Fprintf(os.Stderr, "could not convert %s\n", failure)
+	}
+	return nil
+}
+
+// convertPrometheusRules converts the Prometheus rule file and writes the provisioning file to out.
+// The UIDs of the rules are derived from the folder title, the group and the position of the rule, so that
+// provisioning a converted file again updates the rules.
+func convertPrometheusRules(b []byte, orgID int64, folderTitle, datasourceUID string, out io.Writer) ([]prom.ConversionError, error) {
+	ruleFile, err := prom.ParseRuleFile(b)
+	if err != nil {
+		return nil, err
+	}
+
+	converter := prom.NewConverter(prom.Config{
+		OrgID:           orgID,
+		NamespaceUID:    folderTitle,
+		DatasourceUID:   datasourceUID,
+		DefaultInterval: setting.DefaultRuleEvaluationInterval,
+	})
+	groups, failures := converter.ConvertGroups(ruleFile.Groups)
+
+	withFolderTitle := make([]models.AlertRuleGroupWithFolderTitle, 0, len(groups))
+	for i := range groups {
+		withFolderTitle = append(withFolderTitle, models.AlertRuleGroupWithFolderTitle{
+			AlertRuleGroup: &groups[i],
+			OrgID:          orgID,
+			FolderTitle:    folderTitle,
+		})
+	}
+	export, err := api.AlertingFileExportFromAlertRuleGroupWithFolderTitle(withFolderTitle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provisioning file: %w", err)
+	}
+
+	enc := yaml.NewEncoder(out)
+	if err := enc.Encode(export); err != nil {
+		return nil, fmt.Errorf("failed to write provisioning file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write provisioning file: %w", err)
+	}
+	return failures, nil
+}
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+)
+
+func TestConvertPrometheusRules(t *testing.T) {
+	ruleFile := []byte(`
+groups:
+  - name: node
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        labels:
+          severity: critical
+        annotations:
+          summary: "{{ $labels.instance }} is down"
+      - record: job:up:sum
+        expr: sum by (job) (up)
+`)
+
+	var out bytes.Buffer
+	failures, err := convertPrometheusRules(ruleFile, 1, "Prometheus", "prometheus", &out)
+	require.NoError(t, err)
+	require.Len(t, failures, 1)
+	require.Equal(t, "job:up:sum", failures[0].Rule)
+
+	var export definitions.AlertingFileExport
+	require.NoError(t, yaml.Unmarshal(out.Bytes(), &export))
+	require.Len(t, export.Groups, 1)
+	group := export.Groups[0]
+	require.Equal(t, "node", group.Name)
+	require.Equal(t, "Prometheus", group.Folder)
+	require.Len(t, group.Rules, 1)
+	require.Equal(t, "InstanceDown", group.Rules[0].Title)
+	require.Equal(t, prom.ThresholdRefID, group.Rules[0].Condition)
+	require.NotEmpty(t, group.Rules[0].UID)
+
+	t.Run("should fail if the rule file is invalid", func(t *testing.T) {
+		_, err := convertPrometheusRules([]byte("groups: {"), 1, "Prometheus", "prometheus", &out)
+		require.Error(t, err)
+	})
+}
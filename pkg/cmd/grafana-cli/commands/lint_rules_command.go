@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/alerting"
+)
+
+var errBlockingLintWarnings = errors.New("alert rules have blocking lint warnings")
+
+// lintRulesCommand lints the alert rules of a provisioning file and prints the warnings.
+// It fails if any warning has one of the codes passed with the --block flag, so that it can be used in CI pipelines.
+func lintRulesCommand(c utils.CommandLine) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("the path to the provisioning file is required")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read provisioning file: %w", err)
+	}
+
+	opts := lint.Options{}
+	if s := c.String("scrape-interval"); s != "" {
+		scrapeInterval, err := model.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid --scrape-interval: %w", err)
+		}
+		// The scrape interval of the data sources is not known without a running Grafana, so it is assumed to be the same for all of them.
+		opts.ScrapeInterval = func(string) (time.Duration, bool) {
+			return time.Duration(scrapeInterval), true
+		}
+	}
+
+	lintRules := make(map[string]models.LintRuleAction)
+	for _, code := range c.StringSlice("block") {
+		lintRules[code] = models.LintRuleActionBlock
+	}
+	for _, code := range c.StringSlice("ignore") {
+		lintRules[code] = models.LintRuleActionIgnore
+	}
+	if err := lint.ValidateRules(lintRules); err != nil {
+		return err
+	}
+
+	return lintProvisionedRules(b, opts, lintRules, os.Stdout)
+}
+
+// lintProvisionedRules lints the alert rules of the provisioning file and writes one line per warning to out.
+// It returns errBlockingLintWarnings if any warning blocks according to the lint rules.
+func lintProvisionedRules(b []byte, opts lint.Options, lintRules map[string]models.LintRuleAction, out io.Writer) error {
+	var fileV1 alerting.AlertingFileV1
+	if err := yaml.Unmarshal(b, &fileV1); err != nil {
+		return fmt.Errorf("failed to parse provisioning file: %w", err)
+	}
+	file, err := fileV1.MapToModel()
+	if err != nil {
+		return fmt.Errorf("failed to parse provisioning file: %w", err)
+	}
+
+	blocking := 0
+	for _, group := range file.Groups {
+		for _, rule := range group.Rules {
+			rule := rule
+			// The interval of the rules is set from their group when they are provisioned.
+			rule.IntervalSeconds = group.Interval
+			warnings := lint.Ignored(lint.Rule(&rule, opts), lintRules)
+			for _, w := range warnings {
+				fmt.Fprintf(out, "%s/%s/%s: %s\n", group.FolderTitle, group.Title, rule.Title, w)
+			}
+			blocking += len(lint.Blocking(warnings, lintRules))
+		}
+	}
+	if blocking > 0 {
+		return fmt.Errorf("%w: %d warnings", errBlockingLintWarnings, blocking)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestLintProvisionedRules(t *testing.T) {
+	file := []byte(`
+apiVersion: 1
+groups:
+  - orgId: 1
+    name: node
+    folder: Prometheus
+    interval: 1m
+    rules:
+      - uid: instance-down
+        title: InstanceDown
+        condition: B
+        for: 5m
+        data:
+          - refId: A
+            datasourceUid: prometheus
+            relativeTimeRange:
+              from: 600
+              to: 0
+            model:
+              expr: up
+              range: true
+          - refId: B
+            datasourceUid: __expr__
+            model:
+              type: threshold
+              expression: A
+`)
+	opts := lint.Options{ScrapeInterval: func(string) (time.Duration, bool) { return 30 * time.Second, true }}
+
+	t.Run("should print the warnings", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, lintProvisionedRules(file, opts, nil, &out))
+		require.Equal(t, "Prometheus/node/InstanceDown: info [missing-runbook-url] the rule has no runbook_url annotation to tell responders how to resolve the alert\n"+
+			"Prometheus/node/InstanceDown: error [threshold-on-range-query] B: query A is a range query, so it must be reduced to a single value per series before the threshold is applied\n", out.String())
+	})
+
+	t.Run("should fail if a warning blocks", func(t *testing.T) {
+		var out bytes.Buffer
+		err := lintProvisionedRules(file, opts, map[string]models.LintRuleAction{
+			string(lint.CodeMissingRunbookURL):     models.LintRuleActionIgnore,
+			string(lint.CodeThresholdOnRangeQuery): models.LintRuleActionBlock,
+		}, &out)
+		require.ErrorIs(t, err, errBlockingLintWarnings)
+		require.NotContains(t, out.String(), lint.CodeMissingRunbookURL)
+	})
+}
@@ -0,0 +1,85 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOperation is an operation of an RFC 6902 JSON patch.
+type PatchOperation struct {
+	// Op is one of "add", "remove" or "replace".
+	Op string `json:"op"`
+	// Path is the JSON pointer to the value the operation applies to.
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// CalculatePatch returns the JSON patch that turns the base value into the new value. Objects are compared by key and
+// arrays by index, so inserting an element in the middle of an array replaces the elements after it.
+func CalculatePatch(base, next interface{}) []PatchOperation {
+	ops := []PatchOperation{}
+	return appendPatch(ops, "", base, next)
+}
+
+func appendPatch(ops []PatchOperation, path string, base, next interface{}) []PatchOperation {
+	switch b := base.(type) {
+	case map[string]interface{}:
+		if n, ok := next.(map[string]interface{}); ok {
+			names := make([]string, 0, len(b)+len(n))
+			for name := range keys(b, n) {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				p := path + "/" + escapePointer(name)
+				bv, inBase := b[name]
+				nv, inNext := n[name]
+				switch {
+				case !inNext:
+					ops = append(ops, PatchOperation{Op: "remove", Path: p})
+				case !inBase:
+					ops = append(ops, newPatchOperation("add", p, nv))
+				default:
+					ops = appendPatch(ops, p, bv, nv)
+				}
+			}
+			return ops
+		}
+	case []interface{}:
+		if n, ok := next.([]interface{}); ok {
+			common := len(b)
+			if len(n) < common {
+				common = len(n)
+			}
+			for i := 0; i < common; i++ {
+				ops = appendPatch(ops, path+"/"+strconv.Itoa(i), b[i], n[i])
+			}
+			for i := common; i < len(n); i++ {
+				ops = append(ops, newPatchOperation("add", path+"/"+strconv.Itoa(i), n[i]))
+			}
+			// Elements are removed from the end, so that the indices of the remaining ones do not change.
+			for i := len(b) - 1; i >= common; i-- {
+				ops = append(ops, PatchOperation{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+			}
+			return ops
+		}
+	}
+	if reflect.DeepEqual(base, next) {
+		return ops
+	}
+	return append(ops, newPatchOperation("replace", path, next))
+}
+
+func newPatchOperation(op, path string, value interface{}) PatchOperation {
+	// The values are decoded from JSON, so they can always be encoded again.
+	b, _ := json.Marshal(value)
+	return PatchOperation{Op: op, Path: path, Value: b}
+}
+
+// escapePointer escapes a key for use as a token of a JSON pointer, as defined by RFC 6901.
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,290 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+// SemanticChange is the kind of change of a dashboard element between two versions.
+type SemanticChange string
+
+const (
+	SemanticAdded    SemanticChange = "added"
+	SemanticRemoved  SemanticChange = "removed"
+	SemanticModified SemanticChange = "modified"
+)
+
+// timeSettings are the dashboard properties that control the time range and refresh of the dashboard.
+var timeSettings = []string{"time", "timepicker", "refresh", "timezone", "weekStart", "fiscalYearStartMonth"}
+
+// SemanticDiff is the difference between two dashboard versions in terms of their panels, variables and time
+// settings, together with the JSON patch that turns the base version into the new one.
+type SemanticDiff struct {
+	Panels    []PanelChange    `json:"panels"`
+	Variables []VariableChange `json:"variables"`
+	Time      []SettingChange  `json:"time"`
+	// Patch is the RFC 6902 JSON patch from the base version to the new version.
+	Patch []PatchOperation `json:"patch"`
+}
+
+// PanelChange is a panel that was added, removed or modified. Panels are matched by their ID.
+type PanelChange struct {
+	ID     int64          `json:"id"`
+	Title  string         `json:"title"`
+	Change SemanticChange `json:"change"`
+	// Moved is true if the position of the panel or the row it is in changed.
+	Moved bool `json:"moved,omitempty"`
+	// Datasource is the change of the data source of the panel.
+	Datasource *ValueChange `json:"datasource,omitempty"`
+	// Queries are the queries of the panel that were added, removed or modified. Queries are matched by their RefID.
+	Queries []QueryChange `json:"queries,omitempty"`
+	// Fields are the names of the other properties of the panel that changed, such as "title" or "options".
+	Fields []string `json:"fields,omitempty"`
+}
+
+type QueryChange struct {
+	RefID  string         `json:"refId"`
+	Change SemanticChange `json:"change"`
+	// Datasource is the change of the data source of the query.
+	Datasource *ValueChange `json:"datasource,omitempty"`
+}
+
+// VariableChange is a template variable that was added, removed or modified. Variables are matched by their name.
+type VariableChange struct {
+	Name   string         `json:"name"`
+	Change SemanticChange `json:"change"`
+	// Fields are the names of the properties of the variable that changed, such as "query" or "current".
+	Fields []string `json:"fields,omitempty"`
+}
+
+// SettingChange is a dashboard setting that changed.
+type SettingChange struct {
+	Name string      `json:"name"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+type ValueChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// CalculateSemanticDiff computes the semantic diff of two dashboard versions.
+func CalculateSemanticDiff(baseData, newData *simplejson.Json) (*SemanticDiff, error) {
+	base, err := decodeDashboard(baseData)
+	if err != nil {
+		return nil, err
+	}
+	next, err := decodeDashboard(newData)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SemanticDiff{
+		Panels:    diffPanels(flattenPanels(base), flattenPanels(next)),
+		Variables: diffVariables(variables(base), variables(next)),
+		Time:      []SettingChange{},
+		Patch:     CalculatePatch(base, next),
+	}
+	for _, name := range timeSettings {
+		if !reflect.DeepEqual(base[name], next[name]) {
+			result.Time = append(result.Time, SettingChange{Name: name, Old: base[name], New: next[name]})
+		}
+	}
+	return result, nil
+}
+
+func decodeDashboard(data *simplejson.Json) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if data == nil {
+		return result, nil
+	}
+	// The dashboard is decoded again without simplejson, so that numbers are float64 in both versions.
+	b, err := data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("dashdiff: dashboard is not a JSON object: %w", err)
+	}
+	return result, nil
+}
+
+// panel is a panel of a dashboard, with the ID of the row it is in, if any.
+type panel struct {
+	rowID int64
+	value map[string]interface{}
+}
+
+// flattenPanels returns the panels of the dashboard by ID, including the panels of collapsed rows.
+func flattenPanels(dash map[string]interface{}) map[int64]panel {
+	result := map[int64]panel{}
+	var rowID int64
+	for _, p := range objects(dash["panels"]) {
+		id := int64Value(p["id"])
+		if p["type"] == "row" {
+			rowID = id
+			result[id] = panel{value: p}
+			for _, nested := range objects(p["panels"]) {
+				result[int64Value(nested["id"])] = panel{rowID: id, value: nested}
+			}
+			continue
+		}
+		result[id] = panel{rowID: rowID, value: p}
+	}
+	return result
+}
+
+func diffPanels(base, next map[int64]panel) []PanelChange {
+	result := []PanelChange{}
+	for id, b := range base {
+		n, ok := next[id]
+		if !ok {
+			result = append(result, PanelChange{ID: id, Title: stringValue(b.value["title"]), Change: SemanticRemoved})
+			continue
+		}
+		change := PanelChange{
+			ID:      id,
+			Title:   stringValue(n.value["title"]),
+			Change:  SemanticModified,
+			Moved:   b.rowID != n.rowID || !reflect.DeepEqual(b.value["gridPos"], n.value["gridPos"]),
+			Queries: diffQueries(b.value, n.value),
+			Fields:  changedFields(b.value, n.value, "id", "gridPos", "datasource", "targets", "panels"),
+		}
+		if !reflect.DeepEqual(b.value["datasource"], n.value["datasource"]) {
+			change.Datasource = &ValueChange{Old: b.value["datasource"], New: n.value["datasource"]}
+		}
+		if change.Moved || change.Datasource != nil || len(change.Queries) > 0 || len(change.Fields) > 0 {
+			result = append(result, change)
+		}
+	}
+	for id, n := range next {
+		if _, ok := base[id]; !ok {
+			result = append(result, PanelChange{ID: id, Title: stringValue(n.value["title"]), Change: SemanticAdded})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+func diffQueries(base, next map[string]interface{}) []QueryChange {
+	baseTargets := objectsBy(base["targets"], "refId")
+	nextTargets := objectsBy(next["targets"], "refId")
+	var result []QueryChange
+	for refID, b := range baseTargets {
+		n, ok := nextTargets[refID]
+		if !ok {
+			result = append(result, QueryChange{RefID: refID, Change: SemanticRemoved})
+			continue
+		}
+		if reflect.DeepEqual(b, n) {
+			continue
+		}
+		change := QueryChange{RefID: refID, Change: SemanticModified}
+		if !reflect.DeepEqual(b["datasource"], n["datasource"]) {
+			change.Datasource = &ValueChange{Old: b["datasource"], New: n["datasource"]}
+		}
+		result = append(result, change)
+	}
+	for refID := range nextTargets {
+		if _, ok := baseTargets[refID]; !ok {
+			result = append(result, QueryChange{RefID: refID, Change: SemanticAdded})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RefID < result[j].RefID
+	})
+	return result
+}
+
+func variables(dash map[string]interface{}) map[string]map[string]interface{} {
+	templating, _ := dash["templating"].(map[string]interface{})
+	return objectsBy(templating["list"], "name")
+}
+
+func diffVariables(base, next map[string]map[string]interface{}) []VariableChange {
+	result := []VariableChange{}
+	for name, b := range base {
+		n, ok := next[name]
+		if !ok {
+			result = append(result, VariableChange{Name: name, Change: SemanticRemoved})
+			continue
+		}
+		if fields := changedFields(b, n); len(fields) > 0 {
+			result = append(result, VariableChange{Name: name, Change: SemanticModified, Fields: fields})
+		}
+	}
+	for name := range next {
+		if _, ok := base[name]; !ok {
+			result = append(result, VariableChange{Name: name, Change: SemanticAdded})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// changedFields returns the sorted names of the properties that differ between the objects, except the ignored ones.
+func changedFields(base, next map[string]interface{}, ignored ...string) []string {
+	skip := make(map[string]struct{}, len(ignored))
+	for _, name := range ignored {
+		skip[name] = struct{}{}
+	}
+	var result []string
+	for name := range keys(base, next) {
+		if _, ok := skip[name]; ok {
+			continue
+		}
+		if !reflect.DeepEqual(base[name], next[name]) {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func keys(objects ...map[string]interface{}) map[string]struct{} {
+	result := map[string]struct{}{}
+	for _, o := range objects {
+		for k := range o {
+			result[k] = struct{}{}
+		}
+	}
+	return result
+}
+
+func objects(v interface{}) []map[string]interface{} {
+	list, _ := v.([]interface{})
+	result := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if o, ok := item.(map[string]interface{}); ok {
+			result = append(result, o)
+		}
+	}
+	return result
+}
+
+func objectsBy(v interface{}, key string) map[string]map[string]interface{} {
+	result := map[string]map[string]interface{}{}
+	for _, o := range objects(v) {
+		result[stringValue(o[key])] = o
+	}
+	return result
+}
+
+func stringValue(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func int64Value(v interface{}) int64 {
+	n, _ := v.(float64)
+	return int64(n)
+}
@@ -0,0 +1,167 @@
+package dashdiffs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+)
+
+func TestCalculateSemanticDiff(t *testing.T) {
+	const (
+		baseJSON = `{
+			"title": "Services",
+			"time": {"from": "now-6h", "to": "now"},
+			"refresh": "1m",
+			"templating": {"list": [
+				{"name": "env", "query": "label_values(env)"},
+				{"name": "job", "query": "label_values(job)"}
+			]},
+			"panels": [
+				{"id": 1, "type": "timeseries", "title": "Requests", "gridPos": {"x": 0, "y": 0},
+					"datasource": {"type": "prometheus", "uid": "prom"},
+					"targets": [{"refId": "A", "expr": "rate(requests[5m])"}, {"refId": "B", "expr": "up"}]},
+				{"id": 2, "type": "stat", "title": "Errors", "gridPos": {"x": 12, "y": 0}},
+				{"id": 3, "type": "row", "title": "Details", "collapsed": true, "panels": [
+					{"id": 4, "type": "table", "title": "Instances", "gridPos": {"x": 0, "y": 1}}
+				]}
+			]
+		}`
+
+		newJSON = `{
+			"title": "Services",
+			"time": {"from": "now-24h", "to": "now"},
+			"refresh": "1m",
+			"templating": {"list": [
+				{"name": "env", "query": "label_values(environment)"},
+				{"name": "instance", "query": "label_values(instance)"}
+			]},
+			"panels": [
+				{"id": 1, "type": "timeseries", "title": "Requests", "gridPos": {"x": 0, "y": 0},
+					"datasource": {"type": "prometheus", "uid": "mimir"},
+					"targets": [{"refId": "A", "expr": "rate(requests[1m])", "datasource": {"uid": "mimir"}}, {"refId": "C", "expr": "up"}]},
+				{"id": 3, "type": "row", "title": "Details", "collapsed": true, "panels": [
+					{"id": 4, "type": "table", "title": "Instances", "gridPos": {"x": 0, "y": 1}},
+					{"id": 5, "type": "stat", "title": "Saturation", "gridPos": {"x": 12, "y": 1}}
+				]}
+			]
+		}`
+	)
+
+	base, err := simplejson.NewJson([]byte(baseJSON))
+	require.NoError(t, err)
+	next, err := simplejson.NewJson([]byte(newJSON))
+	require.NoError(t, err)
+
+	diff, err := CalculateSemanticDiff(base, next)
+	require.NoError(t, err)
+
+	require.Equal(t, []PanelChange{
+		{
+			ID:         1,
+			Title:      "Requests",
+			Change:     SemanticModified,
+			Datasource: &ValueChange{Old: map[string]interface{}{"type": "prometheus", "uid": "prom"}, New: map[string]interface{}{"type": "prometheus", "uid": "mimir"}},
+			Queries: []QueryChange{
+				{RefID: "A", Change: SemanticModified, Datasource: &ValueChange{Old: nil, New: map[string]interface{}{"uid": "mimir"}}},
+				{RefID: "B", Change: SemanticRemoved},
+				{RefID: "C", Change: SemanticAdded},
+			},
+		},
+		{ID: 2, Title: "Errors", Change: SemanticRemoved},
+		{ID: 5, Title: "Saturation", Change: SemanticAdded},
+	}, diff.Panels)
+
+	require.Equal(t, []VariableChange{
+		{Name: "env", Change: SemanticModified, Fields: []string{"query"}},
+		{Name: "instance", Change: SemanticAdded},
+		{Name: "job", Change: SemanticRemoved},
+	}, diff.Variables)
+
+	require.Equal(t, []SettingChange{
+		{Name: "time", Old: map[string]interface{}{"from": "now-6h", "to": "now"}, New: map[string]interface{}{"from": "now-24h", "to": "now"}},
+	}, diff.Time)
+
+	require.NotEmpty(t, diff.Patch)
+	require.Equal(t, PatchOperation{Op: "replace", Path: "/time/from", Value: json.RawMessage(`"now-24h"`)}, diff.Patch[len(diff.Patch)-1])
+}
+
+func TestCalculateSemanticDiffMovedPanel(t *testing.T) {
+	base := simplejson.NewFromAny(map[string]interface{}{"panels": []interface{}{
+		map[string]interface{}{"id": 1, "title": "Requests", "gridPos": map[string]interface{}{"x": 0, "y": 0}},
+	}})
+	next := simplejson.NewFromAny(map[string]interface{}{"panels": []interface{}{
+		map[string]interface{}{"id": 1, "title": "Requests", "gridPos": map[string]interface{}{"x": 0, "y": 8}},
+	}})
+
+	diff, err := CalculateSemanticDiff(base, next)
+	require.NoError(t, err)
+	require.Equal(t, []PanelChange{{ID: 1, Title: "Requests", Change: SemanticModified, Moved: true}}, diff.Panels)
+	require.Empty(t, diff.Variables)
+	require.Empty(t, diff.Time)
+}
+
+func TestCalculatePatch(t *testing.T) {
+	decode := func(s string) interface{} {
+		var v interface{}
+		require.NoError(t, json.Unmarshal([]byte(s), &v))
+		return v
+	}
+
+	testCases := []struct {
+		name     string
+		base     string
+		next     string
+		expected []PatchOperation
+	}{
+		{
+			name:     "equal documents",
+			base:     `{"a": [1, 2], "b": {"c": null}}`,
+			next:     `{"a": [1, 2], "b": {"c": null}}`,
+			expected: []PatchOperation{},
+		},
+		{
+			name: "changed object keys",
+			base: `{"a": 1, "b": 2, "c/d": 3}`,
+			next: `{"a": 1, "b": null, "e~f": {"g": true}}`,
+			expected: []PatchOperation{
+				{Op: "replace", Path: "/b", Value: json.RawMessage(`null`)},
+				{Op: "remove", Path: "/c~1d"},
+				{Op: "add", Path: "/e~0f", Value: json.RawMessage(`{"g":true}`)},
+			},
+		},
+		{
+			name: "longer array",
+			base: `{"a": [1, 2]}`,
+			next: `{"a": [1, 3, 4]}`,
+			expected: []PatchOperation{
+				{Op: "replace", Path: "/a/1", Value: json.RawMessage(`3`)},
+				{Op: "add", Path: "/a/2", Value: json.RawMessage(`4`)},
+			},
+		},
+		{
+			name: "shorter array",
+			base: `{"a": [1, 2, 3]}`,
+			next: `{"a": [1]}`,
+			expected: []PatchOperation{
+				{Op: "remove", Path: "/a/2"},
+				{Op: "remove", Path: "/a/1"},
+			},
+		},
+		{
+			name: "changed type",
+			base: `{"a": [1]}`,
+			next: `{"a": {"b": 1}}`,
+			expected: []PatchOperation{
+				{Op: "replace", Path: "/a", Value: json.RawMessage(`{"b":1}`)},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, CalculatePatch(decode(tc.base), decode(tc.next)))
+		})
+	}
+}
@@ -36,6 +36,9 @@ import (
 type ConditionsCmd struct {
 	Conditions []condition
 	RefID      string
+	// LoadedDimensions contains the fingerprints of the dimensions that met the condition at the previous evaluation.
+	// Classic conditions produce a single dimension without labels, so the command is loaded if it contains the fingerprint of empty labels.
+	LoadedDimensions map[data.Fingerprint]struct{}
 }
 
 // condition is a single condition in ConditionsCmd.
@@ -51,6 +54,11 @@ type condition struct {
 	// falls within a range, or does not contain a value.
 	Evaluator evaluator
 
+	// UnloadEvaluator is an optional evaluator that is used instead of Evaluator when the
+	// command met the condition at the previous evaluation. The condition keeps firing until
+	// the value meets UnloadEvaluator.
+	UnloadEvaluator evaluator
+
 	// Operator is the logical operator to use when there are two conditions in ConditionsCmd.
 	// If there are more than two conditions in ConditionsCmd then operator is used to compare
 	// the outcome of this condition with that of the condition before it.
@@ -182,7 +190,12 @@ func (cmd *ConditionsCmd) executeCond(_ context.Context, _ time.Time, cond condi
 			return false, false, nil, fmt.Errorf("can only reduce type series, got type %v", v.Type())
 		}
 
-		isValueFiring := cond.Evaluator.Eval(number)
+		var isValueFiring bool
+		if cond.UnloadEvaluator != nil && cmd.isLoaded() {
+			isValueFiring = number.GetFloat64Value() != nil && !cond.UnloadEvaluator.Eval(number)
+		} else {
+			isValueFiring = cond.Evaluator.Eval(number)
+		}
 		// If the value was either a mathexp.NoData, a mathexp.Number with a nil float64,
 		// or mathexp.Series that reduced to a nil float64, it is no data
 		isValueNoData := number.GetFloat64Value() == nil
@@ -216,6 +229,12 @@ func (cmd *ConditionsCmd) executeCond(_ context.Context, _ time.Time, cond condi
 	return isCondFiring, isCondNoData, matches, nil
 }
 
+// isLoaded returns true if the command met the condition at the previous evaluation.
+func (cmd *ConditionsCmd) isLoaded() bool {
+	_, ok := cmd.LoadedDimensions[data.Labels{}.Fingerprint()]
+	return ok
+}
+
 func compareWithOperator(b1, b2 bool, operator string) bool {
 	if operator == "or" {
 		return b1 || b2
@@ -251,10 +270,11 @@ func (em EvalMatch) MarshalJSON() ([]byte, error) {
 // ConditionJSON is the JSON model for a single condition in ConditionsCmd.
 // It is based on services/alerting/conditions/query.go's newQueryCondition().
 type ConditionJSON struct {
-	Evaluator ConditionEvalJSON     `json:"evaluator"`
-	Operator  ConditionOperatorJSON `json:"operator"`
-	Query     ConditionQueryJSON    `json:"query"`
-	Reducer   ConditionReducerJSON  `json:"reducer"`
+	Evaluator       ConditionEvalJSON     `json:"evaluator"`
+	UnloadEvaluator *ConditionEvalJSON    `json:"unloadEvaluator,omitempty"`
+	Operator        ConditionOperatorJSON `json:"operator"`
+	Query           ConditionQueryJSON    `json:"query"`
+	Reducer         ConditionReducerJSON  `json:"reducer"`
 }
 
 type ConditionEvalJSON struct {
@@ -314,6 +334,13 @@ func UnmarshalConditionsCmd(rawQuery map[string]interface{}, refID string) (*Con
 			return nil, err
 		}
 
+		if cj.UnloadEvaluator != nil {
+			cond.UnloadEvaluator, err = newAlertEvaluator(*cj.UnloadEvaluator)
+			if err != nil {
+				return nil, fmt.Errorf("invalid unload evaluator in condition %v: %w", i+1, err)
+			}
+		}
+
 		c.Conditions = append(c.Conditions, cond)
 	}
 
@@ -595,6 +595,76 @@ func TestConditionsCmd(t *testing.T) {
 			v.SetMeta([]EvalMatch{{Value: util.Pointer(5.0)}, {Metric: "NoData"}})
 			return newResults(v)
 		},
+	}, {
+		name: "single query with unload condition when not loaded uses evaluator",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{
+				Values: []mathexp.Value{newSeries(util.Pointer(3.0))},
+			},
+		},
+		cmd: &ConditionsCmd{
+			Conditions: []condition{
+				{
+					InputRefID:      "A",
+					Reducer:         reducer("last"),
+					Operator:        "and",
+					Evaluator:       &thresholdEvaluator{Type: "gt", Threshold: 5},
+					UnloadEvaluator: &thresholdEvaluator{Type: "lt", Threshold: 2},
+				},
+			}},
+		expected: func() mathexp.Results {
+			v := newNumber(util.Pointer(0.0))
+			v.SetMeta([]EvalMatch{})
+			return newResults(v)
+		},
+	}, {
+		name: "single query with unload condition when loaded keeps firing until unload condition is met",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{
+				Values: []mathexp.Value{newSeries(util.Pointer(3.0))},
+			},
+		},
+		cmd: &ConditionsCmd{
+			Conditions: []condition{
+				{
+					InputRefID:      "A",
+					Reducer:         reducer("last"),
+					Operator:        "and",
+					Evaluator:       &thresholdEvaluator{Type: "gt", Threshold: 5},
+					UnloadEvaluator: &thresholdEvaluator{Type: "lt", Threshold: 2},
+				},
+			},
+			LoadedDimensions: map[data.Fingerprint]struct{}{data.Labels{}.Fingerprint(): {}},
+		},
+		expected: func() mathexp.Results {
+			v := newNumber(util.Pointer(1.0))
+			v.SetMeta([]EvalMatch{{Value: util.Pointer(3.0)}})
+			return newResults(v)
+		},
+	}, {
+		name: "single query with unload condition when loaded stops firing when unload condition is met",
+		vars: mathexp.Vars{
+			"A": mathexp.Results{
+				Values: []mathexp.Value{newSeries(util.Pointer(1.0))},
+			},
+		},
+		cmd: &ConditionsCmd{
+			Conditions: []condition{
+				{
+					InputRefID:      "A",
+					Reducer:         reducer("last"),
+					Operator:        "and",
+					Evaluator:       &thresholdEvaluator{Type: "gt", Threshold: 5},
+					UnloadEvaluator: &thresholdEvaluator{Type: "lt", Threshold: 2},
+				},
+			},
+			LoadedDimensions: map[data.Fingerprint]struct{}{data.Labels{}.Fingerprint(): {}},
+		},
+		expected: func() mathexp.Results {
+			v := newNumber(util.Pointer(0.0))
+			v.SetMeta([]EvalMatch{})
+			return newResults(v)
+		},
 	}}
 
 	for _, tt := range tests {
@@ -692,6 +762,52 @@ func TestUnmarshalConditionsCmd(t *testing.T) {
 			},
 			needsVars: []string{"A"},
 		},
+		{
+			name: "threshold condition with unload evaluator",
+			rawJSON: `{
+				"conditions": [
+				  {
+					"evaluator": {
+					  "params": [
+						5
+					  ],
+					  "type": "gt"
+					},
+					"unloadEvaluator": {
+					  "params": [
+						2
+					  ],
+					  "type": "lt"
+					},
+					"operator": {
+					  "type": "and"
+					},
+					"query": {
+					  "params": [
+						"A"
+					  ]
+					},
+					"reducer": {
+					  "params": [],
+					  "type": "last"
+					},
+					"type": "query"
+				  }
+				]
+			}`,
+			expectedCommand: &ConditionsCmd{
+				Conditions: []condition{
+					{
+						InputRefID:      "A",
+						Reducer:         reducer("last"),
+						Operator:        "and",
+						Evaluator:       &thresholdEvaluator{Type: "gt", Threshold: 5},
+						UnloadEvaluator: &thresholdEvaluator{Type: "lt", Threshold: 2},
+					},
+				},
+			},
+			needsVars: []string{"A"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -77,24 +77,27 @@ func (gm *MathCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Va
 
 // ReduceCommand is an expression command for reduction of a timeseries such as a min, mean, or max.
 type ReduceCommand struct {
-	Reducer      string
-	VarToReduce  string
-	refID        string
-	seriesMapper mathexp.ReduceMapper
+	Reducer       string
+	ReducerParams []float64
+	VarToReduce   string
+	refID         string
+	seriesMapper  mathexp.ReduceMapper
 }
 
 // NewReduceCommand creates a new ReduceCMD.
-func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper) (*ReduceCommand, error) {
-	_, err := mathexp.GetReduceFunc(reducer)
+// params are passed to the reducer, e.g. the percentile reducer expects a single parameter in the range [0, 100].
+func NewReduceCommand(refID, reducer, varToReduce string, mapper mathexp.ReduceMapper, params ...float64) (*ReduceCommand, error) {
+	_, err := mathexp.GetReduceFunc(reducer, params...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &ReduceCommand{
-		Reducer:      reducer,
-		VarToReduce:  varToReduce,
-		refID:        refID,
-		seriesMapper: mapper,
+		Reducer:       reducer,
+		ReducerParams: params,
+		VarToReduce:   varToReduce,
+		refID:         refID,
+		seriesMapper:  mapper,
 	}, nil
 }
 
@@ -119,6 +122,21 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 		return nil, fmt.Errorf("expected reducer to be a string, got %T", rawReducer)
 	}
 
+	var params []float64
+	if rawParams, ok := rn.Query["reducerParams"]; ok && rawParams != nil {
+		list, ok := rawParams.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected reducerParams to be an array of numbers, got %T", rawParams)
+		}
+		for _, p := range list {
+			value, ok := p.(float64)
+			if !ok {
+				return nil, fmt.Errorf("expected reducerParams to be an array of numbers, got element of type %T", p)
+			}
+			params = append(params, value)
+		}
+	}
+
 	var mapper mathexp.ReduceMapper = nil
 	settings, ok := rn.Query["settings"]
 	if ok {
@@ -148,7 +166,7 @@ func UnmarshalReduceCommand(rn *rawNode) (*ReduceCommand, error) {
 			return nil, fmt.Errorf("field settings must be an object, got %T for refId %v", s, rn.RefID)
 		}
 	}
-	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper)
+	return NewReduceCommand(rn.RefID, redFunc, varToReduce, mapper, params...)
 }
 
 // NeedsVars returns the variable names (refIds) that are dependencies
@@ -169,7 +187,7 @@ func (gr *ReduceCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.
 	for _, val := range vars[gr.VarToReduce].Values {
 		switch v := val.(type) {
 		case mathexp.Series:
-			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper)
+			num, err := v.Reduce(gr.refID, gr.Reducer, gr.seriesMapper, gr.ReducerParams...)
 			if err != nil {
 				return newRes, err
 			}
@@ -314,6 +332,8 @@ const (
 	TypeClassicConditions
 	// TypeThreshold is the CMDType for checking if a threshold has been crossed
 	TypeThreshold
+	// TypeSeasonalAnomaly is the CMDType for the local seasonal anomaly detection.
+	TypeSeasonalAnomaly
 )
 
 func (gt CommandType) String() string {
@@ -326,6 +346,8 @@ func (gt CommandType) String() string {
 		return "resample"
 	case TypeClassicConditions:
 		return "classic_conditions"
+	case TypeSeasonalAnomaly:
+		return "seasonal_anomaly"
 	default:
 		return "unknown"
 	}
@@ -344,6 +366,8 @@ func ParseCommandType(s string) (CommandType, error) {
 		return TypeClassicConditions, nil
 	case "threshold":
 		return TypeThreshold, nil
+	case "seasonal_anomaly":
+		return TypeSeasonalAnomaly, nil
 	default:
 		return TypeUnknown, fmt.Errorf("'%v' is not a recognized expression type", s)
 	}
@@ -98,6 +98,66 @@ func Test_UnmarshalReduceCommand_Settings(t *testing.T) {
 	}
 }
 
+func Test_UnmarshalReduceCommand_ReducerParams(t *testing.T) {
+	var tests = []struct {
+		name           string
+		query          string
+		isError        bool
+		expectedParams []float64
+	}{
+		{
+			name:           "no parameters when reducerParams is not specified",
+			query:          `{ "expression" : "$A", "reducer": "sum" }`,
+			expectedParams: nil,
+		},
+		{
+			name:           "percentile with parameter",
+			query:          `{ "expression" : "$A", "reducer": "percentile", "reducerParams": [95] }`,
+			expectedParams: []float64{95},
+		},
+		{
+			name:    "error when percentile has no parameter",
+			query:   `{ "expression" : "$A", "reducer": "percentile" }`,
+			isError: true,
+		},
+		{
+			name:    "error when percentile parameter is out of range",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": [101] }`,
+			isError: true,
+		},
+		{
+			name:    "error when reducerParams is not an array",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": 95 }`,
+			isError: true,
+		},
+		{
+			name:    "error when reducerParams contains not a number",
+			query:   `{ "expression" : "$A", "reducer": "percentile", "reducerParams": ["95"] }`,
+			isError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var qmap = make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(test.query), &qmap))
+
+			cmd, err := UnmarshalReduceCommand(&rawNode{
+				RefID: "A",
+				Query: qmap,
+			})
+
+			if test.isError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedParams, cmd.ReducerParams)
+		})
+	}
+}
+
 func TestReduceExecute(t *testing.T) {
 	varToReduce := util.GenerateShortUID()
 	cmd, err := NewReduceCommand(util.GenerateShortUID(), randomReduceFunc(), varToReduce, nil)
@@ -169,7 +229,13 @@ func TestReduceExecute(t *testing.T) {
 
 func randomReduceFunc() string {
 	res := mathexp.GetSupportedReduceFuncs()
-	return res[rand.Intn(len(res))]
+	for {
+		f := res[rand.Intn(len(res))]
+		// skip functions that require parameters
+		if _, err := mathexp.GetReduceFunc(f); err == nil {
+			return f
+		}
+	}
 }
 
 func TestResampleCommand_Execute(t *testing.T) {
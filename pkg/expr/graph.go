@@ -163,12 +163,13 @@ func (s *Service) buildGraph(req *Request) (*simple.DirectedGraph, error) {
 		}
 
 		rn := &rawNode{
-			Query:      rawQueryProp,
-			QueryRaw:   query.JSON,
-			RefID:      query.RefID,
-			TimeRange:  query.TimeRange,
-			QueryType:  query.QueryType,
-			DataSource: query.DataSource,
+			Query:            rawQueryProp,
+			QueryRaw:         query.JSON,
+			RefID:            query.RefID,
+			TimeRange:        query.TimeRange,
+			QueryType:        query.QueryType,
+			DataSource:       query.DataSource,
+			LoadedDimensions: req.LoadedDimensions,
 		}
 
 		var node Node
@@ -40,6 +40,39 @@ func TestServicebuildPipeLine(t *testing.T) {
 			},
 			expectedOrder: []string{"B", "A"},
 		},
+		{
+			name: "seasonal anomaly requires query and feeds threshold",
+			req: &Request{
+				Queries: []Query{
+					{
+						RefID:      "C",
+						DataSource: dataSourceModel(),
+						JSON: json.RawMessage(`{
+							"expression": "B",
+							"type": "threshold",
+							"conditions": [{"evaluator": {"type": "gt", "params": [0]}}]
+						}`),
+					},
+					{
+						RefID:      "B",
+						DataSource: dataSourceModel(),
+						JSON: json.RawMessage(`{
+							"expression": "A",
+							"season": "1d",
+							"type": "seasonal_anomaly"
+						}`),
+					},
+					{
+						RefID: "A",
+						DataSource: &datasources.DataSource{
+							UID: "Fake",
+						},
+						TimeRange: AbsoluteTimeRange{},
+					},
+				},
+			},
+			expectedOrder: []string{"A", "B", "C"},
+		},
 		{
 			name: "cycle will error",
 			req: &Request{
@@ -1,7 +1,9 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"time"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +70,31 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"shift": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeDuration},
+		Return: parse.TypeSeriesSet,
+		F:      shift,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      rate,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      delta,
+	},
+	"moving_avg": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet, parse.TypeDuration},
+		Return: parse.TypeSeriesSet,
+		F:      movingAvg,
+	},
+	"cumsum": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      cumsum,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -307,3 +334,147 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// shift moves every point of each Series in SeriesSet forward in time by the given duration,
+// so that, for example, $A - shift($A, 1w) compares each point with the point a week before.
+func shift(e *State, varSet Results, d time.Duration) (Results, error) {
+	return perSeries(e, varSet, "shift", func(s Series) Series {
+		newSeries := NewSeries(e.RefID, s.GetLabels(), s.Len())
+		for i := 0; i < s.Len(); i++ {
+			t, f := s.GetPoint(i)
+			newSeries.SetPoint(i, t.Add(d), f)
+		}
+		return newSeries
+	})
+}
+
+// rate returns the per-second rate of increase between consecutive points of each Series in SeriesSet.
+// A decrease of the value is considered to be a counter reset. The first point of a series has no rate and is dropped.
+func rate(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "rate", func(s Series) Series {
+		return perPointPair(e.RefID, s, func(prevT time.Time, prev float64, t time.Time, cur float64) *float64 {
+			seconds := t.Sub(prevT).Seconds()
+			if seconds <= 0 {
+				return nil
+			}
+			increase := cur - prev
+			if cur < prev {
+				increase = cur
+			}
+			f := increase / seconds
+			return &f
+		})
+	})
+}
+
+// delta returns the difference between consecutive points of each Series in SeriesSet.
+// The first point of a series has no delta and is dropped.
+func delta(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "delta", func(s Series) Series {
+		return perPointPair(e.RefID, s, func(_ time.Time, prev float64, _ time.Time, cur float64) *float64 {
+			f := cur - prev
+			return &f
+		})
+	})
+}
+
+// movingAvg returns for each point of each Series in SeriesSet the average of non-null values within the window (t-window, t].
+func movingAvg(e *State, varSet Results, window time.Duration) (Results, error) {
+	if window <= 0 {
+		return Results{}, fmt.Errorf("moving_avg window must be greater than zero, got %s", window)
+	}
+	return perSeries(e, varSet, "moving_avg", func(s Series) Series {
+		sorted := sortedSeriesCopy(e.RefID, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), sorted.Len())
+		start := 0
+		for i := 0; i < sorted.Len(); i++ {
+			t := sorted.GetTime(i)
+			for !sorted.GetTime(start).After(t.Add(-window)) {
+				start++
+			}
+			var sum, count float64
+			for j := start; j <= i; j++ {
+				if f := sorted.GetValue(j); f != nil {
+					sum += *f
+					count++
+				}
+			}
+			if count == 0 {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			avg := sum / count
+			newSeries.SetPoint(i, t, &avg)
+		}
+		return newSeries
+	})
+}
+
+// cumsum returns the cumulative sum of each Series in SeriesSet. Null points remain null and do not contribute to the sum.
+func cumsum(e *State, varSet Results) (Results, error) {
+	return perSeries(e, varSet, "cumsum", func(s Series) Series {
+		sorted := sortedSeriesCopy(e.RefID, s)
+		newSeries := NewSeries(e.RefID, s.GetLabels(), sorted.Len())
+		var sum float64
+		for i := 0; i < sorted.Len(); i++ {
+			t, f := sorted.GetPoint(i)
+			if f == nil {
+				newSeries.SetPoint(i, t, nil)
+				continue
+			}
+			sum += *f
+			nF := sum
+			newSeries.SetPoint(i, t, &nF)
+		}
+		return newSeries
+	})
+}
+
+// perSeries applies seriesF to each Series in the varSet. NoData is passed through, any other type results in an error.
+// The labels of the series are preserved so the results can be combined with other sets by the union logic.
+func perSeries(e *State, varSet Results, name string, seriesF func(s Series) Series) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		switch v := res.(type) {
+		case Series:
+			newRes.Values = append(newRes.Values, seriesF(v))
+		case NoData:
+			newRes.Values = append(newRes.Values, NewNoData())
+		default:
+			return newRes, fmt.Errorf("function %s can only be applied to a series, got %v", name, res.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// perPointPair calls pairF for each pair of consecutive points of the series sorted by time
+// and returns a series of the results with the time of the latter point.
+// If any of the values in a pair is null the resulting point is null.
+func perPointPair(refID string, s Series, pairF func(prevT time.Time, prev float64, t time.Time, cur float64) *float64) Series {
+	sorted := sortedSeriesCopy(refID, s)
+	if sorted.Len() < 2 {
+		return NewSeries(refID, s.GetLabels(), 0)
+	}
+	newSeries := NewSeries(refID, s.GetLabels(), sorted.Len()-1)
+	for i := 1; i < sorted.Len(); i++ {
+		prevT, prev := sorted.GetPoint(i - 1)
+		t, cur := sorted.GetPoint(i)
+		if prev == nil || cur == nil {
+			newSeries.SetPoint(i-1, t, nil)
+			continue
+		}
+		newSeries.SetPoint(i-1, t, pairF(prevT, *prev, t, *cur))
+	}
+	return newSeries
+}
+
+// sortedSeriesCopy returns a copy of the series sorted by time from oldest to newest.
+func sortedSeriesCopy(refID string, s Series) Series {
+	newSeries := NewSeries(refID, s.GetLabels(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, f := s.GetPoint(i)
+		newSeries.SetPoint(i, t, f)
+	}
+	newSeries.SortByTime(false)
+	return newSeries
+}
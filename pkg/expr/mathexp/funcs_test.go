@@ -5,8 +5,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
 )
 
 func TestAbsFunc(t *testing.T) {
@@ -160,3 +163,206 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestSeriesFuncs(t *testing.T) {
+	var tests = []struct {
+		name      string
+		expr      string
+		vars      Vars
+		newErrIs  require.ErrorAssertionFunc
+		execErrIs require.ErrorAssertionFunc
+		results   Results
+	}{
+		{
+			name: "shift moves points forward in time",
+			expr: "shift($A, 1d)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", data.Labels{"host": "a"},
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(60, 0), nil}),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(0, 0).Add(24 * time.Hour), float64Pointer(1)},
+					tp{time.Unix(60, 0).Add(24 * time.Hour), nil}),
+			}},
+		},
+		{
+			name: "shift can be combined with the original series",
+			expr: "$A - shift($A, 10s)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", data.Labels{"host": "a"},
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(10, 0), float64Pointer(3)},
+						tp{time.Unix(20, 0), float64Pointer(7)}),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(4)}),
+			}},
+		},
+		{
+			name: "rate handles counter resets",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)},
+						tp{time.Unix(20, 0), float64Pointer(5)},
+						tp{time.Unix(30, 0), nil}),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(2)},
+					tp{time.Unix(20, 0), float64Pointer(0.5)},
+					tp{time.Unix(30, 0), nil}),
+			}},
+		},
+		{
+			name: "delta of unsorted series",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(20, 0), float64Pointer(5)},
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(30)}),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil,
+					tp{time.Unix(10, 0), float64Pointer(20)},
+					tp{time.Unix(20, 0), float64Pointer(-25)}),
+			}},
+		},
+		{
+			name: "delta of a single point series is empty",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil, tp{time.Unix(0, 0), float64Pointer(10)}),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{[]Value{makeSeries("", nil)}},
+		},
+		{
+			name: "moving_avg averages values within the window",
+			expr: "moving_avg($A, 20s)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(2)},
+						tp{time.Unix(10, 0), float64Pointer(4)},
+						tp{time.Unix(20, 0), nil},
+						tp{time.Unix(30, 0), float64Pointer(9)},
+						tp{time.Unix(60, 0), nil}),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(2)},
+					tp{time.Unix(10, 0), float64Pointer(3)},
+					tp{time.Unix(20, 0), float64Pointer(4)},
+					tp{time.Unix(30, 0), float64Pointer(9)},
+					tp{time.Unix(60, 0), nil}),
+			}},
+		},
+		{
+			name: "cumsum skips null values",
+			expr: "cumsum($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(1)},
+						tp{time.Unix(10, 0), nil},
+						tp{time.Unix(20, 0), float64Pointer(2)}),
+				}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil,
+					tp{time.Unix(0, 0), float64Pointer(1)},
+					tp{time.Unix(10, 0), nil},
+					tp{time.Unix(20, 0), float64Pointer(3)}),
+			}},
+		},
+		{
+			name: "series function passes through no data",
+			expr: "cumsum($A)",
+			vars: Vars{
+				"A": Results{[]Value{NewNoData()}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.NoError,
+			results:   Results{[]Value{NewNoData()}},
+		},
+		{
+			name: "series function on number should error",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{[]Value{makeNumber("", nil, float64Pointer(1))}},
+			},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+		{
+			name:     "shift without duration should error",
+			expr:     "shift($A, 5)",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "duration outside of a function should error",
+			expr:     "$A + 5m",
+			newErrIs: require.Error,
+		},
+		{
+			name:     "invalid duration should error",
+			expr:     "shift($A, 5x)",
+			newErrIs: require.Error,
+		},
+		{
+			name:      "moving_avg with zero window should error",
+			expr:      "moving_avg($A, 0s)",
+			vars:      Vars{"A": Results{[]Value{makeSeries("", nil)}}},
+			newErrIs:  require.NoError,
+			execErrIs: require.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if err != nil {
+				return
+			}
+			res, err := e.Execute("", tt.vars, tracing.NewFakeTracer())
+			tt.execErrIs(t, err)
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.results, res, data.FrameTestCompareOptions()...); diff != "" {
+				t.Errorf("Result mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
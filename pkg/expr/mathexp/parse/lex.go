@@ -56,8 +56,9 @@ const (
 	itemRightParen
 	itemString
 	itemFunc
-	itemVar // e.g. $A
-	itemPow // '**'
+	itemVar      // e.g. $A
+	itemPow      // '**'
+	itemDuration // e.g. 5m
 )
 
 const eof = -1
@@ -89,7 +90,6 @@ func (l *lexer) next() rune {
 }
 
 // peek returns but does not consume the next rune in the input.
-// nolint:unused
 func (l *lexer) peek() rune {
 	r := l.next()
 	l.backup()
@@ -206,10 +206,18 @@ Loop:
 // isn't a perfect number scanner - for instance it accepts "." and "0x0.2"
 // and "089" - but when it's wrong the input is invalid and the parser (via
 // strconv) will notice.
+// A number that is immediately followed by letters is scanned as a duration, e.g. 5m or 1d.
 func lexNumber(l *lexer) stateFn {
 	if !l.scanNumber() {
 		return l.errorf("bad number syntax: %q", l.input[l.start:l.pos])
 	}
+	if unicode.IsLetter(l.peek()) {
+		for unicode.IsLetter(l.next()) {
+		}
+		l.backup()
+		l.emit(itemDuration)
+		return lexItem
+	}
 	l.emit(itemNumber)
 	return lexItem
 }
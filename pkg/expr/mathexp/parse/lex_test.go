@@ -33,6 +33,7 @@ var itemName = map[itemType]string{
 	itemRightParen: ")",
 	itemString:     "string",
 	itemFunc:       "func",
+	itemDuration:   "duration",
 }
 
 func (i itemType) String() string {
@@ -97,6 +98,22 @@ var lexTests = []lexTest{
 		{itemNumber, 0, "1.2e-4"},
 		tEOF,
 	}},
+	{"durations", "5m 1d 1.5h 100ms", []item{
+		{itemDuration, 0, "5m"},
+		{itemDuration, 0, "1d"},
+		{itemDuration, 0, "1.5h"},
+		{itemDuration, 0, "100ms"},
+		tEOF,
+	}},
+	{"func with duration", "shift($A, 1w)", []item{
+		{itemFunc, 0, "shift"},
+		{itemLeftParen, 0, "("},
+		{itemVar, 0, "$A"},
+		{itemComma, 0, ","},
+		{itemDuration, 0, "1w"},
+		{itemRightParen, 0, ")"},
+		tEOF,
+	}},
 	{"curly brace var", "${My Var}", []item{
 		{itemVar, 0, "${My Var}"},
 		tEOF,
@@ -159,6 +159,9 @@ func (t *Tree) Parse(text string, funcs ...map[string]Func) (err error) {
 func (t *Tree) parse() {
 	t.Root = t.O()
 	t.expect(itemEOF, "root input")
+	if t.Root.Return() == TypeDuration {
+		t.errorf("duration can be used only as a function argument")
+	}
 	if err := t.Root.Check(t); err != nil {
 		t.error(err)
 	}
@@ -172,9 +175,9 @@ P -> M {( "+" | "-" ) M}
 M -> E {( "*" | "/" ) F}
 E -> F {( "**" ) F}
 F -> v | "(" O ")" | "!" O | "-" O
-v -> number | func(..) | queryVar
+v -> number | duration | func(..) | queryVar
 Func -> name "(" param {"," param} ")"
-param -> number | "string" | queryVar
+param -> number | duration | "string" | queryVar
 */
 
 // expr:
@@ -260,7 +263,7 @@ func (t *Tree) E() Node {
 // F is v | "(" O ")" | "!" O | "-" O in the grammar.
 func (t *Tree) F() Node {
 	switch token := t.peek(); token.typ {
-	case itemNumber, itemFunc, itemVar:
+	case itemNumber, itemDuration, itemFunc, itemVar:
 		return t.v()
 	case itemNot, itemMinus:
 		return newUnary(t.next(), t.F())
@@ -275,7 +278,7 @@ func (t *Tree) F() Node {
 	return nil
 }
 
-// V is number | func(..) | queryVar in the grammar.
+// V is number | duration | func(..) | queryVar in the grammar.
 func (t *Tree) v() Node {
 	switch token := t.next(); token.typ {
 	case itemNumber:
@@ -284,6 +287,12 @@ func (t *Tree) v() Node {
 			t.error(err)
 		}
 		return n
+	case itemDuration:
+		n, err := newDuration(token.pos, token.val)
+		if err != nil {
+			t.error(err)
+		}
+		return n
 	case itemFunc:
 		t.backup()
 		return t.Func()
@@ -329,6 +338,10 @@ func (t *Tree) Func() (f *FuncNode) {
 				t.errorf("Unquoting error: %s", err)
 			}
 			f.append(newString(token.pos, token.val, s))
+		case itemComma:
+			if len(f.Args) == 0 {
+				t.unexpected(token, "func")
+			}
 		case itemRightParen:
 			return
 		}
@@ -1,8 +1,10 @@
 package mathexp
 
 import (
+	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,7 +83,91 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
-func GetReduceFunc(rFunc string) (ReducerFunc, error) {
+// Median returns the median of the values. It returns NaN if the field is empty or contains a non-number.
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a reducer that calculates the p-th percentile (0 <= p <= 100) of the values
+// using linear interpolation between the closest ranks. The reducer returns NaN if the field is empty or contains a non-number.
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		values, ok := sortedValues(fv)
+		if !ok || len(values) == 0 {
+			nan := math.NaN()
+			return &nan
+		}
+		rank := p / 100 * float64(len(values)-1)
+		lower := int(math.Floor(rank))
+		upper := int(math.Ceil(rank))
+		f := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+		return &f
+	}
+}
+
+// StdDev returns the population standard deviation of the values. It returns NaN if the field is empty or contains a non-number.
+func StdDev(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	avg := Avg(fv)
+	if math.IsNaN(*avg) {
+		return avg
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		d := *fv.GetValue(i) - *avg
+		sum += d * d
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+// Diff returns the difference between the last and the first values. It returns NaN if the field is empty or either of them is not a number.
+func Diff(fv *Float64Field) *float64 {
+	if fv.Len() == 0 {
+		nan := math.NaN()
+		return &nan
+	}
+	first, last := fv.GetValue(0), fv.GetValue(fv.Len()-1)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			continue
+		}
+		f++
+	}
+	return &f
+}
+
+// sortedValues returns a sorted copy of the values. The second result is false if any of the values is null or NaN.
+func sortedValues(fv *Float64Field) ([]float64, bool) {
+	values := make([]float64, 0, fv.Len())
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v == nil || math.IsNaN(*v) {
+			return nil, false
+		}
+		values = append(values, *v)
+	}
+	sort.Float64s(values)
+	return values, true
+}
+
+// GetReduceFunc returns the reduction function by its name. Parameterized functions, such as percentile, read their arguments from params.
+func GetReduceFunc(rFunc string, params ...float64) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
 		return Sum, nil
@@ -95,6 +181,22 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "median":
+		return Median, nil
+	case "percentile":
+		if len(params) != 1 {
+			return nil, errors.New("reduction percentile requires exactly one parameter")
+		}
+		if params[0] < 0 || params[0] > 100 || math.IsNaN(params[0]) {
+			return nil, fmt.Errorf("reduction percentile requires parameter between 0 and 100, got %v", params[0])
+		}
+		return Percentile(params[0]), nil
+	case "stddev":
+		return StdDev, nil
+	case "diff":
+		return Diff, nil
+	case "count_non_null":
+		return CountNonNull, nil
 	default:
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
@@ -102,13 +204,14 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 
 // GetSupportedReduceFuncs returns collection of supported function names
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "median", "percentile", "stddev", "diff", "count_non_null"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
 // if ReduceMapper is defined it applies it to the provided series and performs reduction of the resulting series.
 // Otherwise, the reduction operation is done against the original series.
-func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error) {
+// params are passed to the reduction function, see GetReduceFunc.
+func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper, params ...float64) (Number, error) {
 	var l data.Labels
 	if s.GetLabels() != nil {
 		l = s.GetLabels().Copy()
@@ -121,7 +224,7 @@ func (s Series) Reduce(refID, rFunc string, mapper ReduceMapper) (Number, error)
 	}
 	fVec := series.Frame.Fields[seriesTypeValIdx]
 	floatField := Float64Field(*fVec)
-	reduceFunc, err := GetReduceFunc(rFunc)
+	reduceFunc, err := GetReduceFunc(rFunc, params...)
 	if err != nil {
 		return number, fmt.Errorf("invalid expression '%s': %w", refID, err)
 	}
@@ -514,3 +514,93 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 		})
 	}
 }
+
+var fivePointSeries = makeSeries("temp", nil,
+	tp{time.Unix(5, 0), float64Pointer(4)},
+	tp{time.Unix(10, 0), float64Pointer(1)},
+	tp{time.Unix(15, 0), float64Pointer(3)},
+	tp{time.Unix(20, 0), float64Pointer(5)},
+	tp{time.Unix(25, 0), float64Pointer(2)},
+)
+
+var fivePointSeriesWithNonNumbers = makeSeries("temp", nil,
+	tp{time.Unix(5, 0), float64Pointer(4)},
+	tp{time.Unix(10, 0), nil},
+	tp{time.Unix(15, 0), float64Pointer(1)},
+	tp{time.Unix(20, 0), NaN},
+	tp{time.Unix(25, 0), float64Pointer(3)},
+)
+
+func TestSeriesReduceStatistics(t *testing.T) {
+	replaceWith := float64(10)
+	var tests = []struct {
+		name     string
+		red      string
+		params   []float64
+		series   Series
+		mapper   ReduceMapper
+		expected *float64
+	}{
+		{name: "median odd", red: "median", series: fivePointSeries, expected: float64Pointer(3)},
+		{name: "median even", red: "median", series: makeSeries("temp", nil, tp{time.Unix(5, 0), float64Pointer(4)}, tp{time.Unix(10, 0), float64Pointer(1)}), expected: float64Pointer(2.5)},
+		{name: "median empty", red: "median", series: makeSeries("temp", nil), expected: NaN},
+		{name: "median with non-numbers", red: "median", series: fivePointSeriesWithNonNumbers, expected: NaN},
+		{name: "dropNN: median with non-numbers", red: "median", series: fivePointSeriesWithNonNumbers, mapper: DropNonNumber{}, expected: float64Pointer(3)},
+		{name: "dropNN: median empty", red: "median", series: makeSeries("temp", nil), mapper: DropNonNumber{}, expected: nil},
+		{name: "replaceNN: median with non-numbers", red: "median", series: fivePointSeriesWithNonNumbers, mapper: ReplaceNonNumberWithValue{Value: replaceWith}, expected: float64Pointer(4)},
+		{name: "replaceNN: median empty", red: "median", series: makeSeries("temp", nil), mapper: ReplaceNonNumberWithValue{Value: replaceWith}, expected: float64Pointer(replaceWith)},
+
+		{name: "percentile 0", red: "percentile", params: []float64{0}, series: fivePointSeries, expected: float64Pointer(1)},
+		{name: "percentile 100", red: "percentile", params: []float64{100}, series: fivePointSeries, expected: float64Pointer(5)},
+		{name: "percentile 95", red: "percentile", params: []float64{95}, series: fivePointSeries, expected: float64Pointer(4.8)},
+		{name: "percentile with non-numbers", red: "percentile", params: []float64{95}, series: fivePointSeriesWithNonNumbers, expected: NaN},
+		{name: "dropNN: percentile with non-numbers", red: "percentile", params: []float64{50}, series: fivePointSeriesWithNonNumbers, mapper: DropNonNumber{}, expected: float64Pointer(3)},
+
+		{name: "stddev", red: "stddev", series: fivePointSeries, expected: float64Pointer(math.Sqrt(2))},
+		{name: "stddev empty", red: "stddev", series: makeSeries("temp", nil), expected: NaN},
+		{name: "stddev with non-numbers", red: "stddev", series: fivePointSeriesWithNonNumbers, expected: NaN},
+		{name: "dropNN: stddev with non-numbers", red: "stddev", series: fivePointSeriesWithNonNumbers, mapper: DropNonNumber{}, expected: float64Pointer(math.Sqrt(14.0 / 9))},
+
+		{name: "diff", red: "diff", series: fivePointSeries, expected: float64Pointer(-2)},
+		{name: "diff single point", red: "diff", series: makeSeries("temp", nil, tp{time.Unix(5, 0), float64Pointer(4)}), expected: float64Pointer(0)},
+		{name: "diff empty", red: "diff", series: makeSeries("temp", nil), expected: NaN},
+		{name: "diff with non-number last", red: "diff", series: makeSeries("temp", nil, tp{time.Unix(5, 0), float64Pointer(4)}, tp{time.Unix(10, 0), nil}), expected: NaN},
+		{name: "dropNN: diff with non-number last", red: "diff", series: makeSeries("temp", nil, tp{time.Unix(5, 0), float64Pointer(4)}, tp{time.Unix(10, 0), float64Pointer(6)}, tp{time.Unix(15, 0), nil}), mapper: DropNonNumber{}, expected: float64Pointer(2)},
+		{name: "replaceNN: diff with non-number last", red: "diff", series: makeSeries("temp", nil, tp{time.Unix(5, 0), float64Pointer(4)}, tp{time.Unix(10, 0), nil}), mapper: ReplaceNonNumberWithValue{Value: replaceWith}, expected: float64Pointer(6)},
+
+		{name: "count_non_null", red: "count_non_null", series: fivePointSeriesWithNonNumbers, expected: float64Pointer(3)},
+		{name: "count_non_null empty", red: "count_non_null", series: makeSeries("temp", nil), expected: float64Pointer(0)},
+		{name: "replaceNN: count_non_null", red: "count_non_null", series: fivePointSeriesWithNonNumbers, mapper: ReplaceNonNumberWithValue{Value: replaceWith}, expected: float64Pointer(5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			number, err := tt.series.Reduce("", tt.red, tt.mapper, tt.params...)
+			require.NoError(t, err)
+			actual := number.GetFloat64Value()
+			if tt.expected == nil {
+				require.Nil(t, actual)
+				return
+			}
+			require.NotNil(t, actual)
+			if math.IsNaN(*tt.expected) {
+				require.True(t, math.IsNaN(*actual), "expected NaN but got %v", *actual)
+				return
+			}
+			require.InDelta(t, *tt.expected, *actual, 1e-9)
+		})
+	}
+}
+
+func TestGetReduceFuncPercentileParams(t *testing.T) {
+	_, err := GetReduceFunc("percentile")
+	require.Error(t, err)
+	_, err = GetReduceFunc("percentile", 50, 90)
+	require.Error(t, err)
+	_, err = GetReduceFunc("percentile", -1)
+	require.Error(t, err)
+	_, err = GetReduceFunc("percentile", 100.1)
+	require.Error(t, err)
+	_, err = GetReduceFunc("percentile", 99.9)
+	require.NoError(t, err)
+}
@@ -0,0 +1,333 @@
+package ml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+const (
+	// SeasonalMethodZScore calculates the bands as mean ± sensitivity * standard deviation of the seasonal history.
+	SeasonalMethodZScore = "zscore"
+	// SeasonalMethodMAD calculates the bands as median ± sensitivity * scaled median absolute deviation of the seasonal history.
+	SeasonalMethodMAD = "mad"
+
+	// SeasonalOutputAnomaly makes the command return a series per input series whose value is 1 if the point is outside the bands and 0 otherwise.
+	SeasonalOutputAnomaly = "anomaly"
+	// SeasonalOutputBands makes the command return the upper and lower bands per input series.
+	SeasonalOutputBands = "bands"
+	// SeasonalOutputAll makes the command return both the anomaly series and the upper and lower bands per input series.
+	SeasonalOutputAll = "all"
+
+	// BandLabel is the label that is added to the band series to distinguish the upper band from the lower one.
+	BandLabel = "anomaly_band"
+
+	defaultSeasons     = 3
+	defaultSensitivity = 3.0
+
+	// madScale makes the median absolute deviation a consistent estimator of the standard deviation for normally distributed data.
+	madScale = 1.4826
+)
+
+// SeasonalAnomalyCommand is an expression command that detects anomalies in time series locally, without the Machine Learning API.
+// For every point it builds a baseline from the points of the same series that are whole seasons apart from it,
+// and checks whether the point is within the bands around the baseline.
+// The input query should cover at least Seasons+1 seasons so that the points at the end of the range have enough history.
+type SeasonalAnomalyCommand struct {
+	RefID       string
+	VarToDetect string
+	Season      time.Duration
+	Seasons     int
+	Method      string
+	Sensitivity float64
+	Output      string
+	MinHistory  int
+}
+
+// NewSeasonalAnomalyCommand creates a new SeasonalAnomalyCommand and validates its configuration.
+func NewSeasonalAnomalyCommand(refID, varToDetect string, season time.Duration, seasons int, method string, sensitivity float64, output string) (*SeasonalAnomalyCommand, error) {
+	if season <= 0 {
+		return nil, errors.New("season must be greater than zero")
+	}
+	if seasons < 1 {
+		return nil, fmt.Errorf("number of seasons must be at least 1, got %d", seasons)
+	}
+	if sensitivity <= 0 || math.IsNaN(sensitivity) || math.IsInf(sensitivity, 0) {
+		return nil, fmt.Errorf("sensitivity must be a positive number, got %v", sensitivity)
+	}
+	switch method {
+	case SeasonalMethodZScore, SeasonalMethodMAD:
+	default:
+		return nil, fmt.Errorf("unsupported method '%s'. Should be one of [%s]", method, strings.Join([]string{SeasonalMethodZScore, SeasonalMethodMAD}, ", "))
+	}
+	switch output {
+	case SeasonalOutputAnomaly, SeasonalOutputBands, SeasonalOutputAll:
+	default:
+		return nil, fmt.Errorf("unsupported output '%s'. Should be one of [%s]", output, strings.Join([]string{SeasonalOutputAnomaly, SeasonalOutputBands, SeasonalOutputAll}, ", "))
+	}
+	minHistory := 2
+	if seasons < minHistory {
+		minHistory = seasons
+	}
+	return &SeasonalAnomalyCommand{
+		RefID:       refID,
+		VarToDetect: varToDetect,
+		Season:      season,
+		Seasons:     seasons,
+		Method:      method,
+		Sensitivity: sensitivity,
+		Output:      output,
+		MinHistory:  minHistory,
+	}, nil
+}
+
+// UnmarshalSeasonalAnomalyCommand creates a SeasonalAnomalyCommand from Grafana's frontend query. Only fields "expression" and "season" are required.
+func UnmarshalSeasonalAnomalyCommand(refID string, rawQuery map[string]interface{}) (*SeasonalAnomalyCommand, error) {
+	q := simplejson.NewFromAny(rawQuery)
+
+	varToDetect, err := q.Get("expression").String()
+	if err != nil || varToDetect == "" {
+		return nil, errors.New("no expression ID to detect anomalies in. must be a reference to an existing query or expression")
+	}
+	varToDetect = strings.TrimPrefix(varToDetect, "$")
+
+	rawSeason, err := q.Get("season").String()
+	if err != nil || rawSeason == "" {
+		return nil, errors.New("required field 'season' is not specified or is not a string")
+	}
+	season, err := gtime.ParseDuration(rawSeason)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse "season" duration field %q: %w`, rawSeason, err)
+	}
+
+	seasons := defaultSeasons
+	if v, ok := q.CheckGet("seasons"); ok {
+		i, err := v.Int()
+		if err != nil {
+			return nil, fmt.Errorf("field 'seasons' is expected to be an integer: %w", err)
+		}
+		seasons = i
+	}
+
+	sensitivity := defaultSensitivity
+	if v, ok := q.CheckGet("sensitivity"); ok {
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("field 'sensitivity' is expected to be a number: %w", err)
+		}
+		sensitivity = f
+	}
+
+	method := q.Get("method").MustString(SeasonalMethodZScore)
+	output := q.Get("output").MustString(SeasonalOutputAnomaly)
+
+	return NewSeasonalAnomalyCommand(refID, varToDetect, season, seasons, strings.ToLower(method), sensitivity, strings.ToLower(output))
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (c *SeasonalAnomalyCommand) NeedsVars() []string {
+	return []string{c.VarToDetect}
+}
+
+// Execute runs the command and returns the results or an error if the command failed to execute.
+func (c *SeasonalAnomalyCommand) Execute(ctx context.Context, _ time.Time, vars mathexp.Vars, tracer tracing.Tracer) (mathexp.Results, error) {
+	_, span := tracer.Start(ctx, "SSE.ExecuteSeasonalAnomaly")
+	defer span.End()
+
+	newRes := mathexp.Results{}
+	for _, val := range vars[c.VarToDetect].Values {
+		switch v := val.(type) {
+		case mathexp.Series:
+			newRes.Values = append(newRes.Values, c.detect(v)...)
+		case mathexp.NoData:
+			newRes.Values = append(newRes.Values, v.New())
+		default:
+			return newRes, fmt.Errorf("can only detect anomalies in type series, got type %v", val.Type())
+		}
+	}
+	return newRes, nil
+}
+
+// band is the range of expected values of a point.
+type band struct {
+	lower, upper float64
+}
+
+// detect calculates the bands for every point of the series and returns the series requested by the output.
+func (c *SeasonalAnomalyCommand) detect(s mathexp.Series) []mathexp.Value {
+	points := sortedPoints(s)
+	tolerance := pointsTolerance(points)
+
+	var labels data.Labels
+	if s.GetLabels() != nil {
+		labels = s.GetLabels().Copy()
+	}
+
+	anomaly := mathexp.NewSeries(c.RefID, labels, 0)
+	upper := mathexp.NewSeries(c.RefID, withBandLabel(labels, "upper"), 0)
+	lower := mathexp.NewSeries(c.RefID, withBandLabel(labels, "lower"), 0)
+	for i, p := range points {
+		b, ok := c.band(points, i, tolerance)
+		if !ok {
+			anomaly.AppendPoint(p.t, nil)
+			upper.AppendPoint(p.t, nil)
+			lower.AppendPoint(p.t, nil)
+			continue
+		}
+		u, l := b.upper, b.lower
+		upper.AppendPoint(p.t, &u)
+		lower.AppendPoint(p.t, &l)
+		if p.v == nil {
+			anomaly.AppendPoint(p.t, nil)
+			continue
+		}
+		f := 0.0
+		if *p.v > b.upper || *p.v < b.lower {
+			f = 1
+		}
+		anomaly.AppendPoint(p.t, &f)
+	}
+
+	switch c.Output {
+	case SeasonalOutputBands:
+		return []mathexp.Value{upper, lower}
+	case SeasonalOutputAll:
+		return []mathexp.Value{anomaly, upper, lower}
+	default:
+		return []mathexp.Value{anomaly}
+	}
+}
+
+// band returns the band of the point with index idx. It returns false if there is not enough history to calculate it.
+func (c *SeasonalAnomalyCommand) band(points []point, idx int, tolerance time.Duration) (band, bool) {
+	history := make([]float64, 0, c.Seasons)
+	for season := 1; season <= c.Seasons; season++ {
+		v, ok := lookupPoint(points, points[idx].t.Add(-time.Duration(season)*c.Season), tolerance)
+		if !ok {
+			continue
+		}
+		history = append(history, v)
+	}
+	if len(history) < c.MinHistory {
+		return band{}, false
+	}
+
+	var center, deviation float64
+	switch c.Method {
+	case SeasonalMethodMAD:
+		center = median(history)
+		deviations := make([]float64, len(history))
+		for i, v := range history {
+			deviations[i] = math.Abs(v - center)
+		}
+		deviation = madScale * median(deviations)
+	default:
+		for _, v := range history {
+			center += v
+		}
+		center /= float64(len(history))
+		for _, v := range history {
+			deviation += (v - center) * (v - center)
+		}
+		deviation = math.Sqrt(deviation / float64(len(history)))
+	}
+	return band{
+		lower: center - c.Sensitivity*deviation,
+		upper: center + c.Sensitivity*deviation,
+	}, true
+}
+
+type point struct {
+	t time.Time
+	v *float64
+}
+
+// sortedPoints returns the points of the series sorted by time.
+func sortedPoints(s mathexp.Series) []point {
+	points := make([]point, 0, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		t, v := s.GetPoint(i)
+		points = append(points, point{t: t, v: v})
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+	return points
+}
+
+// pointsTolerance returns the maximal distance between a point and the time it is looked up by.
+// It is half of the smallest step between points, so that a lookup never matches more than one point.
+func pointsTolerance(points []point) time.Duration {
+	var step time.Duration
+	for i := 1; i < len(points); i++ {
+		d := points[i].t.Sub(points[i-1].t)
+		if d > 0 && (step == 0 || d < step) {
+			step = d
+		}
+	}
+	return step / 2
+}
+
+// lookupPoint returns the value of the point that is the closest to t within the tolerance. It returns false if there is no such point or its value is not a number.
+func lookupPoint(points []point, t time.Time, tolerance time.Duration) (float64, bool) {
+	idx := sort.Search(len(points), func(i int) bool {
+		return !points[i].t.Before(t)
+	})
+	best := -1
+	var bestDistance time.Duration
+	for _, i := range []int{idx - 1, idx} {
+		if i < 0 || i >= len(points) {
+			continue
+		}
+		d := points[i].t.Sub(t)
+		if d < 0 {
+			d = -d
+		}
+		if d > tolerance {
+			continue
+		}
+		if best == -1 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	if best == -1 {
+		return 0, false
+	}
+	v := points[best].v
+	if v == nil || math.IsNaN(*v) || math.IsInf(*v, 0) {
+		return 0, false
+	}
+	return *v, true
+}
+
+func median(values []float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func withBandLabel(labels data.Labels, value string) data.Labels {
+	result := make(data.Labels, len(labels)+1)
+	for k, v := range labels {
+		result[k] = v
+	}
+	result[BandLabel] = value
+	return result
+}
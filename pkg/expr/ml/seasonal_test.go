@@ -0,0 +1,218 @@
+package ml
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestUnmarshalSeasonalAnomalyCommand(t *testing.T) {
+	t.Run("should parse command with defaults", func(t *testing.T) {
+		cmd, err := UnmarshalSeasonalAnomalyCommand("B", map[string]interface{}{
+			"expression": "$A",
+			"season":     "1d",
+		})
+		require.NoError(t, err)
+		require.Equal(t, &SeasonalAnomalyCommand{
+			RefID:       "B",
+			VarToDetect: "A",
+			Season:      24 * time.Hour,
+			Seasons:     defaultSeasons,
+			Method:      SeasonalMethodZScore,
+			Sensitivity: defaultSensitivity,
+			Output:      SeasonalOutputAnomaly,
+			MinHistory:  2,
+		}, cmd)
+		require.Equal(t, []string{"A"}, cmd.NeedsVars())
+	})
+
+	t.Run("should parse all fields", func(t *testing.T) {
+		cmd, err := UnmarshalSeasonalAnomalyCommand("B", map[string]interface{}{
+			"expression":  "A",
+			"season":      "1h",
+			"seasons":     float64(1),
+			"method":      "MAD",
+			"sensitivity": 2.5,
+			"output":      "bands",
+		})
+		require.NoError(t, err)
+		require.Equal(t, &SeasonalAnomalyCommand{
+			RefID:       "B",
+			VarToDetect: "A",
+			Season:      time.Hour,
+			Seasons:     1,
+			Method:      SeasonalMethodMAD,
+			Sensitivity: 2.5,
+			Output:      SeasonalOutputBands,
+			MinHistory:  1,
+		}, cmd)
+	})
+
+	t.Run("fails when", func(t *testing.T) {
+		testCases := []struct {
+			name  string
+			query map[string]interface{}
+			err   string
+		}{
+			{
+				name:  "expression is missing",
+				query: map[string]interface{}{"season": "1d"},
+				err:   "no expression ID",
+			},
+			{
+				name:  "season is missing",
+				query: map[string]interface{}{"expression": "A"},
+				err:   "required field 'season'",
+			},
+			{
+				name:  "season is invalid",
+				query: map[string]interface{}{"expression": "A", "season": "abc"},
+				err:   "failed to parse \"season\"",
+			},
+			{
+				name:  "season is not positive",
+				query: map[string]interface{}{"expression": "A", "season": "0s"},
+				err:   "season must be greater than zero",
+			},
+			{
+				name:  "seasons is less than one",
+				query: map[string]interface{}{"expression": "A", "season": "1d", "seasons": float64(0)},
+				err:   "number of seasons must be at least 1",
+			},
+			{
+				name:  "sensitivity is not positive",
+				query: map[string]interface{}{"expression": "A", "season": "1d", "sensitivity": float64(-1)},
+				err:   "sensitivity must be a positive number",
+			},
+			{
+				name:  "method is unknown",
+				query: map[string]interface{}{"expression": "A", "season": "1d", "method": "foo"},
+				err:   "unsupported method",
+			},
+			{
+				name:  "output is unknown",
+				query: map[string]interface{}{"expression": "A", "season": "1d", "output": "foo"},
+				err:   "unsupported output",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				_, err := UnmarshalSeasonalAnomalyCommand("B", tc.query)
+				require.ErrorContains(t, err, tc.err)
+			})
+		}
+	})
+}
+
+func TestSeasonalAnomalyCommandExecute(t *testing.T) {
+	season := time.Hour
+	start := time.Unix(0, 0)
+	labels := data.Labels{"host": "a"}
+	// four seasons of history with four points in every season.
+	// The last season repeats the pattern except for the last point that is far outside the usual values.
+	values := []float64{
+		10, 20, 30, 40,
+		11, 21, 31, 41,
+		9, 19, 29, 39,
+		10, 20, 30, 100,
+	}
+	newInput := func() mathexp.Series {
+		s := mathexp.NewSeries("A", labels, len(values))
+		for i, v := range values {
+			s.SetPoint(i, start.Add(time.Duration(i)*season/4), util.Pointer(v))
+		}
+		return s
+	}
+	execute := func(t *testing.T, cmd *SeasonalAnomalyCommand, values ...mathexp.Value) mathexp.Results {
+		t.Helper()
+		res, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{"A": mathexp.Results{Values: values}}, tracing.NewFakeTracer())
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("anomaly output marks points outside of bands", func(t *testing.T) {
+		for _, method := range []string{SeasonalMethodZScore, SeasonalMethodMAD} {
+			t.Run(method, func(t *testing.T) {
+				cmd, err := NewSeasonalAnomalyCommand("B", "A", season, 3, method, 3, SeasonalOutputAnomaly)
+				require.NoError(t, err)
+
+				res := execute(t, cmd, newInput())
+				require.Len(t, res.Values, 1)
+				s, ok := res.Values[0].(mathexp.Series)
+				require.True(t, ok)
+				require.Equal(t, labels, s.GetLabels())
+				require.Equal(t, len(values), s.Len())
+
+				// the first season has no history at all, the second one has only one previous season.
+				for i := 0; i < 8; i++ {
+					require.Nilf(t, s.GetValue(i), "point %d", i)
+				}
+				for i := 8; i < 15; i++ {
+					require.Equalf(t, 0.0, *s.GetValue(i), "point %d", i)
+				}
+				require.Equal(t, 1.0, *s.GetValue(15))
+			})
+		}
+	})
+
+	t.Run("bands output returns upper and lower bands", func(t *testing.T) {
+		cmd, err := NewSeasonalAnomalyCommand("B", "A", season, 3, SeasonalMethodZScore, 2, SeasonalOutputBands)
+		require.NoError(t, err)
+
+		res := execute(t, cmd, newInput())
+		require.Len(t, res.Values, 2)
+		upper, lower := res.Values[0].(mathexp.Series), res.Values[1].(mathexp.Series)
+		require.Equal(t, data.Labels{"host": "a", BandLabel: "upper"}, upper.GetLabels())
+		require.Equal(t, data.Labels{"host": "a", BandLabel: "lower"}, lower.GetLabels())
+
+		// the history of the last point is 41, 39 and 40
+		stdDev := math.Sqrt(2.0 / 3.0)
+		require.InDelta(t, 40+2*stdDev, *upper.GetValue(15), 1e-9)
+		require.InDelta(t, 40-2*stdDev, *lower.GetValue(15), 1e-9)
+	})
+
+	t.Run("all output returns the anomaly series and the bands", func(t *testing.T) {
+		cmd, err := NewSeasonalAnomalyCommand("B", "A", season, 3, SeasonalMethodZScore, 2, SeasonalOutputAll)
+		require.NoError(t, err)
+
+		res := execute(t, cmd, newInput())
+		require.Len(t, res.Values, 3)
+		anomaly, upper, lower := res.Values[0].(mathexp.Series), res.Values[1].(mathexp.Series), res.Values[2].(mathexp.Series)
+		require.Equal(t, labels, anomaly.GetLabels())
+		require.Equal(t, data.Labels{"host": "a", BandLabel: "upper"}, upper.GetLabels())
+		require.Equal(t, data.Labels{"host": "a", BandLabel: "lower"}, lower.GetLabels())
+
+		require.Equal(t, 0.0, *anomaly.GetValue(14))
+		require.Equal(t, 1.0, *anomaly.GetValue(15))
+		stdDev := math.Sqrt(2.0 / 3.0)
+		require.InDelta(t, 40+2*stdDev, *upper.GetValue(15), 1e-9)
+		require.InDelta(t, 40-2*stdDev, *lower.GetValue(15), 1e-9)
+	})
+
+	t.Run("no data is passed through", func(t *testing.T) {
+		cmd, err := NewSeasonalAnomalyCommand("B", "A", season, 3, SeasonalMethodZScore, 3, SeasonalOutputAnomaly)
+		require.NoError(t, err)
+
+		res := execute(t, cmd, mathexp.NoData{}.New())
+		require.Len(t, res.Values, 1)
+		require.IsType(t, mathexp.NoData{}, res.Values[0])
+	})
+
+	t.Run("fails on numbers", func(t *testing.T) {
+		cmd, err := NewSeasonalAnomalyCommand("B", "A", season, 3, SeasonalMethodZScore, 3, SeasonalOutputAnomaly)
+		require.NoError(t, err)
+
+		_, err = cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: []mathexp.Value{mathexp.NewNumber("A", nil)}},
+		}, tracing.NewFakeTracer())
+		require.ErrorContains(t, err, "can only detect anomalies in type series")
+	})
+}
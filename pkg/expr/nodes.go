@@ -14,6 +14,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/expr/classic"
 	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/expr/ml"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/datasources"
@@ -34,12 +35,13 @@ type baseNode struct {
 }
 
 type rawNode struct {
-	RefID      string `json:"refId"`
-	Query      map[string]interface{}
-	QueryRaw   []byte
-	QueryType  string
-	TimeRange  TimeRange
-	DataSource *datasources.DataSource
+	RefID            string `json:"refId"`
+	Query            map[string]interface{}
+	QueryRaw         []byte
+	QueryType        string
+	TimeRange        TimeRange
+	DataSource       *datasources.DataSource
+	LoadedDimensions Fingerprints
 }
 
 func (rn *rawNode) GetCommandType() (c CommandType, err error) {
@@ -111,9 +113,16 @@ func buildCMDNode(dp *simple.DirectedGraph, rn *rawNode) (*CMDNode, error) {
 	case TypeResample:
 		node.Command, err = UnmarshalResampleCommand(rn)
 	case TypeClassicConditions:
-		node.Command, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
+		var cmd *classic.ConditionsCmd
+		cmd, err = classic.UnmarshalConditionsCmd(rn.Query, rn.RefID)
+		if err == nil {
+			cmd.LoadedDimensions = rn.LoadedDimensions
+			node.Command = cmd
+		}
 	case TypeThreshold:
 		node.Command, err = UnmarshalThresholdCommand(rn)
+	case TypeSeasonalAnomaly:
+		node.Command, err = ml.UnmarshalSeasonalAnomalyCommand(rn.RefID, rn.Query)
 	default:
 		return nil, fmt.Errorf("expression command type '%v' in expression '%v' not implemented", commandType, rn.RefID)
 	}
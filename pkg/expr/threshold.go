@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 	"github.com/grafana/grafana/pkg/infra/tracing"
 )
@@ -16,6 +18,16 @@ type ThresholdCommand struct {
 	RefID         string
 	ThresholdFunc string
 	Conditions    []float64
+	// Unloading is an optional recovery threshold. If it is set, dimensions in LoadedDimensions
+	// keep meeting the condition until they meet the recovery threshold.
+	Unloading        *ThresholdUnloading
+	LoadedDimensions Fingerprints
+}
+
+// ThresholdUnloading is a threshold that is used to evaluate dimensions that met the condition at the previous evaluation.
+type ThresholdUnloading struct {
+	ThresholdFunc string
+	Conditions    []float64
 }
 
 const (
@@ -30,15 +42,8 @@ var (
 )
 
 func NewThresholdCommand(refID, referenceVar, thresholdFunc string, conditions []float64) (*ThresholdCommand, error) {
-	switch thresholdFunc {
-	case ThresholdIsOutsideRange, ThresholdIsWithinRange:
-		if len(conditions) < 2 {
-			return nil, fmt.Errorf("incorrect number of arguments: got %d but need 2", len(conditions))
-		}
-	case ThresholdIsAbove, ThresholdIsBelow:
-		if len(conditions) < 1 {
-			return nil, fmt.Errorf("incorrect number of arguments: got %d but need 1", len(conditions))
-		}
+	if err := validateThresholdArguments(thresholdFunc, conditions); err != nil {
+		return nil, err
 	}
 
 	return &ThresholdCommand{
@@ -49,8 +54,40 @@ func NewThresholdCommand(refID, referenceVar, thresholdFunc string, conditions [
 	}, nil
 }
 
+// NewHysteresisCommand creates a ThresholdCommand that uses the unloading threshold for dimensions that are in loadedDimensions.
+func NewHysteresisCommand(refID, referenceVar string, loading ConditionEvalJSON, unloading ConditionEvalJSON, loadedDimensions Fingerprints) (*ThresholdCommand, error) {
+	cmd, err := NewThresholdCommand(refID, referenceVar, loading.Type, loading.Params)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateThresholdArguments(unloading.Type, unloading.Params); err != nil {
+		return nil, fmt.Errorf("invalid unloading threshold: %w", err)
+	}
+	cmd.Unloading = &ThresholdUnloading{
+		ThresholdFunc: unloading.Type,
+		Conditions:    unloading.Params,
+	}
+	cmd.LoadedDimensions = loadedDimensions
+	return cmd, nil
+}
+
+func validateThresholdArguments(thresholdFunc string, conditions []float64) error {
+	switch thresholdFunc {
+	case ThresholdIsOutsideRange, ThresholdIsWithinRange:
+		if len(conditions) < 2 {
+			return fmt.Errorf("incorrect number of arguments: got %d but need 2", len(conditions))
+		}
+	case ThresholdIsAbove, ThresholdIsBelow:
+		if len(conditions) < 1 {
+			return fmt.Errorf("incorrect number of arguments: got %d but need 1", len(conditions))
+		}
+	}
+	return nil
+}
+
 type ThresholdConditionJSON struct {
-	Evaluator ConditionEvalJSON `json:"evaluator"`
+	Evaluator       ConditionEvalJSON  `json:"evaluator"`
+	UnloadEvaluator *ConditionEvalJSON `json:"unloadEvaluator,omitempty"`
 }
 
 type ConditionEvalJSON struct {
@@ -84,6 +121,9 @@ func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
 		if !IsSupportedThresholdFunc(condition.Evaluator.Type) {
 			return nil, fmt.Errorf("expected threshold function to be one of %s, got %s", strings.Join(supportedThresholdFuncs, ", "), condition.Evaluator.Type)
 		}
+		if condition.UnloadEvaluator != nil && !IsSupportedThresholdFunc(condition.UnloadEvaluator.Type) {
+			return nil, fmt.Errorf("expected unloading threshold function to be one of %s, got %s", strings.Join(supportedThresholdFuncs, ", "), condition.UnloadEvaluator.Type)
+		}
 	}
 
 	// we only support one condition for now, we might want to turn this in to "OR" expressions later
@@ -92,6 +132,9 @@ func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
 	}
 	firstCondition := conditions[0]
 
+	if firstCondition.UnloadEvaluator != nil {
+		return NewHysteresisCommand(rn.RefID, referenceVar, firstCondition.Evaluator, *firstCondition.UnloadEvaluator, rn.LoadedDimensions)
+	}
 	return NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
 }
 
@@ -112,7 +155,38 @@ func (tc *ThresholdCommand) Execute(ctx context.Context, now time.Time, vars mat
 		return mathexp.Results{}, err
 	}
 
-	return mathCommand.Execute(ctx, now, vars, tracer)
+	results, err := mathCommand.Execute(ctx, now, vars, tracer)
+	if err != nil || tc.Unloading == nil || len(tc.LoadedDimensions) == 0 {
+		return results, err
+	}
+
+	// Dimensions that met the condition at the previous evaluation keep meeting it until they meet the unloading threshold.
+	unloadingExpression, err := createMathExpression(tc.ReferenceVar, tc.Unloading.ThresholdFunc, tc.Unloading.Conditions)
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	unloadingCommand, err := NewMathCommand(tc.ReferenceVar, fmt.Sprintf("!(%s)", unloadingExpression))
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	unloadingResults, err := unloadingCommand.Execute(ctx, now, vars, tracer)
+	if err != nil {
+		return mathexp.Results{}, err
+	}
+	keepLoaded := make(map[data.Fingerprint]mathexp.Value, len(unloadingResults.Values))
+	for _, value := range unloadingResults.Values {
+		keepLoaded[value.GetLabels().Fingerprint()] = value
+	}
+	for i, value := range results.Values {
+		fingerprint := value.GetLabels().Fingerprint()
+		if _, ok := tc.LoadedDimensions[fingerprint]; !ok {
+			continue
+		}
+		if v, ok := keepLoaded[fingerprint]; ok {
+			results.Values[i] = v
+		}
+	}
+	return results, nil
 }
 
 // createMathExpression converts all the info we have about a "threshold" expression in to a Math expression
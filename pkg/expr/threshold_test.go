@@ -1,10 +1,16 @@
 package expr
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 )
 
 func TestNewThresholdCommand(t *testing.T) {
@@ -134,6 +140,62 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 			shouldError:   true,
 			expectedError: "expected threshold function to be one of",
 		},
+		{
+			description: "unmarshal with unload evaluator",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "lt",
+						"params": [60]
+					}
+				}]
+			}`,
+			shouldError: false,
+		},
+		{
+			description: "unmarshal with unsupported unload threshold function",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "foo",
+						"params": [60]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "expected unloading threshold function to be one of",
+		},
+		{
+			description: "unmarshal with unload evaluator missing arguments",
+			query: `{
+				"expression" : "A",
+				"type": "threshold",
+				"conditions": [{
+					"evaluator": {
+						"type": "gt",
+						"params": [80]
+					},
+					"unloadEvaluator": {
+						"type": "within_range",
+						"params": [60]
+					}
+				}]
+			}`,
+			shouldError:   true,
+			expectedError: "invalid unloading threshold",
+		},
 		{
 			description: "unmarshal with bad expression",
 			query: `{
@@ -170,6 +232,78 @@ func TestUnmarshalThresholdCommand(t *testing.T) {
 	}
 }
 
+func TestThresholdCommandHysteresis(t *testing.T) {
+	loaded := data.Labels{"host": "loaded"}
+	notLoaded := data.Labels{"host": "not-loaded"}
+	newNumber := func(labels data.Labels, value float64) mathexp.Number {
+		n := mathexp.NewNumber("A", labels)
+		n.SetValue(&value)
+		return n
+	}
+	vars := mathexp.Vars{
+		"A": mathexp.Results{
+			Values: []mathexp.Value{
+				newNumber(loaded, 70),
+				newNumber(notLoaded, 70),
+			},
+		},
+	}
+	loading := ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{80}}
+	unloading := ConditionEvalJSON{Type: ThresholdIsBelow, Params: []float64{60}}
+
+	testCases := []struct {
+		name     string
+		loaded   Fingerprints
+		expected map[data.Fingerprint]float64
+	}{
+		{
+			name:   "no loaded dimensions uses loading threshold",
+			loaded: nil,
+			expected: map[data.Fingerprint]float64{
+				loaded.Fingerprint():    0,
+				notLoaded.Fingerprint(): 0,
+			},
+		},
+		{
+			name:   "loaded dimension keeps meeting the condition until it meets the unloading threshold",
+			loaded: Fingerprints{loaded.Fingerprint(): {}},
+			expected: map[data.Fingerprint]float64{
+				loaded.Fingerprint():    1,
+				notLoaded.Fingerprint(): 0,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := NewHysteresisCommand("B", "A", loading, unloading, tc.loaded)
+			require.NoError(t, err)
+
+			results, err := cmd.Execute(context.Background(), time.Now(), vars, tracing.NewFakeTracer())
+			require.NoError(t, err)
+			require.Len(t, results.Values, len(tc.expected))
+			for _, value := range results.Values {
+				n, ok := value.(mathexp.Number)
+				require.True(t, ok)
+				expected, ok := tc.expected[n.GetLabels().Fingerprint()]
+				require.True(t, ok)
+				require.Equal(t, expected, *n.GetFloat64Value())
+			}
+		})
+	}
+
+	t.Run("loaded dimension stops meeting the condition when it meets the unloading threshold", func(t *testing.T) {
+		cmd, err := NewHysteresisCommand("B", "A", loading, unloading, Fingerprints{loaded.Fingerprint(): {}})
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), mathexp.Vars{
+			"A": mathexp.Results{Values: []mathexp.Value{newNumber(loaded, 50)}},
+		}, tracing.NewFakeTracer())
+		require.NoError(t, err)
+		require.Len(t, results.Values, 1)
+		require.Equal(t, 0.0, *results.Values[0].(mathexp.Number).GetFloat64Value())
+	})
+}
+
 func TestThresholdCommandVars(t *testing.T) {
 	cmd, err := NewThresholdCommand("B", "A", "is_above", []float64{})
 	require.Nil(t, err)
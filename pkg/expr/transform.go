@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -19,8 +20,14 @@ type Request struct {
 	OrgId   int64
 	Queries []Query
 	User    *user.SignedInUser
+	// LoadedDimensions contains fingerprints of labels of dimensions that met the condition at the previous evaluation.
+	// It is used by expressions that support hysteresis, such as threshold and classic conditions.
+	LoadedDimensions Fingerprints
 }
 
+// Fingerprints is a set of fingerprints of labels.
+type Fingerprints map[data.Fingerprint]struct{}
+
 // Query is like plugins.DataSubQuery, but with a a time range, and only the UID
 // for the data source. Also interval is a time.Duration.
 type Query struct {
@@ -12,6 +12,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/auth"
 	"github.com/grafana/grafana/pkg/services/cleanup"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	dashsnapschedule "github.com/grafana/grafana/pkg/services/dashboardsnapshots/schedule"
 	"github.com/grafana/grafana/pkg/services/grpcserver"
 	"github.com/grafana/grafana/pkg/services/guardian"
 	ldapapi "github.com/grafana/grafana/pkg/services/ldap/api"
@@ -26,6 +27,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/pluginsintegration/keyretriever/dynamic"
 	"github.com/grafana/grafana/pkg/services/provisioning"
 	publicdashboardsmetric "github.com/grafana/grafana/pkg/services/publicdashboards/metric"
+	publicdashboardsusage "github.com/grafana/grafana/pkg/services/publicdashboards/usage"
 	"github.com/grafana/grafana/pkg/services/rendering"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
@@ -50,8 +52,10 @@ func ProvideBackgroundServiceRegistry(
 	grpcServerProvider grpcserver.Provider, loginAttemptService *loginattemptimpl.Service,
 	bundleService *supportbundlesimpl.Service,
 	publicDashboardsMetric *publicdashboardsmetric.Service,
+	publicDashboardsUsage *publicdashboardsusage.Service,
 	keyRetriever *dynamic.KeyRetriever,
 	dynamicAngularDetectorsProvider *angulardetectorsprovider.Dynamic,
+	snapshotScheduleService *dashsnapschedule.Service,
 	// Need to make sure these are initialized, is there a better place to put them?
 	_ dashboardsnapshots.Service, _ *alerting.AlertNotificationService,
 	_ serviceaccounts.Service, _ *guardian.Provider,
@@ -86,8 +90,10 @@ func ProvideBackgroundServiceRegistry(
 		loginAttemptService,
 		bundleService,
 		publicDashboardsMetric,
+		publicDashboardsUsage,
 		keyRetriever,
 		dynamicAngularDetectorsProvider,
+		snapshotScheduleService,
 	)
 }
 
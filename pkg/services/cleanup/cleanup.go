@@ -18,8 +18,11 @@ import (
 	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/annotations"
 	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	snapshotschedule "github.com/grafana/grafana/pkg/services/dashboardsnapshots/schedule"
 	dashver "github.com/grafana/grafana/pkg/services/dashboardversion"
 	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/queryhistory"
 	"github.com/grafana/grafana/pkg/services/shorturls"
 	tempuser "github.com/grafana/grafana/pkg/services/temp_user"
@@ -29,37 +32,45 @@ import (
 func ProvideService(cfg *setting.Cfg, serverLockService *serverlock.ServerLockService,
 	shortURLService shorturls.Service, sqlstore db.DB, queryHistoryService queryhistory.Service,
 	dashboardVersionService dashver.Service, dashSnapSvc dashboardsnapshots.Service, deleteExpiredImageService *image.DeleteExpiredService,
-	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner) *CleanUpService {
+	tempUserService tempuser.Service, tracer tracing.Tracer, annotationCleaner annotations.Cleaner,
+	deleteExpiredStateHistoryService *historian.DeleteExpiredService, deleteExpiredDeliveriesService *notifier.DeleteExpiredDeliveriesService,
+	snapshotScheduleService *snapshotschedule.Service) *CleanUpService {
 	s := &CleanUpService{
-		Cfg:                       cfg,
-		ServerLockService:         serverLockService,
-		ShortURLService:           shortURLService,
-		QueryHistoryService:       queryHistoryService,
-		store:                     sqlstore,
-		log:                       log.New("cleanup"),
-		dashboardVersionService:   dashboardVersionService,
-		dashboardSnapshotService:  dashSnapSvc,
-		deleteExpiredImageService: deleteExpiredImageService,
-		tempUserService:           tempUserService,
-		tracer:                    tracer,
-		annotationCleaner:         annotationCleaner,
+		Cfg:                              cfg,
+		ServerLockService:                serverLockService,
+		ShortURLService:                  shortURLService,
+		QueryHistoryService:              queryHistoryService,
+		store:                            sqlstore,
+		log:                              log.New("cleanup"),
+		dashboardVersionService:          dashboardVersionService,
+		dashboardSnapshotService:         dashSnapSvc,
+		deleteExpiredImageService:        deleteExpiredImageService,
+		tempUserService:                  tempUserService,
+		tracer:                           tracer,
+		annotationCleaner:                annotationCleaner,
+		deleteExpiredStateHistoryService: deleteExpiredStateHistoryService,
+		deleteExpiredDeliveriesService:   deleteExpiredDeliveriesService,
+		snapshotScheduleService:          snapshotScheduleService,
 	}
 	return s
 }
 
 type CleanUpService struct {
-	log                       log.Logger
-	tracer                    tracing.Tracer
-	store                     db.DB
-	Cfg                       *setting.Cfg
-	ServerLockService         *serverlock.ServerLockService
-	ShortURLService           shorturls.Service
-	QueryHistoryService       queryhistory.Service
-	dashboardVersionService   dashver.Service
-	dashboardSnapshotService  dashboardsnapshots.Service
-	deleteExpiredImageService *image.DeleteExpiredService
-	tempUserService           tempuser.Service
-	annotationCleaner         annotations.Cleaner
+	log                              log.Logger
+	tracer                           tracing.Tracer
+	store                            db.DB
+	Cfg                              *setting.Cfg
+	ServerLockService                *serverlock.ServerLockService
+	ShortURLService                  shorturls.Service
+	QueryHistoryService              queryhistory.Service
+	dashboardVersionService          dashver.Service
+	dashboardSnapshotService         dashboardsnapshots.Service
+	deleteExpiredImageService        *image.DeleteExpiredService
+	tempUserService                  tempuser.Service
+	annotationCleaner                annotations.Cleaner
+	deleteExpiredStateHistoryService *historian.DeleteExpiredService
+	deleteExpiredDeliveriesService   *notifier.DeleteExpiredDeliveriesService
+	snapshotScheduleService          *snapshotschedule.Service
 }
 
 type cleanUpJob struct {
@@ -96,8 +107,11 @@ func (srv *CleanUpService) clean(ctx context.Context) {
 	cleanupJobs := []cleanUpJob{
 		{"clean up temporary files", srv.cleanUpTmpFiles},
 		{"delete expired snapshots", srv.deleteExpiredSnapshots},
+		{"delete excess scheduled snapshots", srv.deleteExcessScheduledSnapshots},
 		{"delete expired dashboard versions", srv.deleteExpiredDashboardVersions},
 		{"delete expired images", srv.deleteExpiredImages},
+		{"delete expired alert state history", srv.deleteExpiredStateHistory},
+		{"delete expired notification deliveries", srv.deleteExpiredNotificationDeliveries},
 		{"cleanup old annotations", srv.cleanUpOldAnnotations},
 		{"expire old user invites", srv.expireOldUserInvites},
 		{"delete stale short URLs", srv.deleteStaleShortURLs},
@@ -200,6 +214,18 @@ func (srv *CleanUpService) deleteExpiredSnapshots(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteExcessScheduledSnapshots(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if !srv.Cfg.SnapshotEnabled {
+		return
+	}
+	if rowsAffected, err := srv.snapshotScheduleService.DeleteExcessSnapshots(ctx); err != nil {
+		logger.Error("Failed to delete excess scheduled snapshots", "error", err.Error())
+	} else {
+		logger.Debug("Deleted excess scheduled snapshots", "rows affected", rowsAffected)
+	}
+}
+
 func (srv *CleanUpService) deleteExpiredDashboardVersions(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	cmd := dashver.DeleteExpiredVersionsCommand{}
@@ -222,6 +248,30 @@ func (srv *CleanUpService) deleteExpiredImages(ctx context.Context) {
 	}
 }
 
+func (srv *CleanUpService) deleteExpiredStateHistory(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if !srv.Cfg.UnifiedAlerting.IsEnabled() {
+		return
+	}
+	if rowsAffected, err := srv.deleteExpiredStateHistoryService.DeleteExpired(ctx); err != nil {
+		logger.Error("Failed to delete expired alert state history", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired alert state history", "rows affected", rowsAffected)
+	}
+}
+
+func (srv *CleanUpService) deleteExpiredNotificationDeliveries(ctx context.Context) {
+	logger := srv.log.FromContext(ctx)
+	if !srv.Cfg.UnifiedAlerting.IsEnabled() {
+		return
+	}
+	if rowsAffected, err := srv.deleteExpiredDeliveriesService.DeleteExpired(ctx); err != nil {
+		logger.Error("Failed to delete expired notification deliveries", "error", err.Error())
+	} else {
+		logger.Debug("Deleted expired notification deliveries", "rows affected", rowsAffected)
+	}
+}
+
 func (srv *CleanUpService) expireOldUserInvites(ctx context.Context) {
 	logger := srv.log.FromContext(ctx)
 	maxInviteLifetime := srv.Cfg.UserInviteMaxLifetime
@@ -268,6 +268,8 @@ type DashboardProvisioning struct {
 	ExternalID  string `xorm:"external_id"`
 	CheckSum    string
 	Updated     int64
+	// CommitSHA is the commit of the git repository the dashboard was provisioned from, if any.
+	CommitSHA string `xorm:"commit_sha"`
 }
 
 type DeleteDashboardCommand struct {
@@ -63,6 +63,7 @@ func (d *DashboardSnapshotStore) CreateDashboardSnapshot(ctx context.Context, cm
 			External:           cmd.External,
 			ExternalURL:        cmd.ExternalURL,
 			ExternalDeleteURL:  cmd.ExternalDeleteURL,
+			ScheduleID:         cmd.ScheduleID,
 			Dashboard:          simplejson.New(),
 			DashboardEncrypted: cmd.DashboardEncrypted,
 			Expires:            expires,
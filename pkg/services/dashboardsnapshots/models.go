@@ -18,6 +18,7 @@ type DashboardSnapshot struct {
 	External          bool
 	ExternalURL       string `xorm:"external_url"`
 	ExternalDeleteURL string `xorm:"external_delete_url"`
+	ScheduleID        int64  `xorm:"schedule_id"`
 
 	Expires time.Time
 	Created time.Time
@@ -75,6 +76,8 @@ type CreateDashboardSnapshotCommand struct {
 
 	OrgID  int64 `json:"-"`
 	UserID int64 `json:"-"`
+	// ScheduleID is the ID of the schedule that took the snapshot, if any.
+	ScheduleID int64 `json:"-"`
 
 	DashboardEncrypted []byte `json:"-"`
 }
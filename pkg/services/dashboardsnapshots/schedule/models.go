@@ -0,0 +1,61 @@
+package schedule
+
+import (
+	"time"
+
+	"github.com/grafana/grafana/pkg/util/errutil"
+)
+
+var (
+	ErrScheduleNotFound = errutil.NewBase(errutil.StatusNotFound, "snapshotschedule.not-found", errutil.WithPublicMessage("Snapshot schedule not found"))
+	ErrScheduleInvalid  = errutil.NewBase(errutil.StatusBadRequest, "snapshotschedule.invalid")
+	ErrExternalDisabled = errutil.NewBase(errutil.StatusForbidden, "snapshotschedule.external-disabled", errutil.WithPublicMessage("External dashboard creation is disabled"))
+)
+
+// SnapshotSchedule periodically takes a snapshot of a dashboard.
+type SnapshotSchedule struct {
+	ID           int64  `json:"id" xorm:"pk autoincr 'id'"`
+	UID          string `json:"uid" xorm:"uid"`
+	OrgID        int64  `json:"orgId" xorm:"org_id"`
+	DashboardUID string `json:"dashboardUid" xorm:"dashboard_uid"`
+	Name         string `json:"name"`
+	// Schedule is a cron expression, evaluated in UTC unless it starts with CRON_TZ=<location>.
+	Schedule string `json:"schedule"`
+	// Expires is the number of seconds the snapshots are kept for. 0 means the snapshots never expire.
+	Expires int64 `json:"expires"`
+	// Retention is the number of snapshots that are kept. 0 means all snapshots are kept until they expire.
+	Retention int64 `json:"retention"`
+	// ExternalURL is the URL of the external snapshot server the snapshots are stored on. The snapshots are stored
+	// locally if it is empty.
+	ExternalURL string `json:"externalUrl" xorm:"external_url"`
+	UserID      int64  `json:"userId" xorm:"user_id"`
+
+	NextRun time.Time `json:"nextRun"`
+	Created time.Time `json:"created"`
+	Updated time.Time `json:"updated"`
+}
+
+// TableName returns the name of the table of snapshot schedules.
+func (s SnapshotSchedule) TableName() string {
+	return "dashboard_snapshot_schedule"
+}
+
+type CreateSnapshotScheduleCommand struct {
+	// The UID of the dashboard to take snapshots of.
+	// required:true
+	DashboardUID string `json:"dashboardUid"`
+	// Name of the snapshots. Defaults to the title of the dashboard.
+	Name string `json:"name"`
+	// Cron expression of when to take the snapshots.
+	// required:true
+	Schedule string `json:"schedule"`
+	// When the snapshots should expire in seconds. Default is never to expire.
+	Expires int64 `json:"expires"`
+	// Number of snapshots to keep. Default is to keep all of them until they expire.
+	Retention int64 `json:"retention"`
+	// URL of the external snapshot server to store the snapshots on. Default is to store them locally.
+	ExternalURL string `json:"externalUrl"`
+
+	OrgID  int64 `json:"-"`
+	UserID int64 `json:"-"`
+}
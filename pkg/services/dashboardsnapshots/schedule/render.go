@@ -0,0 +1,264 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/tsdb/grafanads"
+	"github.com/grafana/grafana/pkg/tsdb/legacydata"
+)
+
+const (
+	defaultMaxDataPoints = 1000
+	// snapshotTimeLayout is the layout of the absolute time range of the snapshots, the same as the one of the
+	// snapshots shared from the dashboard.
+	snapshotTimeLayout = "2006-01-02T15:04:05.000Z"
+)
+
+// renderSnapshot returns a copy of the dashboard to store as a snapshot. Like the snapshots shared from the dashboard,
+// the results of the queries of the panels are embedded in the panels, and the queries themselves are removed. The
+// queries are run as the user, who must be allowed to query all the data sources of the panels.
+func (s *Service) renderSnapshot(ctx context.Context, dash *dashboards.Dashboard, usr *user.SignedInUser, name string, now time.Time) (*simplejson.Json, error) {
+	b, err := dash.Data.Encode()
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := simplejson.NewJson(b)
+	if err != nil {
+		return nil, err
+	}
+
+	panels := snapshot.Get("panels").MustArray()
+	if err := s.checkCanQuery(ctx, usr, panels); err != nil {
+		return nil, err
+	}
+
+	from, to := s.timeRange(snapshot, now)
+	s.renderPanels(ctx, dash, usr, panels, from, to)
+
+	snapshot.Set("title", name)
+	snapshot.Set("links", []interface{}{})
+	snapshot.Set("time", map[string]interface{}{
+		"from": from.UTC().Format(snapshotTimeLayout),
+		"to":   to.UTC().Format(snapshotTimeLayout),
+	})
+	snapshot.SetPath([]string{"snapshot", "originalUrl"}, "/d/"+dash.UID)
+	scrubAnnotations(snapshot)
+	scrubVariables(snapshot)
+	return snapshot, nil
+}
+
+// timeRange returns the absolute time range of the dashboard at the time now.
+func (s *Service) timeRange(dashboard *simplejson.Json, now time.Time) (time.Time, time.Time) {
+	tr := legacydata.NewDataTimeRange(
+		dashboard.GetPath("time", "from").MustString("now-6h"),
+		dashboard.GetPath("time", "to").MustString("now"),
+	)
+	tr.Now = now
+	loc, err := time.LoadLocation(dashboard.Get("timezone").MustString())
+	if err != nil {
+		loc = time.UTC
+	}
+
+	from, err := tr.ParseFrom(legacydata.WithLocation(loc))
+	if err != nil {
+		s.log.Warn("Failed to parse the start of the time range of the dashboard", "error", err)
+		from = now.Add(-6 * time.Hour)
+	}
+	to, err := tr.ParseTo(legacydata.WithLocation(loc))
+	if err != nil {
+		s.log.Warn("Failed to parse the end of the time range of the dashboard", "error", err)
+		to = now
+	}
+	return from, to
+}
+
+// renderPanels queries the data of the panels, including the ones in collapsed rows, and replaces their queries with
+// the results.
+func (s *Service) renderPanels(ctx context.Context, dash *dashboards.Dashboard, usr *user.SignedInUser, panels []interface{}, from, to time.Time) {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			s.renderPanels(ctx, dash, usr, panel.Get("panels").MustArray(), from, to)
+			continue
+		}
+
+		frames, err := s.queryPanel(ctx, dash, usr, panel, from, to)
+		if err != nil {
+			s.log.Warn("Failed to query the data of a panel", "dashboardUID", dash.UID, "panelID", panel.Get("id").MustInt64(), "error", err)
+		}
+		if frames != nil {
+			panel.Set("snapshotData", frames)
+		}
+
+		panel.Set("targets", []interface{}{})
+		panel.Set("links", []interface{}{})
+		panel.Set("datasource", nil)
+	}
+}
+
+// queryPanel returns the results of the queries of the panel, or nil if the panel has no queries.
+func (s *Service) queryPanel(ctx context.Context, dash *dashboards.Dashboard, usr *user.SignedInUser, panel *simplejson.Json, from, to time.Time) (data.Frames, error) {
+	maxDataPoints := panel.Get("maxDataPoints").MustInt64(defaultMaxDataPoints)
+	if maxDataPoints <= 0 {
+		maxDataPoints = defaultMaxDataPoints
+	}
+	intervalMs := to.Sub(from).Milliseconds() / maxDataPoints
+	if intervalMs < 1 {
+		intervalMs = 1
+	}
+
+	queries := panelQueries(panel)
+	if len(queries) == 0 {
+		return nil, nil
+	}
+	for _, query := range queries {
+		query.Set("intervalMs", intervalMs)
+		query.Set("maxDataPoints", maxDataPoints)
+	}
+
+	res, err := s.queryService.QueryData(ctx, usr, false, dtos.MetricRequest{
+		From:    strconv.FormatInt(from.UnixMilli(), 10),
+		To:      strconv.FormatInt(to.UnixMilli(), 10),
+		Queries: queries,
+	})
+	if err != nil {
+		return data.Frames{}, err
+	}
+
+	frames := data.Frames{}
+	for _, query := range queries {
+		refID := query.Get("refId").MustString("A")
+		resp, ok := res.Responses[refID]
+		if !ok {
+			continue
+		}
+		if resp.Error != nil {
+			s.log.Warn("Query of a panel failed", "dashboardUID", dash.UID, "panelID", panel.Get("id").MustInt64(), "refId", refID, "error", resp.Error)
+		}
+		for _, frame := range resp.Frames {
+			if frame.RefID == "" {
+				frame.RefID = refID
+			}
+			// The query is removed from the snapshot, so it should not be shown in the query inspector either.
+			if frame.Meta != nil {
+				frame.Meta.ExecutedQueryString = ""
+			}
+			frames = append(frames, frame)
+		}
+	}
+	return frames, nil
+}
+
+// panelQueries returns the queries of the panel that are not hidden. Queries without a data source UID are not
+// returned, as the permission to query their data source cannot be checked.
+func panelQueries(panel *simplejson.Json) []*simplejson.Json {
+	var queries []*simplejson.Json
+	for _, targetObj := range panel.Get("targets").MustArray() {
+		query := simplejson.NewFromAny(targetObj)
+		if query.Get("hide").MustBool() {
+			continue
+		}
+		// if query target has no datasource, set it to have the datasource on the panel
+		if _, ok := query.CheckGet("datasource"); !ok {
+			query.Set("datasource", panel.Get("datasource").Interface())
+		}
+		if datasourceUID(query) == "" {
+			continue
+		}
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+// datasourceUID returns the UID of the data source of the query.
+func datasourceUID(query *simplejson.Json) string {
+	uid := query.Get("datasource").Get("uid").MustString()
+	// before 8.3 special types could be sent as datasource (expr)
+	if uid == "" {
+		uid = query.Get("datasource").MustString()
+	}
+	return uid
+}
+
+// checkCanQuery returns an error if the user is not allowed to query one of the data sources of the panels, including
+// the ones in collapsed rows.
+func (s *Service) checkCanQuery(ctx context.Context, usr *user.SignedInUser, panels []interface{}) error {
+	for _, panelObj := range panels {
+		panel := simplejson.NewFromAny(panelObj)
+		if panel.Get("type").MustString() == "row" {
+			if err := s.checkCanQuery(ctx, usr, panel.Get("panels").MustArray()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, query := range panelQueries(panel) {
+			uid := datasourceUID(query)
+			// Expressions and the built-in Grafana data source do not need permissions.
+			if expr.NodeTypeFromDatasourceUID(uid) != expr.TypeDatasourceNode || uid == grafanads.DatasourceUID {
+				continue
+			}
+			evaluator := accesscontrol.EvalPermission(datasources.ActionQuery, datasources.ScopeProvider.GetResourceScopeUID(uid))
+			ok, err := s.accessControl.Evaluate(ctx, usr, evaluator)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("user %d cannot query data source %s", usr.UserID, uid)
+			}
+		}
+	}
+	return nil
+}
+
+// scrubAnnotations removes the queries of the annotations, and the annotations that are not enabled.
+func scrubAnnotations(dashboard *simplejson.Json) {
+	annotations := []interface{}{}
+	for _, annotationObj := range dashboard.GetPath("annotations", "list").MustArray() {
+		annotation := simplejson.NewFromAny(annotationObj)
+		if !annotation.Get("enable").MustBool() {
+			continue
+		}
+		scrubbed := map[string]interface{}{}
+		for _, key := range []string{"name", "enable", "iconColor", "type", "builtIn", "hide"} {
+			if value, ok := annotation.CheckGet(key); ok {
+				scrubbed[key] = value.Interface()
+			}
+		}
+		annotations = append(annotations, scrubbed)
+	}
+	dashboard.SetPath([]string{"annotations", "list"}, annotations)
+}
+
+// scrubVariables removes the queries of the template variables, and only keeps their current values.
+func scrubVariables(dashboard *simplejson.Json) {
+	for _, variableObj := range dashboard.GetPath("templating", "list").MustArray() {
+		variable := simplejson.NewFromAny(variableObj)
+		if _, ok := variable.CheckGet("query"); ok {
+			variable.Set("query", "")
+		}
+		if _, ok := variable.CheckGet("options"); ok {
+			options := []interface{}{}
+			if current := variable.Get("current").MustMap(); len(current) > 0 {
+				options = append(options, current)
+			}
+			variable.Set("options", options)
+		}
+		if _, ok := variable.CheckGet("refresh"); ok {
+			// never refresh
+			variable.Set("refresh", 0)
+		}
+	}
+}
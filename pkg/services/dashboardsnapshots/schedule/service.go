@@ -0,0 +1,349 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/query"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	// runInterval is how often the schedules are checked for snapshots to take.
+	runInterval = time.Minute
+	// runTimeout is how long taking a snapshot can take at most.
+	runTimeout = 5 * time.Minute
+	// lockTimeout is how long the lock of a schedule is held at most, in case the instance taking the snapshot stops.
+	lockTimeout = 10 * time.Minute
+)
+
+// Service takes snapshots of dashboards on a schedule. In high availability setups, each snapshot is taken by a
+// single instance. The snapshots are taken with the permissions the user who created the schedule has at the time.
+type Service struct {
+	cfg                  *setting.Cfg
+	log                  log.Logger
+	store                store
+	serverLock           *serverlock.ServerLockService
+	dashboardService     dashboards.DashboardService
+	snapshotService      dashboardsnapshots.Service
+	queryService         query.Service
+	userService          user.Service
+	accesscontrolService accesscontrol.Service
+	accessControl        accesscontrol.AccessControl
+	now                  func() time.Time
+}
+
+func ProvideService(cfg *setting.Cfg, database db.DB, serverLock *serverlock.ServerLockService,
+	dashboardService dashboards.DashboardService, snapshotService dashboardsnapshots.Service, queryService query.Service,
+	userService user.Service, accesscontrolService accesscontrol.Service, accessControl accesscontrol.AccessControl) *Service {
+	return &Service{
+		cfg:                  cfg,
+		log:                  log.New("dashboardsnapshots.schedule"),
+		store:                &sqlStore{db: database},
+		serverLock:           serverLock,
+		dashboardService:     dashboardService,
+		snapshotService:      snapshotService,
+		queryService:         queryService,
+		userService:          userService,
+		accesscontrolService: accesscontrolService,
+		accessControl:        accessControl,
+		now:                  time.Now,
+	}
+}
+
+func (s *Service) IsDisabled() bool {
+	return !s.cfg.SnapshotEnabled
+}
+
+// Create validates and saves a new schedule. The first snapshot is taken at the next time of the schedule.
+func (s *Service) Create(ctx context.Context, cmd *CreateSnapshotScheduleCommand) (*SnapshotSchedule, error) {
+	if cmd.DashboardUID == "" {
+		return nil, ErrScheduleInvalid.Errorf("dashboard UID is required")
+	}
+	sched, err := cron.ParseStandard(cmd.Schedule)
+	if err != nil {
+		return nil, ErrScheduleInvalid.Errorf("invalid cron expression: %w", err)
+	}
+	if cmd.Expires < 0 {
+		return nil, ErrScheduleInvalid.Errorf("expires must not be negative")
+	}
+	if cmd.Retention < 0 {
+		return nil, ErrScheduleInvalid.Errorf("retention must not be negative")
+	}
+	if cmd.ExternalURL != "" {
+		if !s.cfg.ExternalEnabled {
+			return nil, ErrExternalDisabled.Errorf("external snapshots are disabled")
+		}
+		// Only the configured external snapshot server can be used, so that the snapshots are not sent anywhere else.
+		if cmd.ExternalURL != s.cfg.ExternalSnapshotUrl {
+			return nil, ErrScheduleInvalid.Errorf("external URL must be the external snapshot URL of the server")
+		}
+	}
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: cmd.DashboardUID, OrgID: cmd.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	name := cmd.Name
+	if name == "" {
+		name = dash.Title
+	}
+
+	now := s.now()
+	schedule := &SnapshotSchedule{
+		UID:          util.GenerateShortUID(),
+		OrgID:        cmd.OrgID,
+		DashboardUID: cmd.DashboardUID,
+		Name:         name,
+		Schedule:     cmd.Schedule,
+		Expires:      cmd.Expires,
+		Retention:    cmd.Retention,
+		ExternalURL:  cmd.ExternalURL,
+		UserID:       cmd.UserID,
+		NextRun:      sched.Next(now.UTC()),
+		Created:      now,
+		Updated:      now,
+	}
+	if err := s.store.Insert(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *Service) Get(ctx context.Context, orgID int64, uid string) (*SnapshotSchedule, error) {
+	return s.store.Get(ctx, orgID, uid)
+}
+
+func (s *Service) List(ctx context.Context, orgID int64) ([]*SnapshotSchedule, error) {
+	return s.store.List(ctx, orgID)
+}
+
+// Delete deletes the schedule. The snapshots taken by the schedule are kept until they expire.
+func (s *Service) Delete(ctx context.Context, orgID int64, uid string) error {
+	return s.store.Delete(ctx, orgID, uid)
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(runInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runDue(ctx)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// runDue takes the snapshots of the schedules that are due.
+func (s *Service) runDue(ctx context.Context) {
+	schedules, err := s.store.ListAll(ctx)
+	if err != nil {
+		s.log.Error("Failed to get snapshot schedules", "error", err)
+		return
+	}
+
+	now := s.now()
+	for _, schedule := range schedules {
+		if schedule.NextRun.After(now) {
+			continue
+		}
+
+		orgID, uid := schedule.OrgID, schedule.UID
+		lockName := "snapshot schedule " + strconv.FormatInt(orgID, 10) + "/" + uid
+		err := s.serverLock.LockExecuteAndRelease(ctx, lockName, lockTimeout, func(ctx context.Context) {
+			s.runIfDue(ctx, orgID, uid, now)
+		})
+		var lockErr *serverlock.ServerLockExistsError
+		if errors.As(err, &lockErr) {
+			s.log.Debug("Snapshot schedule is run by another instance", "orgID", orgID, "uid", uid)
+		} else if err != nil {
+			s.log.Error("Failed to lock snapshot schedule", "orgID", orgID, "uid", uid, "error", err)
+		}
+	}
+}
+
+// runIfDue takes the snapshot of the schedule, unless it was taken by another instance in the meantime.
+func (s *Service) runIfDue(ctx context.Context, orgID int64, uid string, now time.Time) {
+	logger := s.log.New("orgID", orgID, "uid", uid)
+
+	schedule, err := s.store.Get(ctx, orgID, uid)
+	if err != nil {
+		if !errors.Is(err, ErrScheduleNotFound) {
+			logger.Error("Failed to get snapshot schedule", "error", err)
+		}
+		return
+	}
+	if schedule.NextRun.After(now) {
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, runTimeout)
+	defer cancel()
+
+	if snapshot, err := s.takeSnapshot(runCtx, schedule, now); err != nil {
+		logger.Error("Failed to take scheduled snapshot", "dashboardUID", schedule.DashboardUID, "error", err)
+	} else {
+		logger.Info("Took scheduled snapshot", "dashboardUID", schedule.DashboardUID, "key", snapshot.Key)
+	}
+
+	sched, err := cron.ParseStandard(schedule.Schedule)
+	if err != nil {
+		logger.Error("Failed to parse snapshot schedule", "error", err)
+		return
+	}
+	if err := s.store.UpdateNextRun(ctx, schedule.ID, sched.Next(now.UTC())); err != nil {
+		logger.Error("Failed to update the next run of snapshot schedule", "error", err)
+	}
+}
+
+// takeSnapshot renders the dashboard of the schedule and stores it as a snapshot, either locally or on the external
+// snapshot server of the schedule.
+func (s *Service) takeSnapshot(ctx context.Context, schedule *SnapshotSchedule, now time.Time) (*dashboardsnapshots.DashboardSnapshot, error) {
+	if schedule.ExternalURL != "" {
+		if !s.cfg.ExternalEnabled {
+			return nil, errors.New("external snapshots are disabled")
+		}
+		if schedule.ExternalURL != s.cfg.ExternalSnapshotUrl {
+			return nil, errors.New("external URL is not the external snapshot URL of the server")
+		}
+	}
+
+	dash, err := s.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{UID: schedule.DashboardUID, OrgID: schedule.OrgID})
+	if err != nil {
+		return nil, err
+	}
+
+	usr, err := s.scheduleUser(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+	g, err := guardian.NewByDashboard(ctx, dash, schedule.OrgID, usr)
+	if err != nil {
+		return nil, err
+	}
+	canView, err := g.CanView()
+	if err != nil {
+		return nil, err
+	}
+	if !canView {
+		return nil, fmt.Errorf("user %d cannot view the dashboard", schedule.UserID)
+	}
+
+	snapshot, err := s.renderSnapshot(ctx, dash, usr, schedule.Name, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render snapshot: %w", err)
+	}
+
+	cmd := &dashboardsnapshots.CreateDashboardSnapshotCommand{
+		Dashboard:  snapshot,
+		Name:       schedule.Name,
+		Expires:    schedule.Expires,
+		OrgID:      schedule.OrgID,
+		UserID:     schedule.UserID,
+		ScheduleID: schedule.ID,
+	}
+
+	if schedule.ExternalURL != "" {
+		resp, err := dashboardsnapshots.CreateExternalSnapshot(cmd, schedule.ExternalURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create external snapshot: %w", err)
+		}
+		cmd.External = true
+		cmd.Key = resp.Key
+		cmd.DeleteKey = resp.DeleteKey
+		cmd.ExternalURL = resp.Url
+		cmd.ExternalDeleteURL = resp.DeleteUrl
+		cmd.Dashboard = simplejson.New()
+	} else {
+		if cmd.Key, err = util.GetRandomString(32); err != nil {
+			return nil, err
+		}
+		if cmd.DeleteKey, err = util.GetRandomString(32); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.snapshotService.CreateDashboardSnapshot(ctx, cmd)
+}
+
+// scheduleUser returns the user who created the schedule, with their current permissions in the organization of the
+// schedule.
+func (s *Service) scheduleUser(ctx context.Context, schedule *SnapshotSchedule) (*user.SignedInUser, error) {
+	usr, err := s.userService.GetSignedInUserWithCacheCtx(ctx, &user.GetSignedInUserQuery{UserID: schedule.UserID, OrgID: schedule.OrgID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %d: %w", schedule.UserID, err)
+	}
+	if usr.IsDisabled {
+		return nil, fmt.Errorf("user %d is disabled", schedule.UserID)
+	}
+	if usr.OrgID != schedule.OrgID {
+		return nil, fmt.Errorf("user %d is not a member of the organization", schedule.UserID)
+	}
+
+	permissions, err := s.accesscontrolService.GetUserPermissions(ctx, usr, accesscontrol.Options{ReloadCache: false})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the permissions of user %d: %w", schedule.UserID, err)
+	}
+	usr.Permissions = map[int64]map[string][]string{usr.OrgID: accesscontrol.GroupScopesByAction(permissions)}
+	return usr, nil
+}
+
+// DeleteExcessSnapshots deletes the oldest snapshots of the schedules that have more snapshots than they retain.
+func (s *Service) DeleteExcessSnapshots(ctx context.Context) (int64, error) {
+	schedules, err := s.store.ListAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	var errs []error
+	for _, schedule := range schedules {
+		if schedule.Retention <= 0 {
+			continue
+		}
+
+		snapshots, err := s.store.ListSnapshots(ctx, schedule.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if int64(len(snapshots)) <= schedule.Retention {
+			continue
+		}
+
+		for _, snapshot := range snapshots[schedule.Retention:] {
+			if snapshot.External {
+				if err := dashboardsnapshots.DeleteExternalSnapshot(snapshot.ExternalDeleteURL); err != nil {
+					errs = append(errs, fmt.Errorf("failed to delete external snapshot %d: %w", snapshot.ID, err))
+					continue
+				}
+			}
+			if err := s.snapshotService.DeleteDashboardSnapshot(ctx, &dashboardsnapshots.DeleteDashboardSnapshotCommand{DeleteKey: snapshot.DeleteKey}); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			deleted++
+		}
+	}
+
+	return deleted, errors.Join(errs...)
+}
@@ -0,0 +1,342 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/dtos"
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/serverlock"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+	dashsnapdb "github.com/grafana/grafana/pkg/services/dashboardsnapshots/database"
+	dashsnapsvc "github.com/grafana/grafana/pkg/services/dashboardsnapshots/service"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/guardian"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/services/user/usertest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type fakeQueryService struct {
+	requests []dtos.MetricRequest
+	users    []*user.SignedInUser
+}
+
+func (f *fakeQueryService) Run(context.Context) error {
+	return nil
+}
+
+func (f *fakeQueryService) QueryData(_ context.Context, usr *user.SignedInUser, _ bool, reqDTO dtos.MetricRequest) (*backend.QueryDataResponse, error) {
+	f.requests = append(f.requests, reqDTO)
+	f.users = append(f.users, usr)
+	res := backend.NewQueryDataResponse()
+	for _, q := range reqDTO.Queries {
+		refID := q.Get("refId").MustString()
+		res.Responses[refID] = backend.DataResponse{
+			Frames: data.Frames{data.NewFrame("series", data.NewField("value", nil, []float64{1, 2}))},
+		}
+	}
+	return res, nil
+}
+
+func TestIntegrationSnapshotSchedules(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	now := time.Date(2023, 5, 1, 10, 30, 0, 0, time.UTC)
+	dash := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{
+		"id":    1,
+		"uid":   "dash",
+		"title": "Audited",
+		"time":  map[string]interface{}{"from": "now-1h", "to": "now"},
+		"links": []interface{}{map[string]interface{}{"title": "link"}},
+		"panels": []interface{}{
+			map[string]interface{}{
+				"id":         1,
+				"type":       "timeseries",
+				"datasource": map[string]interface{}{"type": "prometheus", "uid": "prom"},
+				"targets": []interface{}{
+					map[string]interface{}{"refId": "A", "expr": "up"},
+					map[string]interface{}{"refId": "B", "expr": "down", "hide": true},
+				},
+			},
+			map[string]interface{}{
+				"id":        2,
+				"type":      "row",
+				"collapsed": true,
+				"panels": []interface{}{
+					map[string]interface{}{
+						"id":      3,
+						"type":    "stat",
+						"targets": []interface{}{map[string]interface{}{"refId": "A", "datasource": map[string]interface{}{"uid": "loki"}}},
+					},
+				},
+			},
+		},
+		"templating": map[string]interface{}{
+			"list": []interface{}{
+				map[string]interface{}{
+					"name":    "job",
+					"query":   "label_values(job)",
+					"refresh": 1,
+					"current": map[string]interface{}{"text": "api", "value": "api"},
+					"options": []interface{}{map[string]interface{}{"text": "api"}, map[string]interface{}{"text": "db"}},
+				},
+			},
+		},
+	}))
+	dash.OrgID = 1
+
+	queryPermissions := []accesscontrol.Permission{
+		{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("prom")},
+		{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID("loki")},
+	}
+
+	setup := func(t *testing.T, permissions ...accesscontrol.Permission) (*Service, *fakeQueryService) {
+		sqlStore := db.InitTestDB(t)
+		cfg := setting.NewCfg()
+		cfg.SnapshotEnabled = true
+		if permissions == nil {
+			permissions = queryPermissions
+		}
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: true})
+		userService := &usertest.FakeUserService{GetSignedInUserFn: func(_ context.Context, q *user.GetSignedInUserQuery) (*user.SignedInUser, error) {
+			return &user.SignedInUser{UserID: q.UserID, OrgID: q.OrgID, OrgRole: org.RoleEditor}, nil
+		}}
+
+		dashboardService := dashboards.NewFakeDashboardService(t)
+		dashboardService.On("GetDashboard", mock.Anything, mock.MatchedBy(func(q *dashboards.GetDashboardQuery) bool {
+			return q.UID == "dash"
+		})).Return(dash, nil).Maybe()
+		dashboardService.On("GetDashboard", mock.Anything, mock.Anything).Return(nil, dashboards.ErrDashboardNotFound).Maybe()
+
+		queryService := &fakeQueryService{}
+		s := ProvideService(cfg, sqlStore, serverlock.ProvideService(sqlStore, tracing.InitializeTracerForTest()),
+			dashboardService, dashsnapsvc.ProvideService(dashsnapdb.ProvideStore(sqlStore, cfg), fakes.NewFakeSecretsService()), queryService,
+			userService, &actest.FakeService{ExpectedPermissions: permissions}, acimpl.ProvideAccessControl(cfg))
+		s.now = func() time.Time { return now }
+		return s, queryService
+	}
+
+	t.Run("should validate schedules", func(t *testing.T) {
+		s, _ := setup(t)
+
+		testCases := []struct {
+			name string
+			cmd  CreateSnapshotScheduleCommand
+		}{
+			{name: "without dashboard", cmd: CreateSnapshotScheduleCommand{Schedule: "@daily"}},
+			{name: "with invalid schedule", cmd: CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "daily"}},
+			{name: "with negative retention", cmd: CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@daily", Retention: -1}},
+			{name: "with external snapshots disabled", cmd: CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@daily", ExternalURL: "https://snapshots.example.com"}},
+			{name: "with missing dashboard", cmd: CreateSnapshotScheduleCommand{DashboardUID: "missing", Schedule: "@daily"}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				tc.cmd.OrgID = 1
+				_, err := s.Create(context.Background(), &tc.cmd)
+				require.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("should only allow the external snapshot URL of the server", func(t *testing.T) {
+		s, _ := setup(t)
+		s.cfg.ExternalEnabled = true
+		s.cfg.ExternalSnapshotUrl = "https://snapshots.example.com"
+
+		_, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@daily", ExternalURL: "https://other.example.com", OrgID: 1})
+		require.Error(t, err)
+		_, err = s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@daily", ExternalURL: "https://snapshots.example.com", OrgID: 1})
+		require.NoError(t, err)
+	})
+
+	t.Run("should take snapshots when they are due", func(t *testing.T) {
+		s, queryService := setup(t)
+
+		schedule, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{
+			DashboardUID: "dash",
+			Schedule:     "0 * * * *",
+			Expires:      3600,
+			OrgID:        1,
+			UserID:       2,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "Audited", schedule.Name)
+		require.Equal(t, time.Date(2023, 5, 1, 11, 0, 0, 0, time.UTC), schedule.NextRun.UTC())
+
+		s.runDue(context.Background())
+		require.Empty(t, queryService.requests)
+
+		now = now.Add(time.Hour)
+		s.runDue(context.Background())
+		require.Len(t, queryService.requests, 2)
+
+		// The hidden query of the first panel is not run.
+		require.Len(t, queryService.requests[0].Queries, 1)
+		require.Equal(t, "1682937000000", queryService.requests[0].From)
+		require.Equal(t, "1682940600000", queryService.requests[0].To)
+		// The queries are run as the user who created the schedule.
+		require.Equal(t, int64(2), queryService.users[0].UserID)
+		require.Equal(t, int64(2), queryService.users[1].UserID)
+
+		schedule, err = s.Get(context.Background(), 1, schedule.UID)
+		require.NoError(t, err)
+		require.Equal(t, time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC), schedule.NextRun.UTC())
+
+		snapshots, err := s.store.ListSnapshots(context.Background(), schedule.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+
+		snapshot, err := s.snapshotService.GetDashboardSnapshot(context.Background(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: snapshots[0].Key})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), snapshot.UserID)
+		require.WithinDuration(t, time.Now().Add(time.Hour), snapshot.Expires, time.Minute)
+
+		model := snapshot.Dashboard
+		require.Equal(t, "2023-05-01T10:30:00.000Z", model.GetPath("time", "from").MustString())
+		require.Equal(t, "2023-05-01T11:30:00.000Z", model.GetPath("time", "to").MustString())
+		require.Equal(t, "/d/dash", model.GetPath("snapshot", "originalUrl").MustString())
+		require.Empty(t, model.Get("links").MustArray())
+
+		panel := model.Get("panels").GetIndex(0)
+		require.Empty(t, panel.Get("targets").MustArray())
+		require.Nil(t, panel.Get("datasource").Interface())
+		frames, err := panel.Get("snapshotData").Encode()
+		require.NoError(t, err)
+		var decoded []map[string]interface{}
+		require.NoError(t, json.Unmarshal(frames, &decoded))
+		require.Len(t, decoded, 1)
+		require.Equal(t, "A", decoded[0]["schema"].(map[string]interface{})["refId"])
+
+		nested := model.Get("panels").GetIndex(1).Get("panels").GetIndex(0)
+		require.Len(t, nested.Get("snapshotData").MustArray(), 1)
+
+		variable := model.GetPath("templating", "list").GetIndex(0)
+		require.Empty(t, variable.Get("query").MustString())
+		require.Len(t, variable.Get("options").MustArray(), 1)
+		require.Equal(t, 0, variable.Get("refresh").MustInt())
+
+		// The snapshot is not taken again until the next time of the schedule.
+		s.runDue(context.Background())
+		require.Len(t, queryService.requests, 2)
+	})
+
+	t.Run("should skip snapshots if the user cannot query a data source of the dashboard", func(t *testing.T) {
+		s, queryService := setup(t, queryPermissions[0])
+
+		schedule, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@hourly", OrgID: 1, UserID: 2})
+		require.NoError(t, err)
+		now = schedule.NextRun
+		s.runDue(context.Background())
+		require.Empty(t, queryService.requests)
+
+		snapshots, err := s.store.ListSnapshots(context.Background(), schedule.ID)
+		require.NoError(t, err)
+		require.Empty(t, snapshots)
+
+		// The schedule is run again at its next time.
+		schedule, err = s.Get(context.Background(), 1, schedule.UID)
+		require.NoError(t, err)
+		require.True(t, schedule.NextRun.After(now))
+	})
+
+	t.Run("should skip snapshots if the user cannot view the dashboard", func(t *testing.T) {
+		s, queryService := setup(t)
+		guardian.MockDashboardGuardian(&guardian.FakeDashboardGuardian{CanViewValue: false})
+
+		schedule, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@hourly", OrgID: 1, UserID: 2})
+		require.NoError(t, err)
+		_, err = s.takeSnapshot(context.Background(), schedule, now)
+		require.Error(t, err)
+		require.Empty(t, queryService.requests)
+	})
+
+	t.Run("should not take snapshots of schedules run by another instance", func(t *testing.T) {
+		s, queryService := setup(t)
+
+		schedule, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@hourly", OrgID: 1})
+		require.NoError(t, err)
+		now = schedule.NextRun
+
+		err = s.serverLock.LockExecuteAndRelease(context.Background(), "snapshot schedule 1/"+schedule.UID, lockTimeout, func(context.Context) {
+			s.runDue(context.Background())
+		})
+		require.NoError(t, err)
+		require.Empty(t, queryService.requests)
+	})
+
+	t.Run("should store snapshots on the external snapshot server", func(t *testing.T) {
+		s, _ := setup(t)
+		s.cfg.ExternalEnabled = true
+
+		var external map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/api/snapshots", r.URL.Path)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&external))
+			_, _ = w.Write([]byte(`{"key": "ext", "deleteKey": "ext-delete", "url": "https://snapshots.example.com/dashboard/snapshot/ext", "deleteUrl": "https://snapshots.example.com/api/snapshots-delete/ext-delete"}`))
+		}))
+		t.Cleanup(server.Close)
+		s.cfg.ExternalSnapshotUrl = server.URL
+
+		schedule, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@hourly", ExternalURL: server.URL, OrgID: 1})
+		require.NoError(t, err)
+		now = schedule.NextRun
+		s.runDue(context.Background())
+
+		require.Equal(t, "Audited", external["name"])
+		snapshot, err := s.snapshotService.GetDashboardSnapshot(context.Background(), &dashboardsnapshots.GetDashboardSnapshotQuery{Key: "ext"})
+		require.NoError(t, err)
+		require.True(t, snapshot.External)
+		require.Equal(t, "https://snapshots.example.com/dashboard/snapshot/ext", snapshot.ExternalURL)
+	})
+
+	t.Run("should delete the oldest snapshots beyond the retention", func(t *testing.T) {
+		s, _ := setup(t)
+
+		schedule, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@hourly", Retention: 2, OrgID: 1})
+		require.NoError(t, err)
+		other, err := s.Create(context.Background(), &CreateSnapshotScheduleCommand{DashboardUID: "dash", Schedule: "@hourly", Retention: 2, OrgID: 1})
+		require.NoError(t, err)
+
+		var keys []string
+		for i := 0; i < 4; i++ {
+			snapshot, err := s.takeSnapshot(context.Background(), schedule, now)
+			require.NoError(t, err)
+			keys = append(keys, snapshot.Key)
+		}
+		_, err = s.takeSnapshot(context.Background(), other, now)
+		require.NoError(t, err)
+
+		deleted, err := s.DeleteExcessSnapshots(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		snapshots, err := s.store.ListSnapshots(context.Background(), schedule.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		require.ElementsMatch(t, keys[2:], []string{snapshots[0].Key, snapshots[1].Key})
+
+		snapshots, err = s.store.ListSnapshots(context.Background(), other.ID)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+	})
+}
@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboardsnapshots"
+)
+
+type store interface {
+	Insert(ctx context.Context, schedule *SnapshotSchedule) error
+	Get(ctx context.Context, orgID int64, uid string) (*SnapshotSchedule, error)
+	List(ctx context.Context, orgID int64) ([]*SnapshotSchedule, error)
+	ListAll(ctx context.Context) ([]*SnapshotSchedule, error)
+	Delete(ctx context.Context, orgID int64, uid string) error
+	UpdateNextRun(ctx context.Context, id int64, nextRun time.Time) error
+	// ListSnapshots returns the snapshots taken by the schedule, newest first.
+	ListSnapshots(ctx context.Context, scheduleID int64) ([]*dashboardsnapshots.DashboardSnapshot, error)
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (s *sqlStore) Insert(ctx context.Context, schedule *SnapshotSchedule) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Insert(schedule)
+		return err
+	})
+}
+
+func (s *sqlStore) Get(ctx context.Context, orgID int64, uid string) (*SnapshotSchedule, error) {
+	var schedule SnapshotSchedule
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id=? AND uid=?", orgID, uid).Get(&schedule)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrScheduleNotFound.Errorf("snapshot schedule not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (s *sqlStore) List(ctx context.Context, orgID int64) ([]*SnapshotSchedule, error) {
+	schedules := make([]*SnapshotSchedule, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id=?", orgID).Asc("name").Find(&schedules)
+	})
+	return schedules, err
+}
+
+func (s *sqlStore) ListAll(ctx context.Context) ([]*SnapshotSchedule, error) {
+	schedules := make([]*SnapshotSchedule, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Find(&schedules)
+	})
+	return schedules, err
+}
+
+func (s *sqlStore) Delete(ctx context.Context, orgID int64, uid string) error {
+	return s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		affected, err := sess.Where("org_id=? AND uid=?", orgID, uid).Delete(&SnapshotSchedule{})
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrScheduleNotFound.Errorf("snapshot schedule not found")
+		}
+		return nil
+	})
+}
+
+func (s *sqlStore) UpdateNextRun(ctx context.Context, id int64, nextRun time.Time) error {
+	return s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.ID(id).Cols("next_run", "updated").Update(&SnapshotSchedule{NextRun: nextRun, Updated: time.Now()})
+		return err
+	})
+}
+
+func (s *sqlStore) ListSnapshots(ctx context.Context, scheduleID int64) ([]*dashboardsnapshots.DashboardSnapshot, error) {
+	snapshots := make([]*dashboardsnapshots.DashboardSnapshot, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("dashboard_snapshot").
+			Cols("id", "key", "delete_key", "org_id", "external", "external_delete_url", "schedule_id", "created").
+			Where("schedule_id=?", scheduleID).
+			Desc("created", "id").
+			Find(&snapshots)
+	})
+	return snapshots, err
+}
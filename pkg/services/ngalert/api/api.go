@@ -31,6 +31,7 @@ var timeNow = time.Now
 type ExternalAlertmanagerProvider interface {
 	AlertmanagersFor(orgID int64) []*url.URL
 	DroppedAlertmanagersFor(orgID int64) []*url.URL
+	AlertmanagerStatusesFor(orgID int64) []sender.AlertmanagerStatus
 }
 
 type Alertmanager interface {
@@ -51,8 +52,10 @@ type Alertmanager interface {
 
 	// Receivers
 	GetReceivers(ctx context.Context) []apimodels.Receiver
+	GetReceiverDeliveries(ctx context.Context, receiver string, limit int) ([]apimodels.NotificationDelivery, error)
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
 	TestTemplate(ctx context.Context, c apimodels.TestTemplatesConfigBodyParams) (*notifier.TestTemplatesResults, error)
+	PreviewTemplate(ctx context.Context, c apimodels.TemplatePreviewBodyParams) (*apimodels.TemplatePreviewResults, error)
 }
 
 type AlertingStore interface {
@@ -79,11 +82,13 @@ type API struct {
 	ContactPointService  *provisioning.ContactPointService
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
+	MaintenanceWindows   *provisioning.MaintenanceWindowService
 	AlertRules           *provisioning.AlertRuleService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
 	Historian            Historian
+	EvaluationCosts      EvaluationCostProvider
 
 	AppUrl *url.URL
 
@@ -103,7 +108,14 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 	api.RegisterAlertmanagerApiEndpoints(NewForkingAM(
 		api.DatasourceCache,
 		NewLotexAM(proxy, logger),
-		&AlertmanagerSrv{crypto: api.MultiOrgAlertmanager.Crypto, log: logger, ac: api.AccessControl, mam: api.MultiOrgAlertmanager},
+		&AlertmanagerSrv{
+			crypto:       api.MultiOrgAlertmanager.Crypto,
+			log:          logger,
+			ac:           api.AccessControl,
+			mam:          api.MultiOrgAlertmanager,
+			stateManager: api.StateManager,
+			appUrl:       api.AppUrl,
+		},
 	), m)
 	// Register endpoints for proxying to Prometheus-compatible backends.
 	api.RegisterPrometheusApiEndpoints(NewForkingProm(
@@ -124,6 +136,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:                logger,
 			cfg:                &api.Cfg.UnifiedAlerting,
 			ac:                 api.AccessControl,
+			adminConfigStore:   api.AdminConfigStore,
+			datasourceCache:    api.DatasourceCache,
 		},
 	), m)
 	api.RegisterTestingApiEndpoints(NewTestingApi(
@@ -135,6 +149,7 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			evaluator:       api.EvaluatorFactory,
 			cfg:             &api.Cfg.UnifiedAlerting,
 			backtesting:     backtesting.NewEngine(api.AppUrl, api.EvaluatorFactory),
+			ruleStore:       api.RuleStore,
 			featureManager:  api.FeatureManager,
 			appUrl:          api.AppUrl,
 		}), m)
@@ -153,13 +168,22 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		contactPointService: api.ContactPointService,
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
+		maintenanceWindows:  api.MaintenanceWindows,
 		alertRules:          api.AlertRules,
+		adminConfigStore:    api.AdminConfigStore,
+		datasourceCache:     api.DatasourceCache,
 	}), m)
 
 	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
 		logger: logger,
 		hist:   api.Historian,
 	}), m)
+
+	api.RegisterCostsApiEndpoints(NewEvaluationCostsApi(&EvaluationCostSrv{
+		logger: logger,
+		costs:  api.EvaluationCosts,
+		store:  api.RuleStore,
+	}), m)
 }
 
 func (api *API) Usage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
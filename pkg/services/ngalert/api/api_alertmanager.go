@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +19,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/util"
@@ -26,13 +28,17 @@ import (
 const (
 	defaultTestReceiversTimeout = 15 * time.Second
 	maxTestReceiversTimeout     = 30 * time.Second
+
+	defaultReceiverDeliveriesLimit = 100
 )
 
 type AlertmanagerSrv struct {
-	log    log.Logger
-	ac     accesscontrol.AccessControl
-	mam    *notifier.MultiOrgAlertmanager
-	crypto notifier.Crypto
+	log          log.Logger
+	ac           accesscontrol.AccessControl
+	mam          *notifier.MultiOrgAlertmanager
+	crypto       notifier.Crypto
+	stateManager state.AlertInstanceManager
+	appUrl       *url.URL
 }
 
 type UnknownReceiverError struct {
@@ -301,6 +307,28 @@ func (srv AlertmanagerSrv) RouteGetReceivers(c *contextmodel.ReqContext) respons
 	return response.JSON(http.StatusOK, rcvs)
 }
 
+func (srv AlertmanagerSrv) RouteGetReceiverDeliveries(c *contextmodel.ReqContext, name string) response.Response {
+	limit := defaultReceiverDeliveriesLimit
+	if l := c.Query("limit"); l != "" {
+		v, err := strconv.Atoi(l)
+		if err != nil || v <= 0 {
+			return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid limit %q: must be a positive integer", l), "")
+		}
+		limit = v
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	deliveries, err := am.GetReceiverDeliveries(c.Req.Context(), name, limit)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification deliveries")
+	}
+	return response.JSON(http.StatusOK, deliveries)
+}
+
 func (srv AlertmanagerSrv) RoutePostTestReceivers(c *contextmodel.ReqContext, body apimodels.TestReceiversConfigBodyParams) response.Response {
 	if err := srv.crypto.LoadSecureSettings(c.Req.Context(), c.OrgID, body.Receivers); err != nil {
 		var unknownReceiverError UnknownReceiverError
@@ -356,6 +384,39 @@ func (srv AlertmanagerSrv) RoutePostTestTemplates(c *contextmodel.ReqContext, bo
 	return response.JSON(http.StatusOK, newTestTemplateResult(res))
 }
 
+func (srv AlertmanagerSrv) RoutePostTemplatePreview(c *contextmodel.ReqContext, body apimodels.TemplatePreviewBodyParams) response.Response {
+	if body.RuleUID != "" {
+		if len(body.Alerts) > 0 {
+			return ErrResp(http.StatusBadRequest, errors.New("alerts and rule_uid cannot be used together"), "")
+		}
+		if !accesscontrol.HasAccess(srv.ac, c)(accesscontrol.EvalPermission(accesscontrol.ActionAlertingInstanceRead)) {
+			return ErrResp(http.StatusForbidden, errors.New("user is not authorized to read alert instances"), "")
+		}
+		states := srv.stateManager.GetStatesForRuleUID(c.OrgID, body.RuleUID)
+		if len(states) == 0 {
+			return ErrResp(http.StatusNotFound, fmt.Errorf("no alert instances found for rule %s", body.RuleUID), "")
+		}
+		for _, s := range states {
+			body.Alerts = append(body.Alerts, state.StateToPostableAlert(s, srv.appUrl))
+		}
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	res, err := am.PreviewTemplate(c.Req.Context(), body)
+	if err != nil {
+		if errors.Is(err, notifier.ErrUnknownIntegrationType) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return response.Error(http.StatusInternalServerError, "", err)
+	}
+
+	return response.JSON(http.StatusOK, res)
+}
+
 // contextWithTimeoutFromRequest returns a context with a deadline set from the
 // Request-Timeout header in the HTTP request. If the header is absent then the
 // context will use the default timeout. The timeout in the Request-Timeout
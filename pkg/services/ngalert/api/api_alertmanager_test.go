@@ -493,6 +493,100 @@ func TestRoutePostTestTemplates(t *testing.T) {
 	})
 }
 
+func TestRoutePostTemplatePreview(t *testing.T) {
+	sut := createSut(t)
+	aim := NewFakeAlertInstanceManager(t)
+	aim.GenerateAlertInstances(1, "rule-uid", 2)
+	sut.stateManager = aim
+
+	requestCtx := func(permissions ...string) *contextmodel.ReqContext {
+		rc := createRequestCtxInOrg(1)
+		rc.SignedInUser.Permissions = map[int64]map[string][]string{1: {}}
+		for _, p := range permissions {
+			rc.SignedInUser.Permissions[1][p] = []string{}
+		}
+		return rc
+	}
+
+	t.Run("assert 404 when no alertmanager found", func(t *testing.T) {
+		response := sut.RoutePostTemplatePreview(createRequestCtxInOrg(10), apimodels.TemplatePreviewBodyParams{})
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("assert 400 when alerts and rule UID are used together", func(t *testing.T) {
+		response := sut.RoutePostTemplatePreview(requestCtx(accesscontrol.ActionAlertingInstanceRead), apimodels.TemplatePreviewBodyParams{
+			Alerts:  []*amv2.PostableAlert{{}},
+			RuleUID: "rule-uid",
+		})
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("assert 400 for an unknown integration type", func(t *testing.T) {
+		response := sut.RoutePostTemplatePreview(requestCtx(), apimodels.TemplatePreviewBodyParams{
+			Integrations: []string{"unknown"},
+		})
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("assert 403 when the user cannot read alert instances of the rule", func(t *testing.T) {
+		response := sut.RoutePostTemplatePreview(requestCtx(), apimodels.TemplatePreviewBodyParams{RuleUID: "rule-uid"})
+		require.Equal(t, 403, response.Status())
+	})
+
+	t.Run("assert 404 when the rule has no alert instances", func(t *testing.T) {
+		response := sut.RoutePostTemplatePreview(requestCtx(accesscontrol.ActionAlertingInstanceRead), apimodels.TemplatePreviewBodyParams{RuleUID: "unknown"})
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("assert 200 with the alert instances of the rule", func(t *testing.T) {
+		response := sut.RoutePostTemplatePreview(requestCtx(accesscontrol.ActionAlertingInstanceRead), apimodels.TemplatePreviewBodyParams{
+			RuleUID:      "rule-uid",
+			Integrations: []string{"slack"},
+			Message:      `{{ len .Alerts }} alerts`,
+		})
+		require.Equal(t, 200, response.Status())
+		var res apimodels.TemplatePreviewResults
+		require.NoError(t, json.Unmarshal(response.Body(), &res))
+		require.Len(t, res.Previews, 1)
+		require.Equal(t, "2 alerts", res.Previews[0].Message)
+	})
+}
+
+func TestRouteGetReceiverDeliveries(t *testing.T) {
+	sut := createSut(t)
+	request := func(t *testing.T, orgID int64, limit string) *contextmodel.ReqContext {
+		req, err := http.NewRequest(http.MethodGet, "https://grafana.net", nil)
+		require.NoError(t, err)
+		if limit != "" {
+			q := req.URL.Query()
+			q.Add("limit", limit)
+			req.URL.RawQuery = q.Encode()
+		}
+		rc := createRequestCtxInOrg(orgID)
+		rc.Req = req
+		return rc
+	}
+
+	t.Run("assert 404 when no alertmanager found", func(t *testing.T) {
+		response := sut.RouteGetReceiverDeliveries(request(t, 10, ""), "grafana-default-email")
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("assert 400 when the limit is invalid", func(t *testing.T) {
+		response := sut.RouteGetReceiverDeliveries(request(t, 1, "-1"), "grafana-default-email")
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("assert 200 and empty slice when the contact point did not send notifications", func(t *testing.T) {
+		response := sut.RouteGetReceiverDeliveries(request(t, 1, "10"), "grafana-default-email")
+		require.Equal(t, 200, response.Status())
+
+		var deliveries []apimodels.NotificationDelivery
+		require.NoError(t, json.Unmarshal(response.Body(), &deliveries))
+		require.Empty(t, deliveries)
+	})
+}
+
 func TestSilenceCreate(t *testing.T) {
 	makeSilence := func(comment string, createdBy string,
 		startsAt, endsAt strfmt.DateTime, matchers amv2.Matchers) amv2.Silence {
@@ -634,10 +728,11 @@ func createSut(t *testing.T) AlertmanagerSrv {
 	mam := createMultiOrgAlertmanager(t)
 	log := log.NewNopLogger()
 	return AlertmanagerSrv{
-		mam:    mam,
-		crypto: mam.Crypto,
-		ac:     acimpl.ProvideAccessControl(setting.NewCfg()),
-		log:    log,
+		mam:          mam,
+		crypto:       mam.Crypto,
+		ac:           acimpl.ProvideAccessControl(setting.NewCfg()),
+		log:          log,
+		stateManager: NewFakeAlertInstanceManager(t),
 	}
 }
 
@@ -677,7 +772,7 @@ func createMultiOrgAlertmanager(t *testing.T) *notifier.MultiOrgAlertmanager {
 		}, // do not poll in tests.
 	}
 
-	mam, err := notifier.NewMultiOrgAlertmanager(cfg, configStore, &orgStore, kvStore, provStore, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
+	mam, err := notifier.NewMultiOrgAlertmanager(cfg, configStore, &orgStore, kvStore, provStore, nil, decryptFn, m.GetMultiOrgAlertmanagerMetrics(), nil, log.New("testlogger"), secretsService)
 	require.NoError(t, err)
 	err = mam.LoadAndSyncAlertmanagersForOrgs(context.Background())
 	require.NoError(t, err)
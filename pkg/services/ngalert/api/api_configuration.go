@@ -6,13 +6,12 @@ import (
 	"fmt"
 	"net/http"
 
-	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
-
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/org"
@@ -27,11 +26,24 @@ type ConfigSrv struct {
 }
 
 func (srv ConfigSrv) RouteGetAlertmanagers(c *contextmodel.ReqContext) response.Response {
-	urls := srv.alertmanagerProvider.AlertmanagersFor(c.OrgID)
+	statuses := srv.alertmanagerProvider.AlertmanagerStatusesFor(c.OrgID)
 	droppedURLs := srv.alertmanagerProvider.DroppedAlertmanagersFor(c.OrgID)
-	ams := v1.AlertManagersResult{Active: make([]v1.AlertManager, len(urls)), Dropped: make([]v1.AlertManager, len(droppedURLs))}
-	for i, url := range urls {
-		ams.Active[i].URL = url.String()
+	ams := apimodels.AlertmanagersResult{Active: make([]apimodels.AlertmanagerStatus, len(statuses)), Dropped: make([]apimodels.AlertmanagerStatus, len(droppedURLs))}
+	for i, s := range statuses {
+		ams.Active[i] = apimodels.AlertmanagerStatus{
+			URL:          s.URL.String(),
+			Health:       s.Health,
+			LastError:    s.LastError,
+			QueuedAlerts: s.QueuedAlerts,
+		}
+		if !s.LastErrorAt.IsZero() {
+			lastErrorAt := s.LastErrorAt
+			ams.Active[i].LastErrorAt = &lastErrorAt
+		}
+		if !s.LastSuccessAt.IsZero() {
+			lastSuccessAt := s.LastSuccessAt
+			ams.Active[i].LastSuccessAt = &lastSuccessAt
+		}
 	}
 	for i, url := range droppedURLs {
 		ams.Dropped[i].URL = url.String()
@@ -62,6 +74,12 @@ func (srv ConfigSrv) RouteGetNGalertConfig(c *contextmodel.ReqContext) response.
 	resp := apimodels.GettableNGalertConfig{
 		AlertmanagersChoice: apimodels.AlertmanagersChoice(cfg.SendAlertsTo.String()),
 	}
+	if len(cfg.LintRules) > 0 {
+		resp.LintRules = make(map[string]apimodels.LintRuleAction, len(cfg.LintRules))
+		for code, action := range cfg.LintRules {
+			resp.LintRules[code] = apimodels.LintRuleAction(action)
+		}
+	}
 	return response.JSON(http.StatusOK, resp)
 }
 
@@ -89,6 +107,27 @@ func (srv ConfigSrv) RoutePostNGalertConfig(c *contextmodel.ReqContext, body api
 		OrgID:        c.OrgID,
 	}
 
+	if body.LintRules != nil {
+		cfg.LintRules = make(map[string]ngmodels.LintRuleAction, len(body.LintRules))
+		for code, action := range body.LintRules {
+			cfg.LintRules[code] = ngmodels.LintRuleAction(action)
+		}
+		if err := lint.ValidateRules(cfg.LintRules); err != nil {
+			return response.Error(400, "Invalid lint rules specified", err)
+		}
+	} else {
+		// The lint rules are kept if they are not sent, so that clients that only know about the Alertmanagers choice do not reset them.
+		existing, err := srv.store.GetAdminConfiguration(c.OrgID)
+		if err != nil && !errors.Is(err, store.ErrNoAdminConfiguration) {
+			msg := "failed to fetch admin configuration from the database"
+			srv.log.Error(msg, "error", err)
+			return ErrResp(http.StatusInternalServerError, err, msg)
+		}
+		if existing != nil {
+			cfg.LintRules = existing.LintRules
+		}
+	}
+
 	cmd := store.UpdateAdminConfigurationCmd{AdminConfiguration: cfg}
 	if err := srv.store.UpdateAdminConfiguration(cmd); err != nil {
 		msg := "failed to save the admin configuration to the database"
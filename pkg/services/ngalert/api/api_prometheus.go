@@ -53,7 +53,7 @@ func (srv PrometheusSrv) RouteGetAlertStatuses(c *contextmodel.ReqContext) respo
 		startsAt := alertState.StartsAt
 		valString := ""
 
-		if alertState.State == eval.Alerting || alertState.State == eval.Pending {
+		if alertState.IsFiring() || alertState.State == eval.Pending {
 			valString = formatValues(alertState)
 		}
 
@@ -133,6 +133,8 @@ func getStatesFromRequest(r *http.Request) ([]eval.State, error) {
 			states = append(states, eval.Alerting)
 		case "pending":
 			states = append(states, eval.Pending)
+		case "recovering":
+			states = append(states, eval.Recovering)
 		case "nodata":
 			states = append(states, eval.NoData)
 		// nolint:goconst
@@ -312,11 +314,12 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 	ngmodels.RulesGroup(rules).SortByGroupIndex()
 	for _, rule := range rules {
 		alertingRule := apimodels.AlertingRule{
-			State:       "inactive",
-			Name:        rule.Title,
-			Query:       ruleToQuery(srv.log, rule),
-			Duration:    rule.For.Seconds(),
-			Annotations: rule.Annotations,
+			State:         "inactive",
+			Name:          rule.Title,
+			Query:         ruleToQuery(srv.log, rule),
+			Duration:      rule.For.Seconds(),
+			KeepFiringFor: rule.KeepFiringFor.Seconds(),
+			Annotations:   rule.Annotations,
 		}
 
 		newRule := apimodels.Rule{
@@ -333,7 +336,7 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 		for _, alertState := range states {
 			activeAt := alertState.StartsAt
 			valString := ""
-			if alertState.State == eval.Alerting || alertState.State == eval.Pending {
+			if alertState.IsFiring() || alertState.State == eval.Pending {
 				valString = formatValues(alertState)
 			}
 			stateKey := strings.ToLower(alertState.State.String())
@@ -365,7 +368,7 @@ func (srv PrometheusSrv) toRuleGroup(groupKey ngmodels.AlertRuleGroupKey, folder
 				if alertingRule.State == "inactive" {
 					alertingRule.State = "pending"
 				}
-			case eval.Alerting:
+			case eval.Alerting, eval.Recovering:
 				if alertingRule.ActiveAt == nil || alertingRule.ActiveAt.After(activeAt) {
 					alertingRule.ActiveAt = &activeAt
 				}
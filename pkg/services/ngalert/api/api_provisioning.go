@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -26,7 +27,10 @@ type ProvisioningSrv struct {
 	contactPointService ContactPointService
 	templates           TemplateService
 	muteTimings         MuteTimingService
+	maintenanceWindows  MaintenanceWindowService
 	alertRules          AlertRuleService
+	adminConfigStore    store.AdminConfigurationStore
+	datasourceCache     datasources.CacheService
 }
 
 type ContactPointService interface {
@@ -55,6 +59,14 @@ type MuteTimingService interface {
 	DeleteMuteTiming(ctx context.Context, name string, orgID int64) error
 }
 
+type MaintenanceWindowService interface {
+	GetMaintenanceWindows(ctx context.Context, orgID int64) ([]definitions.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (definitions.MaintenanceWindow, error)
+	CreateMaintenanceWindow(ctx context.Context, orgID int64, mw definitions.MaintenanceWindow, p alerting_models.Provenance) (definitions.MaintenanceWindow, error)
+	UpdateMaintenanceWindow(ctx context.Context, orgID int64, mw definitions.MaintenanceWindow, p alerting_models.Provenance) (definitions.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, p alerting_models.Provenance) error
+}
+
 type AlertRuleService interface {
 	GetAlertRules(ctx context.Context, orgID int64) ([]*alerting_models.AlertRule, error)
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
@@ -262,6 +274,14 @@ func (srv *ProvisioningSrv) RouteGetMuteTimings(c *contextmodel.ReqContext) resp
 	return response.JSON(http.StatusOK, timings)
 }
 
+func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *contextmodel.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return exportResponse(c, AlertingFileExportFromMuteTimings(c.OrgID, timings))
+}
+
 func (srv *ProvisioningSrv) RoutePostMuteTiming(c *contextmodel.ReqContext, mt definitions.MuteTimeInterval) response.Response {
 	mt.Provenance = determineProvenance(c)
 	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), mt, c.OrgID)
@@ -298,6 +318,62 @@ func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, na
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindows(c *contextmodel.ReqContext) response.Response {
+	windows, err := srv.maintenanceWindows.GetMaintenanceWindows(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, windows)
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindow(c *contextmodel.ReqContext, UID string) response.Response {
+	window, err := srv.maintenanceWindows.GetMaintenanceWindow(c.Req.Context(), c.OrgID, UID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, window)
+}
+
+func (srv *ProvisioningSrv) RoutePostMaintenanceWindow(c *contextmodel.ReqContext, mw definitions.MaintenanceWindow) response.Response {
+	created, err := srv.maintenanceWindows.CreateMaintenanceWindow(c.Req.Context(), c.OrgID, mw, alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutMaintenanceWindow(c *contextmodel.ReqContext, mw definitions.MaintenanceWindow, UID string) response.Response {
+	mw.UID = UID
+	updated, err := srv.maintenanceWindows.UpdateMaintenanceWindow(c.Req.Context(), c.OrgID, mw, alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		if errors.Is(err, provisioning.ErrNotFound) {
+			return response.Empty(http.StatusNotFound)
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMaintenanceWindow(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.maintenanceWindows.DeleteMaintenanceWindow(c.Req.Context(), c.OrgID, UID, alerting_models.Provenance(determineProvenance(c)))
+	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
 	rules, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.OrgID)
 	if err != nil {
@@ -323,6 +399,9 @@ func (srv *ProvisioningSrv) RoutePostAlertRule(c *contextmodel.ReqContext, ar de
 	if err != nil {
 		return ErrResp(http.StatusBadRequest, err, "")
 	}
+	if resp := srv.lintAlertRules(c, nil, []alerting_models.AlertRule{upstreamModel}); resp != nil {
+		return resp
+	}
 	provenance := determineProvenance(c)
 	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), upstreamModel, alerting_models.Provenance(provenance), c.UserID)
 	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
@@ -349,6 +428,16 @@ func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar def
 	}
 	updated.OrgID = c.OrgID
 	updated.UID = UID
+	existing, _, err := srv.alertRules.GetAlertRule(c.Req.Context(), c.OrgID, UID)
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return response.Empty(http.StatusNotFound)
+	}
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if resp := srv.lintAlertRules(c, []alerting_models.AlertRule{existing}, []alerting_models.AlertRule{updated}); resp != nil {
+		return resp
+	}
 	provenance := determineProvenance(c)
 	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), updated, alerting_models.Provenance(provenance))
 	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
@@ -455,6 +544,13 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 	if err != nil {
 		ErrResp(http.StatusBadRequest, err, "")
 	}
+	existing, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgID, folderUID, group)
+	if err != nil && !errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+		return ErrResp(http.StatusInternalServerError, err, "")
+	}
+	if resp := srv.lintAlertRules(c, existing.Rules, groupModel.Rules); resp != nil {
+		return resp
+	}
 	provenance := determineProvenance(c)
 	err = srv.alertRules.ReplaceRuleGroup(c.Req.Context(), c.OrgID, groupModel, c.UserID, alerting_models.Provenance(provenance))
 	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
@@ -469,6 +565,46 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 	return response.JSON(http.StatusOK, ag)
 }
 
+// lintAlertRules lints the rules saved through the provisioning API the same way as the rules saved through the ruler
+// API, and responds with an error if the rules cannot be saved. The existing rules are the rules that are replaced.
+// Rules provisioned from files are not linted, so that lint rules cannot stop Grafana from starting.
+func (srv *ProvisioningSrv) lintAlertRules(c *contextmodel.ReqContext, existing []alerting_models.AlertRule, rules []alerting_models.AlertRule) response.Response {
+	lintRules, err := orgLintRules(srv.adminConfigStore, c.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the lint rules of the organization")
+	}
+	if !hasBlockingLintRules(lintRules) {
+		return nil
+	}
+	byUID := make(map[string]*alerting_models.AlertRule, len(existing))
+	for i := range existing {
+		byUID[existing[i].UID] = &existing[i]
+	}
+	changes := &store.GroupDelta{}
+	for i := range rules {
+		rule := &rules[i]
+		if old, ok := byUID[rule.UID]; ok && rule.UID != "" {
+			// The queries are normalized when the rule is stored, so they are normalized before they are compared to the
+			// queries of the stored rule. Invalid queries are rejected when the rule is saved.
+			normalized := alerting_models.CopyRule(rule)
+			for i := range normalized.Data {
+				_ = normalized.Data[i].PreSave()
+			}
+			changes.Update = append(changes.Update, store.RuleDelta{
+				Existing: old,
+				New:      rule,
+				Diff:     old.Diff(normalized, store.AlertRuleFieldsToIgnoreInDiff[:]...),
+			})
+			continue
+		}
+		changes.New = append(changes.New, rule)
+	}
+	if err := verifyLintRules(changes, lintRules, datasourceLintOptions(c.Req.Context(), srv.datasourceCache, c.SignedInUser)); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	return nil
+}
+
 func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	if _, disabled := ctx.Req.Header[disableProvenanceHeaderName]; disabled {
 		return definitions.Provenance(alerting_models.ProvenanceNone)
@@ -489,7 +625,7 @@ func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryPar
 	}
 
 	queryFormat := c.Query("format")
-	if queryFormat == "yaml" || queryFormat == "json" {
+	if queryFormat == "yaml" || queryFormat == "json" || queryFormat == "hcl" {
 		format = queryFormat
 	}
 
@@ -503,6 +639,9 @@ func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryPar
 
 func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExport) response.Response {
 	params := extractExportRequest(c)
+	if params.Format == "hcl" {
+		return hclExportResponse(params, body)
+	}
 	if params.Download {
 		r := response.JSONDownload
 		if params.Format == "yaml" {
@@ -517,3 +656,16 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 	}
 	return r(http.StatusOK, body)
 }
+
+// hclExportResponse writes the export as resources of the Grafana Terraform provider.
+func hclExportResponse(params definitions.ExportQueryParams, body definitions.AlertingFileExport) response.Response {
+	b, err := AlertingFileExportToHCL(body)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to convert alerting file export to hcl")
+	}
+	resp := response.Respond(http.StatusOK, b).SetHeader("Content-Type", "text/hcl")
+	if params.Download {
+		resp.SetHeader("Content-Disposition", `attachment;filename="export.tf"`)
+	}
+	return resp
+}
@@ -23,6 +23,7 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -318,6 +319,86 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 404, response.Status())
 		})
 
+		t.Run("are checked against the blocking lint rules of the organization", func(t *testing.T) {
+			createLintedAlertRule := func(title string) definitions.ProvisionedAlertRule {
+				rule := createTestAlertRule(title, 1)
+				rule.Data[0].RelativeTimeRange.From = definitions.Duration(time.Minute)
+				return rule
+			}
+			setup := func(t *testing.T) ProvisioningSrv {
+				sut := createProvisioningSrvSut(t)
+				insertRule(t, sut, createLintedAlertRule("rule"))
+				adminConfigStore := store.NewFakeAdminConfigStore(t)
+				adminConfigStore.Configs[1] = &models.AdminConfiguration{OrgID: 1, LintRules: map[string]models.LintRuleAction{
+					string(lint.CodeMissingRunbookURL): models.LintRuleActionBlock,
+				}}
+				sut.adminConfigStore = adminConfigStore
+				return sut
+			}
+
+			t.Run("POST returns 400 if the rule has blocking warnings", func(t *testing.T) {
+				sut := setup(t)
+				rc := createTestRequestCtx()
+
+				response := sut.RoutePostAlertRule(&rc, createLintedAlertRule("other"))
+
+				require.Equal(t, 400, response.Status())
+				require.Contains(t, string(response.Body()), string(lint.CodeMissingRunbookURL))
+			})
+
+			t.Run("PUT returns 400 if the linted fields of the rule change", func(t *testing.T) {
+				sut := setup(t)
+				rc := createTestRequestCtx()
+				rule := createLintedAlertRule("rule")
+				rule.Labels = map[string]string{"team": "backend"}
+
+				response := sut.RoutePutAlertRule(&rc, rule, rule.UID)
+
+				require.Equal(t, 400, response.Status())
+				require.Contains(t, string(response.Body()), string(lint.CodeMissingRunbookURL))
+			})
+
+			t.Run("PUT returns 200 if the linted fields of the rule do not change", func(t *testing.T) {
+				sut := setup(t)
+				rc := createTestRequestCtx()
+				rule := createLintedAlertRule("rule")
+				rule.For = model.Duration(120)
+
+				response := sut.RoutePutAlertRule(&rc, rule, rule.UID)
+
+				require.Equal(t, 200, response.Status())
+			})
+
+			t.Run("PUT group returns 400 if a new rule has blocking warnings", func(t *testing.T) {
+				sut := setup(t)
+				rc := createTestRequestCtx()
+				group := definitions.AlertRuleGroup{
+					Title:    "my-cool-group",
+					Interval: 60,
+					Rules:    []definitions.ProvisionedAlertRule{createLintedAlertRule("rule"), createLintedAlertRule("other")},
+				}
+
+				response := sut.RoutePutAlertRuleGroup(&rc, group, "folder-uid", group.Title)
+
+				require.Equal(t, 400, response.Status())
+				require.Contains(t, string(response.Body()), string(lint.CodeMissingRunbookURL))
+			})
+
+			t.Run("PUT group returns 200 if only the interval changes", func(t *testing.T) {
+				sut := setup(t)
+				rc := createTestRequestCtx()
+				group := definitions.AlertRuleGroup{
+					Title:    "my-cool-group",
+					Interval: 120,
+					Rules:    []definitions.ProvisionedAlertRule{createLintedAlertRule("rule")},
+				}
+
+				response := sut.RoutePutAlertRuleGroup(&rc, group, "folder-uid", group.Title)
+
+				require.Equal(t, 200, response.Status())
+			})
+		})
+
 		t.Run("have reached the rule quota, POST returns 403", func(t *testing.T) {
 			env := createTestEnv(t, testConfig)
 			quotas := provisioning.MockQuotaChecker{}
@@ -812,6 +893,36 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, 200, response.Status())
 				require.Equal(t, expectedResponse, string(response.Body()))
 			})
+
+			t.Run("query param format=hcl, GET returns text hcl", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Header.Add("Accept", "application/json")
+				rc.Context.Req.Form.Set("format", "hcl")
+				rc.Context.Req.Form.Set("download", "true")
+				response := sut.RouteGetAlertRulesExport(&rc)
+				response.WriteTo(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, "text/hcl", rc.Context.Resp.Header().Get("Content-Type"))
+				require.Contains(t, rc.Context.Resp.Header().Get("Content-Disposition"), "export.tf")
+			})
+
+			t.Run("hcl body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRuleWithFolderAndGroup("rule1", 1, "folder-uid", "groupa"))
+
+				rc.Context.Req.Form.Set("format", "hcl")
+				expectedResponse := "resource \"grafana_rule_group\" \"rule_group_folder_title_groupa\" {\n  name             = \"groupa\"\n  folder_uid       = \"folder-uid\"\n  interval_seconds = 60\n\n  rule {\n    name           = \"rule1\"\n    uid            = \"rule1\"\n    condition      = \"A\"\n    for            = \"0s\"\n    no_data_state  = \"OK\"\n    exec_err_state = \"OK\"\n    is_paused      = false\n\n    data {\n      ref_id         = \"A\"\n      datasource_uid = \"\"\n      model          = jsonencode({\n        conditions = [\n          {\n            evaluator = {\n              params = [3]\n              type   = \"gt\"\n            }\n            operator = {\n              type = \"and\"\n            }\n            query = {\n              params = [\"A\"]\n            }\n            reducer = {\n              type = \"last\"\n            }\n            type = \"query\"\n          },\n        ]\n        datasource = {\n          type = \"__expr__\"\n          uid  = \"__expr__\"\n        }\n        expression    = \"1==0\"\n        intervalMs    = 1000\n        maxDataPoints = 43200\n        refId         = \"A\"\n        type          = \"math\"\n      })\n\n      relative_time_range {\n        from = 0\n        to   = 0\n      }\n    }\n  }\n}\n"
+
+				response := sut.RouteGetAlertRulesExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
 		})
 
 		t.Run("notification policies", func(t *testing.T) {
@@ -922,6 +1033,48 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, 200, response.Status())
 				require.Equal(t, expectedResponse, string(response.Body()))
 			})
+
+			t.Run("hcl body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = createFakeNotificationPolicyService()
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("format", "hcl")
+				expectedResponse := "resource \"grafana_notification_policy\" \"notification_policy_1\" {\n  contact_point   = \"default-receiver\"\n  group_by        = [\"g1\", \"g2\"]\n  group_wait      = \"30s\"\n  group_interval  = \"5m\"\n  repeat_interval = \"1h\"\n\n  policy {\n    contact_point   = \"nested-receiver\"\n    group_by        = [\"g3\", \"g4\"]\n    continue        = true\n    mute_timings    = [\"interval\"]\n    group_wait      = \"5m\"\n    group_interval  = \"5m\"\n    repeat_interval = \"5m\"\n\n    matcher {\n      label = \"a\"\n      match = \"=\"\n      value = \"b\"\n    }\n\n    matcher {\n      label = \"foo\"\n      match = \"=\"\n      value = \"bar\"\n    }\n  }\n}\n"
+
+				response := sut.RouteGetPolicyTreeExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+		})
+
+		t.Run("mute timings", func(t *testing.T) {
+			t.Run("yaml body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Header.Add("Accept", "application/yaml")
+				expectedResponse := "apiVersion: 1\nmuteTimes:\n    - orgId: 1\n      name: interval\n      time_intervals: []\n"
+
+				response := sut.RouteGetMuteTimingsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
+
+			t.Run("hcl body content is as expected", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("format", "hcl")
+				expectedResponse := "resource \"grafana_mute_timing\" \"mute_timing_interval\" {\n  name = \"interval\"\n}\n"
+
+				response := sut.RouteGetMuteTimingsExport(&rc)
+
+				require.Equal(t, 200, response.Status())
+				require.Equal(t, expectedResponse, string(response.Body()))
+			})
 		})
 	})
 }
@@ -1090,6 +1243,40 @@ func TestProvisioningApiContactPointExport(t *testing.T) {
 			})
 		})
 
+		t.Run("hcl body content is as expected", func(t *testing.T) {
+			env := createTestEnv(t, testContactPointConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+
+			rc.Context.Req.Form.Set("format", "hcl")
+			rc.Context.Req.Form.Set("name", "multiple integrations")
+
+			response := sut.RouteGetContactPointsExport(&rc)
+
+			expectedResponse := `resource "grafana_contact_point" "contact_point_multiple_integrations" {
+  name = "multiple integrations"
+
+  alertmanager {
+    uid                     = "c2090fda-f824-4add-b545-5a4d5c2ef082"
+    disable_resolve_message = true
+    basic_auth_password     = "[REDACTED]"
+    basic_auth_user         = "test"
+    url                     = "http://localhost:9093"
+  }
+
+  discord {
+    uid                     = "c84539ec-f87e-4fc5-9a91-7a687d34bbd1"
+    disable_resolve_message = false
+    avatar_url              = "some avatar"
+    url                     = "some url"
+    use_discord_username    = true
+  }
+}
+`
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, expectedResponse, string(response.Body()))
+		})
+
 		t.Run("yaml body content is as expected", func(t *testing.T) {
 			expectedRedactedResponse := "apiVersion: 1\ncontactPoints:\n    - orgId: 1\n      name: grafana-default-email\n      receivers:\n        - uid: ad95bd8a-49ed-4adc-bf89-1b444fa1aa5b\n          type: email\n          settings:\n            addresses: <example@email.com>\n          disableResolveMessage: false\n    - orgId: 1\n      name: multiple integrations\n      receivers:\n        - uid: c2090fda-f824-4add-b545-5a4d5c2ef082\n          type: prometheus-alertmanager\n          settings:\n            basicAuthPassword: '[REDACTED]'\n            basicAuthUser: test\n            url: http://localhost:9093\n          disableResolveMessage: true\n        - uid: c84539ec-f87e-4fc5-9a91-7a687d34bbd1\n          type: discord\n          settings:\n            avatar_url: some avatar\n            url: some url\n            use_discord_username: true\n          disableResolveMessage: false\n    - orgId: 1\n      name: pagerduty test\n      receivers:\n        - uid: b9bf06f8-bde2-4438-9d4a-bba0522dcd4d\n          type: pagerduty\n          settings:\n            client: some client\n            integrationKey: '[REDACTED]'\n            severity: criticalish\n          disableResolveMessage: false\n    - orgId: 1\n      name: slack test\n      receivers:\n        - uid: cbfd0976-8228-4126-b672-4419f30a9e50\n          type: slack\n          settings:\n            text: title body test\n            title: title test\n            url: '[REDACTED]'\n          disableResolveMessage: true\n"
 			t.Run("decrypt false", func(t *testing.T) {
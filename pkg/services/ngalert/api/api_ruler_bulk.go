@@ -0,0 +1,323 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+var errFolderNotFound = errors.New("folder not found")
+
+// RoutePostBulkRuleOperation applies an action to all alert rules that match the selector and that the user can access.
+// The action is applied group by group through the same change calculation as the ruler API, so the user must be
+// authorized to make the changes, provisioned rules cannot be changed, and the versions of the rules are recorded.
+// All groups are changed in a single transaction: if the action cannot be applied to one group, no rule is changed.
+func (srv RulerSrv) RoutePostBulkRuleOperation(c *contextmodel.ReqContext, body apimodels.PostableBulkRuleOperation) response.Response {
+	if err := validateBulkRuleSelector(body.Selector); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+	apply, err := srv.bulkRuleAction(body)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	namespaces, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.SignedInUser.OrgID, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+	var target *folder.Folder
+	if body.Action == apimodels.BulkRuleActionMove {
+		var ok bool
+		if target, ok = namespaces[body.TargetFolderUID]; !ok {
+			return ErrResp(http.StatusNotFound, fmt.Errorf("%w: %s", errFolderNotFound, body.TargetFolderUID), "")
+		}
+	}
+
+	namespaceUIDs := make([]string, 0, len(namespaces))
+	if len(body.Selector.FolderUIDs) > 0 {
+		for _, uid := range body.Selector.FolderUIDs {
+			if _, ok := namespaces[uid]; ok {
+				namespaceUIDs = append(namespaceUIDs, uid)
+			}
+		}
+	} else {
+		for uid := range namespaces {
+			namespaceUIDs = append(namespaceUIDs, uid)
+		}
+	}
+
+	result := apimodels.BulkRuleOperationResult{Rules: []apimodels.BulkRuleOperationRule{}}
+	if len(namespaceUIDs) == 0 {
+		return response.JSON(http.StatusAccepted, result)
+	}
+
+	lintRules, err := srv.lintRules(c.SignedInUser.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the lint rules of the organization")
+	}
+	lintOpts := srv.lintOptions(c.Req.Context(), c.SignedInUser)
+
+	hasAccess := accesscontrol.HasAccess(srv.ac, c)
+	logger := srv.log.New("org_id", c.SignedInUser.OrgID, "user_id", c.UserID, "action", body.Action)
+	err = srv.xactManager.InTransaction(c.Req.Context(), func(tranCtx context.Context) error {
+		ruleList, err := srv.store.ListAlertRules(tranCtx, &ngmodels.ListAlertRulesQuery{
+			OrgID:         c.SignedInUser.OrgID,
+			NamespaceUIDs: namespaceUIDs,
+		})
+		if err != nil {
+			return err
+		}
+		groups := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+		for _, rule := range ruleList {
+			groups[rule.GetGroupKey()] = append(groups[rule.GetGroupKey()], rule)
+		}
+
+		submitted, changedUIDs, err := srv.bulkSubmittedGroups(tranCtx, groups, body, target, apply, func(rules ngmodels.RulesGroup) bool {
+			return authorizeAccessToRuleGroup(rules, hasAccess)
+		})
+		if err != nil {
+			return err
+		}
+
+		keys := make([]ngmodels.AlertRuleGroupKey, 0, len(submitted))
+		for key := range submitted {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].NamespaceUID == keys[j].NamespaceUID {
+				return keys[i].RuleGroup < keys[j].RuleGroup
+			}
+			return keys[i].NamespaceUID < keys[j].NamespaceUID
+		})
+		for _, groupKey := range keys {
+			changes, err := store.CalculateChanges(tranCtx, srv.store, groupKey, submitted[groupKey])
+			if err != nil {
+				return err
+			}
+			if changes.IsEmpty() {
+				continue
+			}
+			// The submitted group contains all the rules of the group, so the action can never add or delete rules.
+			if len(changes.New) > 0 || len(changes.Delete) > 0 {
+				return fmt.Errorf("rule group %s changed while the action was applied", groupKey)
+			}
+			if err := authorizeRuleChanges(changes, hasAccess); err != nil {
+				return err
+			}
+			if err := verifyProvisionedRulesNotAffected(tranCtx, srv.provenanceStore, c.SignedInUser.OrgID, changes); err != nil {
+				return err
+			}
+			if err := verifyLintRules(changes, lintRules, lintOpts); err != nil {
+				return err
+			}
+
+			finalChanges := store.UpdateCalculatedRuleFields(changes)
+			updates := make([]ngmodels.UpdateRule, 0, len(finalChanges.Update))
+			for _, update := range finalChanges.Update {
+				updates = append(updates, ngmodels.UpdateRule{
+					Existing: update.Existing,
+					New:      *update.New,
+				})
+				if _, ok := changedUIDs[update.New.UID]; ok && len(update.Diff) > 0 {
+					result.Rules = append(result.Rules, apimodels.BulkRuleOperationRule{
+						UID:          update.New.UID,
+						Title:        update.New.Title,
+						NamespaceUID: update.New.NamespaceUID,
+						RuleGroup:    update.New.RuleGroup,
+					})
+				}
+			}
+			logger.Debug("Updating rules of the group", "namespace_uid", groupKey.NamespaceUID, "group", groupKey.RuleGroup, "update", len(updates))
+			if err := srv.store.UpdateAlertRules(tranCtx, updates); err != nil {
+				return fmt.Errorf("failed to update rules: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return ruleGroupErrorToResponse(err)
+	}
+	logger.Info("Applied the action to alert rules", "count", len(result.Rules))
+	return response.JSON(http.StatusAccepted, result)
+}
+
+// bulkSubmittedGroups returns the rule groups, as they would be submitted to the ruler API, that result from applying
+// the action to the selected rules, and the UIDs of the rules the action changes. The groups contain all the rules of
+// the group, including the ones that did not match the selector. Groups the user cannot access are ignored.
+func (srv RulerSrv) bulkSubmittedGroups(
+	ctx context.Context,
+	groups map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup,
+	body apimodels.PostableBulkRuleOperation,
+	target *folder.Folder,
+	apply func(rule *ngmodels.AlertRule),
+	canAccess func(rules ngmodels.RulesGroup) bool,
+) (map[ngmodels.AlertRuleGroupKey][]*ngmodels.AlertRuleWithOptionals, map[string]struct{}, error) {
+	submitted := make(map[ngmodels.AlertRuleGroupKey][]*ngmodels.AlertRuleWithOptionals)
+	changedUIDs := make(map[string]struct{})
+
+	for groupKey, rules := range groups {
+		var matched []*ngmodels.AlertRule
+		for _, rule := range rules {
+			if bulkRuleSelectorMatches(body.Selector, rule) {
+				matched = append(matched, rule)
+			}
+		}
+		if len(matched) == 0 || !canAccess(rules) {
+			continue
+		}
+		rules.SortByGroupIndex()
+
+		if body.Action != apimodels.BulkRuleActionMove {
+			// The interval is the same for all rules in the group, so it is set on the whole group.
+			if body.Action == apimodels.BulkRuleActionSetInterval {
+				matched = rules
+			}
+			changed := make(map[string]struct{}, len(matched))
+			for _, rule := range matched {
+				changed[rule.UID] = struct{}{}
+				changedUIDs[rule.UID] = struct{}{}
+			}
+			result := make([]*ngmodels.AlertRuleWithOptionals, 0, len(rules))
+			for _, rule := range rules {
+				copied := ngmodels.CopyRule(rule)
+				if _, ok := changed[rule.UID]; ok {
+					apply(copied)
+				}
+				result = append(result, &ngmodels.AlertRuleWithOptionals{AlertRule: *copied, HasPause: true})
+			}
+			submitted[groupKey] = result
+			continue
+		}
+
+		if groupKey.NamespaceUID == target.UID {
+			continue
+		}
+		targetKey := ngmodels.AlertRuleGroupKey{OrgID: groupKey.OrgID, NamespaceUID: target.UID, RuleGroup: groupKey.RuleGroup}
+		if _, ok := submitted[targetKey]; !ok {
+			existing, ok := groups[targetKey]
+			if !ok {
+				var err error
+				existing, err = srv.store.ListAlertRules(ctx, &ngmodels.ListAlertRulesQuery{
+					OrgID:         targetKey.OrgID,
+					NamespaceUIDs: []string{targetKey.NamespaceUID},
+					RuleGroup:     targetKey.RuleGroup,
+				})
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+			existing.SortByGroupIndex()
+			result := make([]*ngmodels.AlertRuleWithOptionals, 0, len(existing))
+			for _, rule := range existing {
+				result = append(result, &ngmodels.AlertRuleWithOptionals{AlertRule: *ngmodels.CopyRule(rule), HasPause: true})
+			}
+			submitted[targetKey] = result
+		}
+		for _, rule := range matched {
+			copied := ngmodels.CopyRule(rule)
+			apply(copied)
+			// Rules that join an existing group are evaluated at the interval of the group.
+			if len(submitted[targetKey]) > 0 {
+				copied.IntervalSeconds = submitted[targetKey][0].IntervalSeconds
+			}
+			submitted[targetKey] = append(submitted[targetKey], &ngmodels.AlertRuleWithOptionals{AlertRule: *copied, HasPause: true})
+			changedUIDs[rule.UID] = struct{}{}
+		}
+	}
+
+	for _, rules := range submitted {
+		for i, rule := range rules {
+			rule.RuleGroupIndex = i + 1
+		}
+	}
+	return submitted, changedUIDs, nil
+}
+
+// bulkRuleAction validates the parameters of the action and returns a function that applies it to a rule.
+func (srv RulerSrv) bulkRuleAction(body apimodels.PostableBulkRuleOperation) (func(rule *ngmodels.AlertRule), error) {
+	switch body.Action {
+	case apimodels.BulkRuleActionPause:
+		return func(rule *ngmodels.AlertRule) { rule.IsPaused = true }, nil
+	case apimodels.BulkRuleActionResume:
+		return func(rule *ngmodels.AlertRule) { rule.IsPaused = false }, nil
+	case apimodels.BulkRuleActionMove:
+		if body.TargetFolderUID == "" {
+			return nil, errors.New("target folder UID is required to move rules")
+		}
+		return func(rule *ngmodels.AlertRule) { rule.NamespaceUID = body.TargetFolderUID }, nil
+	case apimodels.BulkRuleActionAddLabels:
+		if len(body.Labels) == 0 {
+			return nil, errors.New("labels are required to add labels to rules")
+		}
+		for name := range body.Labels {
+			if name == "" {
+				return nil, errors.New("label name cannot be empty")
+			}
+		}
+		return func(rule *ngmodels.AlertRule) {
+			if rule.Labels == nil {
+				rule.Labels = make(map[string]string, len(body.Labels))
+			}
+			for name, value := range body.Labels {
+				rule.Labels[name] = value
+			}
+		}, nil
+	case apimodels.BulkRuleActionRemoveLabels:
+		if len(body.LabelNames) == 0 {
+			return nil, errors.New("label names are required to remove labels from rules")
+		}
+		return func(rule *ngmodels.AlertRule) {
+			for _, name := range body.LabelNames {
+				delete(rule.Labels, name)
+			}
+		}, nil
+	case apimodels.BulkRuleActionSetInterval:
+		intervalSeconds, err := validateInterval(srv.cfg, time.Duration(body.Interval))
+		if err != nil {
+			return nil, err
+		}
+		return func(rule *ngmodels.AlertRule) { rule.IntervalSeconds = intervalSeconds }, nil
+	default:
+		return nil, fmt.Errorf("unknown action %q", body.Action)
+	}
+}
+
+func validateBulkRuleSelector(selector apimodels.BulkRuleSelector) error {
+	if len(selector.FolderUIDs) == 0 && len(selector.RuleGroups) == 0 && selector.Title == "" && len(selector.Matchers) == 0 {
+		return errors.New("selector must have at least one criterion")
+	}
+	return nil
+}
+
+// bulkRuleSelectorMatches returns true if the rule matches all the criteria of the selector.
+// The folders are not checked as only the rules of the selected folders are listed.
+func bulkRuleSelectorMatches(selector apimodels.BulkRuleSelector, rule *ngmodels.AlertRule) bool {
+	if len(selector.RuleGroups) > 0 {
+		found := false
+		for _, group := range selector.RuleGroups {
+			if group == rule.RuleGroup {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if selector.Title != "" && !strings.Contains(strings.ToLower(rule.Title), strings.ToLower(selector.Title)) {
+		return false
+	}
+	return matchersMatch(selector.Matchers, rule.Labels)
+}
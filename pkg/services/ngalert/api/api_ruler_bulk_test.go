@@ -0,0 +1,219 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestRoutePostBulkRuleOperation(t *testing.T) {
+	orgID := int64(1)
+	f1 := &folder.Folder{UID: "folder-1", Title: "Folder 1"}
+	f2 := &folder.Folder{UID: "folder-2", Title: "Folder 2"}
+
+	withLabels := func(lbls map[string]string) models.AlertRuleMutator {
+		return func(rule *models.AlertRule) {
+			rule.Labels = lbls
+		}
+	}
+
+	type testRules struct {
+		backend, frontend, other *models.AlertRule
+	}
+	setup := func(t *testing.T) (*fakes.RuleStore, *RulerSrv, testRules) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f1, f2)
+		gen := func(idx int, group string, lbls map[string]string) *models.AlertRule {
+			return models.AlertRuleGen(withOrgID(orgID), withNamespace(f1), withGroup(group), models.WithGroupIndex(idx),
+				models.WithInterval(time.Minute), withLabels(lbls), func(rule *models.AlertRule) {
+					rule.IsPaused = false
+				})()
+		}
+		rules := testRules{
+			backend:  gen(1, "services", map[string]string{"team": "backend"}),
+			frontend: gen(2, "services", map[string]string{"team": "frontend"}),
+			other:    gen(1, "other", map[string]string{"team": "backend"}),
+		}
+		ruleStore.PutRule(context.Background(), rules.backend, rules.frontend, rules.other)
+
+		srv := createService(ruleStore)
+		srv.cfg = &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}
+		return ruleStore, srv, rules
+	}
+	perms := func(rules testRules, actions ...string) map[int64]map[string][]string {
+		p := createPermissionsForRules([]*models.AlertRule{rules.backend, rules.frontend, rules.other}, orgID)
+		for _, action := range actions {
+			p[orgID][action] = []string{dashboards.ScopeFoldersProvider.GetResourceScopeUID(f1.UID), dashboards.ScopeFoldersProvider.GetResourceScopeUID(f2.UID)}
+		}
+		return p
+	}
+	matchers := func(t *testing.T, name, value string) apimodels.ObjectMatchers {
+		m, err := labels.NewMatcher(labels.MatchEqual, name, value)
+		require.NoError(t, err)
+		return apimodels.ObjectMatchers{m}
+	}
+	recordedUpdates := func(ruleStore *fakes.RuleStore) []models.UpdateRule {
+		var result []models.UpdateRule
+		for _, cmd := range ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		}) {
+			result = append(result, cmd.([]models.UpdateRule)...)
+		}
+		return result
+	}
+	changedRules := func(updates []models.UpdateRule) map[string]models.AlertRule {
+		result := map[string]models.AlertRule{}
+		for _, u := range updates {
+			if len(u.Existing.Diff(&u.New, "ID", "Version", "Updated")) > 0 {
+				result[u.New.UID] = u.New
+			}
+		}
+		return result
+	}
+	decode := func(t *testing.T, body []byte) apimodels.BulkRuleOperationResult {
+		var result apimodels.BulkRuleOperationResult
+		require.NoError(t, json.Unmarshal(body, &result))
+		return result
+	}
+
+	t.Run("should pause the rules that match the selector", func(t *testing.T) {
+		ruleStore, srv, rules := setup(t)
+		body := apimodels.PostableBulkRuleOperation{
+			Selector: apimodels.BulkRuleSelector{Matchers: matchers(t, "team", "backend")},
+			Action:   apimodels.BulkRuleActionPause,
+		}
+		resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleUpdate), nil), body)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		require.Len(t, decode(t, resp.Body()).Rules, 2)
+
+		changed := changedRules(recordedUpdates(ruleStore))
+		require.Len(t, changed, 2)
+		require.True(t, changed[rules.backend.UID].IsPaused)
+		require.True(t, changed[rules.other.UID].IsPaused)
+	})
+
+	t.Run("should add and remove labels of the rules that match the title", func(t *testing.T) {
+		ruleStore, srv, rules := setup(t)
+		body := apimodels.PostableBulkRuleOperation{
+			Selector: apimodels.BulkRuleSelector{Title: rules.frontend.Title},
+			Action:   apimodels.BulkRuleActionAddLabels,
+			Labels:   map[string]string{"severity": "critical"},
+		}
+		resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleUpdate), nil), body)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		changed := changedRules(recordedUpdates(ruleStore))
+		require.Len(t, changed, 1)
+		require.Equal(t, map[string]string{"team": "frontend", "severity": "critical"}, changed[rules.frontend.UID].Labels)
+
+		ruleStore.RecordedOps = nil
+		body.Action = apimodels.BulkRuleActionRemoveLabels
+		body.LabelNames = []string{"team"}
+		resp = srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleUpdate), nil), body)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		changed = changedRules(recordedUpdates(ruleStore))
+		require.Len(t, changed, 1)
+		require.Empty(t, changed[rules.frontend.UID].Labels)
+	})
+
+	t.Run("should set the interval of the whole rule group", func(t *testing.T) {
+		ruleStore, srv, rules := setup(t)
+		body := apimodels.PostableBulkRuleOperation{
+			Selector: apimodels.BulkRuleSelector{Matchers: matchers(t, "team", "frontend")},
+			Action:   apimodels.BulkRuleActionSetInterval,
+			Interval: model.Duration(5 * time.Minute),
+		}
+		resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleUpdate), nil), body)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		changed := changedRules(recordedUpdates(ruleStore))
+		require.Len(t, changed, 2)
+		require.EqualValues(t, 300, changed[rules.backend.UID].IntervalSeconds)
+		require.EqualValues(t, 300, changed[rules.frontend.UID].IntervalSeconds)
+	})
+
+	t.Run("should move the rules to the target folder", func(t *testing.T) {
+		ruleStore, srv, rules := setup(t)
+		body := apimodels.PostableBulkRuleOperation{
+			Selector:        apimodels.BulkRuleSelector{RuleGroups: []string{"other"}},
+			Action:          apimodels.BulkRuleActionMove,
+			TargetFolderUID: f2.UID,
+		}
+		resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleCreate, ac.ActionAlertingRuleDelete), nil), body)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		result := decode(t, resp.Body())
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, f2.UID, result.Rules[0].NamespaceUID)
+
+		changed := changedRules(recordedUpdates(ruleStore))
+		require.Len(t, changed, 1)
+		require.Equal(t, f2.UID, changed[rules.other.UID].NamespaceUID)
+		require.Equal(t, "other", changed[rules.other.UID].RuleGroup)
+	})
+
+	t.Run("should not change any rule if the user is not authorized to change all of them", func(t *testing.T) {
+		ruleStore, srv, rules := setup(t)
+		body := apimodels.PostableBulkRuleOperation{
+			Selector:        apimodels.BulkRuleSelector{FolderUIDs: []string{f1.UID}},
+			Action:          apimodels.BulkRuleActionMove,
+			TargetFolderUID: f2.UID,
+		}
+		resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleCreate), nil), body)
+		require.Equal(t, http.StatusUnauthorized, resp.Status())
+		require.Empty(t, recordedUpdates(ruleStore))
+	})
+
+	t.Run("should not change provisioned rules", func(t *testing.T) {
+		ruleStore, srv, rules := setup(t)
+		require.NoError(t, srv.provenanceStore.SetProvenance(context.Background(), rules.other, orgID, models.ProvenanceAPI))
+		body := apimodels.PostableBulkRuleOperation{
+			Selector: apimodels.BulkRuleSelector{Matchers: matchers(t, "team", "backend")},
+			Action:   apimodels.BulkRuleActionPause,
+		}
+		resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleUpdate), nil), body)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Empty(t, recordedUpdates(ruleStore))
+	})
+
+	t.Run("should reject invalid requests", func(t *testing.T) {
+		testCases := map[string]apimodels.PostableBulkRuleOperation{
+			"empty selector":       {Action: apimodels.BulkRuleActionPause},
+			"unknown action":       {Selector: apimodels.BulkRuleSelector{Title: "test"}, Action: "delete"},
+			"move without folder":  {Selector: apimodels.BulkRuleSelector{Title: "test"}, Action: apimodels.BulkRuleActionMove},
+			"add without labels":   {Selector: apimodels.BulkRuleSelector{Title: "test"}, Action: apimodels.BulkRuleActionAddLabels},
+			"remove without names": {Selector: apimodels.BulkRuleSelector{Title: "test"}, Action: apimodels.BulkRuleActionRemoveLabels},
+			"invalid interval":     {Selector: apimodels.BulkRuleSelector{Title: "test"}, Action: apimodels.BulkRuleActionSetInterval, Interval: model.Duration(15 * time.Second)},
+		}
+		for name, body := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, srv, rules := setup(t)
+				resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleUpdate), nil), body)
+				require.Equal(t, http.StatusBadRequest, resp.Status())
+			})
+		}
+	})
+
+	t.Run("should return 404 if the target folder does not exist", func(t *testing.T) {
+		_, srv, rules := setup(t)
+		body := apimodels.PostableBulkRuleOperation{
+			Selector:        apimodels.BulkRuleSelector{Title: "test"},
+			Action:          apimodels.BulkRuleActionMove,
+			TargetFolderUID: "unknown",
+		}
+		resp := srv.RoutePostBulkRuleOperation(createRequestContextWithPerms(orgID, perms(rules, ac.ActionAlertingRuleUpdate), nil), body)
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
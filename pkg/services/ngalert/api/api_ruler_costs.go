@@ -0,0 +1,71 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// EvaluationCostProvider provides the cost of the evaluations of the alert rules that are scheduled on this instance.
+type EvaluationCostProvider interface {
+	EvaluationCosts(orgID int64) []models.RuleEvaluationCost
+}
+
+type EvaluationCostSrv struct {
+	logger log.Logger
+	costs  EvaluationCostProvider
+	store  RuleStore
+}
+
+func (srv *EvaluationCostSrv) RouteGetRuleEvaluationCosts(c *contextmodel.ReqContext) response.Response {
+	ruleUID := c.Query("ruleUID")
+	slowOnly := c.QueryBool("slow")
+
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.OrgID, c.SignedInUser)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get namespaces visible to the user")
+	}
+
+	result := apimodels.RuleEvaluationCosts{Rules: []apimodels.RuleEvaluationCost{}}
+	if srv.costs == nil {
+		return response.JSON(http.StatusOK, result)
+	}
+	for _, cost := range srv.costs.EvaluationCosts(c.OrgID) {
+		if _, ok := namespaceMap[cost.NamespaceUID]; !ok {
+			continue
+		}
+		if ruleUID != "" && cost.UID != ruleUID {
+			continue
+		}
+		if slowOnly && !cost.Slow {
+			continue
+		}
+		result.Rules = append(result.Rules, toRuleEvaluationCost(cost))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func toRuleEvaluationCost(cost models.RuleEvaluationCost) apimodels.RuleEvaluationCost {
+	return apimodels.RuleEvaluationCost{
+		RuleUID:                cost.UID,
+		Title:                  cost.Title,
+		FolderUID:              cost.NamespaceUID,
+		RuleGroup:              cost.RuleGroup,
+		IntervalSeconds:        cost.Interval.Seconds(),
+		Evaluations:            cost.Evaluations,
+		LastEvaluation:         cost.LastEvaluation,
+		LastDuration:           cost.LastDuration.Seconds(),
+		AverageDuration:        cost.AverageDuration().Seconds(),
+		MaxDuration:            cost.MaxDuration.Seconds(),
+		TotalDuration:          cost.TotalDuration.Seconds(),
+		LastQueryCount:         cost.LastQueryCount,
+		LastSeriesCount:        cost.LastSeriesCount,
+		LastDataSourceDuration: cost.LastDataSourceDuration.Seconds(),
+		SlowEvaluations:        cost.SlowEvaluations,
+		Slow:                   cost.Slow,
+	}
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeEvaluationCostProvider struct {
+	costs []ngmodels.RuleEvaluationCost
+}
+
+func (f *fakeEvaluationCostProvider) EvaluationCosts(orgID int64) []ngmodels.RuleEvaluationCost {
+	var result []ngmodels.RuleEvaluationCost
+	for _, c := range f.costs {
+		if c.OrgID == orgID {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func TestRouteGetRuleEvaluationCosts(t *testing.T) {
+	orgID := int64(1)
+	ruleStore := fakes.NewRuleStore(t)
+	visible := ngmodels.AlertRuleGen(ngmodels.WithOrgID(orgID))()
+	ruleStore.PutRule(context.Background(), visible)
+
+	costs := &fakeEvaluationCostProvider{
+		costs: []ngmodels.RuleEvaluationCost{
+			{
+				AlertRuleKey:    visible.GetKey(),
+				Title:           visible.Title,
+				NamespaceUID:    visible.NamespaceUID,
+				RuleGroup:       visible.RuleGroup,
+				Interval:        10 * time.Second,
+				Evaluations:     4,
+				LastDuration:    20 * time.Second,
+				TotalDuration:   48 * time.Second,
+				MaxDuration:     20 * time.Second,
+				LastQueryCount:  2,
+				LastSeriesCount: 30,
+				SlowEvaluations: 3,
+				Slow:            true,
+			},
+			{
+				AlertRuleKey: ngmodels.AlertRuleKey{OrgID: orgID, UID: "hidden-rule"},
+				NamespaceUID: "hidden",
+				Evaluations:  1,
+				Slow:         true,
+			},
+		},
+	}
+	srv := &EvaluationCostSrv{logger: log.NewNopLogger(), costs: costs, store: ruleStore}
+
+	get := func(t *testing.T, query string) apimodels.RuleEvaluationCosts {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/api/v1/rules/costs"+query, nil)
+		require.NoError(t, err)
+		c := &contextmodel.ReqContext{Context: &web.Context{Req: req}, SignedInUser: &user.SignedInUser{OrgID: orgID}}
+		resp := srv.RouteGetRuleEvaluationCosts(c)
+		require.Equal(t, http.StatusOK, resp.Status())
+		var result apimodels.RuleEvaluationCosts
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		return result
+	}
+
+	t.Run("should return only rules in namespaces visible to the user", func(t *testing.T) {
+		result := get(t, "")
+		require.Len(t, result.Rules, 1)
+		cost := result.Rules[0]
+		require.Equal(t, visible.UID, cost.RuleUID)
+		require.Equal(t, visible.NamespaceUID, cost.FolderUID)
+		require.Equal(t, 10.0, cost.IntervalSeconds)
+		require.Equal(t, 20.0, cost.LastDuration)
+		require.Equal(t, 12.0, cost.AverageDuration)
+		require.Equal(t, 2, cost.LastQueryCount)
+		require.Equal(t, 30, cost.LastSeriesCount)
+		require.True(t, cost.Slow)
+	})
+
+	t.Run("should filter by rule UID", func(t *testing.T) {
+		require.Len(t, get(t, "?ruleUID="+visible.UID).Rules, 1)
+		require.Empty(t, get(t, "?ruleUID=unknown").Rules)
+	})
+
+	t.Run("should filter slow rules", func(t *testing.T) {
+		costs.costs[0].Slow = false
+		t.Cleanup(func() { costs.costs[0].Slow = true })
+		require.Len(t, get(t, "").Rules, 1)
+		require.Empty(t, get(t, "?slow=true").Rules)
+	})
+}
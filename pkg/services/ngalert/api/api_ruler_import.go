@@ -0,0 +1,136 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+)
+
+// RoutePostPrometheusRulesImport converts Prometheus rule groups to Grafana-managed rule groups and saves them in the namespace.
+// Every group is saved in its own transaction. Rules that exist in the group are updated if they have the same title,
+// so that importing a rule file again keeps the state of the rules. The rules that could not be converted and the groups
+// that could not be saved are reported in the response.
+func (srv RulerSrv) RoutePostPrometheusRulesImport(c *contextmodel.ReqContext, body apimodels.PostablePrometheusRulesImport, namespaceTitle string) response.Response {
+	namespace, err := srv.store.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.OrgID, c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+	if body.DatasourceUID == "" {
+		return ErrResp(http.StatusBadRequest, errors.New("datasource UID is required"), "")
+	}
+
+	converter := prom.NewConverter(prom.Config{
+		OrgID:           c.SignedInUser.OrgID,
+		NamespaceUID:    namespace.UID,
+		DatasourceUID:   body.DatasourceUID,
+		DefaultInterval: srv.cfg.DefaultRuleEvaluationInterval,
+	})
+	groups, conversionErrors := converter.ConvertGroups(body.Groups)
+
+	result := apimodels.PrometheusRulesImportResult{
+		Imported: []apimodels.PrometheusImportedRuleGroup{},
+		Failed:   []apimodels.PrometheusRuleImportFailure{},
+	}
+	for _, e := range conversionErrors {
+		result.Failed = append(result.Failed, apimodels.PrometheusRuleImportFailure{
+			Group: e.Group,
+			Rule:  e.Rule,
+			Error: e.Err.Error(),
+		})
+	}
+
+	for _, group := range groups {
+		groupKey := ngmodels.AlertRuleGroupKey{
+			OrgID:        c.SignedInUser.OrgID,
+			NamespaceUID: namespace.UID,
+			RuleGroup:    group.Title,
+		}
+		err := srv.importRuleGroup(c, groupKey, namespace, group)
+		if err != nil {
+			srv.log.Warn("Failed to import Prometheus rule group", "namespace_uid", namespace.UID, "group", group.Title, "error", err)
+			result.Failed = append(result.Failed, apimodels.PrometheusRuleImportFailure{
+				Group: group.Title,
+				Error: err.Error(),
+			})
+			continue
+		}
+		imported := apimodels.PrometheusImportedRuleGroup{Name: group.Title, Rules: make([]string, 0, len(group.Rules))}
+		for _, rule := range group.Rules {
+			imported.Rules = append(imported.Rules, rule.Title)
+		}
+		result.Imported = append(result.Imported, imported)
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+func (srv RulerSrv) importRuleGroup(c *contextmodel.ReqContext, groupKey ngmodels.AlertRuleGroupKey, namespace *folder.Folder, group ngmodels.AlertRuleGroup) error {
+	existing, err := srv.store.ListAlertRules(c.Req.Context(), &ngmodels.ListAlertRulesQuery{
+		OrgID:         groupKey.OrgID,
+		NamespaceUIDs: []string{groupKey.NamespaceUID},
+		RuleGroup:     groupKey.RuleGroup,
+	})
+	if err != nil {
+		return err
+	}
+	uids := make(map[string]string, len(existing))
+	for _, rule := range existing {
+		uids[rule.Title] = rule.UID
+	}
+	// Rules that already exist keep their UID. New rules are inserted with the UID of the converter, which is the same
+	// on every import. It is only set on insert, because a rule with a UID is treated as an update of that rule.
+	newRuleUIDs := make(map[string]string, len(group.Rules))
+	for i := range group.Rules {
+		if uid, ok := uids[group.Rules[i].Title]; ok {
+			group.Rules[i].UID = uid
+			continue
+		}
+		newRuleUIDs[group.Rules[i].Title] = group.Rules[i].UID
+		group.Rules[i].UID = ""
+	}
+
+	ruleGroupConfig := postableRuleGroupFromAlertRuleGroup(group)
+	rules, err := validateRuleGroup(&ruleGroupConfig, groupKey.OrgID, namespace, srv.cfg)
+	if err != nil {
+		return err
+	}
+	_, err = srv.saveAlertRulesInGroup(c, groupKey, rules, newRuleUIDs)
+	return err
+}
+
+func postableRuleGroupFromAlertRuleGroup(group ngmodels.AlertRuleGroup) apimodels.PostableRuleGroupConfig {
+	rules := make([]apimodels.PostableExtendedRuleNode, 0, len(group.Rules))
+	for _, rule := range group.Rules {
+		forDuration := model.Duration(rule.For)
+		keepFiringFor := model.Duration(rule.KeepFiringFor)
+		rules = append(rules, apimodels.PostableExtendedRuleNode{
+			ApiRuleNode: &apimodels.ApiRuleNode{
+				For:           &forDuration,
+				KeepFiringFor: &keepFiringFor,
+				Labels:        rule.Labels,
+				Annotations:   rule.Annotations,
+			},
+			GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+				Title:        rule.Title,
+				Condition:    rule.Condition,
+				Data:         ApiAlertQueriesFromAlertQueries(rule.Data),
+				UID:          rule.UID,
+				NoDataState:  apimodels.NoDataState(rule.NoDataState),
+				ExecErrState: apimodels.ExecutionErrorState(rule.ExecErrState),
+			},
+		})
+	}
+	return apimodels.PostableRuleGroupConfig{
+		Name:     group.Title,
+		Interval: model.Duration(time.Duration(group.Interval) * time.Second),
+		Rules:    rules,
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/prom"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestRoutePostPrometheusRulesImport(t *testing.T) {
+	orgID := int64(1)
+	f := &folder.Folder{UID: "folder-uid", Title: "Prometheus"}
+
+	setup := func(t *testing.T) (*fakes.RuleStore, *RulerSrv) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+		srv := createService(ruleStore)
+		srv.cfg = &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second, DefaultRuleEvaluationInterval: time.Minute}
+		srv.conditionValidator = &recordingConditionValidator{}
+		srv.QuotaService = quotatest.New(false, nil)
+		return ruleStore, srv
+	}
+	perms := map[int64]map[string][]string{
+		orgID: {
+			ac.ActionAlertingRuleCreate: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+			datasources.ActionQuery:     {datasources.ScopeAll},
+		},
+	}
+	forDuration := model.Duration(5 * time.Minute)
+	body := apimodels.PostablePrometheusRulesImport{
+		DatasourceUID: "prometheus",
+		Groups: []apimodels.PrometheusRuleGroup{
+			{
+				Name: "node",
+				Rules: []apimodels.ApiRuleNode{
+					{Alert: "InstanceDown", Expr: "up == 0", For: &forDuration, Labels: map[string]string{"severity": "critical"}},
+					{Record: "job:up:sum", Expr: "sum by (job) (up)"},
+				},
+			},
+			{
+				Name:     "invalid-interval",
+				Interval: model.Duration(15 * time.Second),
+				Rules:    []apimodels.ApiRuleNode{{Alert: "InstanceDown", Expr: "up == 0"}},
+			},
+		},
+	}
+
+	t.Run("should import the rules that can be converted", func(t *testing.T) {
+		ruleStore, srv := setup(t)
+		resp := srv.RoutePostPrometheusRulesImport(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+
+		var result apimodels.PrometheusRulesImportResult
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.Len(t, result.Imported, 1)
+		require.Equal(t, "node", result.Imported[0].Name)
+		require.Equal(t, []string{"InstanceDown"}, result.Imported[0].Rules)
+
+		require.Len(t, result.Failed, 2)
+		require.Equal(t, "job:up:sum", result.Failed[0].Rule)
+		require.Equal(t, "invalid-interval", result.Failed[1].Group)
+		require.Empty(t, result.Failed[1].Rule)
+
+		rules := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		})
+		require.Len(t, rules, 1)
+		inserted := rules[0].([]models.AlertRule)
+		require.Len(t, inserted, 1)
+		require.Equal(t, "InstanceDown", inserted[0].Title)
+		require.Equal(t, f.UID, inserted[0].NamespaceUID)
+		require.Equal(t, prom.ThresholdRefID, inserted[0].Condition)
+		require.EqualValues(t, 60, inserted[0].IntervalSeconds)
+		require.Equal(t, 5*time.Minute, inserted[0].For)
+	})
+
+	t.Run("should insert new rules with the UID of the converter", func(t *testing.T) {
+		insertedUIDs := func() []string {
+			ruleStore, srv := setup(t)
+			resp := srv.RoutePostPrometheusRulesImport(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+			require.Equal(t, http.StatusAccepted, resp.Status())
+
+			rules := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+				c, ok := cmd.([]models.AlertRule)
+				return c, ok
+			})
+			require.Len(t, rules, 1)
+			uids := make([]string, 0)
+			for _, rule := range rules[0].([]models.AlertRule) {
+				uids = append(uids, rule.UID)
+			}
+			return uids
+		}
+
+		uids := insertedUIDs()
+		require.Len(t, uids, 1)
+		require.True(t, strings.HasPrefix(uids[0], "prom-"), "expected the UID of the converter, got %q", uids[0])
+		require.Equal(t, uids, insertedUIDs())
+	})
+
+	t.Run("should update the rules with the same title", func(t *testing.T) {
+		ruleStore, srv := setup(t)
+		existing := models.AlertRuleGen(withOrgID(orgID), withNamespace(f), withGroup("node"), func(rule *models.AlertRule) {
+			rule.Title = "InstanceDown"
+		})()
+		ruleStore.PutRule(context.Background(), existing)
+		perms := map[int64]map[string][]string{
+			orgID: {
+				ac.ActionAlertingRuleCreate: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+				ac.ActionAlertingRuleUpdate: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+				datasources.ActionQuery:     {datasources.ScopeAll},
+			},
+		}
+
+		resp := srv.RoutePostPrometheusRulesImport(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+
+		updates := ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.UpdateRule)
+			return c, ok
+		})
+		require.Len(t, updates, 1)
+		updated := updates[0].([]models.UpdateRule)
+		require.Len(t, updated, 1)
+		require.Equal(t, existing.UID, updated[0].New.UID)
+		require.Equal(t, prom.ThresholdRefID, updated[0].New.Condition)
+	})
+
+	t.Run("should require a data source", func(t *testing.T) {
+		_, srv := setup(t)
+		body := body
+		body.DatasourceUID = ""
+		resp := srv.RoutePostPrometheusRulesImport(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("should fail when the namespace does not exist", func(t *testing.T) {
+		store, srv := setup(t)
+		resp := srv.RoutePostPrometheusRulesImport(createRequestContextWithPerms(orgID, perms, nil), body, "unknown")
+		require.NotEqual(t, http.StatusAccepted, resp.Status())
+		require.Empty(t, store.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			return cmd, true
+		}))
+	})
+}
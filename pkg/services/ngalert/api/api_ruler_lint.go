@@ -0,0 +1,173 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/cmputil"
+)
+
+// datasourceScrapeIntervalKey is the key of the JSON data of a data source that holds the interval at which it scrapes its targets.
+const datasourceScrapeIntervalKey = "timeInterval"
+
+// RoutePostRuleGroupLint validates the rule group like RoutePostNameRulesConfig does, and returns the lint warnings
+// of its rules instead of saving them. Warnings whose code the organization ignores are not returned.
+func (srv RulerSrv) RoutePostRuleGroupLint(c *contextmodel.ReqContext, ruleGroupConfig apimodels.PostableRuleGroupConfig, namespaceTitle string) response.Response {
+	namespace, err := srv.store.GetNamespaceByTitle(c.Req.Context(), namespaceTitle, c.SignedInUser.OrgID, c.SignedInUser)
+	if err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	rules, err := validateRuleGroup(&ruleGroupConfig, c.SignedInUser.OrgID, namespace, srv.cfg)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	lintRules, err := srv.lintRules(c.SignedInUser.OrgID)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get the lint rules of the organization")
+	}
+
+	opts := srv.lintOptions(c.Req.Context(), c.SignedInUser)
+	result := apimodels.RuleGroupLintResult{Rules: make([]apimodels.RuleLintResult, 0, len(rules))}
+	for _, rule := range rules {
+		ruleResult := apimodels.RuleLintResult{UID: rule.UID, Title: rule.Title, Warnings: []apimodels.LintWarning{}}
+		for _, w := range lint.Ignored(lint.Rule(&rule.AlertRule, opts), lintRules) {
+			blocking := lintRules[string(w.Code)] == ngmodels.LintRuleActionBlock
+			result.Blocking = result.Blocking || blocking
+			ruleResult.Warnings = append(ruleResult.Warnings, apimodels.LintWarning{
+				Code:     string(w.Code),
+				Severity: string(w.Severity),
+				Message:  w.Message,
+				RefID:    w.RefID,
+				Blocking: blocking,
+			})
+		}
+		result.Rules = append(result.Rules, ruleResult)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+// lintRules returns the lint rules of the organization, or nil if it has no admin configuration.
+func (srv RulerSrv) lintRules(orgID int64) (map[string]ngmodels.LintRuleAction, error) {
+	return orgLintRules(srv.adminConfigStore, orgID)
+}
+
+// lintOptions returns the options to lint rules that query data sources the user has access to.
+func (srv RulerSrv) lintOptions(ctx context.Context, user *user.SignedInUser) lint.Options {
+	return datasourceLintOptions(ctx, srv.datasourceCache, user)
+}
+
+func orgLintRules(adminConfigStore store.AdminConfigurationStore, orgID int64) (map[string]ngmodels.LintRuleAction, error) {
+	if adminConfigStore == nil {
+		return nil, nil
+	}
+	cfg, err := adminConfigStore.GetAdminConfiguration(orgID)
+	if err != nil {
+		if errors.Is(err, store.ErrNoAdminConfiguration) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+	return cfg.LintRules, nil
+}
+
+func datasourceLintOptions(ctx context.Context, datasourceCache datasources.CacheService, user *user.SignedInUser) lint.Options {
+	if datasourceCache == nil {
+		return lint.Options{}
+	}
+	intervals := map[string]time.Duration{}
+	return lint.Options{
+		ScrapeInterval: func(datasourceUID string) (time.Duration, bool) {
+			if interval, ok := intervals[datasourceUID]; ok {
+				return interval, interval > 0
+			}
+			var interval time.Duration
+			ds, err := datasourceCache.GetDatasourceByUID(ctx, datasourceUID, user, false)
+			if err == nil && ds.JsonData != nil {
+				// The interval can be prefixed with ">" to mark it as the minimum interval of queries.
+				s := strings.TrimPrefix(ds.JsonData.Get(datasourceScrapeIntervalKey).MustString(), ">")
+				if d, err := model.ParseDuration(s); err == nil {
+					interval = time.Duration(d)
+				}
+			}
+			intervals[datasourceUID] = interval
+			return interval, interval > 0
+		},
+	}
+}
+
+// lintedRuleFields are the fields of alert rules that are linted. Updates that do not change them, for example pausing
+// a rule or changing the interval of its group, are not blocked by warnings the rule already had.
+var lintedRuleFields = []string{"Data", "Condition", "Labels", "Annotations", "Record"}
+
+// verifyLintRules lints the new rules and the updated rules whose linted fields changed, and returns an error if any of
+// them has a warning whose code the organization configured to block saves.
+func verifyLintRules(groupChanges *store.GroupDelta, lintRules map[string]ngmodels.LintRuleAction, opts lint.Options) error {
+	if !hasBlockingLintRules(lintRules) {
+		return nil
+	}
+	verify := func(rule *ngmodels.AlertRule) error {
+		blocking := lint.Blocking(lint.Rule(rule, opts), lintRules)
+		if len(blocking) == 0 {
+			return nil
+		}
+		messages := make([]string, 0, len(blocking))
+		for _, w := range blocking {
+			messages = append(messages, w.String())
+		}
+		if rule.UID == "" {
+			return fmt.Errorf("%w '%s': %s", ngmodels.ErrAlertRuleFailedValidation, rule.Title, strings.Join(messages, "; "))
+		}
+		return fmt.Errorf("%w '%s' (UID: %s): %s", ngmodels.ErrAlertRuleFailedValidation, rule.Title, rule.UID, strings.Join(messages, "; "))
+	}
+	for _, rule := range groupChanges.New {
+		if err := verify(rule); err != nil {
+			return err
+		}
+	}
+	for _, upd := range groupChanges.Update {
+		if !changesLintedFields(upd.Diff) {
+			continue
+		}
+		if err := verify(upd.New); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func changesLintedFields(diff cmputil.DiffReport) bool {
+	for _, field := range lintedRuleFields {
+		if len(diff.GetDiffsForField(field)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func hasBlockingLintRules(lintRules map[string]ngmodels.LintRuleAction) bool {
+	for _, action := range lintRules {
+		if action == ngmodels.LintRuleActionBlock {
+			return true
+		}
+	}
+	return false
+}
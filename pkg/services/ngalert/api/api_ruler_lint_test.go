@@ -0,0 +1,192 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/lint"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestRoutePostRuleGroupLint(t *testing.T) {
+	orgID := int64(1)
+	f := &folder.Folder{UID: "folder-uid", Title: "Folder"}
+
+	setup := func(t *testing.T, lintRules map[string]models.LintRuleAction) (*fakes.RuleStore, *RulerSrv) {
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.Folders[orgID] = append(ruleStore.Folders[orgID], f)
+		adminConfigStore := store.NewFakeAdminConfigStore(t)
+		adminConfigStore.Configs[orgID] = &models.AdminConfiguration{OrgID: orgID, LintRules: lintRules}
+
+		srv := createService(ruleStore)
+		srv.cfg = &setting.UnifiedAlertingSettings{BaseInterval: 10 * time.Second}
+		srv.conditionValidator = &recordingConditionValidator{}
+		srv.QuotaService = quotatest.New(false, nil)
+		srv.adminConfigStore = adminConfigStore
+		srv.datasourceCache = &fakeDatasources.FakeCacheService{DataSources: []*datasources.DataSource{
+			{UID: "prometheus", JsonData: simplejson.NewFromAny(map[string]any{"timeInterval": "2m"})},
+		}}
+		return ruleStore, srv
+	}
+	perms := map[int64]map[string][]string{
+		orgID: {
+			ac.ActionAlertingRuleCreate: {dashboards.ScopeFoldersProvider.GetResourceScopeUID(f.UID)},
+			datasources.ActionQuery:     {datasources.ScopeAll},
+		},
+	}
+	forDuration := model.Duration(0)
+	body := apimodels.PostableRuleGroupConfig{
+		Name:     "group",
+		Interval: model.Duration(time.Minute),
+		Rules: []apimodels.PostableExtendedRuleNode{
+			{
+				ApiRuleNode: &apimodels.ApiRuleNode{
+					For:    &forDuration,
+					Labels: map[string]string{"team": "backend"},
+				},
+				GrafanaManagedAlert: &apimodels.PostableGrafanaRule{
+					Title:     "InstanceDown",
+					Condition: "A",
+					Data: []apimodels.AlertQuery{{
+						RefID:             "A",
+						DatasourceUID:     "prometheus",
+						RelativeTimeRange: apimodels.RelativeTimeRange{From: apimodels.Duration(10 * time.Minute)},
+						Model:             json.RawMessage(`{"expr": "up == 0", "instant": true}`),
+					}},
+					NoDataState:  apimodels.NoData,
+					ExecErrState: apimodels.AlertingErrState,
+				},
+			},
+		},
+	}
+
+	t.Run("should return the warnings of the rules", func(t *testing.T) {
+		_, srv := setup(t, map[string]models.LintRuleAction{
+			string(lint.CodeMissingRunbookURL): models.LintRuleActionBlock,
+		})
+		resp := srv.RoutePostRuleGroupLint(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result apimodels.RuleGroupLintResult
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.True(t, result.Blocking)
+		require.Len(t, result.Rules, 1)
+		require.Equal(t, "InstanceDown", result.Rules[0].Title)
+		require.Equal(t, []apimodels.LintWarning{
+			{
+				Code:     string(lint.CodeMissingRunbookURL),
+				Severity: string(lint.SeverityInfo),
+				Message:  "the rule has no runbook_url annotation to tell responders how to resolve the alert",
+				Blocking: true,
+			},
+			{
+				Code:     string(lint.CodeIntervalBelowScrapeInterval),
+				Severity: string(lint.SeverityWarning),
+				Message:  "the rule is evaluated every 1m0s, but the data source scrapes every 2m0s",
+				RefID:    "A",
+			},
+		}, result.Rules[0].Warnings)
+	})
+
+	t.Run("should not return ignored warnings", func(t *testing.T) {
+		_, srv := setup(t, map[string]models.LintRuleAction{
+			string(lint.CodeMissingRunbookURL):           models.LintRuleActionIgnore,
+			string(lint.CodeIntervalBelowScrapeInterval): models.LintRuleActionIgnore,
+		})
+		resp := srv.RoutePostRuleGroupLint(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+		require.Equal(t, http.StatusOK, resp.Status())
+
+		var result apimodels.RuleGroupLintResult
+		require.NoError(t, json.Unmarshal(resp.Body(), &result))
+		require.False(t, result.Blocking)
+		require.Empty(t, result.Rules[0].Warnings)
+	})
+
+	t.Run("should reject saving rules with blocking warnings", func(t *testing.T) {
+		ruleStore, srv := setup(t, map[string]models.LintRuleAction{
+			string(lint.CodeMissingRunbookURL): models.LintRuleActionBlock,
+		})
+		resp := srv.RoutePostNameRulesConfig(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+		require.Contains(t, string(resp.Body()), string(lint.CodeMissingRunbookURL))
+		require.Empty(t, ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		}))
+	})
+
+	t.Run("should save rules with warnings that do not block", func(t *testing.T) {
+		ruleStore, srv := setup(t, map[string]models.LintRuleAction{
+			string(lint.CodeReservedLabel): models.LintRuleActionBlock,
+		})
+		resp := srv.RoutePostNameRulesConfig(createRequestContextWithPerms(orgID, perms, nil), body, f.Title)
+		require.Equal(t, http.StatusAccepted, resp.Status())
+		require.Len(t, ruleStore.GetRecordedCommands(func(cmd interface{}) (interface{}, bool) {
+			c, ok := cmd.([]models.AlertRule)
+			return c, ok
+		}), 1)
+	})
+}
+
+func TestVerifyLintRules(t *testing.T) {
+	lintRules := map[string]models.LintRuleAction{
+		string(lint.CodeMissingRunbookURL): models.LintRuleActionBlock,
+	}
+	existing := models.AlertRuleGen()()
+	existing.Annotations = map[string]string{"summary": "down"}
+	existing.Record = nil
+	update := func(mutate func(rule *models.AlertRule)) *store.GroupDelta {
+		rule := models.CopyRule(existing)
+		mutate(rule)
+		return &store.GroupDelta{Update: []store.RuleDelta{{
+			Existing: existing,
+			New:      rule,
+			Diff:     existing.Diff(rule, store.AlertRuleFieldsToIgnoreInDiff[:]...),
+		}}}
+	}
+
+	t.Run("should block new rules with blocking warnings", func(t *testing.T) {
+		err := verifyLintRules(&store.GroupDelta{New: []*models.AlertRule{models.CopyRule(existing)}}, lintRules, lint.Options{})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should block updates of linted fields", func(t *testing.T) {
+		err := verifyLintRules(update(func(rule *models.AlertRule) {
+			rule.Labels = map[string]string{"team": "backend"}
+		}), lintRules, lint.Options{})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		err = verifyLintRules(update(func(rule *models.AlertRule) {
+			rule.Annotations = map[string]string{"summary": "instance down"}
+		}), lintRules, lint.Options{})
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
+
+	t.Run("should not block updates that do not change linted fields", func(t *testing.T) {
+		err := verifyLintRules(update(func(rule *models.AlertRule) {
+			rule.IsPaused = !rule.IsPaused
+		}), lintRules, lint.Options{})
+		require.NoError(t, err)
+
+		err = verifyLintRules(update(func(rule *models.AlertRule) {
+			rule.IntervalSeconds *= 2
+		}), lintRules, lint.Options{})
+		require.NoError(t, err)
+	})
+}
@@ -67,6 +67,12 @@ func validateRuleNode(
 		}
 	}
 
+	condition := ruleNode.GrafanaManagedAlert.Condition
+	if ruleNode.GrafanaManagedAlert.Record != nil && condition == "" {
+		// the condition of a recording rule is the query or expression it records
+		condition = ruleNode.GrafanaManagedAlert.Record.From
+	}
+
 	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
 		if canPatch {
 			if ruleNode.GrafanaManagedAlert.Condition != "" {
@@ -76,7 +82,7 @@ func validateRuleNode(
 			return nil, fmt.Errorf("%w: no queries or expressions are found", ngmodels.ErrAlertRuleFailedValidation)
 		}
 	} else {
-		err = validateCondition(ruleNode.GrafanaManagedAlert.Condition, ruleNode.GrafanaManagedAlert.Data)
+		err = validateCondition(condition, ruleNode.GrafanaManagedAlert.Data)
 		if err != nil {
 			return nil, fmt.Errorf("%w: %s", ngmodels.ErrAlertRuleFailedValidation, err.Error())
 		}
@@ -87,7 +93,7 @@ func validateRuleNode(
 	newAlertRule := ngmodels.AlertRule{
 		OrgID:           orgId,
 		Title:           ruleNode.GrafanaManagedAlert.Title,
-		Condition:       ruleNode.GrafanaManagedAlert.Condition,
+		Condition:       condition,
 		Data:            queries,
 		UID:             ruleNode.GrafanaManagedAlert.UID,
 		IntervalSeconds: intervalSeconds,
@@ -95,6 +101,12 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		DependsOn:       ruleNode.GrafanaManagedAlert.DependsOn,
+		Record:          RecordFromApiRecord(ruleNode.GrafanaManagedAlert.Record),
+	}
+
+	if err = newAlertRule.ValidateDependsOn(); err != nil {
+		return nil, err
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
@@ -102,6 +114,17 @@ func validateRuleNode(
 		return nil, err
 	}
 
+	newAlertRule.KeepFiringFor, err = validateKeepFiringForInterval(ruleNode)
+	if err != nil {
+		return nil, err
+	}
+
+	if newAlertRule.IsRecordingRule() {
+		if err = validateRecordingRule(ruleNode, &newAlertRule, cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
 		newAlertRule.Labels = ruleNode.ApiRuleNode.Labels
@@ -114,6 +137,32 @@ func validateRuleNode(
 	return &newAlertRule, nil
 }
 
+// validateRecordingRule validates the fields that are specific to recording rules. Recording rules do not alert,
+// therefore, they cannot have a pending or keep firing period. The queries must be specified because the recorded
+// query or expression is checked against them.
+func validateRecordingRule(ruleNode *apimodels.PostableExtendedRuleNode, rule *ngmodels.AlertRule, cfg *setting.UnifiedAlertingSettings) error {
+	if !cfg.RecordingRules.Enabled {
+		return fmt.Errorf("%w: recording rules are not enabled", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
+		return fmt.Errorf("%w: recording rule must specify queries and expressions", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	if ruleNode.ApiRuleNode != nil {
+		if ruleNode.ApiRuleNode.For != nil && *ruleNode.ApiRuleNode.For != 0 {
+			return fmt.Errorf("%w: recording rule cannot have field `for`", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if ruleNode.ApiRuleNode.KeepFiringFor != nil && *ruleNode.ApiRuleNode.KeepFiringFor != 0 {
+			return fmt.Errorf("%w: recording rule cannot have field `keep_firing_for`", ngmodels.ErrAlertRuleFailedValidation)
+		}
+	}
+	rule.For = 0
+	rule.KeepFiringFor = 0
+	if rule.Record.TargetDatasourceUID == "" && cfg.RecordingRules.TargetDatasourceUID == "" {
+		return fmt.Errorf("%w: recording rule must specify the target data source because no default one is configured", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	return rule.ValidateRecord()
+}
+
 func validateCondition(condition string, queries []apimodels.AlertQuery) error {
 	if condition == "" {
 		return errors.New("condition cannot be empty")
@@ -173,6 +222,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringForInterval validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil // if it's a new rule, use the 0 as the default
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 // It also returns a map containing current existing alerts that don't contain the is_paused field in the body of the call.
@@ -43,9 +43,11 @@ func config(t *testing.T) *setting.UnifiedAlertingSettings {
 
 func validRule() apimodels.PostableExtendedRuleNode {
 	forDuration := model.Duration(rand.Int63n(1000))
+	keepFiringForDuration := model.Duration(rand.Int63n(1000))
 	return apimodels.PostableExtendedRuleNode{
 		ApiRuleNode: &apimodels.ApiRuleNode{
-			For: &forDuration,
+			For:           &forDuration,
+			KeepFiringFor: &keepFiringForDuration,
 			Labels: map[string]string{
 				"test-label": "data",
 			},
@@ -71,6 +73,7 @@ func validRule() apimodels.PostableExtendedRuleNode {
 			UID:          util.GenerateShortUID(),
 			NoDataState:  allNoData[rand.Intn(len(allNoData))],
 			ExecErrState: allExecError[rand.Intn(len(allExecError))],
+			DependsOn:    []string{util.GenerateShortUID()},
 		},
 	}
 }
@@ -333,8 +336,10 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, models.NoDataState(api.GrafanaManagedAlert.NoDataState), alert.NoDataState)
 				require.Equal(t, models.ExecutionErrorState(api.GrafanaManagedAlert.ExecErrState), alert.ExecErrState)
 				require.Equal(t, time.Duration(*api.ApiRuleNode.For), alert.For)
+				require.Equal(t, time.Duration(*api.ApiRuleNode.KeepFiringFor), alert.KeepFiringFor)
 				require.Equal(t, api.ApiRuleNode.Annotations, alert.Annotations)
 				require.Equal(t, api.ApiRuleNode.Labels, alert.Labels)
+				require.Equal(t, api.GrafanaManagedAlert.DependsOn, alert.DependsOn)
 			},
 		},
 		{
@@ -346,6 +351,7 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 			},
 			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
 				require.Equal(t, time.Duration(0), alert.For)
+				require.Equal(t, time.Duration(0), alert.KeepFiringFor)
 				require.Nil(t, alert.Annotations)
 				require.Nil(t, alert.Labels)
 			},
@@ -543,6 +549,23 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if KeepFiringFor is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-1 * time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
+		{
+			name: "fail if DependsOn has duplicate UIDs",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.DependsOn = []string{"upstream", "upstream"}
+				return &r
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -566,6 +589,127 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 	}
 }
 
+func validRecordingRule() apimodels.PostableExtendedRuleNode {
+	r := validRule()
+	r.ApiRuleNode.For = nil
+	r.ApiRuleNode.KeepFiringFor = nil
+	r.GrafanaManagedAlert.Condition = ""
+	r.GrafanaManagedAlert.Record = &apimodels.Record{
+		Metric: "job:http_requests:rate5m",
+		From:   "A",
+	}
+	return r
+}
+
+func TestValidateRuleNode_RecordingRule(t *testing.T) {
+	orgId := rand.Int63()
+	folder := randFolder()
+	cfg := config(t)
+	cfg.RecordingRules = setting.UnifiedAlertingRecordingRuleSettings{
+		Enabled:             true,
+		TargetDatasourceUID: "prometheus",
+	}
+
+	t.Run("converts api model to AlertRule", func(t *testing.T) {
+		r := validRecordingRule()
+		alert, err := validateRuleNode(&r, "", cfg.BaseInterval, orgId, folder, cfg)
+		require.NoError(t, err)
+		require.True(t, alert.IsRecordingRule())
+		require.Equal(t, "A", alert.Condition)
+		require.Equal(t, &models.Record{Metric: "job:http_requests:rate5m", From: "A"}, alert.Record)
+		require.Equal(t, time.Duration(0), alert.For)
+		require.Equal(t, time.Duration(0), alert.KeepFiringFor)
+	})
+
+	testCases := []struct {
+		name string
+		cfg  func(cfg setting.UnifiedAlertingSettings) *setting.UnifiedAlertingSettings
+		rule func() *apimodels.PostableExtendedRuleNode
+	}{
+		{
+			name: "fail if recording rules are not enabled",
+			cfg: func(cfg setting.UnifiedAlertingSettings) *setting.UnifiedAlertingSettings {
+				cfg.RecordingRules.Enabled = false
+				return &cfg
+			},
+		},
+		{
+			name: "fail if there is no target data source",
+			cfg: func(cfg setting.UnifiedAlertingSettings) *setting.UnifiedAlertingSettings {
+				cfg.RecordingRules.TargetDatasourceUID = ""
+				return &cfg
+			},
+		},
+		{
+			name: "fail if metric name is not valid",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				r.GrafanaManagedAlert.Record.Metric = "http requests"
+				return &r
+			},
+		},
+		{
+			name: "fail if recorded query does not exist",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				r.GrafanaManagedAlert.Record.From = "B"
+				return &r
+			},
+		},
+		{
+			name: "fail if condition is not the recorded query",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				r.GrafanaManagedAlert.Data = append(r.GrafanaManagedAlert.Data, r.GrafanaManagedAlert.Data[0])
+				r.GrafanaManagedAlert.Data[1].RefID = "B"
+				r.GrafanaManagedAlert.Condition = "B"
+				return &r
+			},
+		},
+		{
+			name: "fail if For is set",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				forDuration := model.Duration(time.Minute)
+				r.ApiRuleNode.For = &forDuration
+				return &r
+			},
+		},
+		{
+			name: "fail if KeepFiringFor is set",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				keepFiringFor := model.Duration(time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
+		{
+			name: "fail if Data is empty",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRecordingRule()
+				r.GrafanaManagedAlert.Data = nil
+				return &r
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			r := validRecordingRule()
+			if testCase.rule != nil {
+				r = *testCase.rule()
+			}
+			c := cfg
+			if testCase.cfg != nil {
+				c = testCase.cfg(*cfg)
+			}
+			_, err := validateRuleNode(&r, "", c.BaseInterval, orgId, folder, c)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		})
+	}
+}
+
 func TestValidateRuleNode_UID(t *testing.T) {
 	orgId := rand.Int63()
 	folder := randFolder()
@@ -627,6 +771,17 @@ func TestValidateRuleNode_UID(t *testing.T) {
 				require.Len(t, alert.Data, 0)
 			},
 		},
+		{
+			name: "use -1 KeepFiringFor if it is not specified",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.KeepFiringFor = nil
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Duration(-1), alert.KeepFiringFor)
+			},
+		},
 		{
 			name: "extracts Dashboard UID and Panel Id from annotations",
 			rule: func() *apimodels.PostableExtendedRuleNode {
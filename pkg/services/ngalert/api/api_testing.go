@@ -37,6 +37,7 @@ type TestingApiSrv struct {
 	evaluator       eval.EvaluatorFactory
 	cfg             *setting.UnifiedAlertingSettings
 	backtesting     *backtesting.Engine
+	ruleStore       RuleStore
 	featureManager  featuremgmt.FeatureToggles
 	appUrl          *url.URL
 }
@@ -246,3 +247,114 @@ func (srv TestingApiSrv) BacktestAlertRule(c *contextmodel.ReqContext, cmd apimo
 	}
 	return response.JSON(http.StatusOK, body)
 }
+
+// BacktestCompareAlertRule backtests a version of a stored rule and the proposed edit of the rule over the same range,
+// and responds with the difference between their state timelines.
+func (srv TestingApiSrv) BacktestCompareAlertRule(c *contextmodel.ReqContext, cmd apimodels.BacktestCompareConfig) response.Response {
+	if !srv.featureManager.IsEnabled(featuremgmt.FlagAlertingBacktesting) {
+		return ErrResp(http.StatusNotFound, nil, "Backtesting API is not enabled")
+	}
+
+	if cmd.From.After(cmd.To) {
+		return ErrResp(http.StatusBadRequest, nil, "From cannot be greater than To")
+	}
+	if cmd.RuleUID == "" {
+		return ErrResp(http.StatusBadRequest, nil, "rule_uid must be specified")
+	}
+
+	var base *ngmodels.AlertRule
+	var err error
+	if cmd.Version == 0 {
+		base, err = srv.ruleStore.GetAlertRuleByUID(c.Req.Context(), &ngmodels.GetAlertRuleByUIDQuery{UID: cmd.RuleUID, OrgID: c.OrgID})
+	} else {
+		base, err = srv.ruleStore.GetAlertRuleVersion(c.Req.Context(), &ngmodels.GetAlertRuleVersionQuery{UID: cmd.RuleUID, OrgID: c.OrgID, Version: cmd.Version})
+	}
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrAlertRuleNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to get the alert rule")
+	}
+	if base == nil {
+		return ErrResp(http.StatusNotFound, ngmodels.ErrAlertRuleNotFound, "")
+	}
+	// the rule can only be backtested by users who can read it, that is, who can view its folder.
+	if _, err := srv.ruleStore.GetNamespaceByUID(c.Req.Context(), base.NamespaceUID, c.OrgID, c.SignedInUser); err != nil {
+		return toNamespaceErrorResponse(err)
+	}
+
+	interval := time.Duration(base.IntervalSeconds) * time.Second
+	if cmd.Interval != 0 {
+		interval = time.Duration(cmd.Interval)
+	}
+	proposed, err := proposedBacktestRule(base, &cmd.Proposed, interval, c.OrgID, srv.cfg)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	for _, rule := range []*ngmodels.AlertRule{base, proposed} {
+		if !authorizeDatasourceAccessForRule(rule, func(evaluator accesscontrol.Evaluator) bool {
+			return accesscontrol.HasAccess(srv.accessControl, c)(evaluator)
+		}) {
+			return errorToResponse(fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization))
+		}
+	}
+
+	// prefix backtesting- is to distinguish between executions of regular rule and backtesting in logs (like expression engine, evaluator, state manager etc)
+	baseVersion := base.Version
+	base.UID = "backtesting-" + base.UID
+	proposed.UID = base.UID
+
+	diff, err := srv.backtesting.Compare(c.Req.Context(), c.SignedInUser, base, proposed, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "Failed to evaluate")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate")
+	}
+	return response.JSON(http.StatusOK, backtestCompareResultFromTimelineDiff(baseVersion, diff))
+}
+
+// proposedBacktestRule validates the proposed edit of the base rule and converts it to a rule. The fields the proposed
+// rule leaves out are taken from the base rule, the same way they are when the edit is saved.
+func proposedBacktestRule(base *ngmodels.AlertRule, node *apimodels.PostableExtendedRuleNode, interval time.Duration, orgID int64, cfg *setting.UnifiedAlertingSettings) (*ngmodels.AlertRule, error) {
+	rule, err := validateRuleNode(node, base.RuleGroup, interval, orgID, &folder.Folder{
+		OrgID: orgID,
+		UID:   base.NamespaceUID,
+	}, cfg)
+	if err != nil {
+		return nil, err
+	}
+	proposed := &ngmodels.AlertRuleWithOptionals{AlertRule: *rule}
+	if alert := node.GrafanaManagedAlert; alert != nil && alert.IsPaused != nil {
+		proposed.HasPause = true
+	}
+	ngmodels.PatchPartialAlertRule(base, proposed)
+	return &proposed.AlertRule, nil
+}
+
+func backtestCompareResultFromTimelineDiff(baseVersion int64, diff *backtesting.TimelineDiff) apimodels.BacktestCompareResult {
+	toIntervals := func(intervals []backtesting.Interval) []apimodels.BacktestInterval {
+		result := make([]apimodels.BacktestInterval, 0, len(intervals))
+		for _, interval := range intervals {
+			result = append(result, apimodels.BacktestInterval{Start: interval.Start, End: interval.End})
+		}
+		return result
+	}
+	result := apimodels.BacktestCompareResult{
+		BaseVersion:           baseVersion,
+		Instances:             make([]apimodels.BacktestInstanceDiff, 0, len(diff.Instances)),
+		BaseNotifications:     diff.BaseNotifications,
+		ProposedNotifications: diff.ProposedNotifications,
+	}
+	for _, instance := range diff.Instances {
+		result.Instances = append(result.Instances, apimodels.BacktestInstanceDiff{
+			Labels:                instance.Labels,
+			Added:                 toIntervals(instance.Added),
+			Removed:               toIntervals(instance.Removed),
+			BaseNotifications:     instance.BaseNotifications,
+			ProposedNotifications: instance.ProposedNotifications,
+		})
+	}
+	return result
+}
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"math/rand"
 	"net/http"
 	"testing"
 	"time"
@@ -15,10 +17,12 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	ngfakes "github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -267,6 +271,145 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestBacktestCompareAlertRule(t *testing.T) {
+	orgID := rand.Int63()
+	rule := models.AlertRuleGen(withOrgID(orgID), models.WithInterval(10*time.Second))()
+	ruleStore := ngfakes.NewRuleStore(t)
+	ruleStore.PutRule(context.Background(), rule)
+
+	createSrv := func(features featuremgmt.FeatureToggles) *TestingApiSrv {
+		return &TestingApiSrv{
+			AlertingProxy:  &AlertingProxy{},
+			accessControl:  acMock.New().WithDisabled(),
+			cfg:            config(t),
+			featureManager: features,
+			ruleStore:      ruleStore,
+		}
+	}
+	now := time.Now()
+
+	t.Run("should return 404 if backtesting is disabled", func(t *testing.T) {
+		srv := createSrv(featuremgmt.WithFeatures())
+		response := srv.BacktestCompareAlertRule(createRequestContext(orgID, nil), definitions.BacktestCompareConfig{
+			From:    now.Add(-time.Hour),
+			To:      now,
+			RuleUID: rule.UID,
+		})
+		require.Equal(t, http.StatusNotFound, response.Status())
+	})
+
+	srv := createSrv(featuremgmt.WithFeatures(featuremgmt.FlagAlertingBacktesting))
+
+	t.Run("should return 400 if", func(t *testing.T) {
+		t.Run("from is after to", func(t *testing.T) {
+			response := srv.BacktestCompareAlertRule(createRequestContext(orgID, nil), definitions.BacktestCompareConfig{
+				From:    now,
+				To:      now.Add(-time.Hour),
+				RuleUID: rule.UID,
+			})
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+
+		t.Run("rule UID is empty", func(t *testing.T) {
+			response := srv.BacktestCompareAlertRule(createRequestContext(orgID, nil), definitions.BacktestCompareConfig{
+				From: now.Add(-time.Hour),
+				To:   now,
+			})
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+
+		t.Run("proposed rule is invalid", func(t *testing.T) {
+			response := srv.BacktestCompareAlertRule(createRequestContext(orgID, nil), definitions.BacktestCompareConfig{
+				From:    now.Add(-time.Hour),
+				To:      now,
+				RuleUID: rule.UID,
+			})
+			require.Equal(t, http.StatusBadRequest, response.Status())
+		})
+	})
+
+	t.Run("should return 404 if", func(t *testing.T) {
+		t.Run("rule does not exist", func(t *testing.T) {
+			response := srv.BacktestCompareAlertRule(createRequestContext(orgID, nil), definitions.BacktestCompareConfig{
+				From:    now.Add(-time.Hour),
+				To:      now,
+				RuleUID: "unknown",
+			})
+			require.Equal(t, http.StatusNotFound, response.Status())
+		})
+
+		t.Run("rule belongs to another organization", func(t *testing.T) {
+			response := srv.BacktestCompareAlertRule(createRequestContext(orgID+1, nil), definitions.BacktestCompareConfig{
+				From:    now.Add(-time.Hour),
+				To:      now,
+				RuleUID: rule.UID,
+			})
+			require.Equal(t, http.StatusNotFound, response.Status())
+		})
+
+		t.Run("rule version does not exist", func(t *testing.T) {
+			response := srv.BacktestCompareAlertRule(createRequestContext(orgID, nil), definitions.BacktestCompareConfig{
+				From:    now.Add(-time.Hour),
+				To:      now,
+				RuleUID: rule.UID,
+				Version: rule.Version + 100,
+			})
+			require.Equal(t, http.StatusNotFound, response.Status())
+		})
+
+		t.Run("user cannot view the folder of the rule", func(t *testing.T) {
+			hiddenRule := models.AlertRuleGen(withOrgID(orgID), models.WithInterval(10*time.Second))()
+			ruleStore.PutRule(context.Background(), hiddenRule)
+			// the fake store only returns the folders the user can view
+			folders := ruleStore.Folders[orgID]
+			for i, f := range folders {
+				if f.UID == hiddenRule.NamespaceUID {
+					ruleStore.Folders[orgID] = append(folders[:i:i], folders[i+1:]...)
+				}
+			}
+
+			response := srv.BacktestCompareAlertRule(createRequestContext(orgID, nil), definitions.BacktestCompareConfig{
+				From:    now.Add(-time.Hour),
+				To:      now,
+				RuleUID: hiddenRule.UID,
+			})
+			require.Equal(t, http.StatusNotFound, response.Status())
+		})
+	})
+}
+
+func TestProposedBacktestRule(t *testing.T) {
+	cfg := config(t)
+	base := models.AlertRuleGen(models.WithInterval(cfg.BaseInterval))()
+	base.For = 5 * time.Minute
+	base.KeepFiringFor = time.Minute
+
+	t.Run("fields the proposed rule leaves out are taken from the base rule", func(t *testing.T) {
+		node := validRule()
+		node.GrafanaManagedAlert.UID = base.UID
+		node.ApiRuleNode.For = nil
+		node.ApiRuleNode.KeepFiringFor = nil
+
+		proposed, err := proposedBacktestRule(base, &node, cfg.BaseInterval, base.OrgID, cfg)
+		require.NoError(t, err)
+		require.Equal(t, base.For, proposed.For)
+		require.Equal(t, base.KeepFiringFor, proposed.KeepFiringFor)
+		require.Equal(t, node.GrafanaManagedAlert.Title, proposed.Title)
+		require.Equal(t, base.NamespaceUID, proposed.NamespaceUID)
+		require.Equal(t, base.RuleGroup, proposed.RuleGroup)
+	})
+
+	t.Run("fields the proposed rule specifies are kept", func(t *testing.T) {
+		node := validRule()
+		node.GrafanaManagedAlert.UID = base.UID
+
+		proposed, err := proposedBacktestRule(base, &node, cfg.BaseInterval, base.OrgID, cfg)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(*node.ApiRuleNode.For), proposed.For)
+		require.Equal(t, time.Duration(*node.ApiRuleNode.KeepFiringFor), proposed.KeepFiringFor)
+	})
+}
+
 func createTestingApiSrv(t *testing.T, ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New().WithDisabled()
@@ -12,44 +12,55 @@ import (
 
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
+	condition := a.Condition
+	if a.Record != nil && condition == "" {
+		// the condition of a recording rule is the query or expression it records
+		condition = a.Record.From
+	}
 	return models.AlertRule{
-		ID:           a.ID,
-		UID:          a.UID,
-		OrgID:        a.OrgID,
-		NamespaceUID: a.FolderUID,
-		RuleGroup:    a.RuleGroup,
-		Title:        a.Title,
-		Condition:    a.Condition,
-		Data:         AlertQueriesFromApiAlertQueries(a.Data),
-		Updated:      a.Updated,
-		NoDataState:  models.NoDataState(a.NoDataState),          // TODO there must be a validation
-		ExecErrState: models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
-		For:          time.Duration(a.For),
-		Annotations:  a.Annotations,
-		Labels:       a.Labels,
-		IsPaused:     a.IsPaused,
+		ID:            a.ID,
+		UID:           a.UID,
+		OrgID:         a.OrgID,
+		NamespaceUID:  a.FolderUID,
+		RuleGroup:     a.RuleGroup,
+		Title:         a.Title,
+		Condition:     condition,
+		Data:          AlertQueriesFromApiAlertQueries(a.Data),
+		Updated:       a.Updated,
+		NoDataState:   models.NoDataState(a.NoDataState),          // TODO there must be a validation
+		ExecErrState:  models.ExecutionErrorState(a.ExecErrState), // TODO there must be a validation
+		For:           time.Duration(a.For),
+		KeepFiringFor: time.Duration(a.KeepFiringFor),
+		Annotations:   a.Annotations,
+		Labels:        a.Labels,
+		IsPaused:      a.IsPaused,
+		DependsOn:     a.DependsOn,
+		Record:        RecordFromApiRecord(a.Record),
 	}, nil
 }
 
 // ProvisionedAlertRuleFromAlertRule converts models.AlertRule to definitions.ProvisionedAlertRule and sets provided provenance status
 func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.Provenance) definitions.ProvisionedAlertRule {
 	return definitions.ProvisionedAlertRule{
-		ID:           rule.ID,
-		UID:          rule.UID,
-		OrgID:        rule.OrgID,
-		FolderUID:    rule.NamespaceUID,
-		RuleGroup:    rule.RuleGroup,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         ApiAlertQueriesFromAlertQueries(rule.Data),
-		Updated:      rule.Updated,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
-		IsPaused:     rule.IsPaused,
+		ID:            rule.ID,
+		UID:           rule.UID,
+		OrgID:         rule.OrgID,
+		FolderUID:     rule.NamespaceUID,
+		RuleGroup:     rule.RuleGroup,
+		Title:         rule.Title,
+		For:           model.Duration(rule.For),
+		KeepFiringFor: model.Duration(rule.KeepFiringFor),
+		Condition:     rule.Condition,
+		Data:          ApiAlertQueriesFromAlertQueries(rule.Data),
+		Updated:       rule.Updated,
+		NoDataState:   definitions.NoDataState(rule.NoDataState),          // TODO there may be a validation
+		ExecErrState:  definitions.ExecutionErrorState(rule.ExecErrState), // TODO there may be a validation
+		Annotations:   rule.Annotations,
+		Labels:        rule.Labels,
+		Provenance:    definitions.Provenance(provenance), // TODO validate enum conversion?
+		IsPaused:      rule.IsPaused,
+		DependsOn:     rule.DependsOn,
+		Record:        ApiRecordFromRecord(rule.Record),
 	}
 }
 
@@ -62,6 +73,30 @@ func ProvisionedAlertRuleFromAlertRules(rules []*models.AlertRule) definitions.P
 	return result
 }
 
+// RecordFromApiRecord converts definitions.Record to models.Record
+func RecordFromApiRecord(r *definitions.Record) *models.Record {
+	if r == nil {
+		return nil
+	}
+	return &models.Record{
+		Metric:              r.Metric,
+		From:                r.From,
+		TargetDatasourceUID: r.TargetDatasourceUID,
+	}
+}
+
+// ApiRecordFromRecord converts models.Record to definitions.Record
+func ApiRecordFromRecord(r *models.Record) *definitions.Record {
+	if r == nil {
+		return nil
+	}
+	return &definitions.Record{
+		Metric:              r.Metric,
+		From:                r.From,
+		TargetDatasourceUID: r.TargetDatasourceUID,
+	}
+}
+
 // AlertQueriesFromApiAlertQueries converts a collection of definitions.AlertQuery to collection of models.AlertQuery
 func AlertQueriesFromApiAlertQueries(queries []definitions.AlertQuery) []models.AlertQuery {
 	result := make([]models.AlertQuery, 0, len(queries))
@@ -151,11 +186,12 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle(d models.AlertRuleGro
 		rules = append(rules, alert)
 	}
 	return definitions.AlertRuleGroupExport{
-		OrgID:    d.OrgID,
-		Name:     d.Title,
-		Folder:   d.FolderTitle,
-		Interval: model.Duration(time.Duration(d.Interval) * time.Second),
-		Rules:    rules,
+		OrgID:     d.OrgID,
+		Name:      d.Title,
+		Folder:    d.FolderTitle,
+		FolderUID: d.FolderUID,
+		Interval:  model.Duration(time.Duration(d.Interval) * time.Second),
+		Rules:     rules,
 	}, nil
 }
 
@@ -181,18 +217,21 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 	}
 
 	return definitions.AlertRuleExport{
-		UID:          rule.UID,
-		Title:        rule.Title,
-		For:          model.Duration(rule.For),
-		Condition:    rule.Condition,
-		Data:         data,
-		DashboardUID: dashboardUID,
-		PanelID:      panelID,
-		NoDataState:  definitions.NoDataState(rule.NoDataState),
-		ExecErrState: definitions.ExecutionErrorState(rule.ExecErrState),
-		Annotations:  rule.Annotations,
-		Labels:       rule.Labels,
-		IsPaused:     rule.IsPaused,
+		UID:           rule.UID,
+		Title:         rule.Title,
+		For:           model.Duration(rule.For),
+		KeepFiringFor: model.Duration(rule.KeepFiringFor),
+		Condition:     rule.Condition,
+		Data:          data,
+		DashboardUID:  dashboardUID,
+		PanelID:       panelID,
+		NoDataState:   definitions.NoDataState(rule.NoDataState),
+		ExecErrState:  definitions.ExecutionErrorState(rule.ExecErrState),
+		Annotations:   rule.Annotations,
+		Labels:        rule.Labels,
+		IsPaused:      rule.IsPaused,
+		DependsOn:     rule.DependsOn,
+		Record:        ApiRecordFromRecord(rule.Record),
 	}, nil
 }
 
@@ -298,3 +337,18 @@ func RouteExportFromRoute(route *definitions.Route) *definitions.RouteExport {
 
 	return &export
 }
+
+// AlertingFileExportFromMuteTimings creates a definitions.AlertingFileExport DTO from []definitions.MuteTimeInterval.
+func AlertingFileExportFromMuteTimings(orgID int64, m []definitions.MuteTimeInterval) definitions.AlertingFileExport {
+	f := definitions.AlertingFileExport{
+		APIVersion:  1,
+		MuteTimings: make([]definitions.MuteTimeIntervalExport, 0, len(m)),
+	}
+	for _, mi := range m {
+		f.MuteTimings = append(f.MuteTimings, definitions.MuteTimeIntervalExport{
+			OrgID:            orgID,
+			MuteTimeInterval: mi.MuteTimeInterval,
+		})
+	}
+	return f
+}
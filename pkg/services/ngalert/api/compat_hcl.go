@@ -0,0 +1,353 @@
+package api
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// Names of the resources of the Grafana Terraform provider.
+const (
+	terraformRuleGroupResource          = "grafana_rule_group"
+	terraformContactPointResource       = "grafana_contact_point"
+	terraformNotificationPolicyResource = "grafana_notification_policy"
+	terraformMuteTimingResource         = "grafana_mute_timing"
+)
+
+// terraformIntegrations maps the types of the integrations to the blocks of the grafana_contact_point resource and the
+// keys of their settings to the attributes of the blocks. The settings that do not have an attribute are put in the
+// settings attribute of the block.
+var terraformIntegrations = map[string]struct {
+	block      string
+	attributes map[string]string
+}{
+	"prometheus-alertmanager": {"alertmanager", map[string]string{"url": "url", "basicAuthUser": "basic_auth_user", "basicAuthPassword": "basic_auth_password"}},
+	"dingding":                {"dingding", map[string]string{"url": "url", "msgType": "message_type", "title": "title", "message": "message"}},
+	"discord":                 {"discord", map[string]string{"url": "url", "title": "title", "message": "message", "avatar_url": "avatar_url", "use_discord_username": "use_discord_username"}},
+	"email":                   {"email", map[string]string{"addresses": "addresses", "singleEmail": "single_email", "subject": "subject", "message": "message"}},
+	"googlechat":              {"googlechat", map[string]string{"url": "url", "title": "title", "message": "message"}},
+	"kafka":                   {"kafka", map[string]string{"kafkaRestProxy": "rest_proxy_url", "kafkaTopic": "topic", "description": "description", "details": "details", "username": "username", "password": "password", "apiVersion": "api_version", "kafkaClusterId": "cluster_id"}},
+	"line":                    {"line", map[string]string{"token": "token", "title": "title", "description": "description"}},
+	"opsgenie":                {"opsgenie", map[string]string{"apiUrl": "url", "apiKey": "api_key", "message": "message", "description": "description", "autoClose": "auto_close", "overridePriority": "override_priority", "sendTagsAs": "send_tags_as"}},
+	"pagerduty":               {"pagerduty", map[string]string{"integrationKey": "integration_key", "severity": "severity", "class": "class", "component": "component", "group": "group", "summary": "summary", "source": "source", "client": "client", "client_url": "client_url", "details": "details"}},
+	"pushover":                {"pushover", map[string]string{"userKey": "user_key", "apiToken": "api_token", "priority": "priority", "okPriority": "ok_priority", "retry": "retry", "expire": "expire", "device": "device", "sound": "sound", "okSound": "ok_sound", "title": "title", "message": "message", "uploadImage": "upload_image"}},
+	"sensugo":                 {"sensugo", map[string]string{"url": "url", "apikey": "api_key", "entity": "entity", "check": "check", "namespace": "namespace", "handler": "handler", "message": "message"}},
+	"slack":                   {"slack", map[string]string{"endpointUrl": "endpoint_url", "url": "url", "token": "token", "recipient": "recipient", "text": "text", "title": "title", "username": "username", "icon_emoji": "icon_emoji", "icon_url": "icon_url", "mentionChannel": "mention_channel", "mentionUsers": "mention_users", "mentionGroups": "mention_groups", "color": "color"}},
+	"teams":                   {"teams", map[string]string{"url": "url", "title": "title", "sectiontitle": "section_title", "message": "message"}},
+	"telegram":                {"telegram", map[string]string{"bottoken": "token", "chatid": "chat_id", "message": "message", "parse_mode": "parse_mode", "disable_web_page_preview": "disable_web_page_preview", "protect_content": "protect_content", "disable_notifications": "disable_notifications"}},
+	"threema":                 {"threema", map[string]string{"gateway_id": "gateway_id", "recipient_id": "recipient_id", "api_secret": "api_secret", "title": "title", "description": "description"}},
+	"victorops":               {"victorops", map[string]string{"url": "url", "messageType": "message_type", "title": "title", "description": "description"}},
+	"webex":                   {"webex", map[string]string{"bot_token": "token", "api_url": "api_url", "room_id": "room_id", "message": "message"}},
+	"webhook":                 {"webhook", map[string]string{"url": "url", "httpMethod": "http_method", "username": "basic_auth_user", "password": "basic_auth_password", "authorization_scheme": "authorization_scheme", "authorization_credentials": "authorization_credentials", "maxAlerts": "max_alerts", "title": "title", "message": "message"}},
+	"wecom":                   {"wecom", map[string]string{"url": "url", "secret": "secret", "corp_id": "corp_id", "agent_id": "agent_id", "msgtype": "msg_type", "touser": "to_user", "title": "title", "message": "message"}},
+}
+
+// AlertingFileExportToHCL converts a definitions.AlertingFileExport to resources of the Grafana Terraform provider.
+func AlertingFileExportToHCL(e definitions.AlertingFileExport) ([]byte, error) {
+	var body hcl.Body
+	names := make(map[string]int)
+	resourceName := func(prefix, name string) string {
+		id := hcl.Identifier(prefix + "_" + name)
+		names[id]++
+		if n := names[id]; n > 1 {
+			id = fmt.Sprintf("%s_%d", id, n)
+		}
+		return id
+	}
+
+	for _, group := range e.Groups {
+		if err := ruleGroupToHCL(body.AppendBlock("resource", terraformRuleGroupResource, resourceName("rule_group", group.Folder+"_"+group.Name)), group); err != nil {
+			return nil, fmt.Errorf("rule group %s: %w", group.Name, err)
+		}
+	}
+	for _, cp := range e.ContactPoints {
+		if err := contactPointToHCL(body.AppendBlock("resource", terraformContactPointResource, resourceName("contact_point", cp.Name)), cp); err != nil {
+			return nil, fmt.Errorf("contact point %s: %w", cp.Name, err)
+		}
+	}
+	for _, policy := range e.Policies {
+		if policy.Policy == nil {
+			continue
+		}
+		b := body.AppendBlock("resource", terraformNotificationPolicyResource, resourceName("notification_policy", strconv.FormatInt(policy.OrgID, 10)))
+		routeToHCL(b, policy.Policy, true)
+	}
+	for _, mt := range e.MuteTimings {
+		if err := muteTimingToHCL(body.AppendBlock("resource", terraformMuteTimingResource, resourceName("mute_timing", mt.Name)), mt); err != nil {
+			return nil, fmt.Errorf("mute timing %s: %w", mt.Name, err)
+		}
+	}
+	return body.Bytes()
+}
+
+func ruleGroupToHCL(b *hcl.Body, group definitions.AlertRuleGroupExport) error {
+	b.SetAttribute("name", group.Name)
+	b.SetAttribute("folder_uid", group.FolderUID)
+	b.SetAttribute("interval_seconds", int64(time.Duration(group.Interval).Seconds()))
+	for _, rule := range group.Rules {
+		r := b.AppendBlock("rule")
+		r.SetAttribute("name", rule.Title)
+		r.SetAttribute("uid", rule.UID)
+		r.SetAttribute("condition", rule.Condition)
+		r.SetAttribute("for", rule.For.String())
+		r.SetAttribute("no_data_state", string(rule.NoDataState))
+		r.SetAttribute("exec_err_state", string(rule.ExecErrState))
+		r.SetAttribute("is_paused", rule.IsPaused)
+
+		// The provider links rules to panels with the annotations that Grafana uses for them in the API.
+		annotations := make(map[string]string, len(rule.Annotations)+2)
+		for k, v := range rule.Annotations {
+			annotations[k] = v
+		}
+		if rule.DashboardUID != "" {
+			annotations["__dashboardUid__"] = rule.DashboardUID
+		}
+		if rule.PanelID != 0 {
+			annotations["__panelId__"] = strconv.FormatInt(rule.PanelID, 10)
+		}
+		if len(annotations) > 0 {
+			r.SetAttribute("annotations", annotations)
+		}
+		if len(rule.Labels) > 0 {
+			r.SetAttribute("labels", rule.Labels)
+		}
+
+		for _, query := range rule.Data {
+			model, err := jsonValue(query.Model)
+			if err != nil {
+				return fmt.Errorf("rule %s: %w", rule.Title, err)
+			}
+			d := r.AppendBlock("data")
+			d.SetAttribute("ref_id", query.RefID)
+			if query.QueryType != "" {
+				d.SetAttribute("query_type", query.QueryType)
+			}
+			d.SetAttribute("datasource_uid", query.DatasourceUID)
+			d.SetAttribute("model", hcl.FunctionCall{Name: "jsonencode", Args: []interface{}{model}})
+			tr := d.AppendBlock("relative_time_range")
+			tr.SetAttribute("from", int64(time.Duration(query.RelativeTimeRange.From).Seconds()))
+			tr.SetAttribute("to", int64(time.Duration(query.RelativeTimeRange.To).Seconds()))
+		}
+
+		if rule.Record != nil {
+			rec := r.AppendBlock("record")
+			rec.SetAttribute("metric", rule.Record.Metric)
+			rec.SetAttribute("from", rule.Record.From)
+		}
+	}
+	return nil
+}
+
+func contactPointToHCL(b *hcl.Body, cp definitions.ContactPointExport) error {
+	b.SetAttribute("name", cp.Name)
+	for _, receiver := range cp.Receivers {
+		var settings map[string]interface{}
+		if len(receiver.Settings) > 0 {
+			if err := json.Unmarshal(receiver.Settings, &settings); err != nil {
+				return fmt.Errorf("failed to parse settings of integration %s: %w", receiver.UID, err)
+			}
+		}
+
+		// the types of some integrations, for example LINE, are not lower case
+		typ := strings.ToLower(receiver.Type)
+		integration, ok := terraformIntegrations[typ]
+		if !ok {
+			return fmt.Errorf("integration type %s is not supported by the Terraform provider", receiver.Type)
+		}
+		r := b.AppendBlock(integration.block)
+		r.SetAttribute("uid", receiver.UID)
+		r.SetAttribute("disable_resolve_message", receiver.DisableResolveMessage)
+
+		keys := make([]string, 0, len(settings))
+		for k := range settings {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		other := make(map[string]string)
+		for _, k := range keys {
+			value := settings[k]
+			attr, ok := integration.attributes[k]
+			if !ok {
+				other[k] = settingToString(value)
+				continue
+			}
+			if typ == "email" && attr == "addresses" {
+				if s, ok := value.(string); ok {
+					value = util.SplitEmails(s)
+				}
+			}
+			r.SetAttribute(attr, value)
+		}
+		if len(other) > 0 {
+			r.SetAttribute("settings", other)
+		}
+	}
+	return nil
+}
+
+func settingToString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+func routeToHCL(b *hcl.Body, route *definitions.RouteExport, root bool) {
+	if route.Receiver != "" || root {
+		b.SetAttribute("contact_point", route.Receiver)
+	}
+	if len(route.GroupByStr) > 0 || root {
+		groupBy := route.GroupByStr
+		if groupBy == nil {
+			groupBy = []string{}
+		}
+		b.SetAttribute("group_by", groupBy)
+	}
+	if !root {
+		if route.Continue {
+			b.SetAttribute("continue", true)
+		}
+		if len(route.MuteTimeIntervals) > 0 {
+			b.SetAttribute("mute_timings", route.MuteTimeIntervals)
+		}
+	}
+	if route.GroupWait != nil {
+		b.SetAttribute("group_wait", route.GroupWait.String())
+	}
+	if route.GroupInterval != nil {
+		b.SetAttribute("group_interval", route.GroupInterval.String())
+	}
+	if route.RepeatInterval != nil {
+		b.SetAttribute("repeat_interval", route.RepeatInterval.String())
+	}
+
+	if !root {
+		for _, m := range routeMatchers(route) {
+			matcher := b.AppendBlock("matcher")
+			matcher.SetAttribute("label", m.Name)
+			matcher.SetAttribute("match", m.Type.String())
+			matcher.SetAttribute("value", m.Value)
+		}
+	}
+	for _, r := range route.Routes {
+		routeToHCL(b.AppendBlock("policy"), r, false)
+	}
+}
+
+// routeMatchers returns all matchers of the route, including the deprecated ones.
+func routeMatchers(route *definitions.RouteExport) []labels.Matcher {
+	var result []labels.Matcher
+	for _, k := range sortedKeys(route.Match) {
+		result = append(result, labels.Matcher{Type: labels.MatchEqual, Name: k, Value: route.Match[k]})
+	}
+	matchRE := make(map[string]string, len(route.MatchRE))
+	for k, v := range route.MatchRE {
+		matchRE[k] = v.String()
+	}
+	for _, k := range sortedKeys(matchRE) {
+		result = append(result, labels.Matcher{Type: labels.MatchRegexp, Name: k, Value: matchRE[k]})
+	}
+	for _, m := range route.Matchers {
+		result = append(result, *m)
+	}
+	for _, m := range route.ObjectMatchers {
+		result = append(result, *m)
+	}
+	return result
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func muteTimingToHCL(b *hcl.Body, mt definitions.MuteTimeIntervalExport) error {
+	b.SetAttribute("name", mt.Name)
+	for _, interval := range mt.TimeIntervals {
+		i := b.AppendBlock("intervals")
+		weekdays, err := textRanges(interval.Weekdays)
+		if err != nil {
+			return err
+		}
+		if len(weekdays) > 0 {
+			i.SetAttribute("weekdays", weekdays)
+		}
+		days, err := textRanges(interval.DaysOfMonth)
+		if err != nil {
+			return err
+		}
+		if len(days) > 0 {
+			i.SetAttribute("days_of_month", days)
+		}
+		months, err := textRanges(interval.Months)
+		if err != nil {
+			return err
+		}
+		if len(months) > 0 {
+			i.SetAttribute("months", months)
+		}
+		years, err := textRanges(interval.Years)
+		if err != nil {
+			return err
+		}
+		if len(years) > 0 {
+			i.SetAttribute("years", years)
+		}
+		if interval.Location != nil {
+			i.SetAttribute("location", interval.Location.String())
+		}
+		for _, tr := range interval.Times {
+			t := i.AppendBlock("times")
+			t.SetAttribute("start", fmt.Sprintf("%02d:%02d", tr.StartMinute/60, tr.StartMinute%60))
+			t.SetAttribute("end", fmt.Sprintf("%02d:%02d", tr.EndMinute/60, tr.EndMinute%60))
+		}
+	}
+	return nil
+}
+
+func textRanges[T encoding.TextMarshaler](ranges []T) ([]string, error) {
+	result := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		b, err := r.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, string(b))
+	}
+	return result, nil
+}
+
+// jsonValue converts a value to the generic representation of JSON, so that it can be written as HCL.
+func jsonValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
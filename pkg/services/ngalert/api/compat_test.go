@@ -35,3 +35,22 @@ func TestToModel(t *testing.T) {
 		require.Len(t, tm.Rules, 1)
 	})
 }
+
+func TestAlertingFileExportToHCL(t *testing.T) {
+	t.Run("integration types are matched regardless of case", func(t *testing.T) {
+		export := definitions.AlertingFileExport{
+			ContactPoints: []definitions.ContactPointExport{{
+				OrgID: 1,
+				Name:  "line",
+				Receivers: []definitions.ReceiverExport{{
+					UID:      "line-uid",
+					Type:     "LINE",
+					Settings: definitions.RawMessage(`{"token":"[REDACTED]","title":"t"}`),
+				}},
+			}},
+		}
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+		require.Equal(t, "resource \"grafana_contact_point\" \"contact_point_line\" {\n  name = \"line\"\n\n  line {\n    uid                     = \"line-uid\"\n    disable_resolve_message = false\n    title                   = \"t\"\n    token                   = \"[REDACTED]\"\n  }\n}\n", string(b))
+	})
+}
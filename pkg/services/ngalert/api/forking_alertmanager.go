@@ -182,10 +182,18 @@ func (f *AlertmanagerApiHandler) handleRoutePostGrafanaAlertingConfig(ctx *conte
 	return f.GrafanaSvc.RoutePostAlertingConfig(ctx, conf)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceiverDeliveries(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.GrafanaSvc.RouteGetReceiverDeliveries(ctx, name)
+}
+
 func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceivers(ctx *contextmodel.ReqContext) response.Response {
 	return f.GrafanaSvc.RouteGetReceivers(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaTemplatePreview(ctx *contextmodel.ReqContext, conf apimodels.TemplatePreviewBodyParams) response.Response {
+	return f.GrafanaSvc.RoutePostTemplatePreview(ctx, conf)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *contextmodel.ReqContext, conf apimodels.TestReceiversConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
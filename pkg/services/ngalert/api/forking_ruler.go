@@ -101,6 +101,22 @@ func (f *RulerApiHandler) handleRoutePostNameGrafanaRulesConfig(ctx *contextmode
 	return f.GrafanaRuler.RoutePostNameRulesConfig(ctx, conf, namespace)
 }
 
+func (f *RulerApiHandler) handleRoutePostBulkRuleOperation(ctx *contextmodel.ReqContext, conf apimodels.PostableBulkRuleOperation) response.Response {
+	return f.GrafanaRuler.RoutePostBulkRuleOperation(ctx, conf)
+}
+
+func (f *RulerApiHandler) handleRoutePostPrometheusRulesImport(ctx *contextmodel.ReqContext, conf apimodels.PostablePrometheusRulesImport, namespace string) response.Response {
+	return f.GrafanaRuler.RoutePostPrometheusRulesImport(ctx, conf, namespace)
+}
+
+func (f *RulerApiHandler) handleRoutePostRuleGroupLint(ctx *contextmodel.ReqContext, conf apimodels.PostableRuleGroupConfig, namespace string) response.Response {
+	payloadType := conf.Type()
+	if payloadType != apimodels.GrafanaBackend {
+		return errorToResponse(backendTypeDoesNotMatchPayloadTypeError(apimodels.GrafanaBackend, conf.Type().String()))
+	}
+	return f.GrafanaRuler.RoutePostRuleGroupLint(ctx, conf, namespace)
+}
+
 func (f *RulerApiHandler) getService(ctx *contextmodel.ReqContext) (*LotexRuler, error) {
 	_, err := getDatasourceByUID(ctx, f.DatasourceCache, apimodels.LoTexRulerBackend)
 	if err != nil {
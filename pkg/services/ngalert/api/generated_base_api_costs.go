@@ -0,0 +1,40 @@
+/*Package api contains base API implementation of unified alerting
+ *
+ *Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ *
+ *Do not manually edit these files, please find ngalert/api/swagger-codegen/ for commands on how to generate them.
+ */
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/middleware"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+type CostsApi interface {
+	RouteGetRuleEvaluationCosts(*contextmodel.ReqContext) response.Response
+}
+
+func (f *CostsApiHandler) RouteGetRuleEvaluationCosts(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetRuleEvaluationCosts(ctx)
+}
+
+func (api *API) RegisterCostsApiEndpoints(srv CostsApi, m *metrics.API) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/v1/rules/costs"),
+			api.authorize(http.MethodGet, "/api/v1/rules/costs"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/rules/costs",
+				api.Hooks.Wrap(srv.RouteGetRuleEvaluationCosts),
+				m,
+			),
+		)
+	}, middleware.ReqSignedIn)
+}
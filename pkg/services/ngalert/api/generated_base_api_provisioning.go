@@ -21,6 +21,7 @@ import (
 type ProvisioningApi interface {
 	RouteDeleteAlertRule(*contextmodel.ReqContext) response.Response
 	RouteDeleteContactpoints(*contextmodel.ReqContext) response.Response
+	RouteDeleteMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RouteDeleteMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteDeleteTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRule(*contextmodel.ReqContext) response.Response
@@ -31,18 +32,23 @@ type ProvisioningApi interface {
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
 	RouteGetContactpointsExport(*contextmodel.ReqContext) response.Response
+	RouteGetMaintenanceWindow(*contextmodel.ReqContext) response.Response
+	RouteGetMaintenanceWindows(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTiming(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
+	RouteGetMuteTimingsExport(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTree(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTreeExport(*contextmodel.ReqContext) response.Response
 	RouteGetTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetTemplates(*contextmodel.ReqContext) response.Response
 	RoutePostAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePostContactpoints(*contextmodel.ReqContext) response.Response
+	RoutePostMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RoutePostMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*contextmodel.ReqContext) response.Response
 	RoutePutContactpoint(*contextmodel.ReqContext) response.Response
+	RoutePutMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RoutePutMuteTiming(*contextmodel.ReqContext) response.Response
 	RoutePutPolicyTree(*contextmodel.ReqContext) response.Response
 	RoutePutTemplate(*contextmodel.ReqContext) response.Response
@@ -59,6 +65,11 @@ func (f *ProvisioningApiHandler) RouteDeleteContactpoints(ctx *contextmodel.ReqC
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	return f.handleRouteDeleteContactpoints(ctx, uIDParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteMaintenanceWindow(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -103,6 +114,14 @@ func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *contextmodel.ReqCont
 func (f *ProvisioningApiHandler) RouteGetContactpointsExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpointsExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetMaintenanceWindow(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetMaintenanceWindows(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -111,6 +130,9 @@ func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *contextmodel.ReqContext
 func (f *ProvisioningApiHandler) RouteGetMuteTimings(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetMuteTimings(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetMuteTimingsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetMuteTimingsExport(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetPolicyTree(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTree(ctx)
 }
@@ -141,6 +163,14 @@ func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePostContactpoints(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.MaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostMaintenanceWindow(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.MuteTimeInterval{}
@@ -180,6 +210,16 @@ func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqConte
 	}
 	return f.handleRoutePutContactpoint(ctx, conf, uIDParam)
 }
+func (f *ProvisioningApiHandler) RoutePutMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.MaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutMaintenanceWindow(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -234,6 +274,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows/{UID}"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/maintenance-windows/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteMaintenanceWindow),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			api.authorize(http.MethodDelete, "/api/v1/provisioning/mute-timings/{name}"),
@@ -334,6 +384,26 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows/{UID}"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/maintenance-windows/{UID}",
+				api.Hooks.Wrap(srv.RouteGetMaintenanceWindow),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/maintenance-windows",
+				api.Hooks.Wrap(srv.RouteGetMaintenanceWindows),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/{name}"),
@@ -354,6 +424,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings/export"),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings/export",
+				api.Hooks.Wrap(srv.RouteGetMuteTimingsExport),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/policies"),
 			api.authorize(http.MethodGet, "/api/v1/provisioning/policies"),
@@ -414,6 +494,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows"),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/maintenance-windows",
+				api.Hooks.Wrap(srv.RoutePostMaintenanceWindow),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/mute-timings"),
 			api.authorize(http.MethodPost, "/api/v1/provisioning/mute-timings"),
@@ -454,6 +544,16 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows/{UID}"),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/maintenance-windows/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/maintenance-windows/{UID}",
+				api.Hooks.Wrap(srv.RoutePutMaintenanceWindow),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			api.authorize(http.MethodPut, "/api/v1/provisioning/mute-timings/{name}"),
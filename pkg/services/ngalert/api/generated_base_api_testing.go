@@ -19,12 +19,21 @@ import (
 )
 
 type TestingApi interface {
+	BacktestCompareConfig(*contextmodel.ReqContext) response.Response
 	BacktestConfig(*contextmodel.ReqContext) response.Response
 	RouteEvalQueries(*contextmodel.ReqContext) response.Response
 	RouteTestRuleConfig(*contextmodel.ReqContext) response.Response
 	RouteTestRuleGrafanaConfig(*contextmodel.ReqContext) response.Response
 }
 
+func (f *TestingApiHandler) BacktestCompareConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.BacktestCompareConfig{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleBacktestCompareConfig(ctx, conf)
+}
 func (f *TestingApiHandler) BacktestConfig(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.BacktestConfig{}
@@ -62,6 +71,16 @@ func (f *TestingApiHandler) RouteTestRuleGrafanaConfig(ctx *contextmodel.ReqCont
 
 func (api *API) RegisterTestingApiEndpoints(srv TestingApi, m *metrics.API) {
 	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Post(
+			toMacaronPath("/api/v1/rule/backtest/compare"),
+			api.authorize(http.MethodPost, "/api/v1/rule/backtest/compare"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/rule/backtest/compare",
+				api.Hooks.Wrap(srv.BacktestCompareConfig),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/rule/backtest"),
 			api.authorize(http.MethodPost, "/api/v1/rule/backtest"),
@@ -0,0 +1,313 @@
+// Package hcl writes HCL documents, such as Terraform configurations. It supports the subset of the
+// HCL syntax that is needed to describe resources: attributes with literal values, nested blocks
+// and function calls. The output is formatted the same way as by terraform fmt.
+package hcl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const indentation = "  "
+
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// FunctionCall is a value that is the result of calling a function with the arguments, for example jsonencode.
+type FunctionCall struct {
+	Name string
+	Args []interface{}
+}
+
+// Body is a list of attributes and blocks. The items are written in the order they were added.
+type Body struct {
+	items []item
+}
+
+type item struct {
+	// name and value are set for attributes.
+	name  string
+	value interface{}
+	// block is set for blocks.
+	block *Block
+}
+
+// Block is a block of a body, for example a resource of a Terraform configuration.
+type Block struct {
+	Type   string
+	Labels []string
+	Body   Body
+}
+
+// SetAttribute adds an attribute to the body. The value can be nil, a string, a bool, a number,
+// a FunctionCall, a slice or a map with string keys of any of these.
+func (b *Body) SetAttribute(name string, value interface{}) {
+	b.items = append(b.items, item{name: name, value: value})
+}
+
+// AppendBlock adds a block to the body and returns the body of the block.
+func (b *Body) AppendBlock(blockType string, labels ...string) *Body {
+	block := &Block{Type: blockType, Labels: labels}
+	b.items = append(b.items, item{block: block})
+	return &block.Body
+}
+
+// Bytes returns the body as an HCL document.
+func (b *Body) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := b.write(&buf, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *Body) write(buf *bytes.Buffer, depth int) error {
+	indent := strings.Repeat(indentation, depth)
+	for i := 0; i < len(b.items); {
+		if b.items[i].block != nil {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			if err := b.items[i].block.write(buf, depth); err != nil {
+				return err
+			}
+			i++
+			continue
+		}
+
+		// Consecutive attributes are aligned on the equals sign. A multi-line value ends the alignment group.
+		var names, values []string
+		for i < len(b.items) && b.items[i].block == nil {
+			value, err := formatValue(b.items[i].value, depth)
+			if err != nil {
+				return fmt.Errorf("attribute %s: %w", b.items[i].name, err)
+			}
+			names = append(names, b.items[i].name)
+			values = append(values, value)
+			i++
+			if strings.Contains(value, "\n") {
+				break
+			}
+		}
+		writeAttributes(buf, indent, names, values)
+	}
+	return nil
+}
+
+func writeAttributes(buf *bytes.Buffer, indent string, names, values []string) {
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+	for i := range names {
+		fmt.Fprintf(buf, "%s%-*s = %s\n", indent, width, names[i], values[i])
+	}
+}
+
+func (b *Block) write(buf *bytes.Buffer, depth int) error {
+	indent := strings.Repeat(indentation, depth)
+	buf.WriteString(indent)
+	buf.WriteString(b.Type)
+	for _, label := range b.Labels {
+		buf.WriteString(" ")
+		buf.WriteString(quote(label))
+	}
+	buf.WriteString(" {\n")
+	if err := b.Body.write(buf, depth+1); err != nil {
+		return err
+	}
+	buf.WriteString(indent)
+	buf.WriteString("}\n")
+	return nil
+}
+
+// formatValue returns the HCL expression of the value. Lines after the first one are indented with depth.
+func formatValue(value interface{}, depth int) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "null", nil
+	case string:
+		return quote(v), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	case FunctionCall:
+		args := make([]string, 0, len(v.Args))
+		for _, arg := range v.Args {
+			s, err := formatValue(arg, depth)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, s)
+		}
+		return fmt.Sprintf("%s(%s)", v.Name, strings.Join(args, ", ")), nil
+	case []string:
+		list := make([]interface{}, 0, len(v))
+		for _, s := range v {
+			list = append(list, s)
+		}
+		return formatList(list, depth)
+	case []interface{}:
+		return formatList(v, depth)
+	case map[string]string:
+		m := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			m[k] = s
+		}
+		return formatObject(m, depth)
+	case map[string]interface{}:
+		return formatObject(v, depth)
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", value)
+	}
+}
+
+func formatList(list []interface{}, depth int) (string, error) {
+	if len(list) == 0 {
+		return "[]", nil
+	}
+	multiline := false
+	for _, elem := range list {
+		switch elem.(type) {
+		case []interface{}, []string, map[string]interface{}, map[string]string:
+			multiline = true
+		}
+	}
+	if !multiline {
+		elems := make([]string, 0, len(list))
+		for _, elem := range list {
+			s, err := formatValue(elem, depth)
+			if err != nil {
+				return "", err
+			}
+			elems = append(elems, s)
+		}
+		return "[" + strings.Join(elems, ", ") + "]", nil
+	}
+
+	indent := strings.Repeat(indentation, depth+1)
+	var buf strings.Builder
+	buf.WriteString("[\n")
+	for _, elem := range list {
+		s, err := formatValue(elem, depth+1)
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(indent)
+		buf.WriteString(s)
+		buf.WriteString(",\n")
+	}
+	buf.WriteString(strings.Repeat(indentation, depth))
+	buf.WriteString("]")
+	return buf.String(), nil
+}
+
+func formatObject(m map[string]interface{}, depth int) (string, error) {
+	if len(m) == 0 {
+		return "{}", nil
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	indent := strings.Repeat(indentation, depth+1)
+	var names, values []string
+	for _, k := range keys {
+		value, err := formatValue(m[k], depth+1)
+		if err != nil {
+			return "", fmt.Errorf("key %s: %w", k, err)
+		}
+		name := k
+		if !identifierRe.MatchString(k) || k == "null" || k == "true" || k == "false" {
+			name = quote(k)
+		}
+		names = append(names, name)
+		values = append(values, value)
+		if strings.Contains(value, "\n") {
+			writeAttributes(&buf, indent, names, values)
+			names, values = nil, nil
+		}
+	}
+	writeAttributes(&buf, indent, names, values)
+	buf.WriteString(strings.Repeat(indentation, depth))
+	buf.WriteString("}")
+	return buf.String(), nil
+}
+
+// quote returns the string as a quoted HCL template that evaluates to the string itself.
+// Template interpolations and directives are escaped.
+func quote(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for i, r := range s {
+		switch r {
+		case '\\':
+			buf.WriteString(`\\`)
+		case '"':
+			buf.WriteString(`\"`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		case '$', '%':
+			buf.WriteRune(r)
+			if i+1 < len(s) && s[i+1] == '{' {
+				buf.WriteRune(r)
+			}
+		default:
+			if !unicode.IsPrint(r) {
+				if r > 0xFFFF {
+					fmt.Fprintf(&buf, `\U%08x`, r)
+				} else {
+					fmt.Fprintf(&buf, `\u%04x`, r)
+				}
+				continue
+			}
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
+
+// Identifier returns a valid identifier, for example a name of a Terraform resource, that is derived from the string.
+func Identifier(s string) string {
+	var buf strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(s) {
+		if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			buf.WriteRune(r)
+			underscore = false
+			continue
+		}
+		if !underscore && buf.Len() > 0 {
+			buf.WriteByte('_')
+			underscore = true
+		}
+	}
+	id := strings.TrimSuffix(buf.String(), "_")
+	if id == "" || !unicode.IsLetter(rune(id[0])) {
+		id = "_" + id
+	}
+	return id
+}
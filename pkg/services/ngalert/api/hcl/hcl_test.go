@@ -0,0 +1,95 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBody(t *testing.T) {
+	var body Body
+	r := body.AppendBlock("resource", "grafana_rule_group", "my_group")
+	r.SetAttribute("name", "my group")
+	r.SetAttribute("interval_seconds", int64(60))
+	r.SetAttribute("labels", map[string]string{"team": "a", "app.kubernetes.io/name": "b"})
+	r.SetAttribute("is_paused", false)
+	d := r.AppendBlock("data")
+	d.SetAttribute("model", FunctionCall{Name: "jsonencode", Args: []interface{}{map[string]interface{}{
+		"expr":   "up",
+		"params": []interface{}{float64(1), 2.5},
+		"empty":  nil,
+	}}})
+	body.AppendBlock("resource", "grafana_mute_timing", "empty")
+
+	b, err := body.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, `resource "grafana_rule_group" "my_group" {
+  name             = "my group"
+  interval_seconds = 60
+  labels           = {
+    "app.kubernetes.io/name" = "b"
+    team                     = "a"
+  }
+  is_paused = false
+
+  data {
+    model = jsonencode({
+      empty  = null
+      expr   = "up"
+      params = [1, 2.5]
+    })
+  }
+}
+
+resource "grafana_mute_timing" "empty" {
+}
+`, string(b))
+}
+
+func TestBodyUnsupportedValue(t *testing.T) {
+	var body Body
+	body.SetAttribute("value", struct{}{})
+	_, err := body.Bytes()
+	require.Error(t, err)
+}
+
+func TestQuote(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected string
+	}{
+		{in: "", expected: `""`},
+		{in: `say "hi"`, expected: `"say \"hi\""`},
+		{in: "line\nbreak\ttab", expected: `"line\nbreak\ttab"`},
+		{in: `C:\path`, expected: `"C:\\path"`},
+		{in: "{{ $labels.instance }}", expected: `"{{ $labels.instance }}"`},
+		{in: "${var} and %{if}", expected: `"$${var} and %%{if}"`},
+		{in: "100% $", expected: `"100% $"`},
+		{in: "bell\a", expected: `"bell\u0007"`},
+		{in: "tag\U000E0041", expected: `"tag\U000e0041"`},
+		{in: "emoji 😀", expected: `"emoji 😀"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			require.Equal(t, tc.expected, quote(tc.in))
+		})
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected string
+	}{
+		{in: "my-contact point", expected: "my_contact_point"},
+		{in: "Ops: critical!", expected: "ops_critical"},
+		{in: "123", expected: "_123"},
+		{in: "", expected: "_"},
+		{in: "Zürich", expected: "z_rich"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			require.Equal(t, tc.expected, Identifier(tc.in))
+		})
+	}
+}
@@ -72,6 +72,10 @@ func (f *ProvisioningApiHandler) handleRouteGetMuteTimings(ctx *contextmodel.Req
 	return f.svc.RouteGetMuteTimings(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetMuteTimingsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMuteTimingsExport(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostMuteTiming(ctx *contextmodel.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
 	return f.svc.RoutePostMuteTiming(ctx, mt)
 }
@@ -84,6 +88,26 @@ func (f *ProvisioningApiHandler) handleRouteDeleteMuteTiming(ctx *contextmodel.R
 	return f.svc.RouteDeleteMuteTiming(ctx, name)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMaintenanceWindows(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMaintenanceWindow(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetMaintenanceWindow(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostMaintenanceWindow(ctx *contextmodel.ReqContext, mw apimodels.MaintenanceWindow) response.Response {
+	return f.svc.RoutePostMaintenanceWindow(ctx, mw)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutMaintenanceWindow(ctx *contextmodel.ReqContext, mw apimodels.MaintenanceWindow, uid string) response.Response {
+	return f.svc.RoutePutMaintenanceWindow(ctx, mw, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteMaintenanceWindow(ctx, uid)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetAlertRules(ctx)
 }
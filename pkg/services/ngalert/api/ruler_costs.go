@@ -0,0 +1,20 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+)
+
+type CostsApiHandler struct {
+	svc *EvaluationCostSrv
+}
+
+func NewEvaluationCostsApi(svc *EvaluationCostSrv) *CostsApiHandler {
+	return &CostsApiHandler{
+		svc: svc,
+	}
+}
+
+func (f *CostsApiHandler) handleRouteGetRuleEvaluationCosts(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetRuleEvaluationCosts(ctx)
+}
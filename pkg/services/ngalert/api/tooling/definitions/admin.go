@@ -1,7 +1,7 @@
 package definitions
 
 import (
-	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"time"
 )
 
 // swagger:route GET /api/v1/ngalert configuration RouteGetStatus
@@ -74,20 +74,55 @@ const (
 	HandleGrafanaManagedAlerts                     = "handleGrafanaManagedAlerts"
 )
 
+// LintRuleAction is what happens when linting an alert rule finds a warning.
+// swagger:enum LintRuleAction
+type LintRuleAction string
+
+const (
+	LintRuleActionWarn   LintRuleAction = "warn"
+	LintRuleActionBlock  LintRuleAction = "block"
+	LintRuleActionIgnore LintRuleAction = "ignore"
+)
+
 // swagger:model
 type PostableNGalertConfig struct {
 	AlertmanagersChoice AlertmanagersChoice `json:"alertmanagersChoice"`
+	// LintRules configure the action for warnings found by linting alert rules, by the code of the warning.
+	// Warnings with codes that are not configured are reported without blocking saves.
+	// Blocking warnings reject rules saved through the ruler and provisioning APIs, but not rules provisioned from files.
+	// If no value is sent, the lint rules are not changed.
+	// example: {"missing-runbook-url": "block", "reduce-instant-query": "ignore"}
+	LintRules map[string]LintRuleAction `json:"lintRules,omitempty"`
 }
 
 // swagger:model
 type GettableNGalertConfig struct {
-	AlertmanagersChoice AlertmanagersChoice `json:"alertmanagersChoice"`
+	AlertmanagersChoice AlertmanagersChoice       `json:"alertmanagersChoice"`
+	LintRules           map[string]LintRuleAction `json:"lintRules,omitempty"`
 }
 
 // swagger:model
 type GettableAlertmanagers struct {
-	Status string                 `json:"status"`
-	Data   v1.AlertManagersResult `json:"data"`
+	Status string              `json:"status"`
+	Data   AlertmanagersResult `json:"data"`
+}
+
+// swagger:model
+type AlertmanagersResult struct {
+	Active  []AlertmanagerStatus `json:"activeAlertManagers"`
+	Dropped []AlertmanagerStatus `json:"droppedAlertManagers"`
+}
+
+// AlertmanagerStatus is the health of an external Alertmanager and the number of alerts waiting to be retried.
+// swagger:model
+type AlertmanagerStatus struct {
+	URL string `json:"url"`
+	// Health is "up" if the last attempt to send alerts succeeded, "down" if it failed, and "unknown" if no alerts were sent yet.
+	Health        string     `json:"health,omitempty"`
+	LastError     string     `json:"lastError,omitempty"`
+	LastErrorAt   *time.Time `json:"lastErrorAt,omitempty"`
+	LastSuccessAt *time.Time `json:"lastSuccessAt,omitempty"`
+	QueuedAlerts  int        `json:"queuedAlerts"`
 }
 
 // swagger:model
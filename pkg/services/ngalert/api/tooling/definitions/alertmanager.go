@@ -154,6 +154,14 @@ import (
 //     Responses:
 //       200: receiversResponse
 
+// swagger:route GET /api/alertmanager/grafana/config/api/v1/receivers/{Name}/deliveries alertmanager RouteGetGrafanaReceiverDeliveries
+//
+// Get the most recent attempts of a contact point to send notifications
+//
+//     Responses:
+//       200: receiverDeliveriesResponse
+//       400: ValidationError
+
 // swagger:route POST /api/alertmanager/grafana/config/api/v1/receivers/test alertmanager RoutePostTestGrafanaReceivers
 //
 // Test Grafana managed receivers without saving them.
@@ -181,6 +189,20 @@ import (
 //       403: PermissionDenied
 //       409: AlertManagerNotReady
 
+// swagger:route POST /api/alertmanager/grafana/config/api/v1/templates/preview alertmanager RoutePostGrafanaTemplatePreview
+//
+// Preview the notifications of each integration type rendered with Grafana managed templates without saving them.
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//
+//       200: TemplatePreviewResults
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: NotFound
+//       409: AlertManagerNotReady
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -360,6 +382,75 @@ const (
 	ExecutionError  TemplateErrorKind = "execution_error"
 )
 
+// swagger:parameters RoutePostGrafanaTemplatePreview
+type TemplatePreviewParams struct {
+	// in:body
+	Body TemplatePreviewBodyParams
+}
+
+type TemplatePreviewBodyParams struct {
+	// Template string to preview.
+	Template string `json:"template"`
+
+	// Name of the template file.
+	Name string `json:"name"`
+
+	// Alerts to use as data when rendering the notifications. Cannot be used together with RuleUID.
+	Alerts []*amv2.PostableAlert `json:"alerts,omitempty"`
+
+	// UID of the alert rule whose current alert instances are used as data when rendering the notifications.
+	RuleUID string `json:"rule_uid,omitempty"`
+
+	// Integration types to render the notifications for. All integration types are rendered if empty.
+	Integrations []string `json:"integrations,omitempty"`
+
+	// Title to render instead of the default title of each integration type.
+	Title string `json:"title,omitempty"`
+
+	// Message to render instead of the default message of each integration type.
+	Message string `json:"message,omitempty"`
+}
+
+// swagger:model
+type TemplatePreviewResults struct {
+	Previews []TemplatePreview      `json:"previews,omitempty"`
+	Errors   []TemplatePreviewError `json:"errors,omitempty"`
+}
+
+type TemplatePreview struct {
+	// Integration type, for example slack.
+	Integration string `json:"integration"`
+
+	// Rendered title of the notification. Empty if the integration type has no title.
+	Title string `json:"title,omitempty"`
+
+	// Rendered message of the notification. Empty if the integration type has no message.
+	Message string `json:"message,omitempty"`
+}
+
+type TemplatePreviewError struct {
+	// Integration type whose notification could not be rendered. Empty if the template is invalid.
+	Integration string `json:"integration,omitempty"`
+
+	// Field of the notification that could not be rendered, title or message.
+	Field string `json:"field,omitempty"`
+
+	// Kind of template error that occurred.
+	Kind TemplateErrorKind `json:"kind"`
+
+	// Name of the template file that contains the error. Empty if the error is in the title or message.
+	Template string `json:"template,omitempty"`
+
+	// Line of the error in the template file, or in the title or message.
+	Line int `json:"line,omitempty"`
+
+	// Column of the error in the line.
+	Column int `json:"column,omitempty"`
+
+	// Error message.
+	Message string `json:"message"`
+}
+
 // swagger:parameters RouteCreateSilence RouteCreateGrafanaSilence
 type CreateSilenceParams struct {
 	// in:body
@@ -490,6 +581,42 @@ type ReceiversResponse struct {
 // swagger:model integration
 type Integration = amv2.Integration
 
+// swagger:parameters RouteGetGrafanaReceiverDeliveries
+type ReceiverDeliveriesParams struct {
+	// Name of the contact point
+	// in:path
+	Name string
+	// Maximum number of delivery attempts to return. Defaults to 100.
+	// in:query
+	// required: false
+	Limit int `json:"limit"`
+}
+
+// swagger:response receiverDeliveriesResponse
+type ReceiverDeliveriesResponse struct {
+	// in:body
+	Body []NotificationDelivery
+}
+
+// NotificationDelivery is an attempt of an integration of a contact point to send a notification.
+// swagger:model
+type NotificationDelivery struct {
+	Receiver string `json:"receiver"`
+	// Integration is the type of the integration, for example "email" or "slack".
+	Integration      string `json:"integration"`
+	IntegrationIndex int    `json:"integrationIndex"`
+	GroupKey         string `json:"groupKey"`
+	// AlertFingerprints are the fingerprints of the alerts in the notification.
+	AlertFingerprints []string `json:"alertFingerprints"`
+	// Status is either "success" or "failed".
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// Retry is true if the attempt failed with an error that is retried.
+	Retry      bool      `json:"retry"`
+	DurationMs int64     `json:"durationMs"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
 // swagger:parameters RouteGetAMAlerts RouteGetAMAlertGroups RouteGetGrafanaAMAlerts RouteGetGrafanaAMAlertGroups
 type AlertsParams struct {
 
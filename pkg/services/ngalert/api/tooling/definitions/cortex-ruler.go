@@ -258,12 +258,13 @@ func (c *GettableRuleGroupConfig) validate() error {
 }
 
 type ApiRuleNode struct {
-	Record      string            `yaml:"record,omitempty" json:"record,omitempty"`
-	Alert       string            `yaml:"alert,omitempty" json:"alert,omitempty"`
-	Expr        string            `yaml:"expr" json:"expr"`
-	For         *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
-	Labels      map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
-	Annotations map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	Record        string            `yaml:"record,omitempty" json:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty" json:"alert,omitempty"`
+	Expr          string            `yaml:"expr" json:"expr"`
+	For           *model.Duration   `yaml:"for,omitempty" json:"for,omitempty"`
+	KeepFiringFor *model.Duration   `yaml:"keep_firing_for,omitempty" json:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
 }
 
 type RuleType int
@@ -373,6 +374,8 @@ type PostableGrafanaRule struct {
 	NoDataState  NoDataState         `json:"no_data_state" yaml:"no_data_state"`
 	ExecErrState ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	IsPaused     *bool               `json:"is_paused" yaml:"is_paused"`
+	DependsOn    []string            `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Record       *Record             `json:"record,omitempty" yaml:"record,omitempty"`
 }
 
 // swagger:model
@@ -393,6 +396,25 @@ type GettableGrafanaRule struct {
 	ExecErrState    ExecutionErrorState `json:"exec_err_state" yaml:"exec_err_state"`
 	Provenance      Provenance          `json:"provenance,omitempty" yaml:"provenance,omitempty"`
 	IsPaused        bool                `json:"is_paused" yaml:"is_paused"`
+	DependsOn       []string            `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Record          *Record             `json:"record,omitempty" yaml:"record,omitempty"`
+}
+
+// Record makes a rule a recording rule. A recording rule does not alert. Instead, the result of one of its queries
+// or expressions is written to a Prometheus-compatible data source as a new metric.
+// swagger:model
+type Record struct {
+	// Metric is the name of the metric the result is written as.
+	// required: true
+	// example: grafana_job:http_requests:rate5m
+	Metric string `json:"metric" yaml:"metric"`
+	// From is the RefID of the query or expression whose result is written.
+	// required: true
+	// example: A
+	From string `json:"from" yaml:"from"`
+	// TargetDatasourceUID is the UID of the data source the metric is written to.
+	// If it is empty, the data source configured for recording rules is used.
+	TargetDatasourceUID string `json:"targetDatasourceUid,omitempty" yaml:"targetDatasourceUid,omitempty"`
 }
 
 // AlertQuery represents a single query associated with an alert definition.
@@ -0,0 +1,70 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /api/ruler/grafana/api/v1/import/prometheus/{Namespace} ruler RoutePostPrometheusRulesImport
+//
+// Imports Prometheus rule groups as Grafana-managed alert rules. Existing rule groups with the same name in the namespace are replaced.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: PrometheusRulesImportResult
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RoutePostPrometheusRulesImport
+type PrometheusRulesImportParams struct {
+	// in:path
+	Namespace string
+	// in:body
+	Body PostablePrometheusRulesImport
+}
+
+// swagger:model
+type PostablePrometheusRulesImport struct {
+	// DatasourceUID is the UID of the Prometheus-compatible data source that the expressions of the rules query.
+	DatasourceUID string `yaml:"datasourceUid" json:"datasourceUid"`
+	// Groups are the rule groups in the format of Prometheus rule files.
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// PrometheusRuleFile is a Prometheus rule file.
+type PrometheusRuleFile struct {
+	Groups []PrometheusRuleGroup `yaml:"groups" json:"groups"`
+}
+
+// PrometheusRuleGroup is a rule group in the format of Prometheus rule files.
+// swagger:model
+type PrometheusRuleGroup struct {
+	Name     string         `yaml:"name" json:"name"`
+	Interval model.Duration `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Rules    []ApiRuleNode  `yaml:"rules" json:"rules"`
+}
+
+// swagger:model
+type PrometheusRulesImportResult struct {
+	// Imported are the rule groups that were imported.
+	Imported []PrometheusImportedRuleGroup `json:"imported"`
+	// Failed are the rules that could not be imported.
+	Failed []PrometheusRuleImportFailure `json:"failed"`
+}
+
+type PrometheusImportedRuleGroup struct {
+	Name string `json:"name"`
+	// Rules are the titles of the imported rules.
+	Rules []string `json:"rules"`
+}
+
+type PrometheusRuleImportFailure struct {
+	Group string `json:"group"`
+	// Rule is the name of the alerting or recording rule. It is empty if the whole group could not be imported.
+	Rule  string `json:"rule,omitempty"`
+	Error string `json:"error"`
+}
@@ -7,9 +7,10 @@ type AlertingFileExport struct {
 	Groups        []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
 	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
 	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimings   []MuteTimeIntervalExport   `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport RouteGetContactpointsExport RouteGetContactpointExport
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport RouteGetContactpointsExport RouteGetContactpointExport RouteGetPolicyTreeExport RouteGetMuteTimingsExport
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
@@ -17,7 +18,8 @@ type ExportQueryParams struct {
 	// default: false
 	Download bool `json:"download"`
 
-	// Format of the downloaded file, either yaml or json. Accept header can also be used, but the query parameter will take precedence.
+	// Format of the downloaded file, either yaml, json or hcl. Accept header can also be used, but the query parameter will take precedence.
+	// The hcl format contains resources of the Grafana Terraform provider.
 	// in: query
 	// required: false
 	// default: yaml
@@ -125,6 +125,8 @@ type ProvisionedAlertRule struct {
 	ExecErrState ExecutionErrorState `json:"execErrState"`
 	// required: true
 	For model.Duration `json:"for"`
+	// example: 5m
+	KeepFiringFor model.Duration `json:"keepFiringFor,omitempty"`
 	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
 	Annotations map[string]string `json:"annotations,omitempty"`
 	// example: {"team": "sre-team-1"}
@@ -133,6 +135,9 @@ type ProvisionedAlertRule struct {
 	Provenance Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// example: ["upstream_rule_uid"]
+	DependsOn []string `json:"dependsOn,omitempty"`
+	Record    *Record  `json:"record,omitempty"`
 }
 
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -200,27 +205,32 @@ type AlertRuleGroup struct {
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
 type AlertRuleGroupExport struct {
-	OrgID    int64             `json:"orgId" yaml:"orgId"`
-	Name     string            `json:"name" yaml:"name"`
-	Folder   string            `json:"folder" yaml:"folder"`
-	Interval model.Duration    `json:"interval" yaml:"interval"`
-	Rules    []AlertRuleExport `json:"rules" yaml:"rules"`
+	OrgID  int64  `json:"orgId" yaml:"orgId"`
+	Name   string `json:"name" yaml:"name"`
+	Folder string `json:"folder" yaml:"folder"`
+	// FolderUID is only used by the hcl format. File provisioning refers to the folder by its title.
+	FolderUID string            `json:"-" yaml:"-"`
+	Interval  model.Duration    `json:"interval" yaml:"interval"`
+	Rules     []AlertRuleExport `json:"rules" yaml:"rules"`
 }
 
 // AlertRuleExport is the provisioned file export of models.AlertRule.
 type AlertRuleExport struct {
-	UID          string              `json:"uid" yaml:"uid"`
-	Title        string              `json:"title" yaml:"title"`
-	Condition    string              `json:"condition" yaml:"condition"`
-	Data         []AlertQueryExport  `json:"data" yaml:"data"`
-	DashboardUID string              `json:"dasboardUid,omitempty" yaml:"dashboardUid,omitempty"`
-	PanelID      int64               `json:"panelId,omitempty" yaml:"panelId,omitempty"`
-	NoDataState  NoDataState         `json:"noDataState" yaml:"noDataState"`
-	ExecErrState ExecutionErrorState `json:"execErrState" yaml:"execErrState"`
-	For          model.Duration      `json:"for" yaml:"for"`
-	Annotations  map[string]string   `json:"annotations,omitempty" yaml:"annotations,omitempty"`
-	Labels       map[string]string   `json:"labels,omitempty" yaml:"labels,omitempty"`
-	IsPaused     bool                `json:"isPaused" yaml:"isPaused"`
+	UID           string              `json:"uid" yaml:"uid"`
+	Title         string              `json:"title" yaml:"title"`
+	Condition     string              `json:"condition" yaml:"condition"`
+	Data          []AlertQueryExport  `json:"data" yaml:"data"`
+	DashboardUID  string              `json:"dasboardUid,omitempty" yaml:"dashboardUid,omitempty"`
+	PanelID       int64               `json:"panelId,omitempty" yaml:"panelId,omitempty"`
+	NoDataState   NoDataState         `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  ExecutionErrorState `json:"execErrState" yaml:"execErrState"`
+	For           model.Duration      `json:"for" yaml:"for"`
+	KeepFiringFor model.Duration      `json:"keepFiringFor,omitempty" yaml:"keepFiringFor,omitempty"`
+	Annotations   map[string]string   `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	Labels        map[string]string   `json:"labels,omitempty" yaml:"labels,omitempty"`
+	IsPaused      bool                `json:"isPaused" yaml:"isPaused"`
+	DependsOn     []string            `json:"dependsOn,omitempty" yaml:"dependsOn,omitempty"`
+	Record        *Record             `json:"record,omitempty" yaml:"record,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -0,0 +1,93 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route GET /api/v1/provisioning/maintenance-windows provisioning stable RouteGetMaintenanceWindows
+//
+// Get all the maintenance windows.
+//
+//     Responses:
+//       200: MaintenanceWindows
+
+// swagger:route GET /api/v1/provisioning/maintenance-windows/{UID} provisioning stable RouteGetMaintenanceWindow
+//
+// Get a maintenance window.
+//
+//     Responses:
+//       200: MaintenanceWindow
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/maintenance-windows provisioning stable RoutePostMaintenanceWindow
+//
+// Create a new maintenance window.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: MaintenanceWindow
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/maintenance-windows/{UID} provisioning stable RoutePutMaintenanceWindow
+//
+// Replace an existing maintenance window.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: MaintenanceWindow
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route DELETE /api/v1/provisioning/maintenance-windows/{UID} provisioning stable RouteDeleteMaintenanceWindow
+//
+// Delete a maintenance window.
+//
+//     Responses:
+//       204: description: The maintenance window was deleted successfully.
+
+// swagger:parameters RouteGetMaintenanceWindow RoutePutMaintenanceWindow RouteDeleteMaintenanceWindow
+type MaintenanceWindowUIDReference struct {
+	// Maintenance window UID
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostMaintenanceWindow RoutePutMaintenanceWindow
+type MaintenanceWindowPayload struct {
+	// in:body
+	Body MaintenanceWindow
+}
+
+// swagger:model
+type MaintenanceWindows []MaintenanceWindow
+
+// MaintenanceWindow is a recurring period of time during which the alerts that match the matchers are silenced.
+// When an occurrence of the window starts, Grafana creates a silence that expires when the occurrence ends.
+// swagger:model
+type MaintenanceWindow struct {
+	// example: maintenance-db
+	UID string `json:"uid" yaml:"uid"`
+	// required: true
+	// example: Weekly database maintenance
+	Title string `json:"title" yaml:"title"`
+	// A cron expression, or an RRULE accompanied by DTSTART, that defines when occurrences of the window start.
+	// required: true
+	// example: 0 2 * * SAT
+	Schedule string `json:"schedule" yaml:"schedule"`
+	// The name of the timezone in which the schedule is evaluated. Defaults to UTC.
+	// example: Europe/Berlin
+	Timezone string `json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	// The length of every occurrence of the window.
+	// required: true
+	// example: 2h
+	Duration model.Duration `json:"duration" yaml:"duration"`
+	// The UID of the folder whose alert rules the window applies to. If it is empty, the window applies to the entire organization.
+	FolderUID string `json:"folderUID,omitempty" yaml:"folderUID,omitempty"`
+	// The matchers of the alerts to silence.
+	Matchers   ObjectMatchers `json:"matchers,omitempty" yaml:"matchers,omitempty"`
+	Provenance Provenance     `json:"provenance,omitempty" yaml:"-"`
+}
@@ -0,0 +1,89 @@
+package definitions
+
+import (
+	"github.com/prometheus/common/model"
+)
+
+// swagger:route POST /api/ruler/grafana/api/v1/bulk ruler RoutePostBulkRuleOperation
+//
+// Applies an action to all Grafana-managed alert rules that match the selector. All changes are applied in a single transaction.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       202: BulkRuleOperationResult
+//       400: ValidationError
+//       401: Failure
+//       404: NotFound
+//       409: Failure
+
+// swagger:parameters RoutePostBulkRuleOperation
+type BulkRuleOperationParams struct {
+	// in:body
+	Body PostableBulkRuleOperation
+}
+
+// BulkRuleAction is the action applied to the selected alert rules.
+// swagger:enum BulkRuleAction
+type BulkRuleAction string
+
+const (
+	BulkRuleActionPause        BulkRuleAction = "pause"
+	BulkRuleActionResume       BulkRuleAction = "resume"
+	BulkRuleActionMove         BulkRuleAction = "move"
+	BulkRuleActionAddLabels    BulkRuleAction = "add_labels"
+	BulkRuleActionRemoveLabels BulkRuleAction = "remove_labels"
+	BulkRuleActionSetInterval  BulkRuleAction = "set_interval"
+)
+
+// swagger:model
+type PostableBulkRuleOperation struct {
+	// required: true
+	Selector BulkRuleSelector `json:"selector"`
+	// required: true
+	// example: pause
+	Action BulkRuleAction `json:"action"`
+	// TargetFolderUID is the UID of the folder the rules are moved to by the move action.
+	// The rules keep their rule group, and join the group with the same name if it exists in the folder.
+	TargetFolderUID string `json:"targetFolderUid,omitempty"`
+	// Labels are added to the rules by the add_labels action. Existing labels with the same name are overwritten.
+	Labels map[string]string `json:"labels,omitempty"`
+	// LabelNames are the names of the labels removed from the rules by the remove_labels action.
+	LabelNames []string `json:"labelNames,omitempty"`
+	// Interval is the evaluation interval set by the set_interval action. As all rules of a rule group are evaluated
+	// at the same interval, it is set on the entire rule groups of the selected rules.
+	// example: 1m
+	Interval model.Duration `json:"interval,omitempty"`
+}
+
+// BulkRuleSelector selects the alert rules to apply an action to. A rule is selected if it matches all the criteria
+// that are set. At least one criterion must be set.
+// swagger:model
+type BulkRuleSelector struct {
+	// FolderUIDs selects the rules in any of the folders.
+	FolderUIDs []string `json:"folderUids,omitempty"`
+	// RuleGroups selects the rules in any of the rule groups.
+	RuleGroups []string `json:"ruleGroups,omitempty"`
+	// Title selects the rules whose title contains the text, ignoring case.
+	Title string `json:"title,omitempty"`
+	// Matchers select the rules whose labels match all the matchers.
+	Matchers ObjectMatchers `json:"matchers,omitempty"`
+}
+
+// swagger:model
+type BulkRuleOperationResult struct {
+	// Rules are the rules changed by the action. Rules that matched the selector but were not changed, for example
+	// because they were already paused, are not included.
+	Rules []BulkRuleOperationRule `json:"rules"`
+}
+
+type BulkRuleOperationRule struct {
+	UID          string `json:"uid"`
+	Title        string `json:"title"`
+	NamespaceUID string `json:"namespaceUid"`
+	RuleGroup    string `json:"ruleGroup"`
+}
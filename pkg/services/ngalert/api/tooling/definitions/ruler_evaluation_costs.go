@@ -0,0 +1,55 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/rules/costs costs RouteGetRuleEvaluationCosts
+//
+// Get the cost of the evaluations of the alert rules that are scheduled on this instance, the most expensive rules first.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleEvaluationCosts
+
+// swagger:parameters RouteGetRuleEvaluationCosts
+type RuleEvaluationCostsParams struct {
+	// Return only the cost of the rule with this UID.
+	// in:query
+	// required:false
+	RuleUID string `json:"ruleUID"`
+	// Return only the rules that regularly take longer to evaluate than their interval.
+	// in:query
+	// required:false
+	Slow bool `json:"slow"`
+}
+
+// swagger:model
+type RuleEvaluationCosts struct {
+	Rules []RuleEvaluationCost `json:"rules"`
+}
+
+// RuleEvaluationCost describes how expensive the evaluations of an alert rule are. Durations are in seconds.
+// swagger:model
+type RuleEvaluationCost struct {
+	RuleUID         string  `json:"ruleUid"`
+	Title           string  `json:"title"`
+	FolderUID       string  `json:"folderUid"`
+	RuleGroup       string  `json:"ruleGroup"`
+	IntervalSeconds float64 `json:"intervalSeconds"`
+
+	Evaluations     int64     `json:"evaluations"`
+	LastEvaluation  time.Time `json:"lastEvaluation"`
+	LastDuration    float64   `json:"lastDuration"`
+	AverageDuration float64   `json:"averageDuration"`
+	MaxDuration     float64   `json:"maxDuration"`
+	TotalDuration   float64   `json:"totalDuration"`
+
+	LastQueryCount         int     `json:"lastQueryCount"`
+	LastSeriesCount        int     `json:"lastSeriesCount"`
+	LastDataSourceDuration float64 `json:"lastDataSourceDuration"`
+
+	// SlowEvaluations is the number of recent evaluations that took longer than the interval of the rule.
+	SlowEvaluations int  `json:"slowEvaluations"`
+	Slow            bool `json:"slow"`
+}
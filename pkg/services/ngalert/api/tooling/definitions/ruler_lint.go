@@ -0,0 +1,50 @@
+package definitions
+
+// swagger:route POST /api/ruler/grafana/api/v1/lint/{Namespace} ruler RoutePostRuleGroupLint
+//
+// Lints a Grafana-managed rule group without saving it, and returns the configurations of its rules that are likely to be mistakes.
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: RuleGroupLintResult
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RoutePostRuleGroupLint
+type RuleGroupLintParams struct {
+	// in:path
+	Namespace string
+	// in:body
+	Body PostableRuleGroupConfig
+}
+
+// swagger:model
+type RuleGroupLintResult struct {
+	Rules []RuleLintResult `json:"rules"`
+	// Blocking is true if any warning blocks saving the rule group.
+	Blocking bool `json:"blocking"`
+}
+
+type RuleLintResult struct {
+	UID      string        `json:"uid,omitempty"`
+	Title    string        `json:"title"`
+	Warnings []LintWarning `json:"warnings"`
+}
+
+// LintWarning is a configuration of an alert rule that is likely to be a mistake.
+type LintWarning struct {
+	// example: missing-runbook-url
+	Code string `json:"code"`
+	// Severity is one of "error", "warning" or "info".
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	// RefID is the query or expression the warning is about. It is empty if the warning is about the rule.
+	RefID string `json:"refId,omitempty"`
+	// Blocking is true if the lint rules of the organization block saving rules with warnings of this code.
+	Blocking bool `json:"blocking"`
+}
@@ -68,6 +68,21 @@ import (
 //     Responses:
 //       200: BacktestResult
 
+// swagger:route Post /api/v1/rule/backtest/compare testing BacktestCompareConfig
+//
+// Backtest a version of a stored rule and a proposed edit of it, and compare their state timelines
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestCompareResult
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -233,3 +248,50 @@ type BacktestConfig struct {
 
 // swagger:model
 type BacktestResult data.Frame
+
+// swagger:parameters BacktestCompareConfig
+type BacktestCompareConfigRequest struct {
+	// in:body
+	Body BacktestCompareConfig
+}
+
+// swagger:model
+type BacktestCompareConfig struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+
+	// UID of the stored rule
+	RuleUID string `json:"rule_uid"`
+	// Version of the stored rule to compare the proposed edit with. The current version is used if it is not specified.
+	Version int64 `json:"version,omitempty"`
+
+	// Proposed edit of the rule
+	Proposed PostableExtendedRuleNode `json:"proposed"`
+	// Evaluation interval of the proposed edit. The interval of the stored rule is used if it is not specified.
+	Interval model.Duration `json:"interval,omitempty"`
+}
+
+// swagger:model
+type BacktestCompareResult struct {
+	// Version of the stored rule the proposed edit was compared with
+	BaseVersion int64 `json:"base_version"`
+	// Instances contains the alert instances whose state timelines differ
+	Instances             []BacktestInstanceDiff `json:"instances"`
+	BaseNotifications     int                    `json:"base_notifications"`
+	ProposedNotifications int                    `json:"proposed_notifications"`
+}
+
+type BacktestInstanceDiff struct {
+	Labels map[string]string `json:"labels"`
+	// Added contains the periods of time during which the instance fires only with the proposed edit
+	Added []BacktestInterval `json:"added,omitempty"`
+	// Removed contains the periods of time during which the instance fires only with the stored version
+	Removed               []BacktestInterval `json:"removed,omitempty"`
+	BaseNotifications     int                `json:"base_notifications"`
+	ProposedNotifications int                `json:"proposed_notifications"`
+}
+
+type BacktestInterval struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
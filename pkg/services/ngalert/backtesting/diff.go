@@ -0,0 +1,166 @@
+package backtesting
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Interval is a period of time [Start, End) during which an alert instance was firing.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// InstanceDiff is the difference between the state timelines of an alert instance produced by two versions of a rule.
+type InstanceDiff struct {
+	Labels data.Labels
+	// Added contains the periods of time during which the instance fires with the proposed version but does not fire with the base one.
+	Added []Interval
+	// Removed contains the periods of time during which the instance fires with the base version but does not fire with the proposed one.
+	Removed []Interval
+	// BaseNotifications and ProposedNotifications are the numbers of notifications about the instance
+	// sent with the base and proposed versions, i.e. the number of times the instance started firing or was resolved.
+	BaseNotifications     int
+	ProposedNotifications int
+}
+
+// TimelineDiff is the difference between the state timelines produced by two versions of a rule.
+type TimelineDiff struct {
+	// Instances contains only instances whose timelines differ. They are sorted by labels.
+	Instances             []InstanceDiff
+	BaseNotifications     int
+	ProposedNotifications int
+}
+
+// timeline is a sequence of firing intervals of an alert instance.
+type timeline struct {
+	labels        data.Labels
+	intervals     []Interval
+	notifications int
+	// firingSince is the time when the current firing interval started. It is zero if the instance is not firing.
+	firingSince time.Time
+}
+
+// Compare backtests both versions of a rule in the range [from, to) and returns the difference between their state timelines.
+func (e *Engine) Compare(ctx context.Context, user *user.SignedInUser, base, proposed *models.AlertRule, from, to time.Time) (*TimelineDiff, error) {
+	baseTimelines, err := e.timelines(ctx, user, base, from, to)
+	if err != nil {
+		return nil, err
+	}
+	proposedTimelines, err := e.timelines(ctx, user, proposed, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffTimelines(baseTimelines, proposedTimelines), nil
+}
+
+// timelines backtests the rule and returns the firing timelines of its alert instances, keyed by the instance labels.
+func (e *Engine) timelines(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time) (map[string]*timeline, error) {
+	result := make(map[string]*timeline)
+	err := e.run(ctx, user, rule, from, to, func(_ int, now time.Time, states []state.StateTransition) {
+		for _, s := range states {
+			tl, ok := result[s.CacheID]
+			if !ok {
+				tl = &timeline{labels: s.Labels.Copy()}
+				result[s.CacheID] = tl
+			}
+			firing := s.IsFiring()
+			wasFiring := !tl.firingSince.IsZero()
+			switch {
+			case firing && !wasFiring:
+				tl.firingSince = now
+				tl.notifications++
+			case !firing && wasFiring:
+				tl.intervals = append(tl.intervals, Interval{Start: tl.firingSince, End: now})
+				tl.firingSince = time.Time{}
+				if s.Resolved {
+					tl.notifications++
+				}
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	// close intervals of instances that fire at the end of the range
+	for _, tl := range result {
+		if !tl.firingSince.IsZero() {
+			tl.intervals = append(tl.intervals, Interval{Start: tl.firingSince, End: to})
+			tl.firingSince = time.Time{}
+		}
+	}
+	return result, nil
+}
+
+func diffTimelines(base, proposed map[string]*timeline) *TimelineDiff {
+	keys := make(map[string]struct{}, len(base)+len(proposed))
+	for k := range base {
+		keys[k] = struct{}{}
+	}
+	for k := range proposed {
+		keys[k] = struct{}{}
+	}
+
+	result := &TimelineDiff{}
+	for key := range keys {
+		b, p := base[key], proposed[key]
+		diff := InstanceDiff{}
+		var baseIntervals, proposedIntervals []Interval
+		if b != nil {
+			diff.Labels = b.labels
+			baseIntervals = b.intervals
+			diff.BaseNotifications = b.notifications
+		}
+		if p != nil {
+			diff.Labels = p.labels
+			proposedIntervals = p.intervals
+			diff.ProposedNotifications = p.notifications
+		}
+		diff.Added = subtractIntervals(proposedIntervals, baseIntervals)
+		diff.Removed = subtractIntervals(baseIntervals, proposedIntervals)
+
+		result.BaseNotifications += diff.BaseNotifications
+		result.ProposedNotifications += diff.ProposedNotifications
+		if len(diff.Added) == 0 && len(diff.Removed) == 0 && diff.BaseNotifications == diff.ProposedNotifications {
+			continue
+		}
+		result.Instances = append(result.Instances, diff)
+	}
+	sort.Slice(result.Instances, func(i, j int) bool {
+		return result.Instances[i].Labels.String() < result.Instances[j].Labels.String()
+	})
+	return result
+}
+
+// subtractIntervals returns the parts of intervals a that are not covered by intervals b.
+// Both a and b must be sorted by start and must not overlap, which is the case for intervals of a timeline.
+func subtractIntervals(a, b []Interval) []Interval {
+	var result []Interval
+	j := 0
+	for _, interval := range a {
+		start := interval.Start
+		// skip intervals of b that end before the current interval starts
+		for j < len(b) && !b[j].End.After(start) {
+			j++
+		}
+		for k := j; k < len(b) && b[k].Start.Before(interval.End); k++ {
+			if b[k].Start.After(start) {
+				result = append(result, Interval{Start: start, End: b[k].Start})
+			}
+			if b[k].End.After(start) {
+				start = b[k].End
+			}
+		}
+		if start.Before(interval.End) {
+			result = append(result, Interval{Start: start, End: interval.End})
+		}
+	}
+	return result
+}
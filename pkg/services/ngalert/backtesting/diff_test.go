@@ -0,0 +1,166 @@
+package backtesting
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestSubtractIntervals(t *testing.T) {
+	at := func(sec int64) time.Time {
+		return time.Unix(sec, 0)
+	}
+	interval := func(start, end int64) Interval {
+		return Interval{Start: at(start), End: at(end)}
+	}
+
+	testCases := []struct {
+		name     string
+		a        []Interval
+		b        []Interval
+		expected []Interval
+	}{
+		{
+			name:     "nothing to subtract",
+			a:        []Interval{interval(0, 10), interval(20, 30)},
+			expected: []Interval{interval(0, 10), interval(20, 30)},
+		},
+		{
+			name: "nothing to subtract from",
+			b:    []Interval{interval(0, 10)},
+		},
+		{
+			name: "same intervals",
+			a:    []Interval{interval(0, 10), interval(20, 30)},
+			b:    []Interval{interval(0, 10), interval(20, 30)},
+		},
+		{
+			name:     "not overlapping intervals",
+			a:        []Interval{interval(0, 10)},
+			b:        []Interval{interval(10, 20)},
+			expected: []Interval{interval(0, 10)},
+		},
+		{
+			name:     "interval in the middle",
+			a:        []Interval{interval(0, 30)},
+			b:        []Interval{interval(10, 20)},
+			expected: []Interval{interval(0, 10), interval(20, 30)},
+		},
+		{
+			name:     "intervals overlapping both ends",
+			a:        []Interval{interval(10, 30)},
+			b:        []Interval{interval(0, 15), interval(25, 40)},
+			expected: []Interval{interval(15, 25)},
+		},
+		{
+			name:     "one interval overlaps many",
+			a:        []Interval{interval(0, 10), interval(20, 30), interval(40, 50)},
+			b:        []Interval{interval(5, 45)},
+			expected: []Interval{interval(0, 5), interval(45, 50)},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, subtractIntervals(tc.a, tc.b))
+		})
+	}
+}
+
+func TestEngineCompare(t *testing.T) {
+	evaluator := &fakeBacktestingEvaluator{
+		evalCallback: func(now time.Time) (eval.Results, error) {
+			return eval.Results{}, nil
+		},
+	}
+	backtestingEvaluatorFactory = func(ctx context.Context, evalFactory eval.EvaluatorFactory, user *user.SignedInUser, condition models.Condition) (backtestingEvaluator, error) {
+		return evaluator, nil
+	}
+	t.Cleanup(func() {
+		backtestingEvaluatorFactory = newBacktestingEvaluator
+	})
+
+	rule := models.AlertRuleGen(models.WithInterval(time.Second))()
+	from := time.Unix(0, 0)
+	to := from.Add(6 * time.Second)
+
+	instanceA := data.Labels{"instance": "a"}
+	instanceC := data.Labels{"instance": "c"}
+
+	// newManager creates a state manager that returns the given states of instances at every evaluation.
+	newManager := func(timelines map[string][]eval.State) stateManager {
+		return &fakeStateManager{
+			stateCallback: func(now time.Time) []state.StateTransition {
+				idx := int(now.Sub(from) / time.Second)
+				result := make([]state.StateTransition, 0, len(timelines))
+				for instance, states := range timelines {
+					s := states[idx]
+					resolved := idx > 0 && states[idx-1] == eval.Alerting && s == eval.Normal
+					result = append(result, state.StateTransition{
+						State: &state.State{
+							CacheID:  instance,
+							Labels:   data.Labels{"instance": instance},
+							State:    s,
+							Resolved: resolved,
+						},
+					})
+				}
+				return result
+			},
+		}
+	}
+	n, a := eval.Normal, eval.Alerting
+	managers := []stateManager{
+		newManager(map[string][]eval.State{
+			"a": {n, a, a, n, n, n},
+			"b": {a, a, a, a, a, a},
+			"c": {n, n, n, n, n, n},
+		}),
+		newManager(map[string][]eval.State{
+			"a": {n, n, a, a, n, n},
+			"b": {a, a, a, a, a, a},
+			"c": {n, n, n, n, a, a},
+		}),
+	}
+	engine := &Engine{
+		createStateManager: func() stateManager {
+			m := managers[0]
+			managers = managers[1:]
+			return m
+		},
+	}
+
+	diff, err := engine.Compare(context.Background(), nil, rule, rule, from, to)
+	require.NoError(t, err)
+
+	at := func(sec int64) time.Time {
+		return from.Add(time.Duration(sec) * time.Second)
+	}
+	require.Equal(t, &TimelineDiff{
+		Instances: []InstanceDiff{
+			{
+				Labels:                instanceA,
+				Added:                 []Interval{{Start: at(3), End: at(4)}},
+				Removed:               []Interval{{Start: at(1), End: at(2)}},
+				BaseNotifications:     2,
+				ProposedNotifications: 2,
+			},
+			{
+				Labels:                instanceC,
+				Added:                 []Interval{{Start: at(4), End: to}},
+				BaseNotifications:     0,
+				ProposedNotifications: 1,
+			},
+		},
+		// instance b fires during the whole range with both versions
+		BaseNotifications:     3,
+		ProposedNotifications: 4,
+	}, diff)
+}
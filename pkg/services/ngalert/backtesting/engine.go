@@ -59,37 +59,17 @@ func NewEngine(appUrl *url.URL, evalFactory eval.EvaluatorFactory) *Engine {
 }
 
 func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
-	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
-	logger := logger.FromContext(ctx)
-
-	if !from.Before(to) {
-		return nil, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
-	}
-	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
-		return nil, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
-	}
-	length := int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds)
-
-	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition())
+	length, err := evaluationsCount(rule, from, to)
 	if err != nil {
-		return nil, errors.Join(ErrInvalidInputData, err)
+		return nil, err
 	}
 
-	stateManager := e.createStateManager()
-
-	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", length)
-
 	start := time.Now()
 
 	tsField := data.NewField("Time", nil, make([]time.Time, length))
 	valueFields := make(map[string]*data.Field)
 
-	err = evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
-		if idx >= length {
-			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
-			return nil
-		}
-		states := stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil)
+	err = e.run(ctx, user, rule, from, to, func(idx int, currentTime time.Time, states []state.StateTransition) {
 		tsField.Set(idx, currentTime)
 		for _, s := range states {
 			field, ok := valueFields[s.CacheID]
@@ -106,7 +86,6 @@ func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models
 				continue
 			}
 		}
-		return nil
 	})
 	fields := make([]*data.Field, 0, len(valueFields)+1)
 	fields = append(fields, tsField)
@@ -118,10 +97,51 @@ func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models
 	if err != nil {
 		return nil, err
 	}
-	logger.Info("Rule testing finished successfully", "duration", time.Since(start))
+	logger.FromContext(ctx).Info("Rule testing finished successfully", "duration", time.Since(start))
 	return result, nil
 }
 
+// evaluationsCount returns the number of evaluations of the rule in the range [from, to).
+func evaluationsCount(rule *models.AlertRule, from, to time.Time) (int, error) {
+	if !from.Before(to) {
+		return 0, fmt.Errorf("%w: invalid interval of the backtesting [%d,%d]", ErrInvalidInputData, from.Unix(), to.Unix())
+	}
+	if to.Sub(from).Seconds() < float64(rule.IntervalSeconds) {
+		return 0, fmt.Errorf("%w: interval of the backtesting [%d,%d] is less than evaluation interval [%ds]", ErrInvalidInputData, from.Unix(), to.Unix(), rule.IntervalSeconds)
+	}
+	return int(to.Sub(from).Seconds()) / int(rule.IntervalSeconds), nil
+}
+
+// run evaluates the rule at every evaluation interval in the range [from, to), processes results by a new state manager,
+// and calls the callback with the state transitions of every evaluation.
+func (e *Engine) run(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time, callback func(idx int, now time.Time, states []state.StateTransition)) error {
+	ruleCtx := models.WithRuleKey(ctx, rule.GetKey())
+	logger := logger.FromContext(ctx)
+
+	length, err := evaluationsCount(rule, from, to)
+	if err != nil {
+		return err
+	}
+
+	evaluator, err := backtestingEvaluatorFactory(ruleCtx, e.evalFactory, user, rule.GetEvalCondition())
+	if err != nil {
+		return errors.Join(ErrInvalidInputData, err)
+	}
+
+	stateManager := e.createStateManager()
+
+	logger.Info("Start testing alert rule", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluations", length)
+
+	return evaluator.Eval(ruleCtx, from, time.Duration(rule.IntervalSeconds)*time.Second, length, func(idx int, currentTime time.Time, results eval.Results) error {
+		if idx >= length {
+			logger.Info("Unexpected evaluation. Skipping", "from", from, "to", to, "interval", rule.IntervalSeconds, "evaluationTime", currentTime, "evaluationIndex", idx, "expectedEvaluations", length)
+			return nil
+		}
+		callback(idx, currentTime, stateManager.ProcessEvalResults(ruleCtx, currentTime, rule, results, nil))
+		return nil
+	})
+}
+
 func newBacktestingEvaluator(ctx context.Context, evalFactory eval.EvaluatorFactory, user *user.SignedInUser, condition models.Condition) (backtestingEvaluator, error) {
 	for _, q := range condition.Data {
 		if q.DatasourceUID == "__data__" || q.QueryType == "__data__" {
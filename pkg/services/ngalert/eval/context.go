@@ -3,13 +3,23 @@ package eval
 import (
 	"context"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
+// AlertingResultsReader provides fingerprints of results that are in alerting state.
+// It is used by the threshold expressions with an unloading (recovery) threshold.
+type AlertingResultsReader interface {
+	Read() map[data.Fingerprint]struct{}
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx  context.Context
 	User *user.SignedInUser
+
+	AlertingResultsReader AlertingResultsReader
 }
 
 func NewContext(ctx context.Context, user *user.SignedInUser) EvaluationContext {
@@ -18,3 +28,11 @@ func NewContext(ctx context.Context, user *user.SignedInUser) EvaluationContext
 		User: user,
 	}
 }
+
+func NewContextWithPreviousResults(ctx context.Context, user *user.SignedInUser, reader AlertingResultsReader) EvaluationContext {
+	return EvaluationContext{
+		Ctx:                   ctx,
+		User:                  user,
+		AlertingResultsReader: reader,
+	}
+}
@@ -175,6 +175,8 @@ type Result struct {
 	// as EvalMatches (from "classic condition"), and in the future from operations
 	// like SSE "math".
 	EvaluationString string
+	// StateReason is the reason of the state of the alert instance when the state does not have another reason.
+	StateReason string
 }
 
 func NewResultFromError(err error, evaluatedAt time.Time, duration time.Duration) Result {
@@ -210,14 +212,19 @@ const (
 	// Error is the eval state for an alert rule condition
 	// that evaluated to Error.
 	Error
+
+	// Recovering is the eval state for an alert instance condition
+	// that evaluated to false (Normal) after firing but has not yet
+	// met the KeepFiringFor duration defined in AlertRule.
+	Recovering
 )
 
 func (s State) IsValid() bool {
-	return s <= Error
+	return s <= Recovering
 }
 
 func (s State) String() string {
-	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error"}[s]
+	return [...]string{"Normal", "Alerting", "Pending", "NoData", "Error", "Recovering"}[s]
 }
 
 func buildDatasourceHeaders(ctx context.Context) map[string]string {
@@ -250,6 +257,10 @@ func getExprRequest(ctx EvaluationContext, data []models.AlertQuery, dsCacheServ
 		User:    ctx.User,
 	}
 
+	if ctx.AlertingResultsReader != nil {
+		req.LoadedDimensions = ctx.AlertingResultsReader.Read()
+	}
+
 	datasources := make(map[string]*datasources.DataSource, len(data))
 
 	for _, q := range data {
@@ -689,7 +700,7 @@ func (e *evaluatorImpl) create(condition models.Condition, req *expr.Request) (C
 	for _, node := range pipeline {
 		if node.RefID() == condition.Condition {
 			return &conditionEvaluator{
-				pipeline:          pipeline,
+				pipeline:          withStats(pipeline),
 				expressionService: e.expressionService,
 				condition:         condition,
 				evalTimeout:       e.evaluationTimeout,
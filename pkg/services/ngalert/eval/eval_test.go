@@ -764,3 +764,27 @@ type fakeExpressionService struct {
 func (f fakeExpressionService) ExecutePipeline(ctx context.Context, now time.Time, pipeline expr.DataPipeline) (*backend.QueryDataResponse, error) {
 	return f.hook(ctx, now, pipeline)
 }
+
+type fakeAlertingResultsReader map[data.Fingerprint]struct{}
+
+func (f fakeAlertingResultsReader) Read() map[data.Fingerprint]struct{} {
+	return f
+}
+
+func TestGetExprRequestLoadedDimensions(t *testing.T) {
+	query := models.CreateClassicConditionExpression("B", "A", "last", "gt", 1)
+	u := &user.SignedInUser{OrgID: 1}
+
+	t.Run("should not set loaded dimensions if there is no reader", func(t *testing.T) {
+		req, err := getExprRequest(NewContext(context.Background(), u), []models.AlertQuery{query}, &fakes.FakeCacheService{})
+		require.NoError(t, err)
+		require.Nil(t, req.LoadedDimensions)
+	})
+
+	t.Run("should set loaded dimensions from the reader", func(t *testing.T) {
+		reader := fakeAlertingResultsReader{data.Labels{"test": "test"}.Fingerprint(): {}}
+		req, err := getExprRequest(NewContextWithPreviousResults(context.Background(), u, reader), []models.AlertQuery{query}, &fakes.FakeCacheService{})
+		require.NoError(t, err)
+		require.Equal(t, expr.Fingerprints(reader), req.LoadedDimensions)
+	})
+}
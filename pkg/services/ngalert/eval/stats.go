@@ -0,0 +1,85 @@
+package eval
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// EvaluationStats describes the queries that were executed during an evaluation of a condition.
+type EvaluationStats struct {
+	// QueryCount is the number of executed data source queries.
+	QueryCount int
+	// SeriesCount is the number of series returned by the data source queries.
+	SeriesCount int
+	// DataSourceDuration is the total time spent executing data source queries.
+	DataSourceDuration time.Duration
+}
+
+// StatsCollector collects EvaluationStats of the evaluations that use a context created by NewContextWithStatsCollector.
+type StatsCollector struct {
+	mtx   sync.Mutex
+	stats EvaluationStats
+}
+
+// Stats returns the statistics collected so far.
+func (c *StatsCollector) Stats() EvaluationStats {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.stats
+}
+
+func (c *StatsCollector) observeQuery(series int, d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.stats.QueryCount++
+	c.stats.SeriesCount += series
+	c.stats.DataSourceDuration += d
+}
+
+type statsCollectorKey struct{}
+
+// NewContextWithStatsCollector returns a context that collects the statistics of the queries executed by
+// ConditionEvaluator.Evaluate and ConditionEvaluator.EvaluateRaw, and the collector.
+func NewContextWithStatsCollector(ctx context.Context) (context.Context, *StatsCollector) {
+	c := &StatsCollector{}
+	return context.WithValue(ctx, statsCollectorKey{}, c), c
+}
+
+func statsCollectorFromContext(ctx context.Context) *StatsCollector {
+	c, _ := ctx.Value(statsCollectorKey{}).(*StatsCollector)
+	return c
+}
+
+// statsNode is a query node that reports its execution to the StatsCollector of the context, if any.
+type statsNode struct {
+	expr.Node
+}
+
+func (n statsNode) Execute(ctx context.Context, now time.Time, vars mathexp.Vars, s *expr.Service) (mathexp.Results, error) {
+	c := statsCollectorFromContext(ctx)
+	if c == nil {
+		return n.Node.Execute(ctx, now, vars, s)
+	}
+	start := time.Now()
+	res, err := n.Node.Execute(ctx, now, vars, s)
+	c.observeQuery(len(res.Values), time.Since(start))
+	return res, err
+}
+
+// withStats wraps the nodes that query data sources so that their executions are reported to the StatsCollector of the context.
+func withStats(pipeline expr.DataPipeline) expr.DataPipeline {
+	result := make(expr.DataPipeline, 0, len(pipeline))
+	for _, node := range pipeline {
+		switch node.NodeType() {
+		case expr.TypeDatasourceNode, expr.TypeMLNode:
+			result = append(result, statsNode{Node: node})
+		default:
+			result = append(result, node)
+		}
+	}
+	return result
+}
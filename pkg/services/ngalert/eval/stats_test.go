@@ -0,0 +1,65 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+type fakeNode struct {
+	nodeType expr.NodeType
+	refID    string
+	series   int
+	err      error
+}
+
+func (n *fakeNode) ID() int64               { return 0 }
+func (n *fakeNode) NodeType() expr.NodeType { return n.nodeType }
+func (n *fakeNode) RefID() string           { return n.refID }
+func (n *fakeNode) String() string          { return n.refID }
+
+func (n *fakeNode) Execute(_ context.Context, _ time.Time, _ mathexp.Vars, _ *expr.Service) (mathexp.Results, error) {
+	res := mathexp.Results{}
+	for i := 0; i < n.series; i++ {
+		res.Values = append(res.Values, mathexp.NewNumber(n.refID, data.Labels{}))
+	}
+	return res, n.err
+}
+
+func TestWithStats(t *testing.T) {
+	pipeline := withStats(expr.DataPipeline{
+		&fakeNode{nodeType: expr.TypeDatasourceNode, refID: "A", series: 3},
+		&fakeNode{nodeType: expr.TypeDatasourceNode, refID: "B", series: 2, err: errors.New("failed")},
+		&fakeNode{nodeType: expr.TypeCMDNode, refID: "C", series: 5},
+	})
+
+	t.Run("should wrap only the nodes that query data sources", func(t *testing.T) {
+		require.IsType(t, statsNode{}, pipeline[0])
+		require.IsType(t, statsNode{}, pipeline[1])
+		require.IsType(t, &fakeNode{}, pipeline[2])
+		require.Equal(t, "A", pipeline[0].RefID())
+	})
+
+	t.Run("should collect statistics of the queries when the context has a collector", func(t *testing.T) {
+		ctx, collector := NewContextWithStatsCollector(context.Background())
+		for _, node := range pipeline {
+			_, _ = node.Execute(ctx, time.Now(), nil, nil)
+		}
+		stats := collector.Stats()
+		require.Equal(t, 2, stats.QueryCount)
+		require.Equal(t, 5, stats.SeriesCount)
+	})
+
+	t.Run("should execute the nodes when the context has no collector", func(t *testing.T) {
+		res, err := pipeline[0].Execute(context.Background(), time.Now(), nil, nil)
+		require.NoError(t, err)
+		require.Len(t, res.Values, 3)
+	})
+}
@@ -0,0 +1,226 @@
+// Package lint finds alert rule configurations that are valid, but that are likely to be mistakes.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// Severity is how likely a warning is to be a mistake.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Code identifies the kind of a warning. Codes are used in the lint rules of an organization to decide which warnings block saving alert rules.
+type Code string
+
+const (
+	CodeConditionNotFinal           Code = "condition-not-final"
+	CodeReduceInstantQuery          Code = "reduce-instant-query"
+	CodeThresholdOnRangeQuery       Code = "threshold-on-range-query"
+	CodeMissingRunbookURL           Code = "missing-runbook-url"
+	CodeReservedLabel               Code = "reserved-label"
+	CodeIntervalBelowScrapeInterval Code = "interval-below-scrape-interval"
+)
+
+// Codes are all the codes of warnings that can be found.
+var Codes = map[Code]Severity{
+	CodeConditionNotFinal:           SeverityWarning,
+	CodeReduceInstantQuery:          SeverityInfo,
+	CodeThresholdOnRangeQuery:       SeverityError,
+	CodeMissingRunbookURL:           SeverityInfo,
+	CodeReservedLabel:               SeverityError,
+	CodeIntervalBelowScrapeInterval: SeverityWarning,
+}
+
+// RunbookURLAnnotation is the annotation that links an alert rule to its runbook.
+const RunbookURLAnnotation = "runbook_url"
+
+// Warning is a possible mistake in an alert rule.
+type Warning struct {
+	Code     Code
+	Severity Severity
+	Message  string
+	// RefID is the query or expression the warning is about. It is empty if the warning is about the rule.
+	RefID string
+}
+
+func (w Warning) String() string {
+	if w.RefID != "" {
+		return fmt.Sprintf("%s [%s] %s: %s", w.Severity, w.Code, w.RefID, w.Message)
+	}
+	return fmt.Sprintf("%s [%s] %s", w.Severity, w.Code, w.Message)
+}
+
+// Options are the optional inputs to lint alert rules.
+type Options struct {
+	// ScrapeInterval returns the interval at which the data source with the UID scrapes its targets, if it is known.
+	// Rules that query the data source more often than it scrapes evaluate the same data several times.
+	ScrapeInterval func(datasourceUID string) (time.Duration, bool)
+}
+
+// node is a query or an expression of an alert rule.
+type node struct {
+	query models.AlertQuery
+	// exprType is the type of the expression, or empty if the node is a data source query.
+	exprType string
+	// inputs are the RefIDs of the nodes the expression reads from.
+	inputs []string
+	// instant is true if the node is a data source query that returns a single value per series.
+	instant bool
+}
+
+// Rule returns the warnings for the alert rule, sorted by RefID and code.
+func Rule(rule *models.AlertRule, opts Options) []Warning {
+	nodes := make(map[string]*node, len(rule.Data))
+	for _, q := range rule.Data {
+		nodes[q.RefID] = parseNode(q)
+	}
+
+	var warnings []Warning
+	warn := func(code Code, refID, format string, args ...interface{}) {
+		warnings = append(warnings, Warning{Code: code, Severity: Codes[code], Message: fmt.Sprintf(format, args...), RefID: refID})
+	}
+
+	for refID, n := range nodes {
+		for _, input := range n.inputs {
+			if input == rule.Condition {
+				warn(CodeConditionNotFinal, rule.Condition, "the condition is used by expression %s, so the result of %s is not evaluated", refID, refID)
+			}
+			in, ok := nodes[input]
+			if !ok || in.exprType != "" {
+				continue
+			}
+			switch n.exprType {
+			case "reduce":
+				if in.instant {
+					warn(CodeReduceInstantQuery, refID, "query %s is an instant query that returns a single value per series, so reducing it has no effect", input)
+				}
+			case "threshold":
+				if !in.instant {
+					warn(CodeThresholdOnRangeQuery, refID, "query %s is a range query, so it must be reduced to a single value per series before the threshold is applied", input)
+				}
+			}
+		}
+	}
+
+	if !rule.IsRecordingRule() && rule.Annotations[RunbookURLAnnotation] == "" {
+		warn(CodeMissingRunbookURL, "", "the rule has no %s annotation to tell responders how to resolve the alert", RunbookURLAnnotation)
+	}
+
+	for name := range rule.Labels {
+		if _, ok := models.InternalLabelNameSet[name]; ok {
+			warn(CodeReservedLabel, "", "label %s is reserved by Grafana, so the value set on the rule is replaced when alerts are created", name)
+		}
+	}
+
+	if opts.ScrapeInterval != nil {
+		interval := time.Duration(rule.IntervalSeconds) * time.Second
+		for refID, n := range nodes {
+			if n.exprType != "" {
+				continue
+			}
+			scrapeInterval, ok := opts.ScrapeInterval(n.query.DatasourceUID)
+			if ok && interval < scrapeInterval {
+				warn(CodeIntervalBelowScrapeInterval, refID, "the rule is evaluated every %s, but the data source scrapes every %s", interval, scrapeInterval)
+			}
+		}
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		if warnings[i].RefID == warnings[j].RefID {
+			return warnings[i].Code < warnings[j].Code
+		}
+		return warnings[i].RefID < warnings[j].RefID
+	})
+	return warnings
+}
+
+// Blocking returns the warnings whose code the lint rules configure to block saving alert rules.
+func Blocking(warnings []Warning, rules map[string]models.LintRuleAction) []Warning {
+	var result []Warning
+	for _, w := range warnings {
+		if rules[string(w.Code)] == models.LintRuleActionBlock {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// Ignored removes the warnings whose code the lint rules configure to be ignored.
+func Ignored(warnings []Warning, rules map[string]models.LintRuleAction) []Warning {
+	result := make([]Warning, 0, len(warnings))
+	for _, w := range warnings {
+		if rules[string(w.Code)] != models.LintRuleActionIgnore {
+			result = append(result, w)
+		}
+	}
+	return result
+}
+
+// ValidateRules checks that the lint rules configure known codes with known actions.
+func ValidateRules(rules map[string]models.LintRuleAction) error {
+	for code, action := range rules {
+		if _, ok := Codes[Code(code)]; !ok {
+			return fmt.Errorf("unknown lint code %q", code)
+		}
+		switch action {
+		case models.LintRuleActionWarn, models.LintRuleActionBlock, models.LintRuleActionIgnore:
+		default:
+			return fmt.Errorf("invalid action %q for lint code %q", action, code)
+		}
+	}
+	return nil
+}
+
+func parseNode(q models.AlertQuery) *node {
+	n := &node{query: q}
+	var model struct {
+		Type       string `json:"type"`
+		Expression string `json:"expression"`
+		Conditions []struct {
+			Query struct {
+				Params []string `json:"params"`
+			} `json:"query"`
+		} `json:"conditions"`
+		Instant   bool   `json:"instant"`
+		Range     *bool  `json:"range"`
+		QueryType string `json:"queryType"`
+	}
+	// Invalid models are reported by the validation of the rule, so they are linted as empty ones.
+	_ = json.Unmarshal(q.Model, &model)
+
+	if isExpr, _ := q.IsExpression(); !isExpr {
+		n.instant = model.QueryType == "instant" || (model.Instant && (model.Range == nil || !*model.Range))
+		return n
+	}
+
+	n.exprType = model.Type
+	switch model.Type {
+	case "math":
+		if e, err := mathexp.New(model.Expression); err == nil {
+			n.inputs = e.Tree.VarNames
+		}
+	case "classic_conditions":
+		for _, c := range model.Conditions {
+			if len(c.Query.Params) > 0 {
+				n.inputs = append(n.inputs, c.Query.Params[0])
+			}
+		}
+	default:
+		if model.Expression != "" {
+			n.inputs = []string{strings.TrimPrefix(model.Expression, "$")}
+		}
+	}
+	return n
+}
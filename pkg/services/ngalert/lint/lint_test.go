@@ -0,0 +1,165 @@
+package lint
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRule(t *testing.T) {
+	query := func(refID, model string) models.AlertQuery {
+		return models.AlertQuery{RefID: refID, DatasourceUID: "prometheus", Model: json.RawMessage(model)}
+	}
+	expression := func(refID, model string) models.AlertQuery {
+		return models.AlertQuery{RefID: refID, DatasourceUID: expr.DatasourceUID, Model: json.RawMessage(model)}
+	}
+	rule := func(condition string, data ...models.AlertQuery) *models.AlertRule {
+		return &models.AlertRule{
+			Title:           "test",
+			Condition:       condition,
+			Data:            data,
+			IntervalSeconds: 60,
+			Annotations:     map[string]string{RunbookURLAnnotation: "https://example.com/runbook"},
+		}
+	}
+	codes := func(warnings []Warning) []Code {
+		result := make([]Code, 0, len(warnings))
+		for _, w := range warnings {
+			result = append(result, w.Code)
+		}
+		return result
+	}
+
+	testCases := []struct {
+		name     string
+		rule     *models.AlertRule
+		opts     Options
+		expected []Code
+	}{
+		{
+			name: "no warnings for a reduced range query with a threshold",
+			rule: rule("C",
+				query("A", `{"expr": "up"}`),
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "threshold", "expression": "$B"}`),
+			),
+			expected: []Code{},
+		},
+		{
+			name: "condition that is used by another expression",
+			rule: rule("B",
+				query("A", `{"expr": "up"}`),
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "math", "expression": "$B > 0"}`),
+			),
+			expected: []Code{CodeConditionNotFinal},
+		},
+		{
+			name: "reduce over an instant query",
+			rule: rule("C",
+				query("A", `{"expr": "up", "instant": true}`),
+				expression("B", `{"type": "reduce", "expression": "A", "reducer": "last"}`),
+				expression("C", `{"type": "threshold", "expression": "B"}`),
+			),
+			expected: []Code{CodeReduceInstantQuery},
+		},
+		{
+			name: "threshold on a range query",
+			rule: rule("B",
+				query("A", `{"expr": "up", "range": true}`),
+				expression("B", `{"type": "threshold", "expression": "A"}`),
+			),
+			expected: []Code{CodeThresholdOnRangeQuery},
+		},
+		{
+			name: "threshold on an instant query",
+			rule: rule("B",
+				query("A", `{"expr": "up", "queryType": "instant"}`),
+				expression("B", `{"type": "threshold", "expression": "A"}`),
+			),
+			expected: []Code{},
+		},
+		{
+			name: "condition that is used by classic conditions",
+			rule: rule("A",
+				query("A", `{"expr": "up", "instant": true}`),
+				expression("B", `{"type": "classic_conditions", "conditions": [{"query": {"params": ["A"]}}]}`),
+			),
+			expected: []Code{CodeConditionNotFinal},
+		},
+		{
+			name: "missing runbook URL",
+			rule: func() *models.AlertRule {
+				r := rule("A", query("A", `{"expr": "up", "instant": true}`))
+				r.Annotations = nil
+				return r
+			}(),
+			expected: []Code{CodeMissingRunbookURL},
+		},
+		{
+			name: "recording rule without runbook URL",
+			rule: func() *models.AlertRule {
+				r := rule("A", query("A", `{"expr": "up", "instant": true}`))
+				r.Annotations = nil
+				r.Record = &models.Record{Metric: "up", From: "A"}
+				return r
+			}(),
+			expected: []Code{},
+		},
+		{
+			name: "reserved label",
+			rule: func() *models.AlertRule {
+				r := rule("A", query("A", `{"expr": "up", "instant": true}`))
+				r.Labels = map[string]string{alertingModels.RuleUIDLabel: "uid", "team": "backend"}
+				return r
+			}(),
+			expected: []Code{CodeReservedLabel},
+		},
+		{
+			name: "interval below the scrape interval",
+			rule: rule("A", query("A", `{"expr": "up", "instant": true}`)),
+			opts: Options{ScrapeInterval: func(uid string) (time.Duration, bool) {
+				return 2 * time.Minute, uid == "prometheus"
+			}},
+			expected: []Code{CodeIntervalBelowScrapeInterval},
+		},
+		{
+			name: "interval above the scrape interval",
+			rule: rule("A", query("A", `{"expr": "up", "instant": true}`)),
+			opts: Options{ScrapeInterval: func(uid string) (time.Duration, bool) {
+				return 15 * time.Second, true
+			}},
+			expected: []Code{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, codes(Rule(tc.rule, tc.opts)))
+		})
+	}
+}
+
+func TestLintRules(t *testing.T) {
+	warnings := []Warning{
+		{Code: CodeMissingRunbookURL, Severity: SeverityInfo},
+		{Code: CodeReservedLabel, Severity: SeverityError},
+		{Code: CodeConditionNotFinal, Severity: SeverityWarning},
+	}
+	rules := map[string]models.LintRuleAction{
+		string(CodeMissingRunbookURL): models.LintRuleActionIgnore,
+		string(CodeReservedLabel):     models.LintRuleActionBlock,
+	}
+
+	require.Equal(t, warnings[1:2], Blocking(warnings, rules))
+	require.Equal(t, warnings[1:], Ignored(warnings, rules))
+
+	require.NoError(t, ValidateRules(rules))
+	require.Error(t, ValidateRules(map[string]models.LintRuleAction{"unknown": models.LintRuleActionBlock}))
+	require.Error(t, ValidateRules(map[string]models.LintRuleAction{string(CodeReservedLabel): "fail"}))
+}
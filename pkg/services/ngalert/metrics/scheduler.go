@@ -25,6 +25,11 @@ type Scheduler struct {
 	UpdateSchedulableAlertRulesDuration prometheus.Histogram
 	Ticker                              *ticker.Metrics
 	EvaluationMissed                    *prometheus.CounterVec
+	RuleLastEvalDuration                *prometheus.GaugeVec
+	RuleLastEvalDataSourceDuration      *prometheus.GaugeVec
+	RuleLastEvalQueries                 *prometheus.GaugeVec
+	RuleLastEvalSeries                  *prometheus.GaugeVec
+	SlowRules                           *prometheus.GaugeVec
 }
 
 func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
@@ -121,5 +126,50 @@ func NewSchedulerMetrics(r prometheus.Registerer) *Scheduler {
 			},
 			[]string{"org", "name"},
 		),
+		RuleLastEvalDuration: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_duration_seconds",
+				Help:      "The duration of the last evaluation of a rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		RuleLastEvalDataSourceDuration: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_datasource_duration_seconds",
+				Help:      "The time the last evaluation of a rule spent executing data source queries.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		RuleLastEvalQueries: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_queries",
+				Help:      "The number of data source queries executed by the last evaluation of a rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		RuleLastEvalSeries: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "rule_last_evaluation_series",
+				Help:      "The number of series returned by the data source queries of the last evaluation of a rule.",
+			},
+			[]string{"org", "rule_uid"},
+		),
+		SlowRules: promauto.With(r).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: Namespace,
+				Subsystem: Subsystem,
+				Name:      "schedule_slow_rules",
+				Help:      "The number of rules that regularly take longer to evaluate than their interval.",
+			},
+			[]string{"org"},
+		),
 	}
 }
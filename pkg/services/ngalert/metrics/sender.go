@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type Sender struct {
+	RetryQueueAlerts *prometheus.GaugeVec
+	AlertsQueued     *prometheus.CounterVec
+	AlertsRetried    *prometheus.CounterVec
+	AlertsDropped    *prometheus.CounterVec
+}
+
+func NewSenderMetrics(r prometheus.Registerer) *Sender {
+	return &Sender{
+		RetryQueueAlerts: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "external_alertmanager_retry_queue_alerts",
+			Help:      "The number of alerts waiting in the retry queue of an external Alertmanager.",
+		}, []string{"org", "alertmanager"}),
+		AlertsQueued: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "external_alertmanager_alerts_queued_total",
+			Help:      "The total number of alerts that failed to be sent to an external Alertmanager and were queued for retry.",
+		}, []string{"org", "alertmanager"}),
+		AlertsRetried: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "external_alertmanager_alerts_retried_total",
+			Help:      "The total number of queued alerts that were sent to an external Alertmanager by a retry.",
+		}, []string{"org", "alertmanager"}),
+		AlertsDropped: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "external_alertmanager_alerts_dropped_total",
+			Help:      "The total number of queued alerts that were dropped without being sent to an external Alertmanager.",
+		}, []string{"org", "alertmanager", "reason"}),
+	}
+}
@@ -26,10 +26,25 @@ type AdminConfiguration struct {
 	// SendAlertsTo indicates which set of alertmanagers will handle the alert.
 	SendAlertsTo AlertmanagersChoice `xorm:"send_alerts_to"`
 
+	// LintRules configure what happens when linting alert rules finds a warning, by the code of the warning.
+	LintRules map[string]LintRuleAction `xorm:"lint_rules"`
+
 	CreatedAt int64 `xorm:"created"`
 	UpdatedAt int64 `xorm:"updated"`
 }
 
+// LintRuleAction is what happens when linting an alert rule finds a warning.
+type LintRuleAction string
+
+const (
+	// LintRuleActionWarn reports the warning. It is the action of codes that are not configured.
+	LintRuleActionWarn LintRuleAction = "warn"
+	// LintRuleActionBlock rejects saving the alert rule.
+	LintRuleActionBlock LintRuleAction = "block"
+	// LintRuleActionIgnore does not report the warning.
+	LintRuleActionIgnore LintRuleAction = "ignore"
+)
+
 // String implements the Stringer interface
 func (amc AlertmanagersChoice) String() string {
 	return alertmanagersChoiceMap[amc]
@@ -12,6 +12,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -107,11 +108,13 @@ const (
 )
 
 const (
-	StateReasonMissingSeries = "MissingSeries"
-	StateReasonError         = "Error"
-	StateReasonPaused        = "Paused"
-	StateReasonUpdated       = "Updated"
-	StateReasonRuleDeleted   = "RuleDeleted"
+	StateReasonMissingSeries          = "MissingSeries"
+	StateReasonError                  = "Error"
+	StateReasonPaused                 = "Paused"
+	StateReasonUpdated                = "Updated"
+	StateReasonRuleDeleted            = "RuleDeleted"
+	StateReasonSuppressedByDependency = "SuppressedByDependency"
+	StateReasonSlowEvaluation         = "SlowEvaluation"
 )
 
 var (
@@ -163,10 +166,28 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For time.Duration
+	// KeepFiringFor is the duration the condition must be clear for before a firing alert is resolved.
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// DependsOn contains UIDs of upstream rules in the same organization. The rule is not evaluated while any of them is firing.
+	DependsOn []string
+	// Record is set if the rule is a recording rule.
+	Record *Record `xorm:"json"`
+}
+
+// Record is the configuration of a recording rule. A recording rule does not alert. Instead, the result of one of its
+// queries or expressions is written to a Prometheus-compatible data source as a new metric.
+type Record struct {
+	// Metric is the name of the metric the result is written as.
+	Metric string `json:"metric"`
+	// From is the RefID of the query or expression whose result is written.
+	From string `json:"from"`
+	// TargetDatasourceUID is the UID of the data source the metric is written to.
+	// If it is empty, the data source configured for recording rules is used.
+	TargetDatasourceUID string `json:"targetDatasourceUid,omitempty"`
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -250,6 +271,11 @@ func (alertRule *AlertRule) GetLabels(opts ...LabelOption) map[string]string {
 	return labels
 }
 
+// IsRecordingRule returns true if the rule writes the result of its query as a metric instead of alerting.
+func (alertRule *AlertRule) IsRecordingRule() bool {
+	return alertRule.Record != nil
+}
+
 func (alertRule *AlertRule) GetEvalCondition() Condition {
 	return Condition{
 		Condition: alertRule.Condition,
@@ -413,10 +439,16 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For time.Duration
+	// KeepFiringFor is the duration the condition must be clear for before a firing alert is resolved.
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// DependsOn contains UIDs of upstream rules in the same organization. The rule is not evaluated while any of them is firing.
+	DependsOn []string
+	// Record is set if the rule is a recording rule.
+	Record *Record `xorm:"json"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -425,6 +457,13 @@ type GetAlertRuleByUIDQuery struct {
 	OrgID int64
 }
 
+// GetAlertRuleVersionQuery is the query for retrieving an alert rule as it was at the specified version.
+type GetAlertRuleVersionQuery struct {
+	UID     string
+	OrgID   int64
+	Version int64
+}
+
 // GetAlertRulesGroupByRuleUIDQuery is the query for retrieving a group of alerts by UID of a rule that belongs to that group
 type GetAlertRulesGroupByRuleUIDQuery struct {
 	UID   string
@@ -532,9 +571,57 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
+	if ruleToPatch.DependsOn == nil {
+		ruleToPatch.DependsOn = existingRule.DependsOn
+	}
+	if ruleToPatch.Record == nil {
+		ruleToPatch.Record = existingRule.Record
+	}
+}
+
+// ValidateDependsOn checks that the upstream rules are not empty, unique and do not include the rule itself.
+func (alertRule *AlertRule) ValidateDependsOn() error {
+	seen := make(map[string]struct{}, len(alertRule.DependsOn))
+	for _, uid := range alertRule.DependsOn {
+		if uid == "" {
+			return fmt.Errorf("%w: upstream rule UID cannot be empty", ErrAlertRuleFailedValidation)
+		}
+		if alertRule.UID != "" && uid == alertRule.UID {
+			return fmt.Errorf("%w: rule cannot depend on itself", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := seen[uid]; ok {
+			return fmt.Errorf("%w: upstream rule %s is specified more than once", ErrAlertRuleFailedValidation, uid)
+		}
+		seen[uid] = struct{}{}
+	}
+	return nil
+}
+
+// ValidateRecord checks the configuration of a recording rule. The metric name must be a valid Prometheus metric name
+// and the query or expression whose result is recorded must be the condition of the rule.
+func (alertRule *AlertRule) ValidateRecord() error {
+	if alertRule.Record == nil {
+		return nil
+	}
+	if !model.IsValidMetricName(model.LabelValue(alertRule.Record.Metric)) {
+		return fmt.Errorf("%w: metric name '%s' of recording rule is not valid", ErrAlertRuleFailedValidation, alertRule.Record.Metric)
+	}
+	if alertRule.Record.From == "" {
+		return fmt.Errorf("%w: recording rule must specify the query or expression to record", ErrAlertRuleFailedValidation)
+	}
+	if alertRule.Record.From != alertRule.Condition {
+		return fmt.Errorf("%w: recording rule records '%s' but its condition is '%s'", ErrAlertRuleFailedValidation, alertRule.Record.From, alertRule.Condition)
+	}
+	if alertRule.For != 0 || alertRule.KeepFiringFor != 0 {
+		return fmt.Errorf("%w: recording rule cannot have pending or keep firing period", ErrAlertRuleFailedValidation)
+	}
+	return nil
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
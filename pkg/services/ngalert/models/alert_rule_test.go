@@ -244,12 +244,24 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "KeepFiringFor is -1",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.KeepFiringFor = -1
+				},
+			},
 			{
 				name: "IsPaused did not come in request",
 				mutator: func(r *AlertRuleWithOptionals) {
 					r.IsPaused = true
 				},
 			},
+			{
+				name: "DependsOn is nil",
+				mutator: func(r *AlertRuleWithOptionals) {
+					r.DependsOn = nil
+				},
+			},
 		}
 
 		for _, testCase := range testCases {
@@ -258,6 +270,8 @@ func TestPatchPartialAlertRule(t *testing.T) {
 				for {
 					rule := AlertRuleGen(func(rule *AlertRule) {
 						rule.For = time.Duration(rand.Int63n(1000) + 1)
+						rule.KeepFiringFor = time.Duration(rand.Int63n(1000) + 1)
+						rule.DependsOn = []string{util.GenerateShortUID()}
 					})()
 					existing = &AlertRuleWithOptionals{AlertRule: *rule}
 					cloned := *existing
@@ -729,3 +743,30 @@ func TestTimeRangeYAML(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, yamlRaw, string(serialized))
 }
+
+func TestValidateDependsOn(t *testing.T) {
+	testCases := []struct {
+		name      string
+		uid       string
+		dependsOn []string
+		expectErr bool
+	}{
+		{name: "no dependencies", uid: "rule", dependsOn: nil},
+		{name: "valid dependencies", uid: "rule", dependsOn: []string{"upstream-1", "upstream-2"}},
+		{name: "empty uid", uid: "rule", dependsOn: []string{""}, expectErr: true},
+		{name: "self reference", uid: "rule", dependsOn: []string{"upstream-1", "rule"}, expectErr: true},
+		{name: "duplicate", uid: "rule", dependsOn: []string{"upstream-1", "upstream-1"}, expectErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := AlertRuleGen(WithDependsOn(tc.dependsOn...))()
+			rule.UID = tc.uid
+			err := rule.ValidateDependsOn()
+			if tc.expectErr {
+				require.ErrorIs(t, err, ErrAlertRuleFailedValidation)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
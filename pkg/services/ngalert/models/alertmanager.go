@@ -1,5 +1,7 @@
 package models
 
+import "errors"
+
 const AlertConfigurationVersion = 1
 
 // AlertConfiguration represents a single version of the Alerting Engine Configuration.
@@ -52,3 +54,41 @@ func HistoricConfigFromAlertConfig(config AlertConfiguration) HistoricAlertConfi
 		AlertConfiguration: config,
 	}
 }
+
+var (
+	// ErrAlertmanagerStateNotFound is an error for a shared Alertmanager state that was not saved yet.
+	ErrAlertmanagerStateNotFound = errors.New("could not find alertmanager state")
+	// ErrAlertmanagerStateVersionConflict is an error for a shared Alertmanager state that was changed by another
+	// replica since it was read.
+	ErrAlertmanagerStateVersionConflict = errors.New("alertmanager state was changed concurrently")
+)
+
+// AlertmanagerState is the shared state of a component of the Alertmanager, like the notification log or silences,
+// that is synchronized between replicas through the database. Version is incremented with every update, so that
+// replicas do not overwrite changes they have not merged yet.
+type AlertmanagerState struct {
+	ID  int64  `xorm:"pk autoincr 'id'"`
+	Key string `xorm:"state_key"`
+	// State is the base64 encoded binary representation of the state.
+	State     string `xorm:"state"`
+	Version   int64  `xorm:"'version'"`
+	UpdatedAt int64  `xorm:"updated_at"`
+}
+
+// TableName is a XORM interface that defines the used table for this struct.
+func (s *AlertmanagerState) TableName() string {
+	return "alert_notifier_state"
+}
+
+// AlertmanagerPeer is a replica of the Alertmanager that synchronizes its state through the database.
+type AlertmanagerPeer struct {
+	ID   int64  `xorm:"pk autoincr 'id'"`
+	Name string `xorm:"name"`
+	// HeartbeatAt is the time of the last heartbeat of the replica in seconds since the epoch.
+	HeartbeatAt int64 `xorm:"heartbeat_at"`
+}
+
+// TableName is a XORM interface that defines the used table for this struct.
+func (p *AlertmanagerPeer) TableName() string {
+	return "alert_notifier_peer"
+}
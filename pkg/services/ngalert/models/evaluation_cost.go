@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+)
+
+// RuleEvaluationCost describes how expensive the evaluations of an alert rule are.
+type RuleEvaluationCost struct {
+	AlertRuleKey
+	Title        string
+	NamespaceUID string
+	RuleGroup    string
+	// Interval is the evaluation interval of the rule.
+	Interval time.Duration
+
+	// Evaluations is the number of evaluations since the rule was scheduled on this instance.
+	Evaluations    int64
+	LastEvaluation time.Time
+	LastDuration   time.Duration
+	MaxDuration    time.Duration
+	TotalDuration  time.Duration
+
+	// LastQueryCount is the number of data source queries executed by the last evaluation.
+	LastQueryCount int
+	// LastSeriesCount is the number of series returned by the data source queries of the last evaluation.
+	LastSeriesCount int
+	// LastDataSourceDuration is the time that the last evaluation spent executing data source queries.
+	LastDataSourceDuration time.Duration
+
+	// SlowEvaluations is the number of recent evaluations that took longer than the interval of the rule.
+	SlowEvaluations int
+	// Slow is true if the rule regularly takes longer to evaluate than its interval.
+	Slow bool
+}
+
+// AverageDuration returns the average duration of the evaluations of the rule.
+func (c RuleEvaluationCost) AverageDuration() time.Duration {
+	if c.Evaluations == 0 {
+		return 0
+	}
+	return c.TotalDuration / time.Duration(c.Evaluations)
+}
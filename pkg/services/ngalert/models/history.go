@@ -18,3 +18,43 @@ type HistoryQuery struct {
 	Limit        int
 	SignedInUser *user.SignedInUser
 }
+
+// StateHistoryEntry is a transition of the state of an alert instance that is stored by the SQL state history backend.
+type StateHistoryEntry struct {
+	ID           int64  `xorm:"pk autoincr 'id'"`
+	OrgID        int64  `xorm:"org_id"`
+	RuleUID      string `xorm:"rule_uid"`
+	RuleGroup    string `xorm:"rule_group"`
+	FolderUID    string `xorm:"folder_uid"`
+	DashboardUID string `xorm:"dashboard_uid"`
+	PanelID      int64  `xorm:"panel_id"`
+	Fingerprint  string `xorm:"fingerprint"`
+	// Labels are the labels of the alert instance without the private ones.
+	Labels        map[string]string `xorm:"labels"`
+	PreviousState string            `xorm:"previous_state"`
+	CurrentState  string            `xorm:"current_state"`
+	ErrorMessage  string            `xorm:"error_message"`
+	// Values is the JSON representation of the values of the evaluation that caused the transition.
+	Values    string `xorm:"state_values"`
+	Condition string `xorm:"rule_condition"`
+	// TimestampNano is the time of the transition in nanoseconds since the epoch.
+	TimestampNano int64 `xorm:"timestamp_nano"`
+}
+
+// TableName is a XORM interface that defines the used table for this struct.
+func (e *StateHistoryEntry) TableName() string {
+	return "alert_state_history"
+}
+
+// StateHistoryLabel is a label of a state history entry. Labels are stored separately, so that entries can be queried by them.
+type StateHistoryLabel struct {
+	ID        int64  `xorm:"pk autoincr 'id'"`
+	HistoryID int64  `xorm:"history_id"`
+	Key       string `xorm:"label_key"`
+	Value     string `xorm:"label_value"`
+}
+
+// TableName is a XORM interface that defines the used table for this struct.
+func (l *StateHistoryLabel) TableName() string {
+	return "alert_state_history_label"
+}
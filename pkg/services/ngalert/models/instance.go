@@ -14,6 +14,7 @@ type AlertInstance struct {
 	CurrentStateSince time.Time
 	CurrentStateEnd   time.Time
 	LastEvalTime      time.Time
+	ResultFingerprint string
 }
 
 type AlertInstanceKey struct {
@@ -36,6 +37,8 @@ const (
 	InstanceStateNoData InstanceStateType = "NoData"
 	// InstanceStateError is for an erroring alert.
 	InstanceStateError InstanceStateType = "Error"
+	// InstanceStateRecovering is for a firing alert whose condition is no longer met but has not met the keep firing for duration.
+	InstanceStateRecovering InstanceStateType = "Recovering"
 )
 
 // IsValid checks that the value of InstanceStateType is a valid
@@ -45,7 +48,8 @@ func (i InstanceStateType) IsValid() bool {
 		i == InstanceStateNormal ||
 		i == InstanceStateNoData ||
 		i == InstanceStatePending ||
-		i == InstanceStateError
+		i == InstanceStateError ||
+		i == InstanceStateRecovering
 }
 
 // ListAlertInstancesQuery is the query list alert Instances.
@@ -33,6 +33,10 @@ func TestInstanceStateType_IsValid(t *testing.T) {
 			instanceType:     InstanceStateError,
 			expectedValidity: true,
 		},
+		{
+			instanceType:     InstanceStateRecovering,
+			expectedValidity: true,
+		},
 		{
 			instanceType:     InstanceStateType("notAValidInstanceStateType"),
 			expectedValidity: false,
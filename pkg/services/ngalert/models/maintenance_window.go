@@ -0,0 +1,138 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/util"
+)
+
+var (
+	// ErrMaintenanceWindowNotFound is an error for an unknown maintenance window.
+	ErrMaintenanceWindowNotFound = errors.New("could not find maintenance window")
+	// ErrMaintenanceWindowFailedValidation is an error for an invalid maintenance window.
+	ErrMaintenanceWindowFailedValidation = errors.New("invalid maintenance window")
+)
+
+// MaintenanceWindow is a recurring period of time during which alerts that match the matchers are silenced.
+// Silences are created for every occurrence of the window when it starts, and expire when it ends.
+type MaintenanceWindow struct {
+	ID    int64  `xorm:"pk autoincr 'id'"`
+	OrgID int64  `xorm:"org_id"`
+	UID   string `xorm:"uid"`
+	Title string `xorm:"title"`
+	// Schedule is either a cron expression or an RRULE (RFC 5545) that defines when occurrences of the window start.
+	Schedule string `xorm:"schedule"`
+	// Timezone is the name of the location in which the schedule is evaluated. Defaults to UTC.
+	Timezone string `xorm:"timezone"`
+	// Duration is the length of every occurrence of the window.
+	Duration time.Duration `xorm:"duration"`
+	// FolderUID limits the window to alerts of rules in the folder. If it is empty, the window applies to the entire organization.
+	FolderUID string          `xorm:"folder_uid"`
+	Matchers  labels.Matchers `xorm:"matchers"`
+	Updated   time.Time       `xorm:"updated"`
+}
+
+// TableName is a XORM interface that defines the used table for this struct.
+func (mw *MaintenanceWindow) TableName() string {
+	return "alert_maintenance_window"
+}
+
+// ResourceType returns the resource type of the maintenance window. It allows it to be provisioned.
+func (mw *MaintenanceWindow) ResourceType() string {
+	return "maintenanceWindow"
+}
+
+// ResourceID returns the identifier of the maintenance window. It allows it to be provisioned.
+func (mw *MaintenanceWindow) ResourceID() string {
+	return mw.UID
+}
+
+// Location returns the location in which the schedule of the window is evaluated.
+func (mw *MaintenanceWindow) Location() (*time.Location, error) {
+	if mw.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(mw.Timezone)
+}
+
+// Recurrence parses the schedule of the window.
+func (mw *MaintenanceWindow) Recurrence() (Recurrence, error) {
+	loc, err := mw.Location()
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", mw.Timezone, err)
+	}
+	return ParseRecurrence(mw.Schedule, loc)
+}
+
+// ActiveOccurrence returns the start and the end of the occurrence of the window that is in progress at the moment now.
+// It returns false if no occurrence is in progress.
+func (mw *MaintenanceWindow) ActiveOccurrence(now time.Time) (time.Time, time.Time, bool, error) {
+	recurrence, err := mw.Recurrence()
+	if err != nil {
+		return time.Time{}, time.Time{}, false, err
+	}
+	// the first occurrence that starts after now-duration is the only one that can be in progress.
+	start := recurrence.Next(now.Add(-mw.Duration))
+	if start.IsZero() || start.After(now) {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	return start, start.Add(mw.Duration), true, nil
+}
+
+// SilenceMatchers returns the matchers of silences created for the window. They include the matchers of the window and
+// the matcher of the folder if the window is limited to it.
+func (mw *MaintenanceWindow) SilenceMatchers() (labels.Matchers, error) {
+	result := make(labels.Matchers, 0, len(mw.Matchers)+1)
+	result = append(result, mw.Matchers...)
+	if mw.FolderUID != "" {
+		m, err := labels.NewMatcher(labels.MatchEqual, alertingModels.NamespaceUIDLabel, mw.FolderUID)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// Validate checks that the maintenance window is valid.
+func (mw *MaintenanceWindow) Validate() error {
+	if mw.UID != "" && !util.IsValidShortUID(mw.UID) {
+		return fmt.Errorf("%w: cannot have invalid UID", ErrMaintenanceWindowFailedValidation)
+	}
+	if mw.Title == "" {
+		return fmt.Errorf("%w: title must not be empty", ErrMaintenanceWindowFailedValidation)
+	}
+	if mw.Duration <= 0 {
+		return fmt.Errorf("%w: duration must be positive", ErrMaintenanceWindowFailedValidation)
+	}
+	if _, err := mw.Recurrence(); err != nil {
+		return fmt.Errorf("%w: %s", ErrMaintenanceWindowFailedValidation, err)
+	}
+	matchers, err := mw.SilenceMatchers()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrMaintenanceWindowFailedValidation, err)
+	}
+	// Alertmanager rejects silences that match every alert.
+	for _, m := range matchers {
+		if !m.Matches("") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: either folder or at least one matcher that does not match the empty string must be specified", ErrMaintenanceWindowFailedValidation)
+}
+
+// ListMaintenanceWindowsQuery is the query for listing maintenance windows of an organization.
+type ListMaintenanceWindowsQuery struct {
+	OrgID int64
+}
+
+// GetMaintenanceWindowQuery is the query for retrieving a maintenance window by its UID.
+type GetMaintenanceWindowQuery struct {
+	OrgID int64
+	UID   string
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceWindowActiveOccurrence(t *testing.T) {
+	mw := MaintenanceWindow{
+		Schedule: "0 2 * * *",
+		Duration: 2 * time.Hour,
+	}
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		now    time.Time
+		active bool
+	}{
+		{name: "before the occurrence", now: day.Add(time.Hour)},
+		{name: "at the start of the occurrence", now: day.Add(2 * time.Hour), active: true},
+		{name: "during the occurrence", now: day.Add(3 * time.Hour), active: true},
+		{name: "at the end of the occurrence", now: day.Add(4 * time.Hour)},
+		{name: "after the occurrence", now: day.Add(5 * time.Hour)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, active, err := mw.ActiveOccurrence(tc.now)
+			require.NoError(t, err)
+			require.Equal(t, tc.active, active)
+			if tc.active {
+				require.Equal(t, day.Add(2*time.Hour), start)
+				require.Equal(t, day.Add(4*time.Hour), end)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	matcher := func(t labels.MatchType, name, value string) *labels.Matcher {
+		return &labels.Matcher{Type: t, Name: name, Value: value}
+	}
+	valid := func() MaintenanceWindow {
+		return MaintenanceWindow{
+			UID:      "window",
+			Title:    "window",
+			Schedule: "0 2 * * *",
+			Duration: time.Hour,
+			Matchers: labels.Matchers{matcher(labels.MatchEqual, "service", "db")},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		mutate func(mw *MaintenanceWindow)
+		err    string
+	}{
+		{name: "valid window", mutate: func(mw *MaintenanceWindow) {}},
+		{name: "window limited to a folder", mutate: func(mw *MaintenanceWindow) {
+			mw.FolderUID = "folder"
+			mw.Matchers = nil
+		}},
+		{name: "invalid UID", mutate: func(mw *MaintenanceWindow) { mw.UID = "in valid" }, err: "invalid UID"},
+		{name: "empty title", mutate: func(mw *MaintenanceWindow) { mw.Title = "" }, err: "title must not be empty"},
+		{name: "zero duration", mutate: func(mw *MaintenanceWindow) { mw.Duration = 0 }, err: "duration must be positive"},
+		{name: "invalid schedule", mutate: func(mw *MaintenanceWindow) { mw.Schedule = "never" }, err: "invalid cron expression"},
+		{name: "invalid timezone", mutate: func(mw *MaintenanceWindow) { mw.Timezone = "Nowhere/Nothing" }, err: "invalid timezone"},
+		{name: "no matchers", mutate: func(mw *MaintenanceWindow) { mw.Matchers = nil }, err: "at least one matcher"},
+		{name: "matchers match every alert", mutate: func(mw *MaintenanceWindow) {
+			mw.Matchers = labels.Matchers{matcher(labels.MatchNotEqual, "service", "db")}
+		}, err: "at least one matcher"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mw := valid()
+			tc.mutate(&mw)
+			err := mw.Validate()
+			if tc.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrMaintenanceWindowFailedValidation)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestMaintenanceWindowSilenceMatchers(t *testing.T) {
+	m, err := labels.NewMatcher(labels.MatchEqual, "service", "db")
+	require.NoError(t, err)
+	mw := MaintenanceWindow{FolderUID: "folder", Matchers: labels.Matchers{m}}
+
+	matchers, err := mw.SilenceMatchers()
+	require.NoError(t, err)
+	require.Len(t, matchers, 2)
+	require.Equal(t, m, matchers[0])
+	require.Equal(t, alertingModels.NamespaceUIDLabel, matchers[1].Name)
+	require.Equal(t, "folder", matchers[1].Value)
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// NotificationDeliveryStatus is the outcome of an attempt to send a notification.
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliverySuccess NotificationDeliveryStatus = "success"
+	NotificationDeliveryFailed  NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery is an attempt of an integration of a contact point to send a notification.
+type NotificationDelivery struct {
+	ID       int64  `xorm:"pk autoincr 'id'"`
+	OrgID    int64  `xorm:"org_id"`
+	Receiver string `xorm:"receiver"`
+	// Integration is the type of the integration, for example "email" or "slack".
+	Integration      string `xorm:"integration"`
+	IntegrationIndex int    `xorm:"integration_index"`
+	GroupKey         string `xorm:"group_key"`
+	// AlertFingerprints are the fingerprints of the alerts in the notification.
+	AlertFingerprints []string                   `xorm:"alert_fingerprints"`
+	Status            NotificationDeliveryStatus `xorm:"status"`
+	Error             string                     `xorm:"error"`
+	// Retry is true if the attempt failed with an error that the notification pipeline retries.
+	Retry bool `xorm:"retry"`
+	// DurationMs is the time it took to send the notification in milliseconds.
+	DurationMs int64 `xorm:"duration_ms"`
+	// TimestampNano is the time of the attempt in nanoseconds since the epoch.
+	TimestampNano int64 `xorm:"timestamp_nano"`
+}
+
+// TableName is a XORM interface that defines the used table for this struct.
+func (d *NotificationDelivery) TableName() string {
+	return "alert_notification_delivery"
+}
+
+// NotificationDeliveryQuery is a query for the notification deliveries of a contact point.
+type NotificationDeliveryQuery struct {
+	OrgID    int64
+	Receiver string
+	// From excludes the deliveries that are older than the time if it is not zero.
+	From  time.Time
+	Limit int
+}
@@ -0,0 +1,435 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Recurrence calculates the start times of a recurring event.
+type Recurrence interface {
+	// Next returns the first start time of the event that is after t. It returns the zero time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// ParseRecurrence parses a schedule that is either a standard cron expression with five fields or
+// an RRULE (RFC 5545) with a DTSTART, for example:
+//
+//	DTSTART:20230101T020000
+//	RRULE:FREQ=WEEKLY;BYDAY=SA,SU
+//
+// Times without a timezone are evaluated in the location loc.
+func ParseRecurrence(schedule string, loc *time.Location) (Recurrence, error) {
+	schedule = strings.TrimSpace(schedule)
+	if schedule == "" {
+		return nil, errors.New("schedule must not be empty")
+	}
+	if strings.Contains(strings.ToUpper(schedule), "FREQ=") {
+		return parseRRule(schedule, loc)
+	}
+	s, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+	return cronRecurrence{schedule: s, loc: loc}, nil
+}
+
+type cronRecurrence struct {
+	schedule cron.Schedule
+	loc      *time.Location
+}
+
+func (c cronRecurrence) Next(t time.Time) time.Time {
+	return c.schedule.Next(t.In(c.loc))
+}
+
+type frequency int
+
+const (
+	hourly frequency = iota
+	daily
+	weekly
+	monthly
+	yearly
+)
+
+var frequencies = map[string]frequency{
+	"HOURLY":  hourly,
+	"DAILY":   daily,
+	"WEEKLY":  weekly,
+	"MONTHLY": monthly,
+	"YEARLY":  yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+const (
+	rruleTimeLayout    = "20060102T150405"
+	rruleUTCTimeLayout = "20060102T150405Z"
+	// maxRRulePeriods limits the number of periods that are checked to find the next occurrence,
+	// so that rules whose parts never match, such as the 30th of February, do not loop forever.
+	maxRRulePeriods = 10000
+)
+
+// rrule is a subset of RFC 5545 recurrence rules. It supports the parts FREQ (HOURLY to YEARLY), INTERVAL, COUNT,
+// UNTIL, BYMONTH, BYMONTHDAY, BYDAY (without numeric prefixes), BYHOUR and BYMINUTE. Weeks start on Monday.
+type rrule struct {
+	dtstart    time.Time
+	freq       frequency
+	interval   int
+	count      int
+	until      time.Time
+	byMonth    []int
+	byMonthDay []int
+	byDay      []time.Weekday
+	byHour     []int
+	byMinute   []int
+}
+
+func parseRRule(schedule string, loc *time.Location) (*rrule, error) {
+	r := &rrule{interval: 1}
+	hasFreq := false
+	for _, line := range strings.Split(schedule, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		upper := strings.ToUpper(line)
+		if strings.HasPrefix(upper, "DTSTART") {
+			t, err := parseDTStart(line, loc)
+			if err != nil {
+				return nil, err
+			}
+			r.dtstart = t
+			continue
+		}
+		if strings.HasPrefix(upper, "RRULE:") {
+			line = line[len("RRULE:"):]
+		}
+		for _, part := range strings.Split(line, ";") {
+			if part == "" {
+				continue
+			}
+			name, value, ok := strings.Cut(part, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid RRULE part %q", part)
+			}
+			var err error
+			switch strings.ToUpper(name) {
+			case "FREQ":
+				f, ok := frequencies[strings.ToUpper(value)]
+				if !ok {
+					return nil, fmt.Errorf("unsupported frequency %q", value)
+				}
+				r.freq = f
+				hasFreq = true
+			case "INTERVAL":
+				r.interval, err = strconv.Atoi(value)
+				if err == nil && r.interval < 1 {
+					err = errors.New("must be positive")
+				}
+			case "COUNT":
+				r.count, err = strconv.Atoi(value)
+				if err == nil && r.count < 1 {
+					err = errors.New("must be positive")
+				}
+			case "UNTIL":
+				r.until, err = parseRRuleTime(value, loc)
+			case "BYMONTH":
+				r.byMonth, err = parseIntList(value, 1, 12, false)
+			case "BYMONTHDAY":
+				r.byMonthDay, err = parseIntList(value, 1, 31, true)
+			case "BYHOUR":
+				r.byHour, err = parseIntList(value, 0, 23, false)
+			case "BYMINUTE":
+				r.byMinute, err = parseIntList(value, 0, 59, false)
+			case "BYDAY":
+				for _, d := range strings.Split(value, ",") {
+					wd, ok := weekdays[strings.ToUpper(d)]
+					if !ok {
+						return nil, fmt.Errorf("unsupported BYDAY value %q", d)
+					}
+					r.byDay = append(r.byDay, wd)
+				}
+			case "WKST":
+				if strings.ToUpper(value) != "MO" {
+					return nil, errors.New("only WKST=MO is supported")
+				}
+			default:
+				return nil, fmt.Errorf("unsupported RRULE part %q", name)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", strings.ToUpper(name), err)
+			}
+		}
+	}
+	if !hasFreq {
+		return nil, errors.New("RRULE must specify FREQ")
+	}
+	if r.dtstart.IsZero() {
+		return nil, errors.New("RRULE must be accompanied by DTSTART")
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, errors.New("RRULE must not specify both COUNT and UNTIL")
+	}
+	sort.Ints(r.byHour)
+	sort.Ints(r.byMinute)
+	return r, nil
+}
+
+// parseDTStart parses lines DTSTART:20230101T020000Z, DTSTART:20230101T020000 and DTSTART;TZID=Europe/Paris:20230101T020000.
+func parseDTStart(line string, loc *time.Location) (time.Time, error) {
+	params, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid DTSTART %q", line)
+	}
+	for _, param := range strings.Split(params, ";")[1:] {
+		name, tz, _ := strings.Cut(param, "=")
+		if strings.ToUpper(name) != "TZID" {
+			return time.Time{}, fmt.Errorf("unsupported DTSTART parameter %q", name)
+		}
+		l, err := time.LoadLocation(tz)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid DTSTART timezone %q: %w", tz, err)
+		}
+		loc = l
+	}
+	t, err := parseRRuleTime(value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid DTSTART: %w", err)
+	}
+	return t, nil
+}
+
+func parseRRuleTime(value string, loc *time.Location) (time.Time, error) {
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(rruleUTCTimeLayout, value)
+	}
+	return time.ParseInLocation(rruleTimeLayout, value, loc)
+}
+
+func parseIntList(value string, min, max int, allowNegative bool) ([]int, error) {
+	var result []int
+	for _, s := range strings.Split(value, ",") {
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		abs := i
+		if allowNegative && i < 0 {
+			abs = -i
+		}
+		if abs < min || abs > max {
+			return nil, fmt.Errorf("value %d is out of range", i)
+		}
+		result = append(result, i)
+	}
+	return result, nil
+}
+
+func (r *rrule) Next(t time.Time) time.Time {
+	loc := r.dtstart.Location()
+	t = t.In(loc)
+	// the occurrences have to be counted from the very beginning if the number of them is limited.
+	first := 0
+	if r.count == 0 {
+		first = r.periodBefore(t)
+	}
+	occurrences := 0
+	for k := first; k < first+maxRRulePeriods; k++ {
+		candidates := r.candidates(k)
+		for _, c := range candidates {
+			if c.Before(r.dtstart) {
+				continue
+			}
+			if !r.until.IsZero() && c.After(r.until) {
+				return time.Time{}
+			}
+			occurrences++
+			if r.count > 0 && occurrences > r.count {
+				return time.Time{}
+			}
+			if c.After(t) {
+				return c
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// periodBefore returns the index of a period that starts before t. It is used to skip periods that are not relevant.
+func (r *rrule) periodBefore(t time.Time) int {
+	var periods int
+	switch r.freq {
+	case hourly:
+		periods = int(t.Sub(r.dtstart) / time.Hour)
+	case daily:
+		periods = int(t.Sub(r.dtstart) / (24 * time.Hour))
+	case weekly:
+		periods = int(t.Sub(r.dtstart) / (7 * 24 * time.Hour))
+	case monthly:
+		periods = (t.Year()-r.dtstart.Year())*12 + int(t.Month()) - int(r.dtstart.Month())
+	case yearly:
+		periods = t.Year() - r.dtstart.Year()
+	}
+	// step back one more period to account for the daylight saving time changes.
+	k := periods/r.interval - 1
+	if k < 0 {
+		return 0
+	}
+	return k
+}
+
+// candidates returns the sorted start times in the k-th period of the rule.
+func (r *rrule) candidates(k int) []time.Time {
+	loc := r.dtstart.Location()
+	start := r.dtstart
+	step := k * r.interval
+	var days []time.Time
+	switch r.freq {
+	case hourly:
+		h := time.Date(start.Year(), start.Month(), start.Day(), start.Hour(), 0, 0, 0, loc).Add(time.Duration(step) * time.Hour)
+		if !r.matchesDay(h) || !containsOrEmpty(r.byHour, h.Hour()) {
+			return nil
+		}
+		return r.atMinutes(h, []int{h.Hour()})
+	case daily:
+		d := time.Date(start.Year(), start.Month(), start.Day()+step, 0, 0, 0, 0, loc)
+		if r.matchesDay(d) {
+			days = append(days, d)
+		}
+	case weekly:
+		monday := start.Day() - (int(start.Weekday())+6)%7
+		for i := 0; i < 7; i++ {
+			d := time.Date(start.Year(), start.Month(), monday+7*step+i, 0, 0, 0, 0, loc)
+			wd := r.byDay
+			if len(wd) == 0 {
+				wd = []time.Weekday{start.Weekday()}
+			}
+			if containsWeekday(wd, d.Weekday()) && containsOrEmpty(r.byMonth, int(d.Month())) {
+				days = append(days, d)
+			}
+		}
+	case monthly:
+		m := time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, loc)
+		if containsOrEmpty(r.byMonth, int(m.Month())) {
+			days = r.daysInMonth(m)
+		}
+	case yearly:
+		months := r.byMonth
+		if len(months) == 0 {
+			months = []int{int(start.Month())}
+		}
+		for _, month := range months {
+			days = append(days, r.daysInMonth(time.Date(start.Year()+step, time.Month(month), 1, 0, 0, 0, 0, loc))...)
+		}
+	}
+	hours := r.byHour
+	if len(hours) == 0 {
+		hours = []int{start.Hour()}
+	}
+	var result []time.Time
+	for _, d := range days {
+		result = append(result, r.atMinutes(d, hours)...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Before(result[j])
+	})
+	return result
+}
+
+// atMinutes returns the times of the day d at the hours and the minutes of the rule.
+func (r *rrule) atMinutes(d time.Time, hours []int) []time.Time {
+	minutes := r.byMinute
+	if len(minutes) == 0 {
+		minutes = []int{r.dtstart.Minute()}
+	}
+	result := make([]time.Time, 0, len(hours)*len(minutes))
+	for _, h := range hours {
+		for _, m := range minutes {
+			result = append(result, time.Date(d.Year(), d.Month(), d.Day(), h, m, r.dtstart.Second(), 0, d.Location()))
+		}
+	}
+	return result
+}
+
+// daysInMonth returns the days of the month m that match the rule.
+func (r *rrule) daysInMonth(m time.Time) []time.Time {
+	lastDay := time.Date(m.Year(), m.Month()+1, 0, 0, 0, 0, 0, m.Location()).Day()
+	var result []time.Time
+	for day := 1; day <= lastDay; day++ {
+		d := time.Date(m.Year(), m.Month(), day, 0, 0, 0, 0, m.Location())
+		switch {
+		case len(r.byMonthDay) > 0:
+			if !containsMonthDay(r.byMonthDay, day, lastDay) || !containsWeekdayOrEmpty(r.byDay, d.Weekday()) {
+				continue
+			}
+		case len(r.byDay) > 0:
+			if !containsWeekday(r.byDay, d.Weekday()) {
+				continue
+			}
+		default:
+			if day != r.dtstart.Day() {
+				continue
+			}
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// matchesDay checks that the day d matches the BYMONTH, BYMONTHDAY and BYDAY parts of the rule.
+func (r *rrule) matchesDay(d time.Time) bool {
+	lastDay := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, d.Location()).Day()
+	return containsOrEmpty(r.byMonth, int(d.Month())) &&
+		(len(r.byMonthDay) == 0 || containsMonthDay(r.byMonthDay, d.Day(), lastDay)) &&
+		containsWeekdayOrEmpty(r.byDay, d.Weekday())
+}
+
+func containsOrEmpty(values []int, v int) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// containsMonthDay checks whether the day is in the list of days. Negative days are counted from the end of the month.
+func containsMonthDay(days []int, day, lastDay int) bool {
+	for _, d := range days {
+		if d == day || (d < 0 && lastDay+d+1 == day) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func containsWeekdayOrEmpty(days []time.Weekday, day time.Weekday) bool {
+	return len(days) == 0 || containsWeekday(days, day)
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		schedule string
+		loc      *time.Location
+		from     time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "cron expression",
+			schedule: "30 2 * * *",
+			loc:      time.UTC,
+			from:     time.Date(2023, 3, 1, 3, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2023, 3, 2, 2, 30, 0, 0, time.UTC),
+				time.Date(2023, 3, 3, 2, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "cron expression is evaluated in the location",
+			schedule: "0 2 * * *",
+			loc:      berlin,
+			from:     time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				// midnight in UTC is 1am in Berlin
+				time.Date(2023, 3, 1, 2, 0, 0, 0, berlin),
+				time.Date(2023, 3, 2, 2, 0, 0, 0, berlin),
+			},
+		},
+		{
+			name:     "daily rule with interval",
+			schedule: "DTSTART:20230101T020000\nRRULE:FREQ=DAILY;INTERVAL=2",
+			loc:      time.UTC,
+			from:     time.Date(2023, 1, 4, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2023, 1, 5, 2, 0, 0, 0, time.UTC),
+				time.Date(2023, 1, 7, 2, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "weekly rule with days",
+			schedule: "DTSTART:20230102T080000Z\nRRULE:FREQ=WEEKLY;BYDAY=SA,SU",
+			loc:      time.UTC,
+			from:     time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2023, 1, 7, 8, 0, 0, 0, time.UTC),
+				time.Date(2023, 1, 8, 8, 0, 0, 0, time.UTC),
+				time.Date(2023, 1, 14, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "monthly rule with the last day of the month",
+			schedule: "DTSTART:20230101T230000\nRRULE:FREQ=MONTHLY;BYMONTHDAY=-1",
+			loc:      time.UTC,
+			from:     time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2023, 1, 31, 23, 0, 0, 0, time.UTC),
+				time.Date(2023, 2, 28, 23, 0, 0, 0, time.UTC),
+				time.Date(2023, 3, 31, 23, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "rule with count",
+			schedule: "DTSTART:20230101T000000\nRRULE:FREQ=DAILY;COUNT=2",
+			loc:      time.UTC,
+			from:     time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name:     "rule with until",
+			schedule: "DTSTART:20230101T000000\nRRULE:FREQ=DAILY;UNTIL=20230102T000000",
+			loc:      time.UTC,
+			from:     time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+				{},
+			},
+		},
+		{
+			name:     "rule with timezone of DTSTART",
+			schedule: "DTSTART;TZID=Europe/Berlin:20230325T020000\nRRULE:FREQ=DAILY",
+			loc:      time.UTC,
+			from:     time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC),
+			expected: []time.Time{
+				// 2am does not exist on the day of the change to summer time
+				time.Date(2023, 3, 26, 3, 0, 0, 0, berlin),
+				time.Date(2023, 3, 27, 2, 0, 0, 0, berlin),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tc.schedule, tc.loc)
+			require.NoError(t, err)
+			next := tc.from
+			for _, expected := range tc.expected {
+				next = r.Next(next)
+				if expected.IsZero() {
+					require.True(t, next.IsZero(), "expected no more occurrences but got %s", next)
+					break
+				}
+				require.Truef(t, expected.Equal(next), "expected %s but got %s", expected, next)
+			}
+		})
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		schedule string
+		err      string
+	}{
+		{name: "empty schedule", schedule: " ", err: "schedule must not be empty"},
+		{name: "invalid cron expression", schedule: "* * *", err: "invalid cron expression"},
+		{name: "unsupported frequency", schedule: "DTSTART:20230101T000000\nRRULE:FREQ=SECONDLY", err: "unsupported frequency"},
+		{name: "unsupported part", schedule: "DTSTART:20230101T000000\nRRULE:FREQ=DAILY;BYSETPOS=1", err: "unsupported RRULE part"},
+		{name: "invalid part", schedule: "DTSTART:20230101T000000\nRRULE:FREQ=DAILY;COUNT", err: "invalid RRULE part"},
+		{name: "invalid DTSTART", schedule: "DTSTART:2023\nRRULE:FREQ=DAILY", err: "invalid DTSTART"},
+		{name: "invalid timezone", schedule: "DTSTART;TZID=Nowhere/Nothing:20230101T000000\nRRULE:FREQ=DAILY", err: "invalid DTSTART timezone"},
+		{name: "value out of range", schedule: "DTSTART:20230101T000000\nRRULE:FREQ=DAILY;BYHOUR=24", err: "out of range"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseRecurrence(tc.schedule, time.UTC)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
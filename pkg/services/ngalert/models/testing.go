@@ -179,6 +179,30 @@ func WithFor(duration time.Duration) AlertRuleMutator {
 	}
 }
 
+func WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
+func WithDependsOn(uids ...string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.DependsOn = uids
+	}
+}
+
+// WithRecord makes the rule a recording rule that records its condition as the metric.
+func WithRecord(metric string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Record = &Record{
+			Metric: metric,
+			From:   rule.Condition,
+		}
+		rule.For = 0
+		rule.KeepFiringFor = 0
+	}
+}
+
 func GenerateAlertLabels(count int, prefix string) data.Labels {
 	labels := make(data.Labels, count)
 	for i := 0; i < count; i++ {
@@ -271,6 +295,17 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
+	}
+
+	if r.DependsOn != nil {
+		result.DependsOn = make([]string, len(r.DependsOn))
+		copy(result.DependsOn, r.DependsOn)
+	}
+
+	if r.Record != nil {
+		record := *r.Record
+		result.Record = &record
 	}
 
 	if r.DashboardUID != nil {
@@ -370,6 +405,7 @@ func AlertInstanceGen(mutators ...AlertInstanceMutator) *AlertInstance {
 			InstanceStatePending,
 			InstanceStateNoData,
 			InstanceStateError,
+			InstanceStateRecovering,
 		}
 		return s[rand.Intn(len(s))]
 	}
@@ -42,6 +42,8 @@ var silenceMaintenanceInterval = 15 * time.Minute
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	store.NotificationDeliveryStore
+	store.AlertmanagerStateStore
 }
 
 type Alertmanager struct {
@@ -118,6 +120,16 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		},
 	}
 
+	// If the state is shared through the database, it is persisted there instead of the kvstore.
+	if p, ok := peer.(*dbPeer); ok {
+		silencesOptions.maintenanceFunc = func(alertingNotify.State) (int64, error) {
+			return p.Persist(context.Background(), silencesStateKey(orgID))
+		}
+		nflogOptions.maintenanceFunc = func(alertingNotify.State) (int64, error) {
+			return p.Persist(context.Background(), notificationLogStateKey(orgID))
+		}
+	}
+
 	amcfg := &alertingNotify.GrafanaAlertmanagerConfig{
 		WorkingDirectory:   filepath.Join(cfg.DataPath, workingDir, strconv.Itoa(int(orgID))),
 		ExternalURL:        cfg.AppURL,
@@ -377,6 +389,9 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIRe
 	if err != nil {
 		return nil, err
 	}
+	if am.Settings.UnifiedAlerting.NotificationDeliveryLog.Enabled {
+		integrations = withDeliveryLog(integrations, am.Store, am.orgID, receiver.Name, am.logger)
+	}
 	return integrations, nil
 }
 
@@ -0,0 +1,269 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/alertmanager/cluster"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// dbPeerMaxSyncAttempts is how many times a state is merged and saved again if another replica saved it concurrently.
+	dbPeerMaxSyncAttempts = 5
+	dbPeerSyncTimeout     = 10 * time.Second
+)
+
+// notificationLogStateKey returns the key that the Alertmanager of the organization registers its notification log with.
+func notificationLogStateKey(orgID int64) string {
+	return fmt.Sprintf("notificationlog:%d", orgID)
+}
+
+// silencesStateKey returns the key that the Alertmanager of the organization registers its silences with.
+func silencesStateKey(orgID int64) string {
+	return fmt.Sprintf("silences:%d", orgID)
+}
+
+// dbPeer is a cluster peer that shares the notification log and silences between replicas through the database
+// instead of gossip or Redis. Replicas merge the state that other replicas saved into their own state and save the
+// result. Saving uses optimistic versioning, so that a replica never overwrites changes it has not merged yet.
+// The position of a replica is determined by the sorted names of the replicas that sent a heartbeat recently.
+type dbPeer struct {
+	name         string
+	store        store.AlertmanagerStateStore
+	logger       log.Logger
+	syncInterval time.Duration
+
+	states    map[string]*dbPeerState
+	statesMtx sync.RWMutex
+
+	members    []string
+	membersMtx sync.RWMutex
+
+	readyc    chan struct{}
+	readyOnce sync.Once
+	syncc     chan struct{}
+	shutdownc chan struct{}
+	donec     chan struct{}
+}
+
+type dbPeerState struct {
+	state cluster.State
+	// version is the version of the saved state that was merged last.
+	version int64
+	// dirty is true if the state changed locally since it was saved.
+	dirty atomic.Bool
+	// mtx makes sure that a state is not synchronized concurrently.
+	mtx sync.Mutex
+}
+
+func newDBPeer(st store.AlertmanagerStateStore, logger log.Logger, syncInterval time.Duration) *dbPeer {
+	p := &dbPeer{
+		name:         "peer-" + uuid.New().String(),
+		store:        st,
+		logger:       logger,
+		syncInterval: syncInterval,
+		states:       map[string]*dbPeerState{},
+		members:      []string{},
+		readyc:       make(chan struct{}),
+		syncc:        make(chan struct{}, 1),
+		shutdownc:    make(chan struct{}),
+		donec:        make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+func (p *dbPeer) run() {
+	defer close(p.donec)
+	p.heartbeat()
+	ticker := time.NewTicker(p.syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.heartbeat()
+			p.syncAll(false)
+		case <-p.syncc:
+			p.syncAll(true)
+		case <-p.shutdownc:
+			p.syncAll(true)
+			return
+		}
+	}
+}
+
+// heartbeat records that the peer is alive and updates the members of the cluster.
+func (p *dbPeer) heartbeat() {
+	ctx, cancel := context.WithTimeout(context.Background(), dbPeerSyncTimeout)
+	defer cancel()
+	now := time.Now()
+	if err := p.store.HeartbeatAlertmanagerPeer(ctx, p.name, now); err != nil {
+		p.logger.Error("Failed to send heartbeat", "peer", p.name, "error", err)
+	}
+	members, err := p.store.GetAlertmanagerPeers(ctx, now.Add(-heartbeatTimeout))
+	if err != nil {
+		// Keep the last known members, so that the replicas do not all send notifications at once.
+		p.logger.Error("Failed to get the members of the cluster", "error", err)
+		return
+	}
+	p.membersMtx.Lock()
+	p.members = members
+	p.membersMtx.Unlock()
+	p.readyOnce.Do(func() { close(p.readyc) })
+
+	if _, err := p.store.DeleteAlertmanagerPeersBefore(ctx, now.Add(-heartbeatTimeout)); err != nil {
+		p.logger.Warn("Failed to delete stale members of the cluster", "error", err)
+	}
+}
+
+// syncAll synchronizes all states. If onlyDirty is true, only the states that changed locally are synchronized.
+func (p *dbPeer) syncAll(onlyDirty bool) {
+	p.statesMtx.RLock()
+	states := make(map[string]*dbPeerState, len(p.states))
+	for key, s := range p.states {
+		states[key] = s
+	}
+	p.statesMtx.RUnlock()
+
+	for key, s := range states {
+		if onlyDirty && !s.dirty.Load() {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), dbPeerSyncTimeout)
+		if _, err := p.syncState(ctx, key, s); err != nil {
+			p.logger.Error("Failed to synchronize state", "key", key, "error", err)
+		}
+		cancel()
+	}
+}
+
+// syncState merges the saved state into the local state, and saves the result if the local state changed.
+// It returns the size of the saved state.
+func (p *dbPeer) syncState(ctx context.Context, key string, s *dbPeerState) (int64, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for attempt := 0; attempt < dbPeerMaxSyncAttempts; attempt++ {
+		var version int64
+		saved, err := p.store.GetAlertmanagerState(ctx, key)
+		if err != nil && !errors.Is(err, models.ErrAlertmanagerStateNotFound) {
+			return 0, err
+		}
+		if saved != nil {
+			version = saved.Version
+			if saved.Version != s.version {
+				b, err := decode(saved.State)
+				if err != nil {
+					return 0, fmt.Errorf("failed to decode state: %w", err)
+				}
+				if err := s.state.Merge(b); err != nil {
+					return 0, fmt.Errorf("failed to merge state: %w", err)
+				}
+				s.version = saved.Version
+			}
+		}
+		if !s.dirty.Swap(false) {
+			return 0, nil
+		}
+
+		b, err := s.state.MarshalBinary()
+		if err != nil {
+			s.dirty.Store(true)
+			return 0, fmt.Errorf("failed to marshal state: %w", err)
+		}
+		update := &models.AlertmanagerState{Key: key, State: encode(b), Version: version}
+		err = p.store.SaveAlertmanagerState(ctx, update)
+		if errors.Is(err, models.ErrAlertmanagerStateVersionConflict) {
+			// Another replica saved the state in the meantime. Merge its changes and try again.
+			s.dirty.Store(true)
+			continue
+		}
+		if err != nil {
+			s.dirty.Store(true)
+			return 0, err
+		}
+		s.version = update.Version
+		return int64(len(b)), nil
+	}
+	return 0, fmt.Errorf("state was changed concurrently %d times", dbPeerMaxSyncAttempts)
+}
+
+// Persist synchronizes the state with the key and returns the size of the saved state.
+func (p *dbPeer) Persist(ctx context.Context, key string) (int64, error) {
+	p.statesMtx.RLock()
+	s, ok := p.states[key]
+	p.statesMtx.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("unknown state %s", key)
+	}
+	s.dirty.Store(true)
+	return p.syncState(ctx, key, s)
+}
+
+func (p *dbPeer) AddState(key string, state cluster.State, _ prometheus.Registerer) cluster.ClusterChannel {
+	s := &dbPeerState{state: state}
+	p.statesMtx.Lock()
+	p.states[key] = s
+	p.statesMtx.Unlock()
+
+	// Load the state that other replicas saved before the Alertmanager starts sending notifications.
+	ctx, cancel := context.WithTimeout(context.Background(), dbPeerSyncTimeout)
+	defer cancel()
+	if _, err := p.syncState(ctx, key, s); err != nil {
+		p.logger.Error("Failed to load state", "key", key, "error", err)
+	}
+	return &dbChannel{p: p, state: s}
+}
+
+// Position returns the position of the peer in the sorted list of members of the cluster.
+func (p *dbPeer) Position() int {
+	p.membersMtx.RLock()
+	defer p.membersMtx.RUnlock()
+	for i, member := range p.members {
+		if member == p.name {
+			return i
+		}
+	}
+	return 0
+}
+
+func (p *dbPeer) WaitReady(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.readyc:
+		return nil
+	}
+}
+
+// Shutdown saves the local changes and stops the peer.
+func (p *dbPeer) Shutdown() {
+	p.logger.Info("Stopping database peer...")
+	close(p.shutdownc)
+	<-p.donec
+}
+
+// dbChannel marks the state as changed when the Alertmanager broadcasts a change, so that it is saved right away.
+type dbChannel struct {
+	p     *dbPeer
+	state *dbPeerState
+}
+
+func (c *dbChannel) Broadcast([]byte) {
+	c.state.dirty.Store(true)
+	select {
+	case c.p.syncc <- struct{}{}:
+	default:
+		// A synchronization is pending already.
+	}
+}
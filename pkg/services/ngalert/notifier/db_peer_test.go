@@ -0,0 +1,120 @@
+package notifier
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// fakeSetState is a state that merges sets of entries like the notification log and silences do.
+type fakeSetState struct {
+	mtx     sync.Mutex
+	entries map[string]struct{}
+}
+
+func newFakeSetState(entries ...string) *fakeSetState {
+	s := &fakeSetState{entries: map[string]struct{}{}}
+	s.add(entries...)
+	return s
+}
+
+func (s *fakeSetState) add(entries ...string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, e := range entries {
+		s.entries[e] = struct{}{}
+	}
+}
+
+func (s *fakeSetState) list() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	result := make([]string, 0, len(s.entries))
+	for e := range s.entries {
+		result = append(result, e)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (s *fakeSetState) MarshalBinary() ([]byte, error) {
+	return []byte(strings.Join(s.list(), ",")), nil
+}
+
+func (s *fakeSetState) Merge(b []byte) error {
+	if len(b) > 0 {
+		s.add(strings.Split(string(b), ",")...)
+	}
+	return nil
+}
+
+func TestDBPeer(t *testing.T) {
+	ctx := context.Background()
+	st := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	key := notificationLogStateKey(1)
+
+	a := newDBPeer(st, &logtest.Fake{}, time.Hour)
+	b := newDBPeer(st, &logtest.Fake{}, time.Hour)
+	t.Cleanup(a.Shutdown)
+	t.Cleanup(b.Shutdown)
+
+	stateA := newFakeSetState("x")
+	a.AddState(key, stateA, nil)
+	_, err := a.Persist(ctx, key)
+	require.NoError(t, err)
+
+	t.Run("should load the saved state when a state is added", func(t *testing.T) {
+		stateB := newFakeSetState()
+		b.AddState(key, stateB, nil)
+		require.Equal(t, []string{"x"}, stateB.list())
+
+		t.Run("should merge concurrent changes instead of overwriting them", func(t *testing.T) {
+			stateB.add("y")
+			_, err := b.Persist(ctx, key)
+			require.NoError(t, err)
+
+			// a has not seen the changes of b yet, so its first attempt to save conflicts.
+			stateA.add("z")
+			_, err = a.Persist(ctx, key)
+			require.NoError(t, err)
+			require.Equal(t, []string{"x", "y", "z"}, stateA.list())
+
+			saved, err := st.GetAlertmanagerState(ctx, key)
+			require.NoError(t, err)
+			savedBytes, err := decode(saved.State)
+			require.NoError(t, err)
+			require.Equal(t, "x,y,z", string(savedBytes))
+
+			b.syncAll(false)
+			require.Equal(t, []string{"x", "y", "z"}, stateB.list())
+		})
+	})
+
+	t.Run("should save changes that were broadcast", func(t *testing.T) {
+		state := newFakeSetState()
+		ch := a.AddState(silencesStateKey(1), state, nil)
+		state.add("silence")
+		ch.Broadcast(nil)
+		require.Eventually(t, func() bool {
+			saved, err := st.GetAlertmanagerState(ctx, silencesStateKey(1))
+			return err == nil && saved.State == encode([]byte("silence"))
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should assign different positions to the peers", func(t *testing.T) {
+		require.NoError(t, a.WaitReady(ctx))
+		require.NoError(t, b.WaitReady(ctx))
+		// Both peers must see each other, a heartbeat updates the members.
+		a.heartbeat()
+		b.heartbeat()
+		require.ElementsMatch(t, []int{0, 1}, []int{a.Position(), b.Position()})
+	})
+}
@@ -0,0 +1,137 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// deliveryLogSaveTimeout is the maximum time that saving a delivery attempt can take. Saving uses its own context,
+// so that attempts that failed because the context of the notification expired are logged as well.
+const deliveryLogSaveTimeout = 5 * time.Second
+
+// deliveryLogger is a notifier that records every attempt of an integration to send a notification
+// in the notification delivery log.
+type deliveryLogger struct {
+	integration *alertingNotify.Integration
+	store       store.NotificationDeliveryStore
+	orgID       int64
+	receiver    string
+	clock       clock.Clock
+	logger      log.Logger
+}
+
+// withDeliveryLog wraps the integrations of the receiver, so that their attempts to send notifications are logged.
+func withDeliveryLog(integrations []*alertingNotify.Integration, st store.NotificationDeliveryStore, orgID int64, receiver string, l log.Logger) []*alertingNotify.Integration {
+	result := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		n := &deliveryLogger{
+			integration: integration,
+			store:       st,
+			orgID:       orgID,
+			receiver:    receiver,
+			clock:       clock.New(),
+			logger:      l,
+		}
+		result = append(result, alertingNotify.NewIntegration(n, integration, integration.Name(), integration.Index()))
+	}
+	return result
+}
+
+func (d *deliveryLogger) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := d.clock.Now()
+	retry, err := d.integration.Notify(ctx, alerts...)
+	duration := d.clock.Since(start)
+
+	fingerprints := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		fingerprints = append(fingerprints, a.Fingerprint().String())
+	}
+	groupKey, _ := notify.GroupKey(ctx)
+	delivery := &ngmodels.NotificationDelivery{
+		OrgID:             d.orgID,
+		Receiver:          d.receiver,
+		Integration:       d.integration.Name(),
+		IntegrationIndex:  d.integration.Index(),
+		GroupKey:          groupKey,
+		AlertFingerprints: fingerprints,
+		Status:            ngmodels.NotificationDeliverySuccess,
+		DurationMs:        duration.Milliseconds(),
+		TimestampNano:     start.UnixNano(),
+	}
+	if err != nil {
+		delivery.Status = ngmodels.NotificationDeliveryFailed
+		delivery.Error = err.Error()
+		delivery.Retry = retry
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.Background(), deliveryLogSaveTimeout)
+	defer cancel()
+	if saveErr := d.store.SaveNotificationDelivery(saveCtx, delivery); saveErr != nil {
+		d.logger.Warn("Failed to save notification delivery", "receiver", d.receiver, "integration", d.integration.Name(), "error", saveErr)
+	}
+	return retry, err
+}
+
+// GetReceiverDeliveries returns the most recent attempts of the integrations of the receiver to send notifications.
+func (am *Alertmanager) GetReceiverDeliveries(ctx context.Context, receiver string, limit int) ([]apimodels.NotificationDelivery, error) {
+	deliveries, err := am.Store.FindNotificationDeliveries(ctx, ngmodels.NotificationDeliveryQuery{
+		OrgID:    am.orgID,
+		Receiver: receiver,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := make([]apimodels.NotificationDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, apimodels.NotificationDelivery{
+			Receiver:          d.Receiver,
+			Integration:       d.Integration,
+			IntegrationIndex:  d.IntegrationIndex,
+			GroupKey:          d.GroupKey,
+			AlertFingerprints: d.AlertFingerprints,
+			Status:            string(d.Status),
+			Error:             d.Error,
+			Retry:             d.Retry,
+			DurationMs:        d.DurationMs,
+			Timestamp:         time.Unix(0, d.TimestampNano).UTC(),
+		})
+	}
+	return result, nil
+}
+
+// DeleteExpiredDeliveriesService is a service to delete notification delivery attempts that are older than
+// the retention period of the notification delivery log.
+type DeleteExpiredDeliveriesService struct {
+	store  store.NotificationDeliveryStore
+	maxAge time.Duration
+	clock  clock.Clock
+}
+
+// DeleteExpired deletes the expired delivery attempts. It returns the number of deleted attempts.
+// It does nothing if the retention period is not limited.
+func (s *DeleteExpiredDeliveriesService) DeleteExpired(ctx context.Context) (int64, error) {
+	if s.maxAge <= 0 {
+		return 0, nil
+	}
+	return s.store.DeleteNotificationDeliveriesBefore(ctx, s.clock.Now().Add(-s.maxAge))
+}
+
+func ProvideDeleteExpiredDeliveriesService(cfg *setting.Cfg, store *store.DBstore) *DeleteExpiredDeliveriesService {
+	return &DeleteExpiredDeliveriesService{
+		store:  store,
+		maxAge: cfg.UnifiedAlerting.NotificationDeliveryLog.MaxAge,
+		clock:  clock.New(),
+	}
+}
@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeDeliveryNotifier struct {
+	retry bool
+	err   error
+}
+
+func (n *fakeDeliveryNotifier) Notify(context.Context, ...*types.Alert) (bool, error) {
+	return n.retry, n.err
+}
+
+func (n *fakeDeliveryNotifier) SendResolved() bool {
+	return true
+}
+
+func TestDeliveryLogger(t *testing.T) {
+	alerts := []*types.Alert{
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "a"}}},
+		{Alert: model.Alert{Labels: model.LabelSet{"alertname": "b"}}},
+	}
+	ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"a\"}")
+
+	setup := func(n *fakeDeliveryNotifier) (*alertingNotify.Integration, *fakeConfigStore) {
+		st := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+		integrations := withDeliveryLog([]*alertingNotify.Integration{alertingNotify.NewIntegration(n, n, "email", 2)}, st, 1, "ops", &logtest.Fake{})
+		require.Len(t, integrations, 1)
+		return integrations[0], st
+	}
+
+	t.Run("should keep the name and index of the integration", func(t *testing.T) {
+		integration, _ := setup(&fakeDeliveryNotifier{})
+		require.Equal(t, "email", integration.Name())
+		require.Equal(t, 2, integration.Index())
+		require.True(t, integration.SendResolved())
+	})
+
+	t.Run("should log successful attempts", func(t *testing.T) {
+		integration, st := setup(&fakeDeliveryNotifier{})
+		retry, err := integration.Notify(ctx, alerts...)
+		require.NoError(t, err)
+		require.False(t, retry)
+
+		deliveries, err := st.FindNotificationDeliveries(context.Background(), models.NotificationDeliveryQuery{OrgID: 1, Receiver: "ops"})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		d := deliveries[0]
+		require.Equal(t, "email", d.Integration)
+		require.Equal(t, 2, d.IntegrationIndex)
+		require.Equal(t, "{}:{alertname=\"a\"}", d.GroupKey)
+		require.Equal(t, []string{alerts[0].Fingerprint().String(), alerts[1].Fingerprint().String()}, d.AlertFingerprints)
+		require.Equal(t, models.NotificationDeliverySuccess, d.Status)
+		require.Empty(t, d.Error)
+		require.NotZero(t, d.TimestampNano)
+	})
+
+	t.Run("should log failed attempts", func(t *testing.T) {
+		integration, st := setup(&fakeDeliveryNotifier{retry: true, err: errors.New("connection refused")})
+		retry, err := integration.Notify(ctx, alerts...)
+		require.EqualError(t, err, "connection refused")
+		require.True(t, retry)
+
+		deliveries, err := st.FindNotificationDeliveries(context.Background(), models.NotificationDeliveryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		require.Equal(t, models.NotificationDeliveryFailed, deliveries[0].Status)
+		require.Equal(t, "connection refused", deliveries[0].Error)
+		require.True(t, deliveries[0].Retry)
+	})
+}
+
+func TestDeleteExpiredDeliveriesService(t *testing.T) {
+	st := NewFakeConfigStore(t, map[int64]*models.AlertConfiguration{})
+	for _, sec := range []int64{10, 20, 30} {
+		require.NoError(t, st.SaveNotificationDelivery(context.Background(), &models.NotificationDelivery{OrgID: 1, TimestampNano: time.Unix(sec, 0).UnixNano()}))
+	}
+	clk := clock.NewMock()
+	clk.Set(time.Unix(35, 0))
+
+	t.Run("should not delete anything if the retention is not limited", func(t *testing.T) {
+		s := &DeleteExpiredDeliveriesService{store: st, clock: clk}
+		n, err := s.DeleteExpired(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("should delete deliveries older than the retention", func(t *testing.T) {
+		s := &DeleteExpiredDeliveriesService{store: st, maxAge: 20 * time.Second, clock: clk}
+		n, err := s.DeleteExpired(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+	})
+}
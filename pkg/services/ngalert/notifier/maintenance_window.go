@@ -0,0 +1,200 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// maintenanceWindowSilenceAuthorPrefix is the prefix of the author of silences created for maintenance windows.
+// It is followed by the UID of the window, so that the silences can be matched with the windows they were created for.
+const maintenanceWindowSilenceAuthorPrefix = "maintenance-window/"
+
+// MaintenanceWindowStore is a store of maintenance windows.
+type MaintenanceWindowStore interface {
+	GetAllMaintenanceWindows(ctx context.Context) ([]*models.MaintenanceWindow, error)
+}
+
+// silencer is the part of Alertmanager that manages silences.
+type silencer interface {
+	ListSilences(filter []string) (alertingNotify.GettableSilences, error)
+	CreateSilence(ps *alertingNotify.PostableSilence) (string, error)
+	DeleteSilence(silenceID string) error
+}
+
+// SyncMaintenanceWindows makes sure that the Alertmanager of every organization has a silence for every maintenance
+// window whose occurrence is in progress at the moment now. Silences of windows that were deleted or changed are expired.
+// Silences of occurrences that are over expire by themselves.
+func (moa *MultiOrgAlertmanager) SyncMaintenanceWindows(ctx context.Context, now time.Time) error {
+	if moa.maintenanceWindowStore == nil {
+		return nil
+	}
+	windows, err := moa.maintenanceWindowStore.GetAllMaintenanceWindows(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load maintenance windows: %w", err)
+	}
+	windowsByOrg := make(map[int64][]*models.MaintenanceWindow)
+	for _, mw := range windows {
+		windowsByOrg[mw.OrgID] = append(windowsByOrg[mw.OrgID], mw)
+	}
+
+	moa.alertmanagersMtx.RLock()
+	alertmanagers := make(map[int64]*Alertmanager, len(moa.alertmanagers))
+	for orgID, am := range moa.alertmanagers {
+		alertmanagers[orgID] = am
+	}
+	moa.alertmanagersMtx.RUnlock()
+
+	for orgID, am := range alertmanagers {
+		if !am.Ready() {
+			continue
+		}
+		logger := moa.logger.New("org", orgID, "component", "maintenance-windows")
+		if err := syncMaintenanceWindowSilences(logger, am, windowsByOrg[orgID], now); err != nil {
+			logger.Error("Failed to synchronize silences of maintenance windows", "error", err)
+		}
+	}
+	return nil
+}
+
+// syncMaintenanceWindowSilences creates silences for occurrences of the windows that are in progress,
+// and expires the silences that do not correspond to an occurrence in progress.
+func syncMaintenanceWindowSilences(logger log.Logger, am silencer, windows []*models.MaintenanceWindow, now time.Time) error {
+	silences, err := am.ListSilences(nil)
+	if err != nil {
+		return fmt.Errorf("failed to list silences: %w", err)
+	}
+	silencesByWindow := make(map[string][]*alertingNotify.GettableSilence)
+	for _, s := range silences {
+		if s.CreatedBy == nil || !strings.HasPrefix(*s.CreatedBy, maintenanceWindowSilenceAuthorPrefix) {
+			continue
+		}
+		if s.Status != nil && s.Status.State != nil && *s.Status.State == amv2.SilenceStatusStateExpired {
+			continue
+		}
+		uid := strings.TrimPrefix(*s.CreatedBy, maintenanceWindowSilenceAuthorPrefix)
+		silencesByWindow[uid] = append(silencesByWindow[uid], s)
+	}
+
+	var toExpire []*alertingNotify.GettableSilence
+	for _, mw := range windows {
+		existing := silencesByWindow[mw.UID]
+		delete(silencesByWindow, mw.UID)
+
+		_, end, active, err := mw.ActiveOccurrence(now)
+		if err != nil {
+			logger.Error("Failed to calculate occurrence of maintenance window", "uid", mw.UID, "error", err)
+			continue
+		}
+		if !active {
+			toExpire = append(toExpire, existing...)
+			continue
+		}
+		matchers, err := mw.SilenceMatchers()
+		if err != nil {
+			logger.Error("Failed to build matchers of maintenance window", "uid", mw.UID, "error", err)
+			continue
+		}
+
+		// Every instance of Grafana synchronizes the windows, and therefore there can be several silences for the same occurrence.
+		// Sort them by ID, so that every instance keeps the same one.
+		sort.Slice(existing, func(i, j int) bool {
+			return *existing[i].ID < *existing[j].ID
+		})
+		found := false
+		for _, s := range existing {
+			if !found && silenceMatchesOccurrence(s, matchers, end) {
+				found = true
+				continue
+			}
+			toExpire = append(toExpire, s)
+		}
+		if found {
+			continue
+		}
+		id, err := am.CreateSilence(newMaintenanceWindowSilence(mw, matchers, now, end))
+		if err != nil {
+			logger.Error("Failed to create silence for maintenance window", "uid", mw.UID, "error", err)
+			continue
+		}
+		logger.Info("Created silence for maintenance window", "uid", mw.UID, "silence", id, "endsAt", end)
+	}
+	// the remaining silences belong to windows that were deleted.
+	for _, s := range silencesByWindow {
+		toExpire = append(toExpire, s...)
+	}
+
+	for _, s := range toExpire {
+		if err := am.DeleteSilence(*s.ID); err != nil {
+			logger.Error("Failed to expire silence of maintenance window", "silence", *s.ID, "error", err)
+			continue
+		}
+		logger.Info("Expired silence of maintenance window", "silence", *s.ID, "createdBy", *s.CreatedBy)
+	}
+	return nil
+}
+
+func newMaintenanceWindowSilence(mw *models.MaintenanceWindow, matchers labels.Matchers, startsAt, endsAt time.Time) *alertingNotify.PostableSilence {
+	createdBy := maintenanceWindowSilenceAuthorPrefix + mw.UID
+	comment := fmt.Sprintf("Created automatically for maintenance window %q", mw.Title)
+	start, end := strfmt.DateTime(startsAt), strfmt.DateTime(endsAt)
+	return &alertingNotify.PostableSilence{
+		Silence: amv2.Silence{
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+			StartsAt:  &start,
+			EndsAt:    &end,
+			Matchers:  silenceMatchersFromLabelsMatchers(matchers),
+		},
+	}
+}
+
+// silenceMatchesOccurrence checks whether the silence ends at the end of the occurrence and has the expected matchers.
+// The start of the silence is not compared because Alertmanager can move it forward.
+func silenceMatchesOccurrence(s *alertingNotify.GettableSilence, matchers labels.Matchers, end time.Time) bool {
+	if s.EndsAt == nil || !time.Time(*s.EndsAt).Equal(end) {
+		return false
+	}
+	return silenceMatchersKey(s.Matchers) == silenceMatchersKey(silenceMatchersFromLabelsMatchers(matchers))
+}
+
+func silenceMatchersFromLabelsMatchers(matchers labels.Matchers) amv2.Matchers {
+	result := make(amv2.Matchers, 0, len(matchers))
+	for _, m := range matchers {
+		name, value := m.Name, m.Value
+		isEqual := m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp
+		isRegex := m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp
+		result = append(result, &amv2.Matcher{
+			Name:    &name,
+			Value:   &value,
+			IsEqual: &isEqual,
+			IsRegex: &isRegex,
+		})
+	}
+	return result
+}
+
+// silenceMatchersKey returns a string that is the same for equal sets of matchers.
+func silenceMatchersKey(matchers amv2.Matchers) string {
+	keys := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		if m == nil || m.Name == nil || m.Value == nil {
+			continue
+		}
+		isEqual := m.IsEqual == nil || *m.IsEqual
+		isRegex := m.IsRegex != nil && *m.IsRegex
+		keys = append(keys, fmt.Sprintf("%s|%t|%t|%s", *m.Name, isEqual, isRegex, *m.Value))
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "\n")
+}
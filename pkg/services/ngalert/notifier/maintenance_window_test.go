@@ -0,0 +1,144 @@
+package notifier
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeSilencer struct {
+	silences map[string]*alertingNotify.GettableSilence
+	nextID   int
+	created  []string
+	deleted  []string
+}
+
+func newFakeSilencer() *fakeSilencer {
+	return &fakeSilencer{silences: map[string]*alertingNotify.GettableSilence{}}
+}
+
+func (f *fakeSilencer) ListSilences(_ []string) (alertingNotify.GettableSilences, error) {
+	result := make(alertingNotify.GettableSilences, 0, len(f.silences))
+	for _, s := range f.silences {
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+func (f *fakeSilencer) CreateSilence(ps *alertingNotify.PostableSilence) (string, error) {
+	f.nextID++
+	id := fmt.Sprintf("%03d", f.nextID)
+	state := amv2.SilenceStatusStateActive
+	f.silences[id] = &alertingNotify.GettableSilence{
+		ID:      &id,
+		Status:  &amv2.SilenceStatus{State: &state},
+		Silence: ps.Silence,
+	}
+	f.created = append(f.created, id)
+	return id, nil
+}
+
+func (f *fakeSilencer) DeleteSilence(id string) error {
+	state := amv2.SilenceStatusStateExpired
+	f.silences[id].Status.State = &state
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func TestSyncMaintenanceWindowSilences(t *testing.T) {
+	logger := log.NewNopLogger()
+	m, err := labels.NewMatcher(labels.MatchEqual, "service", "db")
+	require.NoError(t, err)
+	newWindow := func() *models.MaintenanceWindow {
+		return &models.MaintenanceWindow{
+			UID:      "backup",
+			Title:    "Backup",
+			Schedule: "0 2 * * *",
+			Duration: 2 * time.Hour,
+			Matchers: labels.Matchers{m},
+		}
+	}
+	day := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should create a silence when an occurrence is in progress", func(t *testing.T) {
+		am := newFakeSilencer()
+		mw := newWindow()
+
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, []*models.MaintenanceWindow{mw}, day.Add(time.Hour)))
+		require.Empty(t, am.created)
+
+		now := day.Add(3 * time.Hour)
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, []*models.MaintenanceWindow{mw}, now))
+		require.Len(t, am.created, 1)
+		s := am.silences[am.created[0]]
+		require.Equal(t, "maintenance-window/backup", *s.CreatedBy)
+		require.Equal(t, now, time.Time(*s.StartsAt))
+		require.Equal(t, day.Add(4*time.Hour), time.Time(*s.EndsAt))
+		require.Len(t, s.Matchers, 1)
+		require.Equal(t, "service", *s.Matchers[0].Name)
+
+		t.Run("and keep it while the occurrence is in progress", func(t *testing.T) {
+			require.NoError(t, syncMaintenanceWindowSilences(logger, am, []*models.MaintenanceWindow{mw}, now.Add(time.Minute)))
+			require.Len(t, am.created, 1)
+			require.Empty(t, am.deleted)
+		})
+	})
+
+	t.Run("should replace the silence when the window changes", func(t *testing.T) {
+		am := newFakeSilencer()
+		mw := newWindow()
+		now := day.Add(3 * time.Hour)
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, []*models.MaintenanceWindow{mw}, now))
+
+		mw.Duration = 3 * time.Hour
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, []*models.MaintenanceWindow{mw}, now))
+		require.Equal(t, []string{"001"}, am.deleted)
+		require.Equal(t, []string{"001", "002"}, am.created)
+		require.Equal(t, day.Add(5*time.Hour), time.Time(*am.silences["002"].EndsAt))
+	})
+
+	t.Run("should expire the silence when the window is deleted", func(t *testing.T) {
+		am := newFakeSilencer()
+		now := day.Add(3 * time.Hour)
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, []*models.MaintenanceWindow{newWindow()}, now))
+
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, nil, now))
+		require.Equal(t, []string{"001"}, am.deleted)
+	})
+
+	t.Run("should keep one silence when several were created for the same occurrence", func(t *testing.T) {
+		am := newFakeSilencer()
+		mw := newWindow()
+		now := day.Add(3 * time.Hour)
+		matchers, err := mw.SilenceMatchers()
+		require.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, err := am.CreateSilence(newMaintenanceWindowSilence(mw, matchers, now, day.Add(4*time.Hour)))
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, []*models.MaintenanceWindow{mw}, now))
+		require.Equal(t, []string{"002"}, am.deleted)
+		require.Len(t, am.created, 2)
+	})
+
+	t.Run("should ignore silences that were not created for windows", func(t *testing.T) {
+		am := newFakeSilencer()
+		createdBy := "admin"
+		start, end := strfmt.DateTime(day), strfmt.DateTime(day.Add(time.Hour))
+		_, err := am.CreateSilence(&alertingNotify.PostableSilence{Silence: amv2.Silence{CreatedBy: &createdBy, StartsAt: &start, EndsAt: &end}})
+		require.NoError(t, err)
+
+		require.NoError(t, syncMaintenanceWindowSilences(logger, am, nil, day))
+		require.Empty(t, am.deleted)
+	})
+}
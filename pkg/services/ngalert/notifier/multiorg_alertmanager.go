@@ -44,9 +44,10 @@ type MultiOrgAlertmanager struct {
 	peer         alertingNotify.ClusterPeer
 	settleCancel context.CancelFunc
 
-	configStore AlertingStore
-	orgStore    store.OrgStore
-	kvStore     kvstore.KVStore
+	configStore            AlertingStore
+	orgStore               store.OrgStore
+	kvStore                kvstore.KVStore
+	maintenanceWindowStore MaintenanceWindowStore
 
 	decryptFn alertingNotify.GetDecryptedValueFn
 
@@ -55,7 +56,7 @@ type MultiOrgAlertmanager struct {
 }
 
 func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgStore store.OrgStore,
-	kvStore kvstore.KVStore, provStore provisioning.ProvisioningStore, decryptFn alertingNotify.GetDecryptedValueFn,
+	kvStore kvstore.KVStore, provStore provisioning.ProvisioningStore, mwStore MaintenanceWindowStore, decryptFn alertingNotify.GetDecryptedValueFn,
 	m *metrics.MultiOrgAlertmanager, ns notifications.Service, l log.Logger, s secrets.Service,
 ) (*MultiOrgAlertmanager, error) {
 	moa := &MultiOrgAlertmanager{
@@ -69,9 +70,11 @@ func NewMultiOrgAlertmanager(cfg *setting.Cfg, configStore AlertingStore, orgSto
 		orgStore:      orgStore,
 		kvStore:       kvStore,
 		decryptFn:     decryptFn,
-		metrics:       m,
-		ns:            ns,
-		peer:          &NilPeer{},
+
+		maintenanceWindowStore: mwStore,
+		metrics:                m,
+		ns:                     ns,
+		peer:                   &NilPeer{},
 	}
 	if err := moa.setupClustering(cfg); err != nil {
 		return nil, err
@@ -85,6 +88,11 @@ func (moa *MultiOrgAlertmanager) setupClustering(cfg *setting.Cfg) error {
 	// ensuring that a sufficient number of broadcasts have occurred, thereby
 	// increasing the probability of success when waiting for the cluster to settle.
 	const settleTimeout = cluster.DefaultGossipInterval * 10
+	// Database setup.
+	if cfg.UnifiedAlerting.HADatabaseSync {
+		moa.peer = newDBPeer(moa.configStore, clusterLogger, cfg.UnifiedAlerting.HADatabaseSyncInterval)
+		return nil
+	}
 	// Redis setup.
 	if cfg.UnifiedAlerting.HARedisAddr != "" {
 		redisPeer, err := newRedisPeer(redisConfig{
@@ -154,6 +162,9 @@ func (moa *MultiOrgAlertmanager) Run(ctx context.Context) error {
 			if err := moa.LoadAndSyncAlertmanagersForOrgs(ctx); err != nil {
 				moa.logger.Error("error while synchronizing Alertmanager orgs", "error", err)
 			}
+			if err := moa.SyncMaintenanceWindows(ctx, time.Now()); err != nil {
+				moa.logger.Error("error while synchronizing maintenance windows", "error", err)
+			}
 		}
 	}
 }
@@ -343,6 +354,10 @@ func (moa *MultiOrgAlertmanager) StopAndWait() {
 		moa.settleCancel()
 		r.Shutdown()
 	}
+	d, ok := moa.peer.(*dbPeer)
+	if ok {
+		d.Shutdown()
+	}
 }
 
 // AlertmanagerFor returns the Alertmanager instance for the organization provided.
@@ -2,9 +2,16 @@ package notifier
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
 	alertingModels "github.com/grafana/alerting/models"
 	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
 	amv2 "github.com/prometheus/alertmanager/api/v2/models"
 	prometheusModel "github.com/prometheus/common/model"
 
@@ -13,6 +20,47 @@ import (
 
 type TestTemplatesResults = alertingNotify.TestTemplatesResults
 
+var ErrUnknownIntegrationType = errors.New("unknown integration type")
+
+const (
+	defaultTemplatePreviewName = "preview"
+	templatePreviewPrefix      = "__preview_"
+	templatePreviewTitle       = "title"
+	templatePreviewMessage     = "message"
+)
+
+// templatePreviewDefaults is the title and message that integrations of a type render by default.
+// An empty title or message means that the integration type has no such field.
+type templatePreviewDefaults struct {
+	title   string
+	message string
+}
+
+var templatePreviewIntegrations = map[string]templatePreviewDefaults{
+	"dingding":   {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"discord":    {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"email":      {title: alertingTemplates.DefaultMessageTitleEmbed},
+	"googlechat": {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"kafka":      {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"line":       {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"opsgenie":   {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"pagerduty":  {title: alertingTemplates.DefaultMessageTitleEmbed},
+	"pushover":   {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"sensugo":    {message: alertingTemplates.DefaultMessageEmbed},
+	"slack":      {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"teams":      {title: alertingTemplates.DefaultMessageTitleEmbed, message: `{{ template "teams.default.message" .}}`},
+	"telegram":   {message: alertingTemplates.DefaultMessageEmbed},
+	"threema":    {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"victorops":  {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"webex":      {message: alertingTemplates.DefaultMessageEmbed},
+	"webhook":    {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+	"wecom":      {title: alertingTemplates.DefaultMessageTitleEmbed, message: alertingTemplates.DefaultMessageEmbed},
+}
+
+// templateErrorLocationRegexp matches the location at the start of parse and execution errors of text/template,
+// for example "template: name:3:10: executing ...".
+var templateErrorLocationRegexp = regexp.MustCompile(`^template: ([^:]*):(\d+)(?::(\d+))?:`)
+
 var (
 	DefaultLabels = map[string]string{
 		prometheusModel.AlertNameLabel:  `alert title`,
@@ -41,6 +89,158 @@ func (am *Alertmanager) TestTemplate(ctx context.Context, c apimodels.TestTempla
 	})
 }
 
+// templatePreviewField is a title or message of an integration type that is rendered as a template definition
+// appended to the previewed template.
+type templatePreviewField struct {
+	integration string
+	field       string
+	// firstLine is the line of the previewed template that the text of the field starts on.
+	firstLine int
+	// offset is the column that the text of the field starts at in its first line.
+	offset int
+	lines  int
+}
+
+// PreviewTemplate renders the title and message of each integration type with the given template and alerts.
+// Existing templates are used to provide context, except for an existing template with the same name as the given one.
+// Errors report the line in the template file, or in the title or message of the request, that caused the error.
+func (am *Alertmanager) PreviewTemplate(ctx context.Context, c apimodels.TemplatePreviewBodyParams) (*apimodels.TemplatePreviewResults, error) {
+	integrations := c.Integrations
+	if len(integrations) == 0 {
+		for integration := range templatePreviewIntegrations {
+			integrations = append(integrations, integration)
+		}
+		sort.Strings(integrations)
+	}
+	name := c.Name
+	if name == "" {
+		name = defaultTemplatePreviewName
+	}
+
+	// Each field is rendered by a definition that is appended to the template, so that the lines of the template do not change.
+	var b strings.Builder
+	b.WriteString(c.Template)
+	line := strings.Count(c.Template, "\n") + 1
+	fields := map[string]templatePreviewField{}
+	for _, integration := range integrations {
+		defaults, ok := templatePreviewIntegrations[integration]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownIntegrationType, integration)
+		}
+		for _, f := range []struct{ field, text, override string }{
+			{field: templatePreviewTitle, text: defaults.title, override: c.Title},
+			{field: templatePreviewMessage, text: defaults.message, override: c.Message},
+		} {
+			if f.text == "" {
+				continue
+			}
+			if f.override != "" {
+				f.text = f.override
+			}
+			def := templatePreviewPrefix + integration + "_" + f.field
+			start := fmt.Sprintf("\n{{ define %q }}", def)
+			b.WriteString(start)
+			b.WriteString(f.text)
+			b.WriteString("{{ end }}")
+			lines := strings.Count(f.text, "\n") + 1
+			fields[def] = templatePreviewField{
+				integration: integration,
+				field:       f.field,
+				firstLine:   line + 1,
+				offset:      len(start) - 1,
+				lines:       lines,
+			}
+			line += lines
+		}
+	}
+
+	alerts := make([]*amv2.PostableAlert, 0, len(c.Alerts))
+	for _, alert := range c.Alerts {
+		if alert == nil {
+			continue
+		}
+		addDefaultLabelsAndAnnotations(alert)
+		alerts = append(alerts, alert)
+	}
+
+	res, err := am.Base.TestTemplate(ctx, alertingNotify.TestTemplatesConfigBodyParams{
+		Alerts:   alerts,
+		Template: b.String(),
+		Name:     name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	previews := make(map[string]int, len(integrations))
+	result := &apimodels.TemplatePreviewResults{}
+	for _, integration := range integrations {
+		if _, ok := previews[integration]; ok {
+			continue
+		}
+		previews[integration] = len(result.Previews)
+		result.Previews = append(result.Previews, apimodels.TemplatePreview{Integration: integration})
+	}
+	for _, r := range res.Results {
+		f, ok := fields[r.Name]
+		if !ok {
+			// A definition of the previewed template itself.
+			continue
+		}
+		if f.field == templatePreviewTitle {
+			result.Previews[previews[f.integration]].Title = r.Text
+		} else {
+			result.Previews[previews[f.integration]].Message = r.Text
+		}
+	}
+	for _, e := range res.Errors {
+		f, ok := fields[e.Name]
+		if !ok && e.Kind != alertingNotify.InvalidTemplate {
+			// Errors in the definitions of the previewed template are reported for the fields that use them.
+			continue
+		}
+		previewErr := apimodels.TemplatePreviewError{
+			Integration: f.integration,
+			Field:       f.field,
+			Kind:        apimodels.TemplateErrorKind(e.Kind),
+			Message:     e.Error.Error(),
+		}
+		locateTemplatePreviewError(&previewErr, name, fields)
+		result.Errors = append(result.Errors, previewErr)
+	}
+	return result, nil
+}
+
+// locateTemplatePreviewError sets the template file, line and column of the error. If the error is in a field
+// appended to the previewed template, the line and column are relative to the title or message of the request.
+func locateTemplatePreviewError(e *apimodels.TemplatePreviewError, name string, fields map[string]templatePreviewField) {
+	m := templateErrorLocationRegexp.FindStringSubmatch(e.Message)
+	if m == nil {
+		return
+	}
+	e.Template = m[1]
+	e.Line, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		e.Column, _ = strconv.Atoi(m[3])
+	}
+	if e.Template != name {
+		return
+	}
+	for _, f := range fields {
+		if e.Line < f.firstLine || e.Line >= f.firstLine+f.lines {
+			continue
+		}
+		e.Integration = f.integration
+		e.Field = f.field
+		e.Template = ""
+		if e.Line == f.firstLine && e.Column >= f.offset {
+			e.Column -= f.offset
+		}
+		e.Line = e.Line - f.firstLine + 1
+		return
+	}
+}
+
 // addDefaultLabelsAndAnnotations is a slimmed down version of state.StateToPostableAlert and state.GetRuleExtraLabels using default values.
 func addDefaultLabelsAndAnnotations(alert *amv2.PostableAlert) {
 	if alert.Labels == nil {
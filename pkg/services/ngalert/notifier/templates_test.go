@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 var (
@@ -172,3 +174,109 @@ CommonAnnotations: {{ range .CommonAnnotations.SortedPairs }}{{ .Name }}={{ .Val
 		})
 	}
 }
+
+func TestPreviewTemplate(t *testing.T) {
+	am := setupAMTest(t)
+	// Apply a configuration so that the default templates are available.
+	cfg := &apimodels.PostableUserConfig{}
+	require.NoError(t, json.Unmarshal([]byte(setting.GetAlertmanagerDefaultConfiguration()), cfg))
+	require.NoError(t, am.SaveAndApplyConfig(context.Background(), cfg))
+	alerts := func() []*amv2.PostableAlert {
+		alert := simpleAlert
+		alert.Labels = amv2.LabelSet{"alertname": "alert1", "lbl1": "val1"}
+		return []*amv2.PostableAlert{&alert}
+	}
+
+	t.Run("should render the title and message of each integration type", func(t *testing.T) {
+		res, err := am.PreviewTemplate(context.Background(), apimodels.TemplatePreviewBodyParams{
+			Alerts:       alerts(),
+			Name:         "custom",
+			Template:     `{{ define "custom.title" }}{{ len .Alerts.Firing }} firing{{ end }}`,
+			Integrations: []string{"slack", "telegram"},
+			Title:        `{{ template "custom.title" . }}`,
+			Message:      `{{ range .Alerts }}{{ .Labels.lbl1 }}{{ end }}`,
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.Errors)
+		require.Equal(t, []apimodels.TemplatePreview{
+			{Integration: "slack", Title: "1 firing", Message: "val1"},
+			// Telegram has no title.
+			{Integration: "telegram", Message: "val1"},
+		}, res.Previews)
+	})
+
+	t.Run("should render the default title and message", func(t *testing.T) {
+		res, err := am.PreviewTemplate(context.Background(), apimodels.TemplatePreviewBodyParams{
+			Alerts:       alerts(),
+			Integrations: []string{"slack"},
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.Errors)
+		require.Len(t, res.Previews, 1)
+		require.Equal(t, "[FIRING:1] group_label_value (alert1 folder title val1)", res.Previews[0].Title)
+		require.Contains(t, res.Previews[0].Message, "lbl1 = val1")
+	})
+
+	t.Run("should render all integration types by default", func(t *testing.T) {
+		res, err := am.PreviewTemplate(context.Background(), apimodels.TemplatePreviewBodyParams{Alerts: alerts()})
+		require.NoError(t, err)
+		require.Empty(t, res.Errors)
+		require.Len(t, res.Previews, len(templatePreviewIntegrations))
+	})
+
+	t.Run("should return the line of an invalid template", func(t *testing.T) {
+		res, err := am.PreviewTemplate(context.Background(), apimodels.TemplatePreviewBodyParams{
+			Alerts:       alerts(),
+			Name:         "custom",
+			Template:     "{{ define \"custom.title\" }}\n{{ .Alerts | unknown }}\n{{ end }}",
+			Integrations: []string{"slack"},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, apimodels.InvalidTemplate, res.Errors[0].Kind)
+		require.Equal(t, "custom", res.Errors[0].Template)
+		require.Equal(t, 2, res.Errors[0].Line)
+	})
+
+	t.Run("should return the line of an execution error in the template", func(t *testing.T) {
+		res, err := am.PreviewTemplate(context.Background(), apimodels.TemplatePreviewBodyParams{
+			Alerts:       alerts(),
+			Name:         "custom",
+			Template:     "{{ define \"custom.title\" }}\nfiring\n{{ index .Alerts 5 }}\n{{ end }}",
+			Integrations: []string{"slack"},
+			Title:        `{{ template "custom.title" . }}`,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, apimodels.TemplatePreviewError{
+			Integration: "slack",
+			Field:       "title",
+			Kind:        apimodels.ExecutionError,
+			Template:    "custom",
+			Line:        3,
+			Column:      3,
+			Message:     res.Errors[0].Message,
+		}, res.Errors[0])
+		require.Empty(t, res.Previews[0].Title)
+		require.NotEmpty(t, res.Previews[0].Message)
+	})
+
+	t.Run("should return the line of an error in the title or message", func(t *testing.T) {
+		res, err := am.PreviewTemplate(context.Background(), apimodels.TemplatePreviewBodyParams{
+			Alerts:       alerts(),
+			Integrations: []string{"slack"},
+			Message:      "alerts:\n{{ template \"missing\" . }}",
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, "slack", res.Errors[0].Integration)
+		require.Equal(t, "message", res.Errors[0].Field)
+		require.Empty(t, res.Errors[0].Template)
+		require.Equal(t, 2, res.Errors[0].Line)
+	})
+
+	t.Run("should return an error for an unknown integration type", func(t *testing.T) {
+		_, err := am.PreviewTemplate(context.Background(), apimodels.TemplatePreviewBodyParams{Integrations: []string{"unknown"}})
+		require.ErrorIs(t, err, ErrUnknownIntegrationType)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/md5"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -22,6 +23,13 @@ type fakeConfigStore struct {
 
 	// historicConfigs stores configs by orgID.
 	historicConfigs map[int64][]*models.HistoricAlertConfiguration
+
+	deliveriesMtx sync.Mutex
+	deliveries    []*models.NotificationDelivery
+
+	statesMtx sync.Mutex
+	states    map[string]models.AlertmanagerState
+	peers     map[string]int64
 }
 
 // Saves the image or returns an error.
@@ -179,6 +187,108 @@ func (f *fakeConfigStore) GetHistoricalConfiguration(_ context.Context, orgID in
 	return &models.HistoricAlertConfiguration{}, store.ErrNoAlertmanagerConfiguration
 }
 
+func (f *fakeConfigStore) SaveNotificationDelivery(_ context.Context, delivery *models.NotificationDelivery) error {
+	f.deliveriesMtx.Lock()
+	defer f.deliveriesMtx.Unlock()
+	delivery.ID = int64(len(f.deliveries) + 1)
+	f.deliveries = append(f.deliveries, delivery)
+	return nil
+}
+
+func (f *fakeConfigStore) FindNotificationDeliveries(_ context.Context, query models.NotificationDeliveryQuery) ([]*models.NotificationDelivery, error) {
+	f.deliveriesMtx.Lock()
+	defer f.deliveriesMtx.Unlock()
+	var result []*models.NotificationDelivery
+	for i := len(f.deliveries) - 1; i >= 0; i-- {
+		d := f.deliveries[i]
+		if d.OrgID != query.OrgID || (query.Receiver != "" && d.Receiver != query.Receiver) {
+			continue
+		}
+		if !query.From.IsZero() && d.TimestampNano < query.From.UnixNano() {
+			continue
+		}
+		result = append(result, d)
+		if query.Limit > 0 && len(result) == query.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeConfigStore) DeleteNotificationDeliveriesBefore(_ context.Context, before time.Time) (int64, error) {
+	f.deliveriesMtx.Lock()
+	defer f.deliveriesMtx.Unlock()
+	kept := f.deliveries[:0]
+	for _, d := range f.deliveries {
+		if d.TimestampNano >= before.UnixNano() {
+			kept = append(kept, d)
+		}
+	}
+	n := int64(len(f.deliveries) - len(kept))
+	f.deliveries = kept
+	return n, nil
+}
+
+func (f *fakeConfigStore) GetAlertmanagerState(_ context.Context, key string) (*models.AlertmanagerState, error) {
+	f.statesMtx.Lock()
+	defer f.statesMtx.Unlock()
+	state, ok := f.states[key]
+	if !ok {
+		return nil, models.ErrAlertmanagerStateNotFound
+	}
+	return &state, nil
+}
+
+func (f *fakeConfigStore) SaveAlertmanagerState(_ context.Context, state *models.AlertmanagerState) error {
+	f.statesMtx.Lock()
+	defer f.statesMtx.Unlock()
+	if f.states == nil {
+		f.states = map[string]models.AlertmanagerState{}
+	}
+	if f.states[state.Key].Version != state.Version {
+		return models.ErrAlertmanagerStateVersionConflict
+	}
+	state.Version++
+	f.states[state.Key] = *state
+	return nil
+}
+
+func (f *fakeConfigStore) HeartbeatAlertmanagerPeer(_ context.Context, name string, t time.Time) error {
+	f.statesMtx.Lock()
+	defer f.statesMtx.Unlock()
+	if f.peers == nil {
+		f.peers = map[string]int64{}
+	}
+	f.peers[name] = t.Unix()
+	return nil
+}
+
+func (f *fakeConfigStore) GetAlertmanagerPeers(_ context.Context, since time.Time) ([]string, error) {
+	f.statesMtx.Lock()
+	defer f.statesMtx.Unlock()
+	result := []string{}
+	for name, heartbeat := range f.peers {
+		if heartbeat >= since.Unix() {
+			result = append(result, name)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func (f *fakeConfigStore) DeleteAlertmanagerPeersBefore(_ context.Context, before time.Time) (int64, error) {
+	f.statesMtx.Lock()
+	defer f.statesMtx.Unlock()
+	var n int64
+	for name, heartbeat := range f.peers {
+		if heartbeat < before.Unix() {
+			delete(f.peers, name)
+			n++
+		}
+	}
+	return n, nil
+}
+
 type FakeOrgStore struct {
 	orgs []int64
 }
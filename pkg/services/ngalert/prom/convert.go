@@ -0,0 +1,251 @@
+package prom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/prometheus/prometheus/promql/parser"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/expr"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// QueryRefID is the RefID of the data source query that executes the expression of a Prometheus rule.
+	QueryRefID = "query"
+	// MathRefID is the RefID of the math expression that turns every series returned by the query into an alerting one.
+	MathRefID = "prometheus_math"
+	// ThresholdRefID is the RefID of the threshold expression that is the condition of the converted rules.
+	ThresholdRefID = "threshold"
+
+	// queryTimeRange is the relative time range of the query. Prometheus evaluates instant queries at the evaluation time,
+	// the range only bounds the lookback of the data source.
+	queryTimeRange = 10 * time.Minute
+)
+
+var errRecordingRule = errors.New("recording rules are not supported")
+
+// ConversionError describes a Prometheus rule that could not be converted.
+type ConversionError struct {
+	Group string
+	// Rule is the name of the rule, or empty if the whole group could not be converted.
+	Rule string
+	Err  error
+}
+
+func (e ConversionError) Error() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("rule group '%s': %s", e.Group, e.Err)
+	}
+	return fmt.Sprintf("rule '%s' in group '%s': %s", e.Rule, e.Group, e.Err)
+}
+
+func (e ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// Config is the configuration of a Converter.
+type Config struct {
+	OrgID         int64
+	NamespaceUID  string
+	DatasourceUID string
+	// DefaultInterval is the evaluation interval of the groups that do not specify one.
+	DefaultInterval time.Duration
+}
+
+// Converter converts Prometheus alerting rules to Grafana-managed alert rules. Every rule gets three queries:
+// the data source query with the PromQL expression, a math expression that is 1 for every series returned by
+// the query, and a threshold expression that is the condition of the rule. This preserves the semantics of
+// Prometheus, where every series returned by the expression of a rule is an alert.
+type Converter struct {
+	cfg Config
+}
+
+func NewConverter(cfg Config) *Converter {
+	return &Converter{cfg: cfg}
+}
+
+// ParseRuleFile parses a Prometheus rule file.
+func ParseRuleFile(b []byte) (*apimodels.PrometheusRuleFile, error) {
+	var f apimodels.PrometheusRuleFile
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to parse Prometheus rule file: %w", err)
+	}
+	return &f, nil
+}
+
+// ConvertGroups converts the groups. It returns the groups that have at least one rule that could be converted,
+// and an error for every rule that could not be converted.
+func (c *Converter) ConvertGroups(groups []apimodels.PrometheusRuleGroup) ([]models.AlertRuleGroup, []ConversionError) {
+	var result []models.AlertRuleGroup
+	var failures []ConversionError
+	names := make(map[string]struct{}, len(groups))
+	titles := make(map[string]int)
+	for _, group := range groups {
+		if _, ok := names[group.Name]; ok {
+			failures = append(failures, ConversionError{Group: group.Name, Err: errors.New("duplicate rule group name")})
+			continue
+		}
+		names[group.Name] = struct{}{}
+
+		converted, errs := c.convertGroup(group, titles)
+		failures = append(failures, errs...)
+		if len(converted.Rules) > 0 {
+			result = append(result, converted)
+		}
+	}
+	return result, failures
+}
+
+func (c *Converter) convertGroup(group apimodels.PrometheusRuleGroup, titles map[string]int) (models.AlertRuleGroup, []ConversionError) {
+	interval := time.Duration(group.Interval)
+	if interval == 0 {
+		interval = c.cfg.DefaultInterval
+	}
+	result := models.AlertRuleGroup{
+		Title:     group.Name,
+		FolderUID: c.cfg.NamespaceUID,
+		Interval:  int64(interval.Seconds()),
+	}
+	if group.Name == "" {
+		return result, []ConversionError{{Err: errors.New("rule group name cannot be empty")}}
+	}
+
+	var failures []ConversionError
+	for idx, node := range group.Rules {
+		name := node.Alert
+		if name == "" {
+			name = node.Record
+		}
+		rule, err := c.convertRule(group.Name, idx, node, result.Interval)
+		if err != nil {
+			failures = append(failures, ConversionError{Group: group.Name, Rule: name, Err: err})
+			continue
+		}
+		// Prometheus allows several rules with the same name, for example with different severities,
+		// but the titles of Grafana rules must be unique in a folder.
+		titles[rule.Title]++
+		if n := titles[rule.Title]; n > 1 {
+			rule.Title = fmt.Sprintf("%s (%d)", rule.Title, n)
+		}
+		rule.RuleGroupIndex = len(result.Rules) + 1
+		result.Rules = append(result.Rules, rule)
+	}
+	return result, failures
+}
+
+func (c *Converter) convertRule(group string, idx int, node apimodels.ApiRuleNode, intervalSeconds int64) (models.AlertRule, error) {
+	if node.Record != "" {
+		return models.AlertRule{}, errRecordingRule
+	}
+	if node.Alert == "" {
+		return models.AlertRule{}, errors.New("rule has no name")
+	}
+	if _, err := parser.ParseExpr(node.Expr); err != nil {
+		return models.AlertRule{}, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	labels, err := convertTemplates(node.Labels)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("invalid labels: %w", err)
+	}
+	annotations, err := convertTemplates(node.Annotations)
+	if err != nil {
+		return models.AlertRule{}, fmt.Errorf("invalid annotations: %w", err)
+	}
+
+	data, err := c.queries(node.Expr)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+
+	rule := models.AlertRule{
+		OrgID:           c.cfg.OrgID,
+		Title:           node.Alert,
+		UID:             c.ruleUID(group, idx, node.Alert),
+		Condition:       ThresholdRefID,
+		Data:            data,
+		IntervalSeconds: intervalSeconds,
+		NamespaceUID:    c.cfg.NamespaceUID,
+		RuleGroup:       group,
+		// Prometheus does not fire when the expression returns no series.
+		NoDataState:  models.OK,
+		ExecErrState: models.ErrorErrState,
+		Labels:       labels,
+		Annotations:  annotations,
+	}
+	if node.For != nil {
+		rule.For = time.Duration(*node.For)
+	}
+	if node.KeepFiringFor != nil {
+		rule.KeepFiringFor = time.Duration(*node.KeepFiringFor)
+	}
+	return rule, nil
+}
+
+func (c *Converter) queries(promQL string) ([]models.AlertQuery, error) {
+	query, err := json.Marshal(map[string]interface{}{
+		"refId":   QueryRefID,
+		"expr":    promQL,
+		"instant": true,
+		"range":   false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	math, err := json.Marshal(map[string]interface{}{
+		"refId":      MathRefID,
+		"type":       "math",
+		"expression": fmt.Sprintf("is_number($%[1]s) || is_nan($%[1]s) || is_inf($%[1]s)", QueryRefID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := json.Marshal(map[string]interface{}{
+		"refId":      ThresholdRefID,
+		"type":       "threshold",
+		"expression": MathRefID,
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"evaluator": map[string]interface{}{
+					"type":   "gt",
+					"params": []float64{0},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []models.AlertQuery{
+		{
+			RefID:             QueryRefID,
+			DatasourceUID:     c.cfg.DatasourceUID,
+			RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(queryTimeRange)},
+			Model:             query,
+		},
+		{
+			RefID:         MathRefID,
+			DatasourceUID: expr.DatasourceUID,
+			Model:         math,
+		},
+		{
+			RefID:         ThresholdRefID,
+			DatasourceUID: expr.DatasourceUID,
+			Model:         threshold,
+		},
+	}, nil
+}
+
+// ruleUID returns a UID that is the same every time the rule is converted, so that importing
+// a rule file again updates the rules instead of creating new ones.
+func (c *Converter) ruleUID(group string, idx int, name string) string {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d\x00%s\x00%s\x00%d\x00%s", c.cfg.OrgID, c.cfg.NamespaceUID, group, idx, name)
+	return fmt.Sprintf("prom-%x", h.Sum64())
+}
@@ -0,0 +1,131 @@
+package prom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const ruleFile = `
+groups:
+  - name: node
+    interval: 30s
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        for: 5m
+        keep_firing_for: 1m
+        labels:
+          severity: critical
+        annotations:
+          summary: "Instance {{ $labels.instance }} is down"
+          description: "{{ $labels.instance }} has value {{ $value }}"
+      - record: job:up:sum
+        expr: sum by (job) (up)
+      - alert: InstanceDown
+        expr: up == 0
+        for: 15m
+        labels:
+          severity: warning
+  - name: broken
+    rules:
+      - alert: InvalidExpression
+        expr: sum(up
+      - alert: ExternalLabels
+        expr: up == 0
+        annotations:
+          summary: "{{ $externalLabels.cluster }}"
+`
+
+func TestConvertGroups(t *testing.T) {
+	f, err := ParseRuleFile([]byte(ruleFile))
+	require.NoError(t, err)
+	require.Len(t, f.Groups, 2)
+
+	c := NewConverter(Config{
+		OrgID:           1,
+		NamespaceUID:    "folder",
+		DatasourceUID:   "prometheus",
+		DefaultInterval: time.Minute,
+	})
+	groups, failures := c.ConvertGroups(f.Groups)
+
+	t.Run("should report the rules that could not be converted", func(t *testing.T) {
+		require.Len(t, failures, 3)
+		require.Equal(t, "job:up:sum", failures[0].Rule)
+		require.ErrorIs(t, failures[0], errRecordingRule)
+		require.Equal(t, "InvalidExpression", failures[1].Rule)
+		require.Equal(t, "broken", failures[1].Group)
+		require.Equal(t, "ExternalLabels", failures[2].Rule)
+		require.ErrorIs(t, failures[2], errExternalLabels)
+	})
+
+	t.Run("should convert alerting rules", func(t *testing.T) {
+		require.Len(t, groups, 1)
+		group := groups[0]
+		require.Equal(t, "node", group.Title)
+		require.Equal(t, "folder", group.FolderUID)
+		require.EqualValues(t, 30, group.Interval)
+		require.Len(t, group.Rules, 2)
+
+		rule := group.Rules[0]
+		require.Equal(t, "InstanceDown", rule.Title)
+		require.NotEmpty(t, rule.UID)
+		require.EqualValues(t, 1, rule.OrgID)
+		require.Equal(t, "folder", rule.NamespaceUID)
+		require.Equal(t, "node", rule.RuleGroup)
+		require.Equal(t, 1, rule.RuleGroupIndex)
+		require.EqualValues(t, 30, rule.IntervalSeconds)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, time.Minute, rule.KeepFiringFor)
+		require.Equal(t, models.OK, rule.NoDataState)
+		require.Equal(t, ThresholdRefID, rule.Condition)
+		require.Equal(t, map[string]string{"severity": "critical"}, rule.Labels)
+		require.Equal(t, map[string]string{
+			"summary":     "Instance {{ $labels.instance }} is down",
+			"description": "{{ $labels.instance }} has value {{ $values.query.Value }}",
+		}, rule.Annotations)
+
+		require.Len(t, rule.Data, 3)
+		require.Equal(t, QueryRefID, rule.Data[0].RefID)
+		require.Equal(t, "prometheus", rule.Data[0].DatasourceUID)
+		var query map[string]interface{}
+		require.NoError(t, json.Unmarshal(rule.Data[0].Model, &query))
+		require.Equal(t, "up == 0", query["expr"])
+		require.Equal(t, true, query["instant"])
+		require.Equal(t, MathRefID, rule.Data[1].RefID)
+		require.Equal(t, expr.DatasourceUID, rule.Data[1].DatasourceUID)
+		require.Equal(t, ThresholdRefID, rule.Data[2].RefID)
+		require.Equal(t, expr.DatasourceUID, rule.Data[2].DatasourceUID)
+		for _, q := range rule.Data {
+			require.NoError(t, q.PreSave())
+		}
+	})
+
+	t.Run("should make titles unique", func(t *testing.T) {
+		require.Equal(t, "InstanceDown (2)", groups[0].Rules[1].Title)
+		require.Equal(t, 15*time.Minute, groups[0].Rules[1].For)
+	})
+
+	t.Run("should generate the same UIDs every time", func(t *testing.T) {
+		again, _ := c.ConvertGroups(f.Groups)
+		require.Equal(t, groups[0].Rules[0].UID, again[0].Rules[0].UID)
+		require.NotEqual(t, groups[0].Rules[0].UID, groups[0].Rules[1].UID)
+	})
+
+	t.Run("should use the default interval", func(t *testing.T) {
+		groups, _ := c.ConvertGroups(f.Groups[1:])
+		require.Empty(t, groups)
+
+		f.Groups[1].Rules = f.Groups[1].Rules[:0]
+		f.Groups[1].Rules = append(f.Groups[1].Rules, f.Groups[0].Rules[0])
+		groups, failures := c.ConvertGroups(f.Groups[1:])
+		require.Empty(t, failures)
+		require.EqualValues(t, 60, groups[0].Interval)
+	})
+}
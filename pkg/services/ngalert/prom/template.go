@@ -0,0 +1,65 @@
+package prom
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	templateActionRe = regexp.MustCompile(`(?s){{.*?}}`)
+
+	externalLabelsRe = regexp.MustCompile(`\$externalLabels\b|\.ExternalLabels\b`)
+
+	valueVarRe       = regexp.MustCompile(`\$value\b`)
+	valueFieldRe     = regexp.MustCompile(`(^|[^\w.$\])])\.Value\b`)
+	externalURLVarRe = regexp.MustCompile(`\$externalURL\b`)
+	externalURLRe    = regexp.MustCompile(`(^|[^\w.$\])])\.ExternalURL\b`)
+)
+
+// valueReplacement is the Grafana equivalent of the value of the series in Prometheus templates.
+var valueReplacement = fmt.Sprintf("$values.%s.Value", QueryRefID)
+
+var errExternalLabels = errors.New("external labels are not supported in templates")
+
+// convertTemplates converts the Prometheus templates in the values of a map of labels or annotations.
+func convertTemplates(m map[string]string) (map[string]string, error) {
+	if m == nil {
+		return nil, nil
+	}
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		converted, err := convertTemplate(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		result[k] = converted
+	}
+	return result, nil
+}
+
+// convertTemplate rewrites a Prometheus template so that it can be expanded by state/template.
+// $labels and the template functions of Prometheus, including query, are supported by Grafana as they are.
+// The value of the series, $value or .Value, is the value of the query in Grafana, and
+// $externalURL is the externalURL function. External labels are not supported.
+func convertTemplate(tmpl string) (string, error) {
+	if !strings.Contains(tmpl, "{{") {
+		return tmpl, nil
+	}
+	var err error
+	result := templateActionRe.ReplaceAllStringFunc(tmpl, func(action string) string {
+		if externalLabelsRe.MatchString(action) {
+			err = errExternalLabels
+		}
+		action = valueVarRe.ReplaceAllLiteralString(action, valueReplacement)
+		action = valueFieldRe.ReplaceAllString(action, "${1}"+strings.ReplaceAll(valueReplacement, "$", "$$"))
+		action = externalURLVarRe.ReplaceAllLiteralString(action, "externalURL")
+		action = externalURLRe.ReplaceAllString(action, "${1}externalURL")
+		return action
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
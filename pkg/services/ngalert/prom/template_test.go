@@ -0,0 +1,78 @@
+package prom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		tmpl     string
+		expected string
+		err      error
+	}{
+		{
+			name:     "text without templates is not changed",
+			tmpl:     "CPU usage is $value",
+			expected: "CPU usage is $value",
+		},
+		{
+			name:     "labels are not changed",
+			tmpl:     "Instance {{ $labels.instance }} of {{ .Labels.job }} is down",
+			expected: "Instance {{ $labels.instance }} of {{ .Labels.job }} is down",
+		},
+		{
+			name:     "value variable is replaced with the value of the query",
+			tmpl:     "CPU usage is {{ $value }}",
+			expected: "CPU usage is {{ $values.query.Value }}",
+		},
+		{
+			name:     "value variable in function calls is replaced",
+			tmpl:     "CPU usage is {{ $value | humanizePercentage }}, {{ printf \"%.2f\" $value }}",
+			expected: "CPU usage is {{ $values.query.Value | humanizePercentage }}, {{ printf \"%.2f\" $values.query.Value }}",
+		},
+		{
+			name:     "value field is replaced",
+			tmpl:     "{{ .Value }} {{humanize .Value}}",
+			expected: "{{ $values.query.Value }} {{humanize $values.query.Value}}",
+		},
+		{
+			name:     "values variable is not changed",
+			tmpl:     "{{ $values.B.Value }}",
+			expected: "{{ $values.B.Value }}",
+		},
+		{
+			name:     "external URL is replaced with the function",
+			tmpl:     "{{ $externalURL }}/alerting and {{ .ExternalURL }}",
+			expected: "{{ externalURL }}/alerting and {{ externalURL }}",
+		},
+		{
+			name: "external labels are not supported",
+			tmpl: "{{ $externalLabels.cluster }}",
+			err:  errExternalLabels,
+		},
+		{
+			name:     "query function is not changed",
+			tmpl:     `{{ with query "up" }}{{ . | first | value }}{{ end }}`,
+			expected: `{{ with query "up" }}{{ . | first | value }}{{ end }}`,
+		},
+		{
+			name:     "labels named query are supported",
+			tmpl:     "{{ $labels.query }}",
+			expected: "{{ $labels.query }}",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := convertTemplate(tc.tmpl)
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
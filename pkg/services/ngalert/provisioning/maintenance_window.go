@@ -0,0 +1,176 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type MaintenanceWindowService struct {
+	store MaintenanceWindowStore
+	prov  ProvisioningStore
+	xact  TransactionManager
+	log   log.Logger
+}
+
+func NewMaintenanceWindowService(store MaintenanceWindowStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *MaintenanceWindowService {
+	return &MaintenanceWindowService{
+		store: store,
+		prov:  prov,
+		xact:  xact,
+		log:   log,
+	}
+}
+
+// GetMaintenanceWindows returns all maintenance windows within the specified org.
+func (svc *MaintenanceWindowService) GetMaintenanceWindows(ctx context.Context, orgID int64) ([]definitions.MaintenanceWindow, error) {
+	windows, err := svc.store.ListMaintenanceWindows(ctx, &models.ListMaintenanceWindowsQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	provenances, err := svc.prov.GetProvenances(ctx, orgID, (&models.MaintenanceWindow{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.MaintenanceWindow, 0, len(windows))
+	for _, mw := range windows {
+		result = append(result, MaintenanceWindowToDefinition(mw, provenances[mw.UID]))
+	}
+	return result, nil
+}
+
+// GetMaintenanceWindow returns the maintenance window with the UID within the specified org.
+// It returns ErrNotFound if the window does not exist.
+func (svc *MaintenanceWindowService) GetMaintenanceWindow(ctx context.Context, orgID int64, uid string) (definitions.MaintenanceWindow, error) {
+	mw, err := svc.getMaintenanceWindow(ctx, orgID, uid)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	provenance, err := svc.prov.GetProvenance(ctx, mw, orgID)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	return MaintenanceWindowToDefinition(mw, provenance), nil
+}
+
+// CreateMaintenanceWindow adds a new maintenance window within the specified org. The created window is returned.
+func (svc *MaintenanceWindowService) CreateMaintenanceWindow(ctx context.Context, orgID int64, window definitions.MaintenanceWindow, provenance models.Provenance) (definitions.MaintenanceWindow, error) {
+	mw := MaintenanceWindowFromDefinition(orgID, window)
+	if mw.UID == "" {
+		mw.UID = util.GenerateShortUID()
+	}
+	if err := mw.Validate(); err != nil {
+		return definitions.MaintenanceWindow{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	mw.Updated = time.Now()
+	err := svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.InsertMaintenanceWindow(ctx, mw); err != nil {
+			if errors.Is(err, models.ErrMaintenanceWindowFailedValidation) {
+				return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+			}
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, mw, orgID, provenance)
+	})
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	return MaintenanceWindowToDefinition(mw, provenance), nil
+}
+
+// UpdateMaintenanceWindow replaces an existing maintenance window within the specified org. The updated window is returned.
+// It returns ErrNotFound if the window does not exist.
+func (svc *MaintenanceWindowService) UpdateMaintenanceWindow(ctx context.Context, orgID int64, window definitions.MaintenanceWindow, provenance models.Provenance) (definitions.MaintenanceWindow, error) {
+	mw := MaintenanceWindowFromDefinition(orgID, window)
+	if err := mw.Validate(); err != nil {
+		return definitions.MaintenanceWindow{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if _, err := svc.getMaintenanceWindow(ctx, orgID, mw.UID); err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	storedProvenance, err := svc.prov.GetProvenance(ctx, mw, orgID)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return definitions.MaintenanceWindow{}, fmt.Errorf("%w: cannot change provenance from '%s' to '%s'", ErrValidation, storedProvenance, provenance)
+	}
+	mw.Updated = time.Now()
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.UpdateMaintenanceWindow(ctx, mw); err != nil {
+			return err
+		}
+		return svc.prov.SetProvenance(ctx, mw, orgID, provenance)
+	})
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	return MaintenanceWindowToDefinition(mw, provenance), nil
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window with the UID within the specified org.
+// It does nothing if the window does not exist.
+func (svc *MaintenanceWindowService) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string, provenance models.Provenance) error {
+	target := &models.MaintenanceWindow{OrgID: orgID, UID: uid}
+	storedProvenance, err := svc.prov.GetProvenance(ctx, target, orgID)
+	if err != nil {
+		return err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return fmt.Errorf("%w: cannot delete with provided provenance '%s', needs '%s'", ErrValidation, provenance, storedProvenance)
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.store.DeleteMaintenanceWindow(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return svc.prov.DeleteProvenance(ctx, target, orgID)
+	})
+}
+
+func (svc *MaintenanceWindowService) getMaintenanceWindow(ctx context.Context, orgID int64, uid string) (*models.MaintenanceWindow, error) {
+	mw, err := svc.store.GetMaintenanceWindow(ctx, &models.GetMaintenanceWindowQuery{OrgID: orgID, UID: uid})
+	if err != nil {
+		if errors.Is(err, models.ErrMaintenanceWindowNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, err.Error())
+		}
+		return nil, err
+	}
+	return mw, nil
+}
+
+// MaintenanceWindowFromDefinition converts the API model of a maintenance window to the internal model.
+func MaintenanceWindowFromDefinition(orgID int64, mw definitions.MaintenanceWindow) *models.MaintenanceWindow {
+	return &models.MaintenanceWindow{
+		OrgID:     orgID,
+		UID:       mw.UID,
+		Title:     mw.Title,
+		Schedule:  mw.Schedule,
+		Timezone:  mw.Timezone,
+		Duration:  time.Duration(mw.Duration),
+		FolderUID: mw.FolderUID,
+		Matchers:  labels.Matchers(mw.Matchers),
+	}
+}
+
+// MaintenanceWindowToDefinition converts the internal model of a maintenance window to the API model.
+func MaintenanceWindowToDefinition(mw *models.MaintenanceWindow, provenance models.Provenance) definitions.MaintenanceWindow {
+	return definitions.MaintenanceWindow{
+		UID:        mw.UID,
+		Title:      mw.Title,
+		Schedule:   mw.Schedule,
+		Timezone:   mw.Timezone,
+		Duration:   model.Duration(mw.Duration),
+		FolderUID:  mw.FolderUID,
+		Matchers:   definitions.ObjectMatchers(mw.Matchers),
+		Provenance: definitions.Provenance(provenance),
+	}
+}
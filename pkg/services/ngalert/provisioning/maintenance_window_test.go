@@ -0,0 +1,114 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestMaintenanceWindowService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("service creates a window and generates its UID", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		mw := createMaintenanceWindow()
+		mw.UID = ""
+
+		created, err := sut.CreateMaintenanceWindow(ctx, 1, mw, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+		require.NotEmpty(t, created.UID)
+		require.Equal(t, definitions.Provenance(models.ProvenanceAPI), created.Provenance)
+		stored, err := sut.GetMaintenanceWindow(ctx, 1, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, created, stored)
+	})
+
+	t.Run("service rejects invalid windows", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		mw := createMaintenanceWindow()
+		mw.Schedule = "never"
+
+		_, err := sut.CreateMaintenanceWindow(ctx, 1, mw, models.ProvenanceNone)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("service rejects windows with an existing UID", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		_, err := sut.CreateMaintenanceWindow(ctx, 1, createMaintenanceWindow(), models.ProvenanceNone)
+		require.NoError(t, err)
+
+		_, err = sut.CreateMaintenanceWindow(ctx, 1, createMaintenanceWindow(), models.ProvenanceNone)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("service returns not found for unknown windows", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+
+		_, err := sut.GetMaintenanceWindow(ctx, 1, "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		_, err = sut.UpdateMaintenanceWindow(ctx, 1, createMaintenanceWindow(), models.ProvenanceNone)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("service updates a window", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		mw := createMaintenanceWindow()
+		_, err := sut.CreateMaintenanceWindow(ctx, 1, mw, models.ProvenanceNone)
+		require.NoError(t, err)
+
+		mw.Title = "updated"
+		_, err = sut.UpdateMaintenanceWindow(ctx, 1, mw, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		windows, err := sut.GetMaintenanceWindows(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, "updated", windows[0].Title)
+		require.Equal(t, definitions.Provenance(models.ProvenanceAPI), windows[0].Provenance)
+	})
+
+	t.Run("service respects provenance", func(t *testing.T) {
+		sut := createMaintenanceWindowSvcSut()
+		mw := createMaintenanceWindow()
+		_, err := sut.CreateMaintenanceWindow(ctx, 1, mw, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.UpdateMaintenanceWindow(ctx, 1, mw, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		err = sut.DeleteMaintenanceWindow(ctx, 1, mw.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		err = sut.DeleteMaintenanceWindow(ctx, 1, mw.UID, models.ProvenanceFile)
+		require.NoError(t, err)
+		_, err = sut.GetMaintenanceWindow(ctx, 1, mw.UID)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func createMaintenanceWindowSvcSut() *MaintenanceWindowService {
+	return NewMaintenanceWindowService(newFakeMaintenanceWindowStore(), NewFakeProvisioningStore(), newNopTransactionManager(), log.NewNopLogger())
+}
+
+func createMaintenanceWindow() definitions.MaintenanceWindow {
+	m, _ := labels.NewMatcher(labels.MatchEqual, "service", "db")
+	return definitions.MaintenanceWindow{
+		UID:      "backup",
+		Title:    "Backup",
+		Schedule: "0 2 * * *",
+		Duration: model.Duration(2 * time.Hour),
+		Matchers: definitions.ObjectMatchers{m},
+	}
+}
@@ -44,6 +44,15 @@ type RuleStore interface {
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
 }
 
+// MaintenanceWindowStore represents the ability to persist and query maintenance windows.
+type MaintenanceWindowStore interface {
+	ListMaintenanceWindows(ctx context.Context, query *models.ListMaintenanceWindowsQuery) ([]*models.MaintenanceWindow, error)
+	GetMaintenanceWindow(ctx context.Context, query *models.GetMaintenanceWindowQuery) (*models.MaintenanceWindow, error)
+	InsertMaintenanceWindow(ctx context.Context, mw *models.MaintenanceWindow) error
+	UpdateMaintenanceWindow(ctx context.Context, mw *models.MaintenanceWindow) error
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error
+}
+
 // QuotaChecker represents the ability to evaluate whether quotas are met.
 //
 //go:generate mockery --name QuotaChecker --structname MockQuotaChecker --inpackage --filename quota_checker_mock.go --with-expecter
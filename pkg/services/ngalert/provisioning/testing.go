@@ -185,3 +185,53 @@ func (m *MockQuotaChecker_Expecter) LimitExceeded() *MockQuotaChecker_Expecter {
 	m.CheckQuotaReached(mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
 	return m
 }
+
+type fakeMaintenanceWindowStore struct {
+	windows map[int64]map[string]*models.MaintenanceWindow
+}
+
+func newFakeMaintenanceWindowStore() *fakeMaintenanceWindowStore {
+	return &fakeMaintenanceWindowStore{
+		windows: map[int64]map[string]*models.MaintenanceWindow{},
+	}
+}
+
+func (f *fakeMaintenanceWindowStore) ListMaintenanceWindows(_ context.Context, query *models.ListMaintenanceWindowsQuery) ([]*models.MaintenanceWindow, error) {
+	result := make([]*models.MaintenanceWindow, 0, len(f.windows[query.OrgID]))
+	for _, mw := range f.windows[query.OrgID] {
+		result = append(result, mw)
+	}
+	return result, nil
+}
+
+func (f *fakeMaintenanceWindowStore) GetMaintenanceWindow(_ context.Context, query *models.GetMaintenanceWindowQuery) (*models.MaintenanceWindow, error) {
+	mw, ok := f.windows[query.OrgID][query.UID]
+	if !ok {
+		return nil, models.ErrMaintenanceWindowNotFound
+	}
+	return mw, nil
+}
+
+func (f *fakeMaintenanceWindowStore) InsertMaintenanceWindow(_ context.Context, mw *models.MaintenanceWindow) error {
+	if _, ok := f.windows[mw.OrgID][mw.UID]; ok {
+		return models.ErrMaintenanceWindowFailedValidation
+	}
+	if _, ok := f.windows[mw.OrgID]; !ok {
+		f.windows[mw.OrgID] = map[string]*models.MaintenanceWindow{}
+	}
+	f.windows[mw.OrgID][mw.UID] = mw
+	return nil
+}
+
+func (f *fakeMaintenanceWindowStore) UpdateMaintenanceWindow(_ context.Context, mw *models.MaintenanceWindow) error {
+	if _, ok := f.windows[mw.OrgID][mw.UID]; !ok {
+		return models.ErrMaintenanceWindowNotFound
+	}
+	f.windows[mw.OrgID][mw.UID] = mw
+	return nil
+}
+
+func (f *fakeMaintenanceWindowStore) DeleteMaintenanceWindow(_ context.Context, orgID int64, uid string) error {
+	delete(f.windows[orgID], uid)
+	return nil
+}
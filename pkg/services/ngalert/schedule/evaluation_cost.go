@@ -0,0 +1,139 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// slowEvaluationsWindow is the number of the most recent evaluations of a rule that are considered to detect slow rules.
+	slowEvaluationsWindow = 5
+	// slowEvaluationsThreshold is the number of evaluations in the window that must take longer than the interval
+	// of the rule for the rule to be considered slow.
+	slowEvaluationsThreshold = 3
+)
+
+type ruleEvaluationCost struct {
+	cost models.RuleEvaluationCost
+	// recent is a ring buffer that tells whether each of the recent evaluations took longer than the interval of the rule.
+	recent []bool
+	next   int
+}
+
+// evaluationCostTracker keeps track of the cost of the evaluations of the scheduled rules.
+type evaluationCostTracker struct {
+	mu      sync.Mutex
+	costs   map[models.AlertRuleKey]*ruleEvaluationCost
+	metrics *metrics.Scheduler
+}
+
+func newEvaluationCostTracker(m *metrics.Scheduler) *evaluationCostTracker {
+	return &evaluationCostTracker{
+		costs:   make(map[models.AlertRuleKey]*ruleEvaluationCost),
+		metrics: m,
+	}
+}
+
+// record updates the cost of the rule with an evaluation and returns true if the rule is slow.
+func (t *evaluationCostTracker) record(rule *models.AlertRule, evaluatedAt time.Time, dur time.Duration, stats eval.EvaluationStats) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := rule.GetKey()
+	c, ok := t.costs[key]
+	if !ok {
+		c = &ruleEvaluationCost{recent: make([]bool, slowEvaluationsWindow)}
+		t.costs[key] = c
+	}
+	c.cost.AlertRuleKey = key
+	c.cost.Title = rule.Title
+	c.cost.NamespaceUID = rule.NamespaceUID
+	c.cost.RuleGroup = rule.RuleGroup
+	c.cost.Interval = time.Duration(rule.IntervalSeconds) * time.Second
+	c.cost.Evaluations++
+	c.cost.LastEvaluation = evaluatedAt
+	c.cost.LastDuration = dur
+	c.cost.TotalDuration += dur
+	if dur > c.cost.MaxDuration {
+		c.cost.MaxDuration = dur
+	}
+	c.cost.LastQueryCount = stats.QueryCount
+	c.cost.LastSeriesCount = stats.SeriesCount
+	c.cost.LastDataSourceDuration = stats.DataSourceDuration
+
+	c.recent[c.next] = c.cost.Interval > 0 && dur > c.cost.Interval
+	c.next = (c.next + 1) % len(c.recent)
+	slowEvaluations := 0
+	for _, slow := range c.recent {
+		if slow {
+			slowEvaluations++
+		}
+	}
+	wasSlow := c.cost.Slow
+	c.cost.SlowEvaluations = slowEvaluations
+	c.cost.Slow = slowEvaluations >= slowEvaluationsThreshold
+
+	if t.metrics != nil {
+		orgID, ruleUID := fmt.Sprint(key.OrgID), key.UID
+		t.metrics.RuleLastEvalDuration.WithLabelValues(orgID, ruleUID).Set(dur.Seconds())
+		t.metrics.RuleLastEvalDataSourceDuration.WithLabelValues(orgID, ruleUID).Set(stats.DataSourceDuration.Seconds())
+		t.metrics.RuleLastEvalQueries.WithLabelValues(orgID, ruleUID).Set(float64(stats.QueryCount))
+		t.metrics.RuleLastEvalSeries.WithLabelValues(orgID, ruleUID).Set(float64(stats.SeriesCount))
+		if c.cost.Slow && !wasSlow {
+			t.metrics.SlowRules.WithLabelValues(orgID).Inc()
+		} else if !c.cost.Slow && wasSlow {
+			t.metrics.SlowRules.WithLabelValues(orgID).Dec()
+		}
+	}
+	return c.cost.Slow
+}
+
+// delete forgets the cost of the rule.
+func (t *evaluationCostTracker) delete(key models.AlertRuleKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	c, ok := t.costs[key]
+	if !ok {
+		return
+	}
+	delete(t.costs, key)
+
+	if t.metrics != nil {
+		orgID, ruleUID := fmt.Sprint(key.OrgID), key.UID
+		t.metrics.RuleLastEvalDuration.DeleteLabelValues(orgID, ruleUID)
+		t.metrics.RuleLastEvalDataSourceDuration.DeleteLabelValues(orgID, ruleUID)
+		t.metrics.RuleLastEvalQueries.DeleteLabelValues(orgID, ruleUID)
+		t.metrics.RuleLastEvalSeries.DeleteLabelValues(orgID, ruleUID)
+		if c.cost.Slow {
+			t.metrics.SlowRules.WithLabelValues(orgID).Dec()
+		}
+	}
+}
+
+// get returns the costs of the rules of the organization, the most expensive rules first.
+func (t *evaluationCostTracker) get(orgID int64) []models.RuleEvaluationCost {
+	t.mu.Lock()
+	result := make([]models.RuleEvaluationCost, 0, len(t.costs))
+	for key, c := range t.costs {
+		if key.OrgID != orgID {
+			continue
+		}
+		result = append(result, c.cost)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AverageDuration() != result[j].AverageDuration() {
+			return result[i].AverageDuration() > result[j].AverageDuration()
+		}
+		return result[i].UID < result[j].UID
+	})
+	return result
+}
@@ -0,0 +1,102 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestEvaluationCostTracker(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	stats := eval.EvaluationStats{QueryCount: 2, SeriesCount: 10, DataSourceDuration: 3 * time.Second}
+
+	t.Run("should record the cost of evaluations", func(t *testing.T) {
+		m := metrics.NewSchedulerMetrics(prometheus.NewPedanticRegistry())
+		tracker := newEvaluationCostTracker(m)
+		rule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(time.Minute))()
+
+		require.False(t, tracker.record(rule, now, 4*time.Second, stats))
+		require.False(t, tracker.record(rule, now.Add(time.Minute), 2*time.Second, eval.EvaluationStats{QueryCount: 1, SeriesCount: 5, DataSourceDuration: time.Second}))
+
+		costs := tracker.get(1)
+		require.Len(t, costs, 1)
+		cost := costs[0]
+		require.Equal(t, rule.GetKey(), cost.AlertRuleKey)
+		require.Equal(t, rule.Title, cost.Title)
+		require.Equal(t, time.Minute, cost.Interval)
+		require.EqualValues(t, 2, cost.Evaluations)
+		require.Equal(t, now.Add(time.Minute), cost.LastEvaluation)
+		require.Equal(t, 2*time.Second, cost.LastDuration)
+		require.Equal(t, 4*time.Second, cost.MaxDuration)
+		require.Equal(t, 6*time.Second, cost.TotalDuration)
+		require.Equal(t, 3*time.Second, cost.AverageDuration())
+		require.Equal(t, 1, cost.LastQueryCount)
+		require.Equal(t, 5, cost.LastSeriesCount)
+		require.Equal(t, time.Second, cost.LastDataSourceDuration)
+		require.False(t, cost.Slow)
+
+		require.Empty(t, tracker.get(2))
+
+		orgID := "1"
+		require.Equal(t, 2.0, testutil.ToFloat64(m.RuleLastEvalDuration.WithLabelValues(orgID, rule.UID)))
+		require.Equal(t, 1.0, testutil.ToFloat64(m.RuleLastEvalQueries.WithLabelValues(orgID, rule.UID)))
+		require.Equal(t, 5.0, testutil.ToFloat64(m.RuleLastEvalSeries.WithLabelValues(orgID, rule.UID)))
+		require.Equal(t, 1.0, testutil.ToFloat64(m.RuleLastEvalDataSourceDuration.WithLabelValues(orgID, rule.UID)))
+	})
+
+	t.Run("should flag rules that regularly take longer than their interval", func(t *testing.T) {
+		m := metrics.NewSchedulerMetrics(prometheus.NewPedanticRegistry())
+		tracker := newEvaluationCostTracker(m)
+		rule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(10*time.Second))()
+
+		durations := []time.Duration{11 * time.Second, time.Second, 12 * time.Second, 20 * time.Second}
+		expected := []bool{false, false, false, true}
+		for i, d := range durations {
+			require.Equalf(t, expected[i], tracker.record(rule, now, d, stats), "evaluation %d", i)
+		}
+		require.Equal(t, 3, tracker.get(1)[0].SlowEvaluations)
+		require.Equal(t, 1.0, testutil.ToFloat64(m.SlowRules.WithLabelValues("1")))
+
+		// the rule is no longer slow once the slow evaluations leave the window
+		for i := 0; i < slowEvaluationsWindow-slowEvaluationsThreshold+1; i++ {
+			tracker.record(rule, now, time.Second, stats)
+		}
+		require.False(t, tracker.get(1)[0].Slow)
+		require.Equal(t, 0.0, testutil.ToFloat64(m.SlowRules.WithLabelValues("1")))
+	})
+
+	t.Run("should sort rules by average duration", func(t *testing.T) {
+		tracker := newEvaluationCostTracker(nil)
+		cheap := models.AlertRuleGen(models.WithOrgID(1))()
+		expensive := models.AlertRuleGen(models.WithOrgID(1))()
+		tracker.record(cheap, now, time.Second, stats)
+		tracker.record(expensive, now, 5*time.Second, stats)
+
+		costs := tracker.get(1)
+		require.Len(t, costs, 2)
+		require.Equal(t, expensive.UID, costs[0].UID)
+		require.Equal(t, cheap.UID, costs[1].UID)
+	})
+
+	t.Run("should forget deleted rules", func(t *testing.T) {
+		m := metrics.NewSchedulerMetrics(prometheus.NewPedanticRegistry())
+		tracker := newEvaluationCostTracker(m)
+		rule := models.AlertRuleGen(models.WithOrgID(1), models.WithInterval(10*time.Second))()
+		for i := 0; i < slowEvaluationsThreshold; i++ {
+			tracker.record(rule, now, time.Minute, stats)
+		}
+		require.Equal(t, 1.0, testutil.ToFloat64(m.SlowRules.WithLabelValues("1")))
+
+		tracker.delete(rule.GetKey())
+		require.Empty(t, tracker.get(1))
+		require.Equal(t, 0.0, testutil.ToFloat64(m.SlowRules.WithLabelValues("1")))
+		require.Equal(t, 0, testutil.CollectAndCount(m.RuleLastEvalDuration))
+	})
+}
@@ -342,6 +342,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.OrgID)
 	writeInt(rule.IntervalSeconds)
 	writeInt(int64(rule.For))
+	writeInt(int64(rule.KeepFiringFor))
 	writeLabels(rule.Annotations)
 	if rule.DashboardUID != nil {
 		writeString(*rule.DashboardUID)
@@ -353,5 +354,13 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(int64(rule.RuleGroupIndex))
 	writeString(string(rule.NoDataState))
 	writeString(string(rule.ExecErrState))
+	for _, uid := range rule.DependsOn {
+		writeString(uid)
+	}
+	if rule.Record != nil {
+		writeString(rule.Record.Metric)
+		writeString(rule.Record.From)
+		writeString(rule.Record.TargetDatasourceUID)
+	}
 	return fingerprint(sum.Sum64())
 }
@@ -408,13 +408,19 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata",
 			ExecErrState:    "test-err",
 			For:             12,
+			KeepFiringFor:   13,
 			Annotations: map[string]string{
 				"key-annotation": "value-annotation",
 			},
 			Labels: map[string]string{
 				"key-label": "value-label",
 			},
-			IsPaused: false,
+			IsPaused:  false,
+			DependsOn: []string{"upstream-1"},
+			Record: &models.Record{
+				Metric: "metric_1",
+				From:   "A",
+			},
 		}
 		r2 := &models.AlertRule{
 			ID:        2,
@@ -443,13 +449,20 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 			NoDataState:     "test-nodata2",
 			ExecErrState:    "test-err2",
 			For:             1141,
+			KeepFiringFor:   1142,
 			Annotations: map[string]string{
 				"key-annotation2": "value-annotation",
 			},
 			Labels: map[string]string{
 				"key-label": "value-label23",
 			},
-			IsPaused: true,
+			IsPaused:  true,
+			DependsOn: []string{"upstream-2"},
+			Record: &models.Record{
+				Metric:              "metric_2",
+				From:                "B",
+				TargetDatasourceUID: "prometheus",
+			},
 		}
 
 		excludedFields := map[string]struct{}{
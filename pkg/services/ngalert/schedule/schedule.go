@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sync/errgroup"
 
@@ -39,6 +40,11 @@ type AlertsSender interface {
 	Send(key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
 }
 
+// RecordingWriter writes the results of recording rules as metrics.
+type RecordingWriter interface {
+	Write(ctx context.Context, rule *ngmodels.AlertRule, t time.Time, frames data.Frames) error
+}
+
 // RulesStore is a store that provides alert rules for scheduling
 type RulesStore interface {
 	GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error)
@@ -82,12 +88,20 @@ type schedule struct {
 	alertsSender    AlertsSender
 	minRuleInterval time.Duration
 
+	// recordingWriter writes the results of recording rules. It is nil if recording rules are not enabled.
+	recordingWriter RecordingWriter
+
 	// schedulableAlertRules contains the alert rules that are considered for
 	// evaluation in the current tick. The evaluation of an alert rule in the
 	// current tick depends on its evaluation interval and when it was
 	// last evaluated.
 	schedulableAlertRules alertRulesRegistry
 
+	// evaluationCosts keeps track of the cost of the evaluations of the scheduled rules.
+	evaluationCosts *evaluationCostTracker
+	// slowRuleStateReason determines whether alert instances of slow rules get the state reason SlowEvaluation.
+	slowRuleStateReason bool
+
 	tracer tracing.Tracer
 }
 
@@ -104,6 +118,9 @@ type SchedulerCfg struct {
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
 	Tracer               tracing.Tracer
+	SlowRuleStateReason  bool
+	// RecordingWriter writes the results of recording rules. If it is nil, recording rules are not evaluated.
+	RecordingWriter RecordingWriter
 }
 
 // NewScheduler returns a new schedule.
@@ -124,11 +141,20 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
 		tracer:                cfg.Tracer,
+		evaluationCosts:       newEvaluationCostTracker(cfg.Metrics),
+		slowRuleStateReason:   cfg.SlowRuleStateReason,
+		recordingWriter:       cfg.RecordingWriter,
 	}
 
 	return &sch
 }
 
+// EvaluationCosts returns the cost of the evaluations of the rules of the organization that are scheduled
+// on this instance, the most expensive rules first.
+func (sch *schedule) EvaluationCosts(orgID int64) []ngmodels.RuleEvaluationCost {
+	return sch.evaluationCosts.get(orgID)
+}
+
 func (sch *schedule) Run(ctx context.Context) error {
 	t := ticker.New(sch.clock, sch.baseInterval, sch.metrics.Ticker)
 	defer t.Stop()
@@ -368,19 +394,32 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		notify(states)
 	}
 
+	suppress := func(ctx context.Context, e *evaluation) {
+		states := sch.stateManager.SuppressStateByRuleUID(ctx, e.rule, e.scheduledAt, ngmodels.StateReasonSuppressedByDependency)
+		alerts := state.FromStateTransitionToPostableAlerts(states, sch.stateManager, sch.appURL)
+		if len(alerts.PostableAlerts) > 0 {
+			sch.alertsSender.Send(key, alerts)
+		}
+		sch.evalApplied(key, e.scheduledAt)
+	}
+
 	evaluate := func(ctx context.Context, f fingerprint, attempt int64, e *evaluation, span tracing.Span) {
 		logger := logger.New("version", e.rule.Version, "fingerprint", f, "attempt", attempt, "now", e.scheduledAt).FromContext(ctx)
 		start := sch.clock.Now()
 
-		evalCtx := eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID))
+		evalCtx := eval.NewContextWithPreviousResults(ctx, SchedulerUserFor(e.rule.OrgID), &state.AlertingResultsFromRuleState{
+			Manager: sch.stateManager,
+			Rule:    e.rule,
+		})
 		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
+		statsCtx, stats := eval.NewContextWithStatsCollector(ctx)
 		var results eval.Results
 		var dur time.Duration
 		if err != nil {
 			dur = sch.clock.Now().Sub(start)
 			logger.Error("Failed to build rule evaluator", "error", err)
 		} else {
-			results, err = ruleEval.Evaluate(ctx, e.scheduledAt)
+			results, err = ruleEval.Evaluate(statsCtx, e.scheduledAt)
 			dur = sch.clock.Now().Sub(start)
 			if err != nil {
 				logger.Error("Failed to evaluate rule", "error", err, "duration", dur)
@@ -389,6 +428,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 
 		evalTotal.Inc()
 		evalDuration.Observe(dur.Seconds())
+		slow := sch.evaluationCosts.record(e.rule, e.scheduledAt, dur, stats.Stats())
+		if slow {
+			logger.Warn("Alert rule regularly takes longer to evaluate than its interval", "duration", dur, "interval", time.Duration(e.rule.IntervalSeconds)*time.Second)
+		}
 
 		if err != nil || results.HasErrors() {
 			evalTotalFailures.Inc()
@@ -422,6 +465,11 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 			logger.Debug("Skip updating the state because the context has been cancelled")
 			return
 		}
+		if slow && sch.slowRuleStateReason {
+			for i := range results {
+				results[i].StateReason = ngmodels.StateReasonSlowEvaluation
+			}
+		}
 		processedStates := sch.stateManager.ProcessEvalResults(
 			ctx,
 			e.scheduledAt,
@@ -442,6 +490,58 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		}
 	}
 
+	// record evaluates a recording rule and writes the result as a metric. The state manager is not involved,
+	// so recording rules never create alert instances.
+	record := func(ctx context.Context, f fingerprint, attempt int64, e *evaluation, span tracing.Span) {
+		logger := logger.New("version", e.rule.Version, "fingerprint", f, "attempt", attempt, "now", e.scheduledAt).FromContext(ctx)
+		if sch.recordingWriter == nil {
+			logger.Debug("Skip evaluation of recording rule because recording rules are not enabled")
+			return
+		}
+		start := sch.clock.Now()
+
+		var frames data.Frames
+		ruleEval, err := sch.evaluatorFactory.Create(eval.NewContext(ctx, SchedulerUserFor(e.rule.OrgID)), e.rule.GetEvalCondition())
+		statsCtx, stats := eval.NewContextWithStatsCollector(ctx)
+		if err == nil {
+			resp, evalErr := ruleEval.EvaluateRaw(statsCtx, e.scheduledAt)
+			if evalErr != nil {
+				err = evalErr
+			} else if res, ok := resp.Responses[e.rule.Record.From]; ok {
+				frames, err = res.Frames, res.Error
+			}
+		}
+		dur := sch.clock.Now().Sub(start)
+
+		evalTotal.Inc()
+		evalDuration.Observe(dur.Seconds())
+		if sch.evaluationCosts.record(e.rule, e.scheduledAt, dur, stats.Stats()) {
+			logger.Warn("Recording rule regularly takes longer to evaluate than its interval", "duration", dur, "interval", time.Duration(e.rule.IntervalSeconds)*time.Second)
+		}
+		if err == nil && ctx.Err() == nil {
+			err = sch.recordingWriter.Write(ctx, e.rule, e.scheduledAt, frames)
+		}
+		if err != nil {
+			evalTotalFailures.Inc()
+			logger.Error("Failed to record rule", "error", err, "duration", dur)
+			span.RecordError(err)
+			span.AddEvents(
+				[]string{"error", "message"},
+				[]tracing.EventValue{
+					{Str: fmt.Sprintf("%v", err)},
+					{Str: "rule recording failed"},
+				})
+			return
+		}
+		logger.Debug("Recording rule evaluated", "frames", len(frames), "duration", dur)
+		span.AddEvents(
+			[]string{"message", "frames"},
+			[]tracing.EventValue{
+				{Str: "rule recorded"},
+				{Num: int64(len(frames))},
+			})
+	}
+
 	retryIfError := func(f func(attempt int64) error) error {
 		var attempt int64
 		var err error
@@ -457,6 +557,7 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	evalRunning := false
 	var currentFingerprint fingerprint
 	defer sch.stopApplied(key)
+	defer sch.evaluationCosts.delete(key)
 	for {
 		select {
 		// used by external services (API) to notify that rule is updated.
@@ -508,6 +609,11 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 						logger.Debug("Skip rule evaluation because it is paused")
 						return nil
 					}
+					if upstreamUID, ok := sch.firingUpstreamRule(ctx.rule); ok {
+						logger.Debug("Skip rule evaluation because an upstream rule is firing", "upstream_rule_uid", upstreamUID)
+						suppress(grafanaCtx, ctx)
+						return nil
+					}
 					tracingCtx, span := sch.tracer.Start(grafanaCtx, "alert rule execution")
 					defer span.End()
 
@@ -519,6 +625,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 					utcTick := ctx.scheduledAt.UTC().Format(time.RFC3339Nano)
 					span.SetAttributes("tick", utcTick, attribute.String("tick", utcTick))
 
+					if ctx.rule.IsRecordingRule() {
+						record(tracingCtx, f, attempt, ctx, span)
+						return nil
+					}
 					evaluate(tracingCtx, f, attempt, ctx, span)
 					return nil
 				})
@@ -543,6 +653,18 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	}
 }
 
+// firingUpstreamRule returns the UID of the first upstream rule of the given rule that has at least one firing alert instance.
+func (sch *schedule) firingUpstreamRule(rule *ngmodels.AlertRule) (string, bool) {
+	for _, uid := range rule.DependsOn {
+		for _, s := range sch.stateManager.GetStatesForRuleUID(rule.OrgID, uid) {
+			if s.IsFiring() {
+				return uid, true
+			}
+		}
+	}
+	return "", false
+}
+
 // evalApplied is only used on tests.
 func (sch *schedule) evalApplied(alertDefKey ngmodels.AlertRuleKey, now time.Time) {
 	if sch.evalAppliedFunc == nil {
@@ -716,6 +716,107 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 		})
 	})
 
+	t.Run("when an upstream rule is firing", func(t *testing.T) {
+		t.Run("it should skip evaluation and resolve the alerts", func(t *testing.T) {
+			upstream := models.AlertRuleGen(withQueryForState(t, eval.Alerting))()
+			rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithOrgID(upstream.OrgID), models.WithDependsOn(upstream.UID))()
+
+			evalChan := make(chan *evaluation)
+			upstreamEvalChan := make(chan *evaluation)
+			evalAppliedChan := make(chan time.Time)
+
+			sender := AlertsSenderMock{}
+			sender.EXPECT().Send(mock.Anything, mock.Anything).Return()
+
+			sch, ruleStore, _, _ := createSchedule(evalAppliedChan, &sender)
+			ruleStore.PutRule(context.Background(), upstream, rule)
+
+			go func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+				_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+			}()
+			go func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+				_ = sch.ruleRoutine(ctx, upstream.GetKey(), upstreamEvalChan, make(chan ruleVersionAndPauseStatus))
+			}()
+
+			evalChan <- &evaluation{
+				scheduledAt: sch.clock.Now(),
+				rule:        rule,
+			}
+			waitForTimeChannel(t, evalAppliedChan)
+
+			states := sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+			require.Len(t, states, 1)
+			require.Equal(t, eval.Alerting, states[0].State)
+
+			upstreamEvalChan <- &evaluation{
+				scheduledAt: sch.clock.Now(),
+				rule:        upstream,
+			}
+			waitForTimeChannel(t, evalAppliedChan)
+
+			evalChan <- &evaluation{
+				scheduledAt: sch.clock.Now().Add(time.Duration(rule.IntervalSeconds) * time.Second),
+				rule:        rule,
+			}
+			waitForTimeChannel(t, evalAppliedChan)
+
+			states = sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID)
+			require.Len(t, states, 1)
+			require.Equal(t, eval.Normal, states[0].State)
+			require.Equal(t, models.StateReasonSuppressedByDependency, states[0].StateReason)
+
+			sender.AssertNumberOfCalls(t, "Send", 3)
+			args, ok := sender.Calls[2].Arguments[1].(definitions.PostableAlerts)
+			require.Truef(t, ok, fmt.Sprintf("expected argument of function was supposed to be 'definitions.PostableAlerts' but got %T", sender.Calls[2].Arguments[1]))
+			require.Len(t, args.PostableAlerts, 1)
+			require.Equal(t, rule.GetKey(), sender.Calls[2].Arguments[0])
+		})
+	})
+
+	t.Run("when rule is a recording rule", func(t *testing.T) {
+		t.Run("it should write the result and not create alert instances", func(t *testing.T) {
+			rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithRecord("test:metric"))()
+
+			evalChan := make(chan *evaluation)
+			evalAppliedChan := make(chan time.Time)
+
+			sender := AlertsSenderMock{}
+			sender.EXPECT().Send(rule.GetKey(), mock.Anything).Return()
+
+			sch, ruleStore, _, _ := createSchedule(evalAppliedChan, &sender)
+			writer := &fakeRecordingWriter{}
+			sch.recordingWriter = writer
+			ruleStore.PutRule(context.Background(), rule)
+
+			go func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				t.Cleanup(cancel)
+				_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersionAndPauseStatus))
+			}()
+
+			evalChan <- &evaluation{
+				scheduledAt: sch.clock.Now(),
+				rule:        rule,
+			}
+
+			waitForTimeChannel(t, evalAppliedChan)
+
+			writes := writer.getWrites()
+			require.Len(t, writes, 1)
+			require.Len(t, writes[0], 1)
+			value, err := writes[0][0].Fields[0].NullableFloatAt(0)
+			require.NoError(t, err)
+			require.Equal(t, float64(1), *value)
+
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+	})
+
 	t.Run("when there are no alerts to send it should not call notifiers", func(t *testing.T) {
 		rule := models.AlertRuleGen(withQueryForState(t, eval.Normal))()
 
@@ -0,0 +1,118 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// templateQueryRefID is the RefID of the query that runs the query function of a template.
+const templateQueryRefID = "A"
+
+var errNoTemplateQueryDatasource = errors.New("alert rule has no data source to query")
+
+// TemplateQuerier runs the queries of the query function in the templates of labels and annotations.
+// Queries are instant queries against the data source of the first query of the alert rule,
+// and must be written in the query language of that data source, such as PromQL.
+type TemplateQuerier struct {
+	evaluatorFactory eval.EvaluatorFactory
+}
+
+func NewTemplateQuerier(evaluatorFactory eval.EvaluatorFactory) *TemplateQuerier {
+	return &TemplateQuerier{evaluatorFactory: evaluatorFactory}
+}
+
+// Query runs an instant query at the given time against the data source of the alert rule and returns
+// the most recent value of each series as a sample.
+func (q *TemplateQuerier) Query(ctx context.Context, rule *models.AlertRule, query string, ts time.Time) (promql.Vector, error) {
+	ruleQuery, err := templateQueryDatasource(rule)
+	if err != nil {
+		return nil, err
+	}
+	model, err := json.Marshal(map[string]interface{}{
+		"refId":     templateQueryRefID,
+		"expr":      query,
+		"instant":   true,
+		"range":     false,
+		"queryType": "instant",
+	})
+	if err != nil {
+		return nil, err
+	}
+	condition := models.Condition{
+		Condition: templateQueryRefID,
+		Data: []models.AlertQuery{{
+			RefID:             templateQueryRefID,
+			DatasourceUID:     ruleQuery.DatasourceUID,
+			RelativeTimeRange: ruleQuery.RelativeTimeRange,
+			Model:             model,
+		}},
+	}
+
+	evaluator, err := q.evaluatorFactory.Create(eval.NewContext(ctx, SchedulerUserFor(rule.OrgID)), condition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+	resp, err := evaluator.EvaluateRaw(ctx, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	res, ok := resp.Responses[templateQueryRefID]
+	if !ok {
+		return nil, nil
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", res.Error)
+	}
+	return vectorFromFrames(res.Frames, ts), nil
+}
+
+// templateQueryDatasource returns the first query of the alert rule that is not an expression.
+func templateQueryDatasource(rule *models.AlertRule) (models.AlertQuery, error) {
+	for _, q := range rule.Data {
+		isExpr, err := q.IsExpression()
+		if err != nil {
+			return models.AlertQuery{}, err
+		}
+		if !isExpr {
+			return q, nil
+		}
+	}
+	return models.AlertQuery{}, errNoTemplateQueryDatasource
+}
+
+// vectorFromFrames converts the numeric fields of the frames to samples at the given time. Each sample has the labels
+// of the field and its most recent non-null value. Fields without values are skipped.
+func vectorFromFrames(frames data.Frames, ts time.Time) promql.Vector {
+	var result promql.Vector
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+			var value *float64
+			for i := field.Len() - 1; i >= 0 && value == nil; i-- {
+				if v, err := field.NullableFloatAt(i); err == nil {
+					value = v
+				}
+			}
+			if value == nil {
+				continue
+			}
+			result = append(result, promql.Sample{
+				Point:  promql.Point{T: ts.UnixMilli(), V: *value},
+				Metric: labels.FromMap(field.Labels),
+			})
+		}
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeEvaluatorFactory struct {
+	conditions []models.Condition
+	evaluator  eval.ConditionEvaluator
+}
+
+func (f *fakeEvaluatorFactory) Validate(eval.EvaluationContext, models.Condition) error {
+	return nil
+}
+
+func (f *fakeEvaluatorFactory) Create(_ eval.EvaluationContext, condition models.Condition) (eval.ConditionEvaluator, error) {
+	f.conditions = append(f.conditions, condition)
+	return f.evaluator, nil
+}
+
+func TestTemplateQuerier(t *testing.T) {
+	ts := time.Unix(1000, 0)
+	rule := models.AlertRuleGen(models.WithOrgID(1))()
+	rule.Data = []models.AlertQuery{{
+		RefID:             "A",
+		DatasourceUID:     "prometheus",
+		RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(10 * time.Minute)},
+		Model:             []byte(`{"expr": "up"}`),
+	}, {
+		RefID:         "B",
+		DatasourceUID: expr.DatasourceUID,
+		Model:         []byte(`{"type": "reduce", "expression": "A", "reducer": "last"}`),
+	}}
+
+	t.Run("should query the data source of the rule", func(t *testing.T) {
+		evaluator := eval_mocks.NewConditionEvaluatorMock(t)
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, ts).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{
+				templateQueryRefID: {Frames: data.Frames{
+					data.NewFrame("",
+						data.NewField("time", nil, []time.Time{ts.Add(-time.Minute), ts}),
+						data.NewField("value", data.Labels{"instance": "a"}, []*float64{util.Pointer(1.0), nil}),
+					),
+					data.NewFrame("",
+						data.NewField("value", data.Labels{"instance": "b"}, []float64{2}),
+					),
+					data.NewFrame("",
+						data.NewField("value", data.Labels{"instance": "c"}, []*float64{nil}),
+					),
+				}},
+			},
+		}, nil)
+		factory := &fakeEvaluatorFactory{evaluator: evaluator}
+
+		res, err := NewTemplateQuerier(factory).Query(context.Background(), rule, `sum(up)`, ts)
+		require.NoError(t, err)
+		require.Equal(t, promql.Vector{
+			{Point: promql.Point{T: ts.UnixMilli(), V: 1}, Metric: labels.FromStrings("instance", "a")},
+			{Point: promql.Point{T: ts.UnixMilli(), V: 2}, Metric: labels.FromStrings("instance", "b")},
+		}, res)
+
+		require.Len(t, factory.conditions, 1)
+		condition := factory.conditions[0]
+		require.Equal(t, templateQueryRefID, condition.Condition)
+		require.Len(t, condition.Data, 1)
+		require.Equal(t, "prometheus", condition.Data[0].DatasourceUID)
+		require.Equal(t, rule.Data[0].RelativeTimeRange, condition.Data[0].RelativeTimeRange)
+		q, err := condition.Data[0].GetQuery()
+		require.NoError(t, err)
+		require.Equal(t, `sum(up)`, q)
+	})
+
+	t.Run("should return the error of the query", func(t *testing.T) {
+		evaluator := eval_mocks.NewConditionEvaluatorMock(t)
+		evaluator.EXPECT().EvaluateRaw(mock.Anything, ts).Return(&backend.QueryDataResponse{
+			Responses: backend.Responses{
+				templateQueryRefID: {Error: errors.New("parse error")},
+			},
+		}, nil)
+
+		_, err := NewTemplateQuerier(&fakeEvaluatorFactory{evaluator: evaluator}).Query(context.Background(), rule, `sum(`, ts)
+		require.ErrorContains(t, err, "parse error")
+	})
+
+	t.Run("should return an error if the rule has no data source", func(t *testing.T) {
+		rule := models.CopyRule(rule)
+		rule.Data = rule.Data[1:]
+
+		_, err := NewTemplateQuerier(&fakeEvaluatorFactory{}).Query(context.Background(), rule, `up`, ts)
+		require.ErrorIs(t, err, errNoTemplateQueryDatasource)
+	})
+}
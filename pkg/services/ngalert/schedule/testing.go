@@ -2,9 +2,12 @@ package schedule
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -80,3 +83,21 @@ func (f *fakeRulesStore) DeleteRule(rules ...*models.AlertRule) {
 func (f *fakeRulesStore) getNamespaceTitle(uid string) string {
 	return "TEST-FOLDER-" + uid
 }
+
+type fakeRecordingWriter struct {
+	mtx    sync.Mutex
+	writes []data.Frames
+}
+
+func (f *fakeRecordingWriter) Write(_ context.Context, _ *models.AlertRule, _ time.Time, frames data.Frames) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.writes = append(f.writes, frames)
+	return nil
+}
+
+func (f *fakeRecordingWriter) getWrites() []data.Frames {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.writes
+}
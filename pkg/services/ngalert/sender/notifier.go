@@ -117,6 +117,9 @@ type Manager struct {
 
 	alertmanagers map[string]*alertmanagerSet
 	logger        log.Logger
+
+	// Extension: retries queues the alerts that failed to be sent to an Alertmanager and retries them.
+	retries *retrier
 }
 
 // Options are the configurable parameters of a Handler.
@@ -347,6 +350,8 @@ func (n *Manager) Send(alerts ...*Alert) {
 	// Queue capacity should be significantly larger than a single alert
 	// batch could be.
 	if d := len(alerts) - n.opts.QueueCapacity; d > 0 {
+		// Extension: queue the dropped alerts to retry sending them.
+		n.retryDropped(alerts[:d])
 		alerts = alerts[d:]
 
 		level.Warn(n.logger).Log("msg", "Alert batch larger than queue capacity, dropping alerts", "num_dropped", d)
@@ -356,6 +361,8 @@ func (n *Manager) Send(alerts ...*Alert) {
 	// If the queue is full, remove the oldest alerts in favor
 	// of newer ones.
 	if d := (len(n.queue) + len(alerts)) - n.opts.QueueCapacity; d > 0 {
+		// Extension: queue the dropped alerts to retry sending them.
+		n.retryDropped(n.queue[:d])
 		n.queue = n.queue[d:]
 
 		level.Warn(n.logger).Log("msg", "Alert notification queue full, dropping alerts", "num_dropped", d)
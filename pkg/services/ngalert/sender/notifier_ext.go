@@ -148,8 +148,16 @@ func (n *Manager) sendAll(alerts ...*Alert) bool {
 				if err := n.sendOne(ctx, client, url, payload, headers); err != nil {
 					level.Error(n.logger).Log("alertmanager", url, "count", len(alerts), "msg", "Error sending alert", "err", err)
 					n.metrics.errors.WithLabelValues(url).Inc()
+					// Extension: queue the alerts to retry sending them to this Alertmanager.
+					if n.retries != nil {
+						n.retries.failed(url, alerts, err)
+					}
 				} else {
 					numSuccess.Inc()
+					// Extension: record the successful delivery for the health of the Alertmanager.
+					if n.retries != nil {
+						n.retries.succeeded(url, alerts)
+					}
 				}
 				n.metrics.latency.WithLabelValues(url).Observe(time.Since(begin).Seconds())
 				n.metrics.sent.WithLabelValues(url).Add(float64(len(alerts)))
@@ -166,6 +174,23 @@ func (n *Manager) sendAll(alerts ...*Alert) bool {
 	return numSuccess.Load() > 0
 }
 
+// retryDropped queues the alerts dropped from the full send queue to retry sending them to each Alertmanager.
+// It must be called with n.mtx held.
+func (n *Manager) retryDropped(alerts []*Alert) {
+	if n.retries == nil || len(alerts) == 0 {
+		return
+	}
+	var urls []string
+	for _, ams := range n.alertmanagers {
+		ams.mtx.RLock()
+		for _, am := range ams.ams {
+			urls = append(urls, am.url().String())
+		}
+		ams.mtx.RUnlock()
+	}
+	n.retries.overflowed(urls, append([]*Alert(nil), alerts...))
+}
+
 // Extension: added headers parameter.
 func (n *Manager) sendOne(ctx context.Context, c *http.Client, url string, b []byte, headers map[string]string) error {
 	req, err := http.NewRequest("POST", url, bytes.NewReader(b))
@@ -0,0 +1,554 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/config"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// retryQueueKVNamespace and retryQueueKVKey locate the stored retry queues of an organization in the kvstore.
+	retryQueueKVNamespace = "ngalert.sender"
+	retryQueueKVKey       = "retry_queue"
+
+	// retryInterval is how often the retry queues are checked for alerts whose backoff has passed.
+	retryInterval = time.Second
+	// retryQueuePersistInterval is how often the retry queues are stored in the kvstore if they changed.
+	retryQueuePersistInterval = 10 * time.Second
+
+	dropReasonQueueFull   = "queue_full"
+	dropReasonMaxAttempts = "max_attempts"
+	dropReasonRemoved     = "alertmanager_removed"
+)
+
+const (
+	AlertmanagerHealthUnknown = "unknown"
+	AlertmanagerHealthUp      = "up"
+	AlertmanagerHealthDown    = "down"
+)
+
+var errAlertmanagerNotFound = errors.New("alertmanager is not discovered")
+
+// AlertmanagerStatus is the health and the backlog of an external Alertmanager.
+type AlertmanagerStatus struct {
+	URL *url.URL
+	// Health is up if the last attempt to send alerts succeeded, down if it failed, and unknown if no alerts were sent yet.
+	Health        string
+	LastError     string
+	LastErrorAt   time.Time
+	LastSuccessAt time.Time
+	// QueuedAlerts is the number of alerts waiting to be retried.
+	QueuedAlerts int
+}
+
+// retryBatch is a batch of alerts that failed to be sent to an external Alertmanager.
+type retryBatch struct {
+	Alerts []*Alert `json:"alerts"`
+	// Attempts is the number of failed retries since the alerts were queued.
+	Attempts int `json:"attempts"`
+}
+
+// retryQueue holds the alerts that failed to be sent to an external Alertmanager, and the health of the Alertmanager.
+type retryQueue struct {
+	batches []*retryBatch
+	length  int
+	// failures is the number of consecutive failed attempts to send alerts to the Alertmanager.
+	failures    int
+	nextAttempt time.Time
+
+	lastError     string
+	lastErrorAt   time.Time
+	lastSuccessAt time.Time
+}
+
+// retrier queues the alerts that failed to be sent to the external Alertmanagers of an organization, and retries
+// them with exponential backoff. The queues are bounded, and stored in the kvstore so that alerts are retried after a restart.
+type retrier struct {
+	mtx    sync.Mutex
+	orgID  int64
+	cfg    setting.UnifiedAlertingExternalAlertmanagerRetrySettings
+	queues map[string]*retryQueue
+	// configured is the URLs of the Alertmanagers in the configuration, or nil if no configuration was applied yet.
+	configured map[string]struct{}
+	// dirty tells whether the queues changed since they were last stored.
+	dirty bool
+
+	kv      *kvstore.NamespacedKVStore
+	metrics *metrics.Sender
+	logger  log.Logger
+	now     func() time.Time
+}
+
+func newRetrier(orgID int64, cfg setting.UnifiedAlertingExternalAlertmanagerRetrySettings, kv kvstore.KVStore, m *metrics.Sender, l log.Logger) *retrier {
+	r := &retrier{
+		orgID:   orgID,
+		cfg:     cfg,
+		queues:  make(map[string]*retryQueue),
+		metrics: m,
+		logger:  l,
+		now:     time.Now,
+	}
+	if kv != nil {
+		r.kv = kvstore.WithNamespace(kv, orgID, retryQueueKVNamespace)
+	}
+	return r
+}
+
+// failed records that the alerts could not be sent to the Alertmanager with the URL and queues them for retry.
+func (r *retrier) failed(url string, alerts []*Alert, err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	q := r.queue(url)
+	now := r.now()
+	q.lastError, q.lastErrorAt = err.Error(), now
+	q.failures++
+	if next := now.Add(r.backoff(q.failures)); next.After(q.nextAttempt) {
+		q.nextAttempt = next
+	}
+	r.enqueue(url, q, alerts)
+}
+
+// overflowed queues the alerts that were dropped from the full send queue of the Manager, so they are retried to each
+// of the Alertmanagers with the URLs. The health and backoff of the Alertmanagers are not changed.
+func (r *retrier) overflowed(urls []string, alerts []*Alert) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for _, url := range urls {
+		r.enqueue(url, r.queue(url), alerts)
+	}
+}
+
+// enqueue adds the alerts as a new batch to the queue of the Alertmanager with the URL. The oldest alerts are dropped
+// when the queue is over capacity.
+func (r *retrier) enqueue(url string, q *retryQueue, alerts []*Alert) {
+	if !r.cfg.Enabled || len(alerts) == 0 {
+		return
+	}
+
+	r.supersede(q, alerts)
+	q.batches = append(q.batches, &retryBatch{Alerts: alerts})
+	q.length += len(alerts)
+	r.metrics.AlertsQueued.WithLabelValues(r.org(), url).Add(float64(len(alerts)))
+	if d := q.length - r.cfg.Capacity; d > 0 {
+		r.logger.Warn("Retry queue of external Alertmanager is full, dropping the oldest alerts", "alertmanager", url, "dropped", d)
+		r.dropOldest(url, q, d)
+	}
+	r.dirty = true
+	r.metrics.RetryQueueAlerts.WithLabelValues(r.org(), url).Set(float64(q.length))
+}
+
+// succeeded records that the alerts were sent to the Alertmanager with the URL. Queued alerts with the same labels are
+// outdated and removed, and the remaining queued alerts are retried without waiting for the backoff.
+func (r *retrier) succeeded(url string, alerts []*Alert) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	q := r.queue(url)
+	q.lastSuccessAt = r.now()
+	q.failures = 0
+	q.nextAttempt = time.Time{}
+	if r.supersede(q, alerts) {
+		r.dirty = true
+		r.metrics.RetryQueueAlerts.WithLabelValues(r.org(), url).Set(float64(q.length))
+	}
+}
+
+// retry sends the queued alerts of each Alertmanager whose backoff has passed. The alerts of an Alertmanager are sent
+// one batch at a time, oldest first, and retrying stops at the first batch that fails.
+func (r *retrier) retry(send func(url string, alerts []*Alert) error) {
+	r.mtx.Lock()
+	now := r.now()
+	var due []string
+	for url, q := range r.queues {
+		if len(q.batches) > 0 && !now.Before(q.nextAttempt) {
+			due = append(due, url)
+		}
+	}
+	r.mtx.Unlock()
+
+	var wg sync.WaitGroup
+	for _, url := range due {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			for {
+				batch, alerts := r.next(url)
+				if batch == nil {
+					return
+				}
+				err := send(url, alerts)
+				r.retried(url, batch, alerts, err)
+				if err != nil {
+					r.logger.Warn("Failed to retry sending alerts to external Alertmanager", "alertmanager", url, "count", len(alerts), "error", err)
+					return
+				}
+			}
+		}(url)
+	}
+	wg.Wait()
+}
+
+// next returns the oldest batch of alerts queued for the Alertmanager with the URL and a copy of its alerts,
+// or nil if there are no alerts to retry yet.
+func (r *retrier) next(url string) (*retryBatch, []*Alert) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	q, ok := r.queues[url]
+	if !ok || len(q.batches) == 0 || r.now().Before(q.nextAttempt) {
+		return nil, nil
+	}
+	return q.batches[0], append([]*Alert(nil), q.batches[0].Alerts...)
+}
+
+// retried records the result of retrying to send the alerts of the batch.
+func (r *retrier) retried(url string, batch *retryBatch, alerts []*Alert, err error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	q := r.queue(url)
+	now := r.now()
+	r.dirty = true
+	if err == nil {
+		q.lastSuccessAt = now
+		q.failures = 0
+		q.nextAttempt = time.Time{}
+		for i, b := range q.batches {
+			if b == batch {
+				q.batches = append(q.batches[:i], q.batches[i+1:]...)
+				q.length -= len(b.Alerts)
+				break
+			}
+		}
+		r.metrics.AlertsRetried.WithLabelValues(r.org(), url).Add(float64(len(alerts)))
+		r.metrics.RetryQueueAlerts.WithLabelValues(r.org(), url).Set(float64(q.length))
+		return
+	}
+
+	q.lastError, q.lastErrorAt = err.Error(), now
+	q.failures++
+	q.nextAttempt = now.Add(r.backoff(q.failures))
+	kept := q.batches[:0]
+	for _, b := range q.batches {
+		b.Attempts++
+		if r.cfg.MaxAttempts > 0 && b.Attempts >= r.cfg.MaxAttempts {
+			q.length -= len(b.Alerts)
+			r.metrics.AlertsDropped.WithLabelValues(r.org(), url, dropReasonMaxAttempts).Add(float64(len(b.Alerts)))
+			continue
+		}
+		kept = append(kept, b)
+	}
+	q.batches = kept
+	r.metrics.RetryQueueAlerts.WithLabelValues(r.org(), url).Set(float64(q.length))
+}
+
+// status returns the health and backlog of the Alertmanager with the URL.
+func (r *retrier) status(u *url.URL) AlertmanagerStatus {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	s := AlertmanagerStatus{URL: u, Health: AlertmanagerHealthUnknown}
+	q, ok := r.queues[u.String()]
+	if !ok {
+		return s
+	}
+	s.LastError, s.LastErrorAt, s.LastSuccessAt, s.QueuedAlerts = q.lastError, q.lastErrorAt, q.lastSuccessAt, q.length
+	switch {
+	case q.lastErrorAt.After(q.lastSuccessAt):
+		s.Health = AlertmanagerHealthDown
+	case !q.lastSuccessAt.IsZero():
+		s.Health = AlertmanagerHealthUp
+	}
+	return s
+}
+
+// load restores the queues stored in the kvstore.
+func (r *retrier) load(ctx context.Context) error {
+	if r.kv == nil || !r.cfg.Enabled {
+		return nil
+	}
+	value, ok, err := r.kv.Get(ctx, retryQueueKVKey)
+	if err != nil || !ok {
+		return err
+	}
+	var stored map[string][]*retryBatch
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for url, batches := range stored {
+		if !r.isConfigured(url) {
+			r.logger.Info("Dropping the retry queue of an external Alertmanager that is no longer configured", "alertmanager", url)
+			r.dirty = true
+			continue
+		}
+		q := r.queue(url)
+		for _, b := range batches {
+			if len(b.Alerts) == 0 {
+				continue
+			}
+			q.batches = append(q.batches, b)
+			q.length += len(b.Alerts)
+		}
+		if d := q.length - r.cfg.Capacity; d > 0 {
+			r.dropOldest(url, q, d)
+		}
+		r.metrics.RetryQueueAlerts.WithLabelValues(r.org(), url).Set(float64(q.length))
+	}
+	return nil
+}
+
+// persist stores the queues in the kvstore if they changed since they were last stored.
+func (r *retrier) persist(ctx context.Context) error {
+	if r.kv == nil {
+		return nil
+	}
+	r.mtx.Lock()
+	if !r.dirty {
+		r.mtx.Unlock()
+		return nil
+	}
+	stored := make(map[string][]*retryBatch, len(r.queues))
+	for url, q := range r.queues {
+		if len(q.batches) == 0 {
+			continue
+		}
+		batches := make([]*retryBatch, 0, len(q.batches))
+		for _, b := range q.batches {
+			batches = append(batches, &retryBatch{Alerts: b.Alerts, Attempts: b.Attempts})
+		}
+		stored[url] = batches
+	}
+	r.dirty = false
+	r.mtx.Unlock()
+
+	var err error
+	if len(stored) == 0 {
+		err = r.kv.Del(ctx, retryQueueKVKey)
+	} else {
+		var b []byte
+		if b, err = json.Marshal(stored); err == nil {
+			err = r.kv.Set(ctx, retryQueueKVKey, string(b))
+		}
+	}
+	if err != nil {
+		r.mtx.Lock()
+		r.dirty = true
+		r.mtx.Unlock()
+	}
+	return err
+}
+
+// clear removes all queued alerts, including the ones stored in the kvstore.
+func (r *retrier) clear(ctx context.Context) error {
+	r.mtx.Lock()
+	for url := range r.queues {
+		r.metrics.RetryQueueAlerts.DeleteLabelValues(r.org(), url)
+	}
+	r.queues = make(map[string]*retryQueue)
+	r.dirty = false
+	r.mtx.Unlock()
+	if r.kv == nil {
+		return nil
+	}
+	return r.kv.Del(ctx, retryQueueKVKey)
+}
+
+// retain drops the queues of the Alertmanagers that are not in the URLs, as they were removed from the configuration.
+// Queues of other Alertmanagers that are loaded from the kvstore later are dropped too.
+func (r *retrier) retain(urls []string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.configured = make(map[string]struct{}, len(urls))
+	for _, url := range urls {
+		r.configured[url] = struct{}{}
+	}
+	for url, q := range r.queues {
+		if r.isConfigured(url) {
+			continue
+		}
+		if q.length > 0 {
+			r.logger.Info("Dropping the retry queue of an external Alertmanager that is no longer configured", "alertmanager", url, "dropped", q.length)
+			r.metrics.AlertsDropped.WithLabelValues(r.org(), url, dropReasonRemoved).Add(float64(q.length))
+			r.dirty = true
+		}
+		delete(r.queues, url)
+		r.metrics.RetryQueueAlerts.DeleteLabelValues(r.org(), url)
+	}
+}
+
+// isConfigured returns true if the Alertmanager with the URL is in the configuration, or if no configuration was
+// applied yet. The caller must hold the lock.
+func (r *retrier) isConfigured(url string) bool {
+	if r.configured == nil {
+		return true
+	}
+	_, ok := r.configured[url]
+	return ok
+}
+
+// queue returns the queue of the Alertmanager with the URL, creating it if it does not exist. The caller must hold the lock.
+func (r *retrier) queue(url string) *retryQueue {
+	q, ok := r.queues[url]
+	if !ok {
+		q = &retryQueue{}
+		r.queues[url] = q
+	}
+	return q
+}
+
+// supersede removes the queued alerts that have the same labels as any of the alerts, as those are more recent.
+// It returns true if any queued alerts were removed. The caller must hold the lock.
+func (r *retrier) supersede(q *retryQueue, alerts []*Alert) bool {
+	if q.length == 0 {
+		return false
+	}
+	hashes := make(map[uint64]struct{}, len(alerts))
+	for _, a := range alerts {
+		hashes[a.Hash()] = struct{}{}
+	}
+	removed := false
+	batches := q.batches[:0]
+	for _, b := range q.batches {
+		kept := make([]*Alert, 0, len(b.Alerts))
+		for _, a := range b.Alerts {
+			if _, ok := hashes[a.Hash()]; ok {
+				q.length--
+				removed = true
+				continue
+			}
+			kept = append(kept, a)
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		if len(kept) < len(b.Alerts) {
+			// Batches are shared with in-flight retries, so replace the alerts rather than change them in place.
+			b = &retryBatch{Alerts: kept, Attempts: b.Attempts}
+		}
+		batches = append(batches, b)
+	}
+	q.batches = batches
+	return removed
+}
+
+// dropOldest drops the n oldest queued alerts. The caller must hold the lock.
+func (r *retrier) dropOldest(url string, q *retryQueue, n int) {
+	r.metrics.AlertsDropped.WithLabelValues(r.org(), url, dropReasonQueueFull).Add(float64(n))
+	q.length -= n
+	for n > 0 && len(q.batches) > 0 {
+		b := q.batches[0]
+		if len(b.Alerts) > n {
+			q.batches[0] = &retryBatch{Alerts: b.Alerts[n:], Attempts: b.Attempts}
+			return
+		}
+		n -= len(b.Alerts)
+		q.batches = q.batches[1:]
+	}
+}
+
+// backoff returns the time to wait after the given number of consecutive failures.
+func (r *retrier) backoff(failures int) time.Duration {
+	d := r.cfg.InitialBackoff
+	for i := 1; i < failures && d < r.cfg.MaxBackoff; i++ {
+		d *= 2
+	}
+	if r.cfg.MaxBackoff > 0 && d > r.cfg.MaxBackoff {
+		d = r.cfg.MaxBackoff
+	}
+	return d
+}
+
+func (r *retrier) org() string {
+	return strconv.FormatInt(r.orgID, 10)
+}
+
+// runRetries retries the queued alerts until the manager is stopped, and stores the queues in the kvstore.
+func (n *Manager) runRetries() {
+	if err := n.retries.load(n.ctx); err != nil {
+		n.retries.logger.Error("Failed to load the retry queues of the external Alertmanagers", "error", err)
+	}
+
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	lastPersist := time.Now()
+	for {
+		select {
+		case <-n.ctx.Done():
+			// The manager context is canceled, so the queues are stored with a new one.
+			ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+			if err := n.retries.persist(ctx); err != nil {
+				n.retries.logger.Error("Failed to store the retry queues of the external Alertmanagers", "error", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			n.retries.retry(n.sendQueued)
+			if time.Since(lastPersist) >= retryQueuePersistInterval {
+				if err := n.retries.persist(n.ctx); err != nil {
+					n.retries.logger.Error("Failed to store the retry queues of the external Alertmanagers", "error", err)
+				}
+				lastPersist = time.Now()
+			}
+		}
+	}
+}
+
+// sendQueued sends the queued alerts to the Alertmanager with the URL, using the client and headers of its Alertmanager set.
+func (n *Manager) sendQueued(url string, alerts []*Alert) error {
+	n.mtx.RLock()
+	amSets := n.alertmanagers
+	n.mtx.RUnlock()
+
+	for _, ams := range amSets {
+		ams.mtx.RLock()
+		found := false
+		for _, am := range ams.ams {
+			if am.url().String() == url {
+				found = true
+				break
+			}
+		}
+		client, headers, cfg := ams.client, ams.headers, ams.cfg
+		ams.mtx.RUnlock()
+		if !found {
+			continue
+		}
+
+		var (
+			payload []byte
+			err     error
+		)
+		switch cfg.APIVersion {
+		case config.AlertmanagerAPIVersionV1:
+			payload, err = json.Marshal(alerts)
+		case config.AlertmanagerAPIVersionV2:
+			payload, err = json.Marshal(alertsToOpenAPIAlerts(alerts))
+		default:
+			err = fmt.Errorf("invalid Alertmanager API version '%v'", cfg.APIVersion)
+		}
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(n.ctx, time.Duration(cfg.Timeout))
+		defer cancel()
+		return n.sendOne(ctx, client, url, payload, headers)
+	}
+	return errAlertmanagerNotFound
+}
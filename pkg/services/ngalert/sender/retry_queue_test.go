@@ -0,0 +1,223 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testAlertmanagerURL = "http://localhost:9093/api/v2/alerts"
+
+func TestRetrier(t *testing.T) {
+	cfg := setting.UnifiedAlertingExternalAlertmanagerRetrySettings{
+		Enabled:        true,
+		Capacity:       3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     4 * time.Second,
+		MaxAttempts:    2,
+	}
+	errSend := errors.New("connection refused")
+
+	setup := func(t *testing.T, cfg setting.UnifiedAlertingExternalAlertmanagerRetrySettings) (*retrier, *metrics.Sender, *time.Time) {
+		t.Helper()
+		m := metrics.NewSenderMetrics(prometheus.NewRegistry())
+		r := newRetrier(1, cfg, kvstore.NewFakeKVStore(), m, &logtest.Fake{})
+		now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		r.now = func() time.Time { return now }
+		return r, m, &now
+	}
+
+	t.Run("failed alerts are queued and retried after the backoff", func(t *testing.T) {
+		r, m, now := setup(t, cfg)
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+
+		var sent []*Alert
+		send := func(_ string, alerts []*Alert) error {
+			sent = append(sent, alerts...)
+			return nil
+		}
+		r.retry(send)
+		require.Empty(t, sent)
+
+		*now = now.Add(time.Second)
+		r.retry(send)
+		require.Equal(t, []*Alert{testAlert("a")}, sent)
+		require.Empty(t, r.queues[testAlertmanagerURL].batches)
+		require.Equal(t, 1.0, testutil.ToFloat64(m.AlertsRetried.WithLabelValues("1", testAlertmanagerURL)))
+		require.Equal(t, 0.0, testutil.ToFloat64(m.RetryQueueAlerts.WithLabelValues("1", testAlertmanagerURL)))
+	})
+
+	t.Run("alerts are not queued if retries are disabled", func(t *testing.T) {
+		disabled := cfg
+		disabled.Enabled = false
+		r, _, _ := setup(t, disabled)
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+		require.Empty(t, r.queues[testAlertmanagerURL].batches)
+		require.Equal(t, AlertmanagerHealthDown, r.status(mustParseURL(t, testAlertmanagerURL)).Health)
+	})
+
+	t.Run("newer alerts with the same labels supersede queued alerts", func(t *testing.T) {
+		r, _, _ := setup(t, cfg)
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a"), testAlert("b")}, errSend)
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+		q := r.queues[testAlertmanagerURL]
+		require.Len(t, q.batches, 2)
+		require.Equal(t, 2, q.length)
+
+		r.succeeded(testAlertmanagerURL, []*Alert{testAlert("b")})
+		require.Len(t, q.batches, 1)
+		require.Equal(t, 1, q.length)
+	})
+
+	t.Run("oldest alerts are dropped when the queue is full", func(t *testing.T) {
+		r, m, _ := setup(t, cfg)
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a"), testAlert("b")}, errSend)
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("c"), testAlert("d")}, errSend)
+		q := r.queues[testAlertmanagerURL]
+		require.Equal(t, 3, q.length)
+		require.Equal(t, []*Alert{testAlert("b")}, q.batches[0].Alerts)
+		require.Equal(t, 1.0, testutil.ToFloat64(m.AlertsDropped.WithLabelValues("1", testAlertmanagerURL, dropReasonQueueFull)))
+	})
+
+	t.Run("alerts dropped from the full send queue are queued without backoff", func(t *testing.T) {
+		r, m, _ := setup(t, cfg)
+		const otherURL = "http://localhost:9094/api/v2/alerts"
+		r.overflowed([]string{testAlertmanagerURL, otherURL}, []*Alert{testAlert("a")})
+
+		var sent []string
+		r.retry(func(url string, _ []*Alert) error {
+			sent = append(sent, url)
+			return nil
+		})
+		require.ElementsMatch(t, []string{testAlertmanagerURL, otherURL}, sent)
+		require.Equal(t, AlertmanagerHealthUp, r.status(mustParseURL(t, testAlertmanagerURL)).Health)
+		require.Equal(t, 1.0, testutil.ToFloat64(m.AlertsQueued.WithLabelValues("1", otherURL)))
+	})
+
+	t.Run("alerts are dropped after the maximum number of attempts", func(t *testing.T) {
+		r, m, now := setup(t, cfg)
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+		send := func(string, []*Alert) error { return errSend }
+
+		*now = now.Add(time.Second)
+		r.retry(send)
+		require.Len(t, r.queues[testAlertmanagerURL].batches, 1)
+
+		*now = now.Add(4 * time.Second)
+		r.retry(send)
+		require.Empty(t, r.queues[testAlertmanagerURL].batches)
+		require.Equal(t, 1.0, testutil.ToFloat64(m.AlertsDropped.WithLabelValues("1", testAlertmanagerURL, dropReasonMaxAttempts)))
+	})
+
+	t.Run("backoff grows exponentially up to the maximum", func(t *testing.T) {
+		r, _, _ := setup(t, cfg)
+		require.Equal(t, time.Second, r.backoff(1))
+		require.Equal(t, 2*time.Second, r.backoff(2))
+		require.Equal(t, 4*time.Second, r.backoff(3))
+		require.Equal(t, 4*time.Second, r.backoff(10))
+	})
+
+	t.Run("status reports the health of the Alertmanager", func(t *testing.T) {
+		r, _, now := setup(t, cfg)
+		u := mustParseURL(t, testAlertmanagerURL)
+		require.Equal(t, AlertmanagerStatus{URL: u, Health: AlertmanagerHealthUnknown}, r.status(u))
+
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+		s := r.status(u)
+		require.Equal(t, AlertmanagerHealthDown, s.Health)
+		require.Equal(t, errSend.Error(), s.LastError)
+		require.Equal(t, 1, s.QueuedAlerts)
+
+		*now = now.Add(time.Second)
+		r.succeeded(testAlertmanagerURL, []*Alert{testAlert("b")})
+		s = r.status(u)
+		require.Equal(t, AlertmanagerHealthUp, s.Health)
+		require.Equal(t, *now, s.LastSuccessAt)
+	})
+
+	t.Run("queues are restored from the kvstore", func(t *testing.T) {
+		kv := kvstore.NewFakeKVStore()
+		m := metrics.NewSenderMetrics(prometheus.NewRegistry())
+		r := newRetrier(1, cfg, kv, m, &logtest.Fake{})
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+		require.NoError(t, r.persist(context.Background()))
+
+		restored := newRetrier(1, cfg, kv, m, &logtest.Fake{})
+		require.NoError(t, restored.load(context.Background()))
+		require.Equal(t, 1, restored.queues[testAlertmanagerURL].length)
+		require.Equal(t, testAlert("a").Labels, restored.queues[testAlertmanagerURL].batches[0].Alerts[0].Labels)
+
+		require.NoError(t, restored.clear(context.Background()))
+		_, ok, err := kv.Get(context.Background(), 1, retryQueueKVNamespace, retryQueueKVKey)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("queues of removed Alertmanagers are dropped", func(t *testing.T) {
+		const otherURL = "http://localhost:9094/api/v2/alerts"
+		kv := kvstore.NewFakeKVStore()
+		m := metrics.NewSenderMetrics(prometheus.NewRegistry())
+		r := newRetrier(1, cfg, kv, m, &logtest.Fake{})
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+		r.failed(otherURL, []*Alert{testAlert("a"), testAlert("b")}, errSend)
+		require.NoError(t, r.persist(context.Background()))
+
+		r.retain([]string{testAlertmanagerURL})
+		require.Contains(t, r.queues, testAlertmanagerURL)
+		require.NotContains(t, r.queues, otherURL)
+		require.Equal(t, 2.0, testutil.ToFloat64(m.AlertsDropped.WithLabelValues("1", otherURL, dropReasonRemoved)))
+
+		require.NoError(t, r.persist(context.Background()))
+		restored := newRetrier(1, cfg, kv, m, &logtest.Fake{})
+		require.NoError(t, restored.load(context.Background()))
+		require.Contains(t, restored.queues, testAlertmanagerURL)
+		require.NotContains(t, restored.queues, otherURL)
+
+		r.retain(nil)
+		require.Empty(t, r.queues)
+		require.NoError(t, r.persist(context.Background()))
+		_, ok, err := kv.Get(context.Background(), 1, retryQueueKVNamespace, retryQueueKVKey)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("stored queues of removed Alertmanagers are not restored", func(t *testing.T) {
+		kv := kvstore.NewFakeKVStore()
+		m := metrics.NewSenderMetrics(prometheus.NewRegistry())
+		r := newRetrier(1, cfg, kv, m, &logtest.Fake{})
+		r.failed(testAlertmanagerURL, []*Alert{testAlert("a")}, errSend)
+		require.NoError(t, r.persist(context.Background()))
+
+		restored := newRetrier(1, cfg, kv, m, &logtest.Fake{})
+		restored.retain(nil)
+		require.NoError(t, restored.load(context.Background()))
+		require.Empty(t, restored.queues)
+		require.NoError(t, restored.persist(context.Background()))
+		_, ok, err := kv.Get(context.Background(), 1, retryQueueKVNamespace, retryQueueKVKey)
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func testAlert(name string) *Alert {
+	return &Alert{Labels: labels.FromStrings(labels.AlertName, name)}
+}
+
+func mustParseURL(t *testing.T, s string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(s)
+	require.NoError(t, err)
+	return u
+}
@@ -13,13 +13,16 @@ import (
 	"github.com/benbjohnson/clock"
 
 	"github.com/grafana/grafana/pkg/api/datasource"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 // AlertsRouter handles alerts generated during alert rule evaluation.
@@ -47,11 +50,17 @@ type AlertsRouter struct {
 
 	datasourceService datasources.DataSourceService
 	secretService     secrets.Service
+
+	// retryCfg, kvStore and metrics are used by the senders to retry the alerts that failed to be sent.
+	retryCfg setting.UnifiedAlertingExternalAlertmanagerRetrySettings
+	kvStore  kvstore.KVStore
+	metrics  *metrics.Sender
 }
 
 func NewAlertsRouter(multiOrgNotifier *notifier.MultiOrgAlertmanager, store store.AdminConfigurationStore,
 	clk clock.Clock, appURL *url.URL, disabledOrgs map[int64]struct{}, configPollInterval time.Duration,
-	datasourceService datasources.DataSourceService, secretService secrets.Service) *AlertsRouter {
+	datasourceService datasources.DataSourceService, secretService secrets.Service,
+	retryCfg setting.UnifiedAlertingExternalAlertmanagerRetrySettings, kvStore kvstore.KVStore, m *metrics.Sender) *AlertsRouter {
 	d := &AlertsRouter{
 		logger:           log.New("ngalert.sender.router"),
 		clock:            clk,
@@ -70,6 +79,10 @@ func NewAlertsRouter(multiOrgNotifier *notifier.MultiOrgAlertmanager, store stor
 
 		datasourceService: datasourceService,
 		secretService:     secretService,
+
+		retryCfg: retryCfg,
+		kvStore:  kvStore,
+		metrics:  m,
 	}
 	return d
 }
@@ -152,7 +165,7 @@ func (d *AlertsRouter) SyncAndApplyConfigFromDatabase() error {
 
 		// No sender and have Alertmanager(s) to send to - start a new one.
 		d.logger.Info("Creating new sender for the external alertmanagers", "org", cfg.OrgID, "alertmanagers", redactedAMs)
-		s := NewExternalAlertmanagerSender()
+		s := NewExternalAlertmanagerSender(cfg.OrgID, d.retryCfg, d.kvStore, d.metrics)
 		d.externalAlertmanagers[cfg.OrgID] = s
 		s.Run()
 
@@ -180,6 +193,10 @@ func (d *AlertsRouter) SyncAndApplyConfigFromDatabase() error {
 	for orgID, s := range sendersToStop {
 		d.logger.Info("Stopping sender", "org", orgID)
 		s.Stop()
+		// The organization no longer sends alerts to these Alertmanagers, so there is nothing to retry.
+		if err := s.ClearRetryQueue(context.Background()); err != nil {
+			d.logger.Error("Failed to clear the retry queue of the sender", "org", orgID, "error", err)
+		}
 		d.logger.Info("Stopped sender", "org", orgID)
 	}
 
@@ -355,6 +372,18 @@ func (d *AlertsRouter) DroppedAlertmanagersFor(orgID int64) []*url.URL {
 	return s.DroppedAlertmanagers()
 }
 
+// AlertmanagerStatusesFor returns the health and the number of alerts waiting to be retried of all the discovered
+// Alertmanager(s) for a particular organization.
+func (d *AlertsRouter) AlertmanagerStatusesFor(orgID int64) []AlertmanagerStatus {
+	d.adminConfigMtx.RLock()
+	defer d.adminConfigMtx.RUnlock()
+	s, ok := d.externalAlertmanagers[orgID]
+	if !ok {
+		return []AlertmanagerStatus{}
+	}
+	return s.AlertmanagerStatuses()
+}
+
 // Run starts regular updates of the configuration.
 func (d *AlertsRouter) Run(ctx context.Context) error {
 	for {
@@ -20,8 +20,11 @@ import (
 	"github.com/prometheus/prometheus/discovery"
 	"github.com/prometheus/prometheus/model/labels"
 
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 const (
@@ -68,7 +71,7 @@ func (cfg *externalAMcfg) headerString() string {
 	return result.String()
 }
 
-func NewExternalAlertmanagerSender() *ExternalAlertmanager {
+func NewExternalAlertmanagerSender(orgID int64, retryCfg setting.UnifiedAlertingExternalAlertmanagerRetrySettings, kvStore kvstore.KVStore, m *metrics.Sender) *ExternalAlertmanager {
 	l := log.New("ngalert.sender.external-alertmanager")
 	sdCtx, sdCancel := context.WithCancel(context.Background())
 	s := &ExternalAlertmanager{
@@ -82,6 +85,7 @@ func NewExternalAlertmanagerSender() *ExternalAlertmanager {
 		&Options{QueueCapacity: defaultMaxQueueCapacity, Registerer: prometheus.NewRegistry()},
 		s.logger,
 	)
+	s.manager.retries = newRetrier(orgID, retryCfg, kvStore, m, s.logger)
 
 	s.sdManager = discovery.NewManager(sdCtx, s.logger)
 
@@ -101,6 +105,8 @@ func (s *ExternalAlertmanager) ApplyConfig(orgId, id int64, alertmanagers []exte
 	if err := s.manager.ApplyConfig(notifierCfg, headers); err != nil {
 		return err
 	}
+	// Drop the alerts waiting to be retried to the Alertmanagers that were removed from the configuration.
+	s.manager.retries.retain(alertmanagerURLs(notifierCfg))
 
 	sdCfgs := make(map[string]discovery.Configs)
 	for k, v := range notifierCfg.AlertingConfig.AlertmanagerConfigs.ToMap() {
@@ -111,7 +117,7 @@ func (s *ExternalAlertmanager) ApplyConfig(orgId, id int64, alertmanagers []exte
 }
 
 func (s *ExternalAlertmanager) Run() {
-	s.wg.Add(2)
+	s.wg.Add(3)
 
 	go func() {
 		s.logger.Info("Initiating communication with a group of external Alertmanagers")
@@ -126,6 +132,11 @@ func (s *ExternalAlertmanager) Run() {
 		s.manager.Run(s.sdManager.SyncCh())
 		s.wg.Done()
 	}()
+
+	go func() {
+		s.manager.runRetries()
+		s.wg.Done()
+	}()
 }
 
 // SendAlerts sends a set of alerts to the configured Alertmanager(s).
@@ -155,11 +166,47 @@ func (s *ExternalAlertmanager) Alertmanagers() []*url.URL {
 	return s.manager.Alertmanagers()
 }
 
+// AlertmanagerStatuses returns the health and the number of alerts waiting to be retried of the discovered Alertmanager(s).
+func (s *ExternalAlertmanager) AlertmanagerStatuses() []AlertmanagerStatus {
+	ams := s.manager.Alertmanagers()
+	statuses := make([]AlertmanagerStatus, 0, len(ams))
+	for _, u := range ams {
+		statuses = append(statuses, s.manager.retries.status(u))
+	}
+	return statuses
+}
+
+// ClearRetryQueue removes the alerts waiting to be retried, including the ones stored in the database.
+// It is used when the organization no longer sends alerts to external Alertmanagers.
+func (s *ExternalAlertmanager) ClearRetryQueue(ctx context.Context) error {
+	return s.manager.retries.clear(ctx)
+}
+
 // DroppedAlertmanagers returns a list of Alertmanager(s) we no longer send alerts to.
 func (s *ExternalAlertmanager) DroppedAlertmanagers() []*url.URL {
 	return s.manager.DroppedAlertmanagers()
 }
 
+// alertmanagerURLs returns the URLs the alerts are sent to for the static configuration of the Alertmanagers.
+func alertmanagerURLs(cfg *config.Config) []string {
+	var urls []string
+	for _, amCfg := range cfg.AlertingConfig.AlertmanagerConfigs {
+		for _, sdCfg := range amCfg.ServiceDiscoveryConfigs {
+			static, ok := sdCfg.(discovery.StaticConfig)
+			if !ok {
+				continue
+			}
+			for _, tg := range static {
+				for _, target := range tg.Targets {
+					u := url.URL{Scheme: amCfg.Scheme, Host: string(target[model.AddressLabel]), Path: postPath(amCfg.PathPrefix, amCfg.APIVersion)}
+					urls = append(urls, u.String())
+				}
+			}
+		}
+	}
+	return urls
+}
+
 func buildNotifierConfig(alertmanagers []externalAMcfg) (*config.Config, map[string]map[string]string, error) {
 	amConfigs := make([]*config.AlertmanagerConfig, 0, len(alertmanagers))
 	headers := map[string]map[string]string{}
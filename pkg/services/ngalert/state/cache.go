@@ -33,11 +33,11 @@ func newCache() *cache {
 	}
 }
 
-func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) *State {
+func (c *cache) getOrCreate(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL, queryFunc template.QueryFunc) *State {
 	// Calculation of state ID involves label and annotation expansion, which may be resource intensive operations, and doing it in the context guarded by mtxStates may create a lot of contention.
 	// Instead of just calculating ID we create an entire state - a candidate. If rule states already hold a state with this ID, this candidate will be discarded and the existing one will be returned.
 	// Otherwise, this candidate will be added to the rule states and returned.
-	stateCandidate := calculateState(ctx, log, alertRule, result, extraLabels, externalURL)
+	stateCandidate := calculateState(ctx, log, alertRule, result, extraLabels, externalURL, queryFunc)
 
 	c.mtxStates.Lock()
 	defer c.mtxStates.Unlock()
@@ -77,19 +77,20 @@ func (rs *ruleStates) getOrAdd(stateCandidate State) *State {
 	}
 	state.Annotations = stateCandidate.Annotations
 	state.Values = stateCandidate.Values
+	state.ResultFingerprint = stateCandidate.ResultFingerprint
 	rs.states[stateCandidate.CacheID] = state
 	return state
 }
 
-func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL) State {
+func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, externalURL *url.URL, queryFunc template.QueryFunc) State {
 	// Merge both the extra labels and the labels from the evaluation into a common set
 	// of labels that can be expanded in custom labels and annotations.
 	templateData := template.NewData(mergeLabels(extraLabels, result.Instance), result)
 
 	// For now, do nothing with these errors as they are already logged in expand.
 	// In the future, we want to show these errors to the user somehow.
-	labels, _ := expand(ctx, log, alertRule.Title, alertRule.Labels, templateData, externalURL, result.EvaluatedAt)
-	annotations, _ := expand(ctx, log, alertRule.Title, alertRule.Annotations, templateData, externalURL, result.EvaluatedAt)
+	labels, _ := expand(ctx, log, alertRule.Title, alertRule.Labels, templateData, externalURL, result.EvaluatedAt, queryFunc)
+	annotations, _ := expand(ctx, log, alertRule.Title, alertRule.Annotations, templateData, externalURL, result.EvaluatedAt, queryFunc)
 
 	values := make(map[string]float64)
 	for refID, v := range result.Values {
@@ -151,6 +152,7 @@ func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.Ale
 		Values:             values,
 		StartsAt:           result.EvaluatedAt,
 		EndsAt:             result.EvaluatedAt,
+		ResultFingerprint:  result.Instance.Fingerprint(),
 	}
 	return newState
 }
@@ -159,13 +161,13 @@ func calculateState(ctx context.Context, log log.Logger, alertRule *ngModels.Ale
 // If a template cannot be expanded due to an error in the template the original template is
 // maintained and an error is added to the multierror. All errors in the multierror are
 // template.ExpandError errors.
-func expand(ctx context.Context, log log.Logger, name string, original map[string]string, data template.Data, externalURL *url.URL, evaluatedAt time.Time) (map[string]string, error) {
+func expand(ctx context.Context, log log.Logger, name string, original map[string]string, data template.Data, externalURL *url.URL, evaluatedAt time.Time, queryFunc template.QueryFunc) (map[string]string, error) {
 	var (
 		errs     error
 		expanded = make(map[string]string, len(original))
 	)
 	for k, v := range original {
-		result, err := template.Expand(ctx, name, v, data, externalURL, evaluatedAt)
+		result, err := template.Expand(ctx, name, v, data, externalURL, evaluatedAt, queryFunc)
 		if err != nil {
 			log.Error("Error in expanding template", "error", err)
 			errs = errors.Join(errs, err)
@@ -297,11 +299,12 @@ func (c *cache) recordMetrics(metrics *metrics.State) {
 	// Set default values to zero such that gauges are reset
 	// after all values from a single state disappear.
 	ct := map[eval.State]int{
-		eval.Normal:   0,
-		eval.Alerting: 0,
-		eval.Pending:  0,
-		eval.NoData:   0,
-		eval.Error:    0,
+		eval.Normal:     0,
+		eval.Alerting:   0,
+		eval.Pending:    0,
+		eval.NoData:     0,
+		eval.Error:      0,
+		eval.Recovering: 0,
 	}
 
 	for _, orgMap := range c.states {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -27,7 +28,7 @@ func Test_expand(t *testing.T) {
 	// If the expand function forgets to use ErrorOrNil() then the error returned will
 	// be non-nil even if no errors have been added to the multierror.
 	t.Run("err is nil if there are no errors", func(t *testing.T) {
-		result, err := expand(ctx, logger, "test", map[string]string{}, template.Data{}, nil, time.Now())
+		result, err := expand(ctx, logger, "test", map[string]string{}, template.Data{}, nil, time.Now(), nil)
 		require.NoError(t, err)
 		require.Len(t, result, 0)
 	})
@@ -36,7 +37,18 @@ func Test_expand(t *testing.T) {
 		original := map[string]string{"Summary": `Instance {{ $labels.instance }} has been down for more than 5 minutes`}
 		expected := map[string]string{"Summary": "Instance host1 has been down for more than 5 minutes"}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, results)
+	})
+
+	t.Run("original is expanded with the results of queries", func(t *testing.T) {
+		original := map[string]string{"Summary": `{{ query "up" | first | value | humanize }} instances are up`}
+		expected := map[string]string{"Summary": "1.5k instances are up"}
+		queryFunc := func(_ context.Context, q string, ts time.Time) (promql.Vector, error) {
+			return promql.Vector{{Point: promql.Point{T: ts.UnixMilli(), V: 1500}}}, nil
+		}
+		results, err := expand(ctx, logger, "test", original, template.Data{}, nil, time.Now(), queryFunc)
 		require.NoError(t, err)
 		require.Equal(t, expected, results)
 	})
@@ -46,7 +58,7 @@ func Test_expand(t *testing.T) {
 			"Summary": `Instance {{ $labels. }} has been down for more than 5 minutes`,
 		}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
 		require.NotNil(t, err)
 		require.Equal(t, original, results)
 
@@ -62,7 +74,7 @@ func Test_expand(t *testing.T) {
 			"Description": "The instance has been down for {{ $value minutes, please check the instance is online",
 		}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
 		require.NotNil(t, err)
 		require.Equal(t, original, results)
 
@@ -99,7 +111,7 @@ func Test_expand(t *testing.T) {
 			"Description": "The instance has been down for {{ $value minutes, please check the instance is online",
 		}
 		data := template.Data{Labels: map[string]string{"instance": "host1"}}
-		results, err := expand(ctx, logger, "test", original, data, nil, time.Now())
+		results, err := expand(ctx, logger, "test", original, data, nil, time.Now(), nil)
 		require.NotNil(t, err)
 		require.Equal(t, expected, results)
 
@@ -134,7 +146,7 @@ func Test_getOrCreate(t *testing.T) {
 		result := eval.Result{
 			Instance: models.GenerateAlertLabels(5, "result-"),
 		}
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			require.Equal(t, expected, state.Labels[key])
 		}
@@ -162,7 +174,7 @@ func Test_getOrCreate(t *testing.T) {
 			result.Instance[key] = "result-" + util.GenerateShortUID()
 		}
 
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			require.Equal(t, expected, state.Labels[key])
 		}
@@ -178,7 +190,7 @@ func Test_getOrCreate(t *testing.T) {
 		for key := range rule.Labels {
 			result.Instance[key] = "result-" + util.GenerateShortUID()
 		}
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range rule.Labels {
 			require.Equal(t, expected, state.Labels[key])
 		}
@@ -200,7 +212,7 @@ func Test_getOrCreate(t *testing.T) {
 		}
 		rule.Labels = labelTemplates
 
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			assert.Equal(t, expected, state.Labels["rule-"+key])
 		}
@@ -227,7 +239,7 @@ func Test_getOrCreate(t *testing.T) {
 		}
 		rule.Annotations = annotationTemplates
 
-		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, extraLabels, url, nil)
 		for key, expected := range extraLabels {
 			assert.Equal(t, expected, state.Annotations["rule-"+key])
 		}
@@ -246,7 +258,7 @@ func Test_getOrCreate(t *testing.T) {
 		}
 		rule := generateRule()
 
-		state := c.getOrCreate(context.Background(), l, rule, result, nil, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, nil, url, nil)
 		assert.Equal(t, map[string]float64{"A": 1, "B": 2}, state.Values)
 	})
 
@@ -260,7 +272,7 @@ func Test_getOrCreate(t *testing.T) {
 		}
 		rule := generateRule()
 
-		state := c.getOrCreate(context.Background(), l, rule, result, nil, url)
+		state := c.getOrCreate(context.Background(), l, rule, result, nil, url, nil)
 		assert.Equal(t, map[string]float64{"B0": 1, "B1": 2}, state.Values)
 	})
 }
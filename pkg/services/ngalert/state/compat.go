@@ -151,7 +151,7 @@ func FromStateTransitionToPostableAlerts(firingStates []StateTransition, stateMa
 	return alerts
 }
 
-// FromAlertsStateToStoppedAlert selects only transitions from firing states (states eval.Alerting, eval.NoData, eval.Error)
+// FromAlertsStateToStoppedAlert selects only transitions from firing states (states eval.Alerting, eval.Recovering, eval.NoData, eval.Error)
 // and converts them to models.PostableAlert with EndsAt set to time.Now
 func FromAlertsStateToStoppedAlert(firingStates []StateTransition, appURL *url.URL, clock clock.Clock) apimodels.PostableAlerts {
 	alerts := apimodels.PostableAlerts{PostableAlerts: make([]models.PostableAlert, 0, len(firingStates))}
@@ -0,0 +1,227 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// defaultSQLQueryLimit is the maximum number of entries returned by a query that does not specify a limit.
+const defaultSQLQueryLimit = 1000
+
+// SQLStore is the storage of the SQL state history backend.
+type SQLStore interface {
+	SaveStateHistory(ctx context.Context, entries []*models.StateHistoryEntry) error
+	FindStateHistory(ctx context.Context, query models.HistoryQuery) ([]*models.StateHistoryEntry, error)
+}
+
+// SQLBackend is a state.Historian that records state history to dedicated tables in the Grafana database.
+type SQLBackend struct {
+	store   SQLStore
+	clock   clock.Clock
+	metrics *metrics.Historian
+	log     log.Logger
+}
+
+func NewSQLBackend(store SQLStore, metrics *metrics.Historian) *SQLBackend {
+	return &SQLBackend{
+		store:   store,
+		clock:   clock.New(),
+		metrics: metrics,
+		log:     log.New("ngalert.state.historian", "backend", "sql"),
+	}
+}
+
+// Record writes a number of state transitions for a given rule to the database.
+func (h *SQLBackend) Record(ctx context.Context, rule history_model.RuleMeta, states []state.StateTransition) <-chan error {
+	logger := h.log.FromContext(ctx)
+	entries := statesToEntries(rule, states, logger)
+
+	errCh := make(chan error, 1)
+	if len(entries) == 0 {
+		close(errCh)
+		return errCh
+	}
+
+	// This is a new background job, so let's create a brand new context for it.
+	// We want it to be isolated, i.e. we don't want grafana shutdowns to interrupt this work
+	// immediately but rather try to flush writes.
+	// This also prevents timeouts or other lingering objects (like transactions) from being
+	// incorrectly propagated here from other areas.
+	writeCtx := context.Background()
+	writeCtx, cancel := context.WithTimeout(writeCtx, StateHistoryWriteTimeout)
+	writeCtx = history_model.WithRuleData(writeCtx, rule)
+	writeCtx = tracing.ContextWithSpan(writeCtx, tracing.SpanFromContext(ctx))
+
+	go func(ctx context.Context) {
+		defer cancel()
+		defer close(errCh)
+		logger := h.log.FromContext(ctx)
+
+		org := fmt.Sprint(rule.OrgID)
+		h.metrics.WritesTotal.WithLabelValues(org, "sql").Inc()
+		h.metrics.TransitionsTotal.WithLabelValues(org).Add(float64(len(entries)))
+
+		if err := h.store.SaveStateHistory(ctx, entries); err != nil {
+			logger.Error("Failed to save alert state history batch", "error", err)
+			h.metrics.WritesFailed.WithLabelValues(org, "sql").Inc()
+			h.metrics.TransitionsFailed.WithLabelValues(org).Add(float64(len(entries)))
+			errCh <- fmt.Errorf("failed to save alert state history batch: %w", err)
+			return
+		}
+		logger.Debug("Done saving alert state history batch")
+	}(writeCtx)
+	return errCh
+}
+
+// Query retrieves state history entries from the database and formats the results into a dataframe.
+// The dataframe has the same format as the one returned by the Loki backend.
+func (h *SQLBackend) Query(ctx context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	now := h.clock.Now().UTC()
+	if query.To.IsZero() {
+		query.To = now
+	}
+	if query.From.IsZero() {
+		query.From = now.Add(-defaultQueryRange)
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultSQLQueryLimit
+	}
+
+	entries, err := h.store.FindStateHistory(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return entriesToFrame(entries)
+}
+
+func statesToEntries(rule history_model.RuleMeta, states []state.StateTransition, logger log.Logger) []*models.StateHistoryEntry {
+	entries := make([]*models.StateHistoryEntry, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		values, err := json.Marshal(valuesAsDataBlob(state.State))
+		if err != nil {
+			logger.Error("Failed to serialize values of state, skipping", "error", err)
+			continue
+		}
+		sanitizedLabels := removePrivateLabels(state.Labels)
+		entry := &models.StateHistoryEntry{
+			OrgID:         rule.OrgID,
+			RuleUID:       rule.UID,
+			RuleGroup:     rule.Group,
+			FolderUID:     rule.NamespaceUID,
+			DashboardUID:  rule.DashboardUID,
+			PanelID:       rule.PanelID,
+			Fingerprint:   labelFingerprint(sanitizedLabels),
+			Labels:        sanitizedLabels,
+			PreviousState: state.PreviousFormatted(),
+			CurrentState:  state.Formatted(),
+			Values:        string(values),
+			Condition:     rule.Condition,
+			TimestampNano: state.State.LastEvaluationTime.UnixNano(),
+		}
+		if state.State.State == eval.Error {
+			entry.ErrorMessage = state.Error.Error()
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// entriesToFrame formats the entries in the same way as the Loki backend formats the log lines. See merge.
+func entriesToFrame(entries []*models.StateHistoryEntry) (*data.Frame, error) {
+	frame := data.NewFrame("states")
+	lbls := data.Labels(map[string]string{})
+
+	times := make([]time.Time, 0, len(entries))
+	lines := make([]json.RawMessage, 0, len(entries))
+	labels := make([]json.RawMessage, 0, len(entries))
+	for _, e := range entries {
+		var values *simplejson.Json
+		if e.Values != "" {
+			v, err := simplejson.NewJson([]byte(e.Values))
+			if err != nil {
+				return nil, fmt.Errorf("failed to deserialize values of entry %d: %w", e.ID, err)
+			}
+			values = v
+		}
+		line, err := json.Marshal(lokiEntry{
+			SchemaVersion:  1,
+			Previous:       e.PreviousState,
+			Current:        e.CurrentState,
+			Error:          e.ErrorMessage,
+			Values:         values,
+			Condition:      e.Condition,
+			DashboardUID:   e.DashboardUID,
+			PanelID:        e.PanelID,
+			Fingerprint:    e.Fingerprint,
+			RuleUID:        e.RuleUID,
+			InstanceLabels: e.Labels,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize entry %d: %w", e.ID, err)
+		}
+		streamLbls, err := json.Marshal(map[string]string{
+			StateHistoryLabelKey: StateHistoryLabelValue,
+			OrgIDLabel:           fmt.Sprint(e.OrgID),
+			GroupLabel:           e.RuleGroup,
+			FolderUIDLabel:       e.FolderUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize labels of entry %d: %w", e.ID, err)
+		}
+
+		times = append(times, time.Unix(0, e.TimestampNano))
+		lines = append(lines, line)
+		labels = append(labels, streamLbls)
+	}
+
+	frame.Fields = append(frame.Fields, data.NewField(dfTime, lbls, times))
+	frame.Fields = append(frame.Fields, data.NewField(dfLine, lbls, lines))
+	frame.Fields = append(frame.Fields, data.NewField(dfLabels, lbls, labels))
+
+	return frame, nil
+}
+
+// DeleteExpiredService is a service to delete state history that is older than the retention period
+// from the tables of the SQL backend.
+type DeleteExpiredService struct {
+	store  store.StateHistoryStore
+	maxAge time.Duration
+	clock  clock.Clock
+}
+
+// DeleteExpired deletes the expired state history. It returns the number of deleted entries.
+// It does nothing if the retention period is not limited.
+func (s *DeleteExpiredService) DeleteExpired(ctx context.Context) (int64, error) {
+	if s.maxAge <= 0 {
+		return 0, nil
+	}
+	return s.store.DeleteStateHistoryBefore(ctx, s.clock.Now().Add(-s.maxAge))
+}
+
+func ProvideDeleteExpiredService(cfg *setting.Cfg, store *store.DBstore) *DeleteExpiredService {
+	return &DeleteExpiredService{
+		store:  store,
+		maxAge: cfg.UnifiedAlerting.StateHistory.SQLMaxAge,
+		clock:  clock.New(),
+	}
+}
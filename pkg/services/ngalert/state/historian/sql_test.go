@@ -0,0 +1,203 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestSQLBackendRecord(t *testing.T) {
+	t.Run("writes state transitions to the store", func(t *testing.T) {
+		store := &fakeSQLStore{}
+		sql := createTestSQLBackend(store, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+		rule := createTestRule()
+		now := time.Unix(100, 0)
+		states := singleFromNormal(&state.State{
+			State:              eval.Alerting,
+			Labels:             data.Labels{"a": "b", "__private__": "c"},
+			Values:             map[string]float64{"A": 1},
+			LastEvaluationTime: now,
+		})
+
+		err := <-sql.Record(context.Background(), rule, states)
+
+		require.NoError(t, err)
+		require.Len(t, store.entries, 1)
+		entry := store.entries[0]
+		require.Equal(t, rule.OrgID, entry.OrgID)
+		require.Equal(t, rule.UID, entry.RuleUID)
+		require.Equal(t, rule.Group, entry.RuleGroup)
+		require.Equal(t, rule.NamespaceUID, entry.FolderUID)
+		require.Equal(t, rule.DashboardUID, entry.DashboardUID)
+		require.Equal(t, rule.PanelID, entry.PanelID)
+		require.Equal(t, map[string]string{"a": "b"}, entry.Labels)
+		require.Equal(t, "Normal", entry.PreviousState)
+		require.Equal(t, "Alerting", entry.CurrentState)
+		require.JSONEq(t, `{"A":1}`, entry.Values)
+		require.Equal(t, now.UnixNano(), entry.TimestampNano)
+	})
+
+	t.Run("records the error of the state", func(t *testing.T) {
+		store := &fakeSQLStore{}
+		sql := createTestSQLBackend(store, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+		states := singleFromNormal(&state.State{
+			State: eval.Error,
+			Error: errors.New("oh no"),
+		})
+
+		err := <-sql.Record(context.Background(), createTestRule(), states)
+
+		require.NoError(t, err)
+		require.Len(t, store.entries, 1)
+		require.Equal(t, "oh no", store.entries[0].ErrorMessage)
+	})
+
+	t.Run("elides write if nothing to save", func(t *testing.T) {
+		store := &fakeSQLStore{err: errors.New("should not be called")}
+		sql := createTestSQLBackend(store, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+
+		err := <-sql.Record(context.Background(), createTestRule(), []state.StateTransition{})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("emits expected write metrics", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		met := metrics.NewHistorianMetrics(reg)
+		sql := createTestSQLBackend(&fakeSQLStore{}, met)
+		errSQL := createTestSQLBackend(&fakeSQLStore{err: errors.New("failed")}, met)
+		rule := createTestRule()
+		states := singleFromNormal(&state.State{
+			State:  eval.Alerting,
+			Labels: data.Labels{"a": "b"},
+		})
+
+		<-sql.Record(context.Background(), rule, states)
+		err := <-errSQL.Record(context.Background(), rule, states)
+		require.ErrorContains(t, err, "failed to save alert state history batch")
+
+		exp := bytes.NewBufferString(`
+# HELP grafana_alerting_state_history_transitions_failed_total The total number of state transitions that failed to be written - they are not retried.
+# TYPE grafana_alerting_state_history_transitions_failed_total counter
+grafana_alerting_state_history_transitions_failed_total{org="1"} 1
+# HELP grafana_alerting_state_history_transitions_total The total number of state transitions processed.
+# TYPE grafana_alerting_state_history_transitions_total counter
+grafana_alerting_state_history_transitions_total{org="1"} 2
+# HELP grafana_alerting_state_history_writes_failed_total The total number of failed writes of state history batches.
+# TYPE grafana_alerting_state_history_writes_failed_total counter
+grafana_alerting_state_history_writes_failed_total{backend="sql",org="1"} 1
+# HELP grafana_alerting_state_history_writes_total The total number of state history batches that were attempted to be written.
+# TYPE grafana_alerting_state_history_writes_total counter
+grafana_alerting_state_history_writes_total{backend="sql",org="1"} 2
+`)
+		err = testutil.GatherAndCompare(reg, exp,
+			"grafana_alerting_state_history_transitions_total",
+			"grafana_alerting_state_history_transitions_failed_total",
+			"grafana_alerting_state_history_writes_total",
+			"grafana_alerting_state_history_writes_failed_total",
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestSQLBackendQuery(t *testing.T) {
+	t.Run("applies defaults to the query", func(t *testing.T) {
+		store := &fakeSQLStore{}
+		sql := createTestSQLBackend(store, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+		now := time.Unix(100000, 0).UTC()
+		sql.clock.(*clock.Mock).Set(now)
+
+		_, err := sql.Query(context.Background(), models.HistoryQuery{OrgID: 1, Labels: map[string]string{"a": "b"}})
+
+		require.NoError(t, err)
+		require.Equal(t, models.HistoryQuery{
+			OrgID:  1,
+			Labels: map[string]string{"a": "b"},
+			From:   now.Add(-defaultQueryRange),
+			To:     now,
+			Limit:  defaultSQLQueryLimit,
+		}, store.lastQuery)
+	})
+
+	t.Run("formats entries in the same way as the Loki backend", func(t *testing.T) {
+		store := &fakeSQLStore{
+			entries: []*models.StateHistoryEntry{
+				{
+					OrgID:         1,
+					RuleUID:       "rule-uid",
+					RuleGroup:     "my-group",
+					FolderUID:     "my-folder",
+					Labels:        map[string]string{"a": "b"},
+					PreviousState: "Normal",
+					CurrentState:  "Alerting",
+					Values:        `{"A":1}`,
+					Condition:     "A",
+					Fingerprint:   "0000000000000001",
+					TimestampNano: time.Unix(10, 0).UnixNano(),
+				},
+				{
+					OrgID:         1,
+					RuleUID:       "rule-uid",
+					RuleGroup:     "my-group",
+					FolderUID:     "my-folder",
+					PreviousState: "Alerting",
+					CurrentState:  "Error",
+					ErrorMessage:  "oh no",
+					TimestampNano: time.Unix(20, 0).UnixNano(),
+				},
+			},
+		}
+		sql := createTestSQLBackend(store, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+
+		frame, err := sql.Query(context.Background(), models.HistoryQuery{OrgID: 1, RuleUID: "rule-uid"})
+
+		require.NoError(t, err)
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, dfTime, frame.Fields[0].Name)
+		require.Equal(t, dfLine, frame.Fields[1].Name)
+		require.Equal(t, dfLabels, frame.Fields[2].Name)
+		require.Equal(t, 2, frame.Rows())
+		require.Equal(t, time.Unix(10, 0), frame.Fields[0].At(0))
+
+		var entry lokiEntry
+		require.NoError(t, json.Unmarshal(frame.Fields[1].At(0).(json.RawMessage), &entry))
+		require.Equal(t, "Normal", entry.Previous)
+		require.Equal(t, "Alerting", entry.Current)
+		require.Equal(t, "rule-uid", entry.RuleUID)
+		require.Equal(t, map[string]string{"a": "b"}, entry.InstanceLabels)
+		require.Equal(t, 1.0, entry.Values.Get("A").MustFloat64())
+
+		require.NoError(t, json.Unmarshal(frame.Fields[1].At(1).(json.RawMessage), &entry))
+		require.Equal(t, "oh no", entry.Error)
+
+		require.JSONEq(t, `{"from":"state-history","orgID":"1","group":"my-group","folderUID":"my-folder"}`, string(frame.Fields[2].At(0).(json.RawMessage)))
+	})
+
+	t.Run("propagates errors of the store", func(t *testing.T) {
+		sql := createTestSQLBackend(&fakeSQLStore{err: errors.New("failed")}, metrics.NewHistorianMetrics(prometheus.NewRegistry()))
+
+		_, err := sql.Query(context.Background(), models.HistoryQuery{OrgID: 1})
+
+		require.ErrorContains(t, err, "failed")
+	})
+}
+
+func createTestSQLBackend(store SQLStore, met *metrics.Historian) *SQLBackend {
+	sql := NewSQLBackend(store, met)
+	sql.clock = clock.NewMock()
+	return sql
+}
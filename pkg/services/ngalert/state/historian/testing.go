@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 type fakeRequester struct {
@@ -47,3 +48,25 @@ func (f *failingAnnotationRepo) SaveMany(_ context.Context, _ []annotations.Item
 func (f *failingAnnotationRepo) Find(_ context.Context, _ *annotations.ItemQuery) ([]*annotations.ItemDTO, error) {
 	return nil, fmt.Errorf("failed to query annotations")
 }
+
+type fakeSQLStore struct {
+	entries   []*models.StateHistoryEntry
+	lastQuery models.HistoryQuery
+	err       error
+}
+
+func (f *fakeSQLStore) SaveStateHistory(_ context.Context, entries []*models.StateHistoryEntry) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.entries = append(f.entries, entries...)
+	return nil
+}
+
+func (f *fakeSQLStore) FindStateHistory(_ context.Context, query models.HistoryQuery) ([]*models.StateHistoryEntry, error) {
+	f.lastQuery = query
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.entries, nil
+}
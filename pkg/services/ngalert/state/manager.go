@@ -3,17 +3,20 @@ package state
 import (
 	"context"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/grafana/dskit/concurrency"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/promql"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	ngModels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/template"
 )
 
 var (
@@ -39,6 +42,7 @@ type Manager struct {
 	images        ImageCapturer
 	historian     Historian
 	externalURL   *url.URL
+	querier       TemplateQuerier
 
 	doNotSaveNormalState    bool
 	maxStateSaveConcurrency int
@@ -51,6 +55,8 @@ type ManagerCfg struct {
 	Images        ImageCapturer
 	Clock         clock.Clock
 	Historian     Historian
+	// TemplateQuerier runs the queries of the query function in templates. If it is nil then the query function returns no results.
+	TemplateQuerier TemplateQuerier
 	// DoNotSaveNormalState controls whether eval.Normal state is persisted to the database and returned by get methods
 	DoNotSaveNormalState bool
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
@@ -68,6 +74,7 @@ func NewManager(cfg ManagerCfg) *Manager {
 		historian:               cfg.Historian,
 		clock:                   cfg.Clock,
 		externalURL:             cfg.ExternalURL,
+		querier:                 cfg.TemplateQuerier,
 		doNotSaveNormalState:    cfg.DoNotSaveNormalState,
 		maxStateSaveConcurrency: cfg.MaxStateSaveConcurrency,
 	}
@@ -148,7 +155,10 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 			if err != nil {
 				st.log.Error("Error getting cacheId for entry", "error", err)
 			}
-			rulesStates.states[cacheID] = &State{
+			// If the instance was saved before the result fingerprint was introduced,
+			// the fingerprint is empty and parsing will fail. That's fine.
+			resultFp, _ := strconv.ParseUint(entry.ResultFingerprint, 16, 64)
+			state := &State{
 				AlertRuleUID:         entry.RuleUID,
 				OrgID:                entry.RuleOrgID,
 				CacheID:              cacheID,
@@ -160,7 +170,14 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				EndsAt:               entry.CurrentStateEnd,
 				LastEvaluationTime:   entry.LastEvalTime,
 				Annotations:          ruleForEntry.Annotations,
+				ResultFingerprint:    data.Fingerprint(resultFp),
 			}
+			// The time since when the state is Recovering is not saved. Assume it started with the last evaluation,
+			// so that the alert keeps firing for at least the keep firing for duration after a restart.
+			if state.State == eval.Recovering {
+				state.RecoveringSince = entry.LastEvalTime
+			}
+			rulesStates.states[cacheID] = state
 			statesCount++
 		}
 	}
@@ -190,6 +207,7 @@ func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 	for _, s := range states {
 		oldState := s.State
 		oldReason := s.StateReason
+		wasFiring := s.IsFiring()
 		startsAt := s.StartsAt
 		if s.State != eval.Normal {
 			startsAt = now
@@ -197,7 +215,7 @@ func (st *Manager) DeleteStateByRuleUID(ctx context.Context, ruleKey ngModels.Al
 		s.SetNormal(reason, startsAt, now)
 		// Set Resolved property so the scheduler knows to send a postable alert
 		// to Alertmanager.
-		s.Resolved = oldState == eval.Alerting
+		s.Resolved = wasFiring
 		s.LastEvaluationTime = now
 		s.Values = map[string]float64{}
 		transitions = append(transitions, StateTransition{
@@ -239,6 +257,43 @@ func (st *Manager) ResetStateByRuleUID(ctx context.Context, rule *ngModels.Alert
 	return transitions
 }
 
+// SuppressStateByRuleUID sets all states of the rule to Normal with the provided reason without evaluating the rule,
+// e.g. when one of its upstream rules is firing. Firing states are resolved. It returns only the states that changed,
+// which are saved to the instanceStore and state history.
+func (st *Manager) SuppressStateByRuleUID(ctx context.Context, rule *ngModels.AlertRule, evaluatedAt time.Time, reason string) []StateTransition {
+	logger := st.log.FromContext(ctx)
+	states := st.cache.getStatesForRuleUID(rule.OrgID, rule.UID, false)
+
+	transitions := make([]StateTransition, 0, len(states))
+	for _, s := range states {
+		// Keep the states evaluated so they are not considered stale.
+		s.LastEvaluationTime = evaluatedAt
+		if s.State == eval.Normal && s.StateReason == reason {
+			continue
+		}
+		oldState := s.State
+		oldReason := s.StateReason
+		wasFiring := s.IsFiring()
+		s.SetNormal(reason, evaluatedAt, evaluatedAt)
+		s.Resolved = wasFiring
+		transitions = append(transitions, StateTransition{
+			State:               s,
+			PreviousState:       oldState,
+			PreviousStateReason: oldReason,
+		})
+	}
+	if len(transitions) == 0 {
+		return nil
+	}
+	logger.Debug("Rule states were suppressed", "states", len(transitions), "reason", reason)
+
+	st.saveAlertStates(ctx, logger, transitions...)
+	if st.historian != nil {
+		st.historian.Record(ctx, history_model.NewRuleMeta(rule, logger), transitions)
+	}
+	return transitions
+}
+
 // ProcessEvalResults updates the current states that belong to a rule with the evaluation results.
 // if extraLabels is not empty, those labels will be added to every state. The extraLabels take precedence over rule labels and result labels
 func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time, alertRule *ngModels.AlertRule, results eval.Results, extraLabels data.Labels) []StateTransition {
@@ -262,9 +317,19 @@ func (st *Manager) ProcessEvalResults(ctx context.Context, evaluatedAt time.Time
 	return allChanges
 }
 
+// templateQueryFunc returns the function that runs the queries of the query function in the templates of the alert rule.
+func (st *Manager) templateQueryFunc(alertRule *ngModels.AlertRule) template.QueryFunc {
+	if st.querier == nil {
+		return nil
+	}
+	return func(ctx context.Context, query string, ts time.Time) (promql.Vector, error) {
+		return st.querier.Query(ctx, alertRule, query, ts)
+	}
+}
+
 // Set the current state based on evaluation results
 func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRule, result eval.Result, extraLabels data.Labels, logger log.Logger) StateTransition {
-	currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL)
+	currentState := st.cache.getOrCreate(ctx, logger, alertRule, result, extraLabels, st.externalURL, st.templateQueryFunc(alertRule))
 
 	currentState.LastEvaluationTime = result.EvaluatedAt
 	currentState.EvaluationDuration = result.EvaluationDuration
@@ -278,6 +343,7 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 	currentState.TrimResults(alertRule)
 	oldState := currentState.State
 	oldReason := currentState.StateReason
+	wasFiring := currentState.IsFiring()
 
 	// Add the instance to the log context to help correlate log lines for a state
 	logger = logger.New("instance", result.Instance)
@@ -307,10 +373,13 @@ func (st *Manager) setNextState(ctx context.Context, alertRule *ngModels.AlertRu
 		result.State != eval.Alerting {
 		currentState.StateReason = result.State.String()
 	}
+	if currentState.StateReason == "" {
+		currentState.StateReason = result.StateReason
+	}
 
 	// Set Resolved property so the scheduler knows to send a postable alert
 	// to Alertmanager.
-	currentState.Resolved = oldState == eval.Alerting && currentState.State == eval.Normal
+	currentState.Resolved = wasFiring && currentState.State == eval.Normal
 
 	if shouldTakeImage(currentState.State, oldState, currentState.Image, currentState.Resolved) {
 		image, err := takeImage(ctx, st.images, alertRule)
@@ -375,6 +444,7 @@ func (st *Manager) saveAlertStates(ctx context.Context, logger log.Logger, state
 			LastEvalTime:      s.LastEvaluationTime,
 			CurrentStateSince: s.StartsAt,
 			CurrentStateEnd:   s.EndsAt,
+			ResultFingerprint: s.ResultFingerprint.String(),
 		}
 
 		err = st.instanceStore.SaveAlertInstance(ctx, instance)
@@ -426,6 +496,8 @@ func translateInstanceState(state ngModels.InstanceStateType) eval.State {
 		return eval.NoData
 	case ngModels.InstanceStatePending:
 		return eval.Pending
+	case ngModels.InstanceStateRecovering:
+		return eval.Recovering
 	default:
 		return eval.Error
 	}
@@ -443,13 +515,14 @@ func (st *Manager) deleteStaleStatesFromCache(ctx context.Context, logger log.Lo
 		logger.Info("Detected stale state entry", "cacheID", s.CacheID, "state", s.State, "reason", s.StateReason)
 		oldState := s.State
 		oldReason := s.StateReason
+		wasFiring := s.IsFiring()
 
 		s.State = eval.Normal
 		s.StateReason = ngModels.StateReasonMissingSeries
 		s.EndsAt = evaluatedAt
 		s.LastEvaluationTime = evaluatedAt
 
-		if oldState == eval.Alerting {
+		if wasFiring {
 			s.Resolved = true
 			image, err := takeImage(ctx, st.images, alertRule)
 			if err != nil {
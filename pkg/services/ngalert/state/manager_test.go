@@ -328,8 +328,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -385,8 +386,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label_1":             "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"instance_label_1": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -411,8 +413,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label_2":             "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Alerting,
+					ResultFingerprint: data.Labels{"instance_label_2": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Alerting,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -469,8 +472,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -533,8 +537,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Alerting,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Alerting,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -606,8 +611,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Alerting,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Alerting,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -633,6 +639,245 @@ func TestProcessEvalResults(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "alerting -> recovering when KeepFiringFor is set but not exceeded",
+			alertRule: &models.AlertRule{
+				OrgID:           1,
+				Title:           "test_title",
+				UID:             "test_alert_rule_uid_2",
+				NamespaceUID:    "test_namespace_uid",
+				Annotations:     map[string]string{"annotation": "test"},
+				Labels:          map[string]string{"label": "test"},
+				IntervalSeconds: 10,
+				KeepFiringFor:   1 * time.Minute,
+			},
+			evalResults: []eval.Results{
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime,
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(10 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(40 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+			},
+			expectedAnnotations: 2,
+			expectedStates: map[string]*state.State{
+				`[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`: {
+					AlertRuleUID: "test_alert_rule_uid_2",
+					OrgID:        1,
+					CacheID:      `[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`,
+					Labels: data.Labels{
+						"__alert_rule_namespace_uid__": "test_namespace_uid",
+						"__alert_rule_uid__":           "test_alert_rule_uid_2",
+						"alertname":                    "test_title",
+						"label":                        "test",
+						"instance_label":               "test",
+					},
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Recovering,
+					Results: []state.Evaluation{
+						{
+							EvaluationTime:  evaluationTime,
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(10 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(40 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+					},
+					RecoveringSince:    evaluationTime.Add(10 * time.Second),
+					StartsAt:           evaluationTime,
+					EndsAt:             evaluationTime.Add(40 * time.Second).Add(state.ResendDelay * 3),
+					LastEvaluationTime: evaluationTime.Add(40 * time.Second),
+					EvaluationDuration: evaluationDuration,
+					Annotations:        map[string]string{"annotation": "test"},
+				},
+			},
+		},
+		{
+			desc: "alerting -> recovering -> alerting when KeepFiringFor is set and result is alerting again",
+			alertRule: &models.AlertRule{
+				OrgID:           1,
+				Title:           "test_title",
+				UID:             "test_alert_rule_uid_2",
+				NamespaceUID:    "test_namespace_uid",
+				Annotations:     map[string]string{"annotation": "test"},
+				Labels:          map[string]string{"label": "test"},
+				IntervalSeconds: 10,
+				KeepFiringFor:   1 * time.Minute,
+			},
+			evalResults: []eval.Results{
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime,
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(10 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime.Add(20 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+			},
+			expectedAnnotations: 3,
+			expectedStates: map[string]*state.State{
+				`[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`: {
+					AlertRuleUID: "test_alert_rule_uid_2",
+					OrgID:        1,
+					CacheID:      `[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`,
+					Labels: data.Labels{
+						"__alert_rule_namespace_uid__": "test_namespace_uid",
+						"__alert_rule_uid__":           "test_alert_rule_uid_2",
+						"alertname":                    "test_title",
+						"label":                        "test",
+						"instance_label":               "test",
+					},
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Alerting,
+					Results: []state.Evaluation{
+						{
+							EvaluationTime:  evaluationTime,
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(10 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(20 * time.Second),
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+					},
+					StartsAt:           evaluationTime,
+					EndsAt:             evaluationTime.Add(20 * time.Second).Add(state.ResendDelay * 3),
+					LastEvaluationTime: evaluationTime.Add(20 * time.Second),
+					EvaluationDuration: evaluationDuration,
+					Annotations:        map[string]string{"annotation": "test"},
+				},
+			},
+		},
+		{
+			desc: "alerting -> recovering -> normal when KeepFiringFor is exceeded",
+			alertRule: &models.AlertRule{
+				OrgID:           1,
+				Title:           "test_title",
+				UID:             "test_alert_rule_uid_2",
+				NamespaceUID:    "test_namespace_uid",
+				Annotations:     map[string]string{"annotation": "test"},
+				Labels:          map[string]string{"label": "test"},
+				IntervalSeconds: 10,
+				KeepFiringFor:   1 * time.Minute,
+			},
+			evalResults: []eval.Results{
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Alerting,
+						EvaluatedAt:        evaluationTime,
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(10 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+				{
+					eval.Result{
+						Instance:           data.Labels{"instance_label": "test"},
+						State:              eval.Normal,
+						EvaluatedAt:        evaluationTime.Add(70 * time.Second),
+						EvaluationDuration: evaluationDuration,
+					},
+				},
+			},
+			expectedAnnotations: 3,
+			expectedStates: map[string]*state.State{
+				`[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`: {
+					AlertRuleUID: "test_alert_rule_uid_2",
+					OrgID:        1,
+					CacheID:      `[["__alert_rule_namespace_uid__","test_namespace_uid"],["__alert_rule_uid__","test_alert_rule_uid_2"],["alertname","test_title"],["instance_label","test"],["label","test"]]`,
+					Labels: data.Labels{
+						"__alert_rule_namespace_uid__": "test_namespace_uid",
+						"__alert_rule_uid__":           "test_alert_rule_uid_2",
+						"alertname":                    "test_title",
+						"label":                        "test",
+						"instance_label":               "test",
+					},
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
+					Resolved:          true,
+					Results: []state.Evaluation{
+						{
+							EvaluationTime:  evaluationTime,
+							EvaluationState: eval.Alerting,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(10 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+						{
+							EvaluationTime:  evaluationTime.Add(70 * time.Second),
+							EvaluationState: eval.Normal,
+							Values:          make(map[string]*float64),
+						},
+					},
+					StartsAt:           evaluationTime.Add(70 * time.Second),
+					EndsAt:             evaluationTime.Add(70 * time.Second),
+					LastEvaluationTime: evaluationTime.Add(70 * time.Second),
+					EvaluationDuration: evaluationDuration,
+					Annotations:        map[string]string{"annotation": "test"},
+				},
+			},
+		},
 		{
 			desc: "normal -> alerting -> noData -> alerting when For is set",
 			alertRule: &models.AlertRule{
@@ -701,8 +946,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Pending,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Pending,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(30 * time.Second),
@@ -783,8 +1029,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(20 * time.Second),
@@ -848,8 +1095,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Pending,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Pending,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -913,8 +1161,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Pending,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Pending,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -979,9 +1228,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Pending,
-					StateReason: eval.NoData.String(),
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Pending,
+					StateReason:       eval.NoData.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1070,9 +1320,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Alerting,
-					StateReason: eval.NoData.String(),
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Alerting,
+					StateReason:       eval.NoData.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(20 * time.Second),
@@ -1141,8 +1392,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1206,8 +1458,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1231,8 +1484,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultFingerprint: data.Labels{}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -1297,8 +1551,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test-1",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"instance_label": "test-1"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1323,8 +1578,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test-2",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"instance_label": "test-2"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1348,8 +1604,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultFingerprint: data.Labels{}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -1416,8 +1673,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1446,8 +1704,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"alertname":                    "test_title",
 						"label":                        "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultFingerprint: data.Labels{}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(10 * time.Second),
@@ -1506,9 +1765,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Normal,
-					StateReason: eval.NoData.String(),
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
+					StateReason:       eval.NoData.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1574,10 +1834,11 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Pending,
-					StateReason: eval.Error.String(),
-					Error:       errors.New("test error"),
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Pending,
+					StateReason:       eval.Error.String(),
+					Error:             errors.New("test error"),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1666,9 +1927,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Alerting,
-					StateReason: eval.Error.String(),
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Alerting,
+					StateReason:       eval.Error.String(),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(20 * time.Second),
@@ -1745,9 +2007,10 @@ func TestProcessEvalResults(t *testing.T) {
 						"datasource_uid":               "datasource_uid_1",
 						"ref_id":                       "A",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Error,
-					Error:  expr.MakeQueryError("A", "", errors.New("this is an error")),
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Error,
+					Error:             expr.MakeQueryError("A", "", errors.New("this is an error")),
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1817,10 +2080,11 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Normal,
-					StateReason: eval.Error.String(),
-					Error:       nil,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
+					StateReason:       eval.Error.String(),
+					Error:             nil,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1890,10 +2154,11 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values:      make(map[string]float64),
-					State:       eval.Normal,
-					StateReason: eval.Error.String(),
-					Error:       nil,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
+					StateReason:       eval.Error.String(),
+					Error:             nil,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -1990,8 +2255,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Error,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Error,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(40 * time.Second),
@@ -2072,8 +2338,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Pending,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Pending,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(30 * time.Second),
@@ -2160,8 +2427,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"instance_label":               "test",
 					},
-					Values: make(map[string]float64),
-					State:  eval.NoData,
+					ResultFingerprint: data.Labels{"instance_label": "test"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.NoData,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime.Add(30 * time.Second),
@@ -2223,8 +2491,9 @@ func TestProcessEvalResults(t *testing.T) {
 						"label":                        "test",
 						"job":                          "prod/grafana",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"cluster": "us-central-1", "namespace": "prod", "pod": "grafana"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -2401,8 +2670,9 @@ func TestStaleResultsHandler(t *testing.T) {
 						"alertname":                    rule.Title,
 						"test1":                        "testValue1",
 					},
-					Values: make(map[string]float64),
-					State:  eval.Normal,
+					ResultFingerprint: data.Labels{"test1": "testValue1"}.Fingerprint(),
+					Values:            make(map[string]float64),
+					State:             eval.Normal,
 					Results: []state.Evaluation{
 						{
 							EvaluationTime:  evaluationTime,
@@ -2869,3 +3139,86 @@ func TestResetStateByRuleUID(t *testing.T) {
 		})
 	}
 }
+
+func TestSuppressStateByRuleUID(t *testing.T) {
+	interval := time.Minute
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, 1)
+
+	const mainOrgID int64 = 1
+	rule := tests.CreateTestAlertRule(t, ctx, dbstore, int64(interval.Seconds()), mainOrgID)
+
+	labels1 := models.InstanceLabels{"test1": "testValue1"}
+	_, hash1, _ := labels1.StringAndHash()
+	labels2 := models.InstanceLabels{"test2": "testValue2"}
+	_, hash2, _ := labels2.StringAndHash()
+	instances := []models.AlertInstance{
+		{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  rule.OrgID,
+				RuleUID:    rule.UID,
+				LabelsHash: hash1,
+			},
+			CurrentState: models.InstanceStateNormal,
+			Labels:       labels1,
+		},
+		{
+			AlertInstanceKey: models.AlertInstanceKey{
+				RuleOrgID:  rule.OrgID,
+				RuleUID:    rule.UID,
+				LabelsHash: hash2,
+			},
+			CurrentState: models.InstanceStateFiring,
+			Labels:       labels2,
+		},
+	}
+
+	for _, instance := range instances {
+		_ = dbstore.SaveAlertInstance(ctx, instance)
+	}
+
+	fakeHistorian := &state.FakeHistorian{StateTransitions: make([]state.StateTransition, 0)}
+	clk := clock.NewMock()
+	clk.Set(time.Now())
+	cfg := state.ManagerCfg{
+		Metrics:                 testMetrics.GetStateMetrics(),
+		ExternalURL:             nil,
+		InstanceStore:           dbstore,
+		Images:                  &state.NoopImageService{},
+		Clock:                   clk,
+		Historian:               fakeHistorian,
+		MaxStateSaveConcurrency: 1,
+	}
+	st := state.NewManager(cfg)
+	st.Warm(ctx, dbstore)
+
+	transitions := st.SuppressStateByRuleUID(ctx, rule, clk.Now(), models.StateReasonSuppressedByDependency)
+
+	require.Len(t, transitions, 2)
+	for _, s := range transitions {
+		assert.Equal(t, eval.Normal, s.State.State)
+		assert.Equal(t, models.StateReasonSuppressedByDependency, s.StateReason)
+		assert.Equal(t, clk.Now(), s.LastEvaluationTime)
+		assert.Equal(t, s.PreviousState == eval.Alerting, s.Resolved)
+	}
+	assert.Equal(t, transitions, fakeHistorian.StateTransitions)
+
+	// States remain in the cache and in the database.
+	existingStatesForRule := st.GetStatesForRuleUID(rule.OrgID, rule.UID)
+	assert.Len(t, existingStatesForRule, 2)
+	alertInstances, _ := dbstore.ListAlertInstances(ctx, &models.ListAlertInstancesQuery{RuleOrgID: rule.OrgID, RuleUID: rule.UID})
+	require.Len(t, alertInstances, 2)
+	for _, instance := range alertInstances {
+		assert.Equal(t, models.InstanceStateNormal, instance.CurrentState)
+		assert.Equal(t, models.StateReasonSuppressedByDependency, instance.CurrentReason)
+	}
+
+	t.Run("should not produce transitions if states are already suppressed", func(t *testing.T) {
+		clk.Add(interval)
+		transitions := st.SuppressStateByRuleUID(ctx, rule, clk.Now(), models.StateReasonSuppressedByDependency)
+		assert.Empty(t, transitions)
+		for _, s := range st.GetStatesForRuleUID(rule.OrgID, rule.UID) {
+			assert.Equal(t, clk.Now(), s.LastEvaluationTime)
+		}
+	})
+}
@@ -2,6 +2,9 @@ package state
 
 import (
 	"context"
+	"time"
+
+	"github.com/prometheus/prometheus/promql"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	history_model "github.com/grafana/grafana/pkg/services/ngalert/state/historian/model"
@@ -29,6 +32,12 @@ type Historian interface {
 	Record(ctx context.Context, rule history_model.RuleMeta, states []StateTransition) <-chan error
 }
 
+// TemplateQuerier runs the queries of the query function in the templates of labels and annotations.
+type TemplateQuerier interface {
+	// Query runs an instant query at the given time against the data source of the alert rule.
+	Query(ctx context.Context, r *models.AlertRule, query string, ts time.Time) (promql.Vector, error)
+}
+
 // ImageCapturer captures images.
 //
 //go:generate mockgen -destination=image_mock.go -package=state github.com/grafana/grafana/pkg/services/ngalert/state ImageCapturer
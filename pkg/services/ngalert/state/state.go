@@ -61,6 +61,14 @@ type State struct {
 	// conditions.
 	Values map[string]float64
 
+	// ResultFingerprint is the fingerprint of the labels of the evaluation result (without the labels of the rule).
+	// It is used to find the state of a dimension in the results of expressions.
+	ResultFingerprint data.Fingerprint
+
+	// RecoveringSince is when the state changed from Alerting to Recovering. It is zero unless the state is
+	// Recovering. The start time is kept while the state is Recovering, as the alert is still firing.
+	RecoveringSince time.Time
+
 	StartsAt             time.Time
 	EndsAt               time.Time
 	LastSentAt           time.Time
@@ -91,6 +99,7 @@ func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.StateReason = reason
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
+	a.RecoveringSince = time.Time{}
 	a.Error = nil
 }
 
@@ -100,6 +109,17 @@ func (a *State) SetPending(reason string, startsAt, endsAt time.Time) {
 	a.StateReason = reason
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
+	a.RecoveringSince = time.Time{}
+	a.Error = nil
+}
+
+// SetRecovering sets the state to Recovering. It keeps the start time, as the alert is still firing, and changes
+// the time since when the state is Recovering and the end time.
+func (a *State) SetRecovering(reason string, recoveringSince, endsAt time.Time) {
+	a.State = eval.Recovering
+	a.StateReason = reason
+	a.RecoveringSince = recoveringSince
+	a.EndsAt = endsAt
 	a.Error = nil
 }
 
@@ -109,6 +129,7 @@ func (a *State) SetNoData(reason string, startsAt, endsAt time.Time) {
 	a.StateReason = reason
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
+	a.RecoveringSince = time.Time{}
 	a.Error = nil
 }
 
@@ -118,6 +139,7 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.StateReason = models.StateReasonError
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
+	a.RecoveringSince = time.Time{}
 	a.Error = err
 }
 
@@ -127,6 +149,7 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.StateReason = reason
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
+	a.RecoveringSince = time.Time{}
 	a.Error = nil
 }
 
@@ -136,6 +159,7 @@ func (a *State) Resolve(reason string, endsAt time.Time) {
 	a.StateReason = reason
 	a.Resolved = true
 	a.EndsAt = endsAt
+	a.RecoveringSince = time.Time{}
 }
 
 // Maintain updates the end time using the most recent evaluation.
@@ -143,6 +167,11 @@ func (a *State) Maintain(interval int64, evaluatedAt time.Time) {
 	a.EndsAt = nextEndsTime(interval, evaluatedAt)
 }
 
+// IsFiring returns true if the state is Alerting or Recovering, i.e. an alert is active in the Alertmanager.
+func (a *State) IsFiring() bool {
+	return a.State == eval.Alerting || a.State == eval.Recovering
+}
+
 // IsNormalStateWithNoReason returns true if the state is Normal and reason is empty
 func IsNormalStateWithNoReason(s *State) bool {
 	return s.State == eval.Normal && s.StateReason == ""
@@ -187,10 +216,35 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger) {
-	if state.State == eval.Normal {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
+	switch {
+	case state.State == eval.Normal:
 		logger.Debug("Keeping state", "state", state.State)
-	} else {
+	case state.State == eval.Alerting && rule.KeepFiringFor > 0:
+		// If the alert rule has a KeepFiringFor duration that should be observed then the state should be set to Recovering
+		nextEndsAt := nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+		logger.Debug("Changing state",
+			"previous_state",
+			state.State,
+			"next_state",
+			eval.Recovering,
+			"previous_ends_at",
+			state.EndsAt,
+			"next_ends_at",
+			nextEndsAt)
+		state.SetRecovering("", result.EvaluatedAt, nextEndsAt)
+	case state.State == eval.Recovering && result.EvaluatedAt.Sub(state.RecoveringSince) < rule.KeepFiringFor:
+		// If the previous state is Recovering then keep firing until the KeepFiringFor duration has been observed
+		prevEndsAt := state.EndsAt
+		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		logger.Debug("Keeping state",
+			"state",
+			state.State,
+			"previous_ends_at",
+			prevEndsAt,
+			"next_ends_at",
+			state.EndsAt)
+	default:
 		nextEndsAt := result.EvaluatedAt
 		logger.Debug("Changing state",
 			"previous_state",
@@ -218,6 +272,20 @@ func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, lo
 			prevEndsAt,
 			"next_ends_at",
 			state.EndsAt)
+	case eval.Recovering:
+		// If the previous state is Recovering then the alert has not been resolved yet and should go back to Alerting.
+		// The start time is kept as the alert has been firing since then.
+		nextEndsAt := nextEndsTime(rule.IntervalSeconds, result.EvaluatedAt)
+		logger.Debug("Changing state",
+			"previous_state",
+			state.State,
+			"next_state",
+			eval.Alerting,
+			"previous_ends_at",
+			state.EndsAt,
+			"next_ends_at",
+			nextEndsAt)
+		state.SetAlerting("", state.StartsAt, nextEndsAt)
 	case eval.Pending:
 		// If the previous state is Pending then check if the For duration has been observed
 		if result.EvaluatedAt.Sub(state.StartsAt) >= rule.For {
@@ -449,7 +517,7 @@ func (a *State) GetLastEvaluationValuesForCondition() map[string]float64 {
 // been resolved.
 func shouldTakeImage(state, previousState eval.State, previousImage *models.Image, resolved bool) bool {
 	return resolved ||
-		state == eval.Alerting && previousState != eval.Alerting ||
+		state == eval.Alerting && previousState != eval.Alerting && previousState != eval.Recovering ||
 		state == eval.Alerting && previousImage == nil
 }
 
@@ -489,3 +557,22 @@ func GetRuleExtraLabels(rule *models.AlertRule, folderTitle string, includeFolde
 	}
 	return extraLabels
 }
+
+// AlertingResultsFromRuleState implements the eval.AlertingResultsReader interface.
+// It returns the fingerprints of the results of the rule's states that met the condition at the previous evaluation.
+type AlertingResultsFromRuleState struct {
+	Manager *Manager
+	Rule    *models.AlertRule
+}
+
+func (n *AlertingResultsFromRuleState) Read() map[data.Fingerprint]struct{} {
+	states := n.Manager.GetStatesForRuleUID(n.Rule.OrgID, n.Rule.UID)
+
+	active := map[data.Fingerprint]struct{}{}
+	for _, st := range states {
+		if st.State == eval.Alerting || st.State == eval.Pending {
+			active[st.ResultFingerprint] = struct{}{}
+		}
+	}
+	return active
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/benbjohnson/clock"
 	"github.com/golang/mock/gomock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -109,6 +110,53 @@ func TestSetPending(t *testing.T) {
 	}
 }
 
+func TestSetRecovering(t *testing.T) {
+	mock := clock.NewMock()
+	tests := []struct {
+		name            string
+		state           State
+		reason          string
+		recoveringSince time.Time
+		endsAt          time.Time
+		expected        State
+	}{{
+		name:            "state is set to Recovering",
+		reason:          "this is a reason",
+		recoveringSince: mock.Now(),
+		endsAt:          mock.Now().Add(time.Minute),
+		expected: State{
+			State:           eval.Recovering,
+			StateReason:     "this is a reason",
+			RecoveringSince: mock.Now(),
+			EndsAt:          mock.Now().Add(time.Minute),
+		},
+	}, {
+		name: "previous state is removed and start time is kept",
+		state: State{
+			State:       eval.Alerting,
+			StateReason: "this is a reason",
+			Error:       errors.New("this is an error"),
+			StartsAt:    mock.Now().Add(-time.Hour),
+		},
+		recoveringSince: mock.Now(),
+		endsAt:          mock.Now().Add(time.Minute),
+		expected: State{
+			State:           eval.Recovering,
+			StartsAt:        mock.Now().Add(-time.Hour),
+			RecoveringSince: mock.Now(),
+			EndsAt:          mock.Now().Add(time.Minute),
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := test.state
+			actual.SetRecovering(test.reason, test.recoveringSince, test.endsAt)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestNormal(t *testing.T) {
 	mock := clock.NewMock()
 	tests := []struct {
@@ -585,6 +633,17 @@ func TestShouldTakeImage(t *testing.T) {
 		state:         eval.Alerting,
 		previousState: eval.Alerting,
 		previousImage: &ngmodels.Image{URL: "https://example.com/foo.png"},
+	}, {
+		name:          "should not take image for alerting state that was recovering with image",
+		state:         eval.Alerting,
+		previousState: eval.Recovering,
+		previousImage: &ngmodels.Image{URL: "https://example.com/foo.png"},
+	}, {
+		name:          "should take image for state resolved after recovering",
+		state:         eval.Normal,
+		previousState: eval.Recovering,
+		resolved:      true,
+		expected:      true,
 	}}
 
 	for _, test := range tests {
@@ -666,3 +725,33 @@ func TestTakeImage(t *testing.T) {
 		assert.Equal(t, ngmodels.Image{Path: "foo.png"}, *image)
 	})
 }
+
+func TestAlertingResultsFromRuleState(t *testing.T) {
+	rule := ngmodels.AlertRuleGen()()
+	c := newCache()
+	m := &Manager{cache: c}
+
+	newState := func(s eval.State, instance data.Labels) *State {
+		return &State{
+			OrgID:             rule.OrgID,
+			AlertRuleUID:      rule.UID,
+			CacheID:           instance.String(),
+			State:             s,
+			ResultFingerprint: instance.Fingerprint(),
+		}
+	}
+	alerting := data.Labels{"instance": "alerting"}
+	pending := data.Labels{"instance": "pending"}
+	normal := data.Labels{"instance": "normal"}
+	noData := data.Labels{"instance": "nodata"}
+	c.set(newState(eval.Alerting, alerting))
+	c.set(newState(eval.Pending, pending))
+	c.set(newState(eval.Normal, normal))
+	c.set(newState(eval.NoData, noData))
+
+	reader := &AlertingResultsFromRuleState{Manager: m, Rule: rule}
+	require.Equal(t, map[data.Fingerprint]struct{}{
+		alerting.Fingerprint(): {},
+		pending.Fingerprint():  {},
+	}, reader.Read())
+}
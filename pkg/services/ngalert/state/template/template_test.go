@@ -5,8 +5,11 @@ import (
 	"errors"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -436,7 +439,7 @@ func TestExpandTemplate(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			v, err := Expand(context.Background(), "test", c.text, NewData(c.labels, c.alertInstance), externalURL, c.alertInstance.EvaluatedAt)
+			v, err := Expand(context.Background(), "test", c.text, NewData(c.labels, c.alertInstance), externalURL, c.alertInstance.EvaluatedAt, nil)
 			if c.expectedError != nil {
 				require.NotNil(t, err)
 				require.EqualError(t, c.expectedError, err.Error())
@@ -447,3 +450,52 @@ func TestExpandTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandTemplateWithQuery(t *testing.T) {
+	evaluatedAt := time.Unix(1000, 0)
+	queryFunc := func(_ context.Context, q string, ts time.Time) (promql.Vector, error) {
+		if q == "invalid" {
+			return nil, errors.New("invalid query")
+		}
+		require.Equal(t, evaluatedAt, ts)
+		return promql.Vector{
+			{Point: promql.Point{T: ts.UnixMilli(), V: 2048}, Metric: labels.FromStrings("instance", "b")},
+			{Point: promql.Point{T: ts.UnixMilli(), V: 0.5}, Metric: labels.FromStrings("instance", "a")},
+		}, nil
+	}
+
+	cases := []struct {
+		name          string
+		text          string
+		expected      string
+		expectedError string
+	}{{
+		name:     "query returns the first sample",
+		text:     `{{ query "up" | first | value }}`,
+		expected: "2048",
+	}, {
+		name:     "query results can be sorted by label and humanized",
+		text:     `{{ range query "up" | sortByLabel "instance" }}{{ .Labels.instance }}={{ .Value | humanize1024 }} {{ end }}`,
+		expected: "a=0.5 b=2ki ",
+	}, {
+		name:     "query results can be ranged over",
+		text:     `{{ with query "up" }}{{ len . }}{{ end }}`,
+		expected: "2",
+	}, {
+		name:          "query errors are returned",
+		text:          `{{ query "invalid" }}`,
+		expectedError: "invalid query",
+	}}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			v, err := Expand(context.Background(), "test", c.text, Data{}, nil, evaluatedAt, queryFunc)
+			if c.expectedError != "" {
+				require.ErrorContains(t, err, c.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, v)
+		})
+	}
+}
@@ -98,6 +98,45 @@ func (st DBstore) GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAler
 	return result, err
 }
 
+// GetAlertRuleVersion is a handler for retrieving an alert rule as it was at the specified version.
+// It returns models.ErrAlertRuleNotFound if the rule did not have such version.
+func (st DBstore) GetAlertRuleVersion(ctx context.Context, query *ngmodels.GetAlertRuleVersionQuery) (result *ngmodels.AlertRule, err error) {
+	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		version := ngmodels.AlertRuleVersion{}
+		has, err := sess.Table("alert_rule_version").Where("rule_org_id = ? AND rule_uid = ? AND version = ?", query.OrgID, query.UID, query.Version).Get(&version)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return fmt.Errorf("%w: version %d of rule %s does not exist", ngmodels.ErrAlertRuleNotFound, query.Version, query.UID)
+		}
+		result = &ngmodels.AlertRule{
+			OrgID:           version.RuleOrgID,
+			UID:             version.RuleUID,
+			NamespaceUID:    version.RuleNamespaceUID,
+			RuleGroup:       version.RuleGroup,
+			RuleGroupIndex:  version.RuleGroupIndex,
+			Version:         version.Version,
+			Updated:         version.Created,
+			Title:           version.Title,
+			Condition:       version.Condition,
+			Data:            version.Data,
+			IntervalSeconds: version.IntervalSeconds,
+			NoDataState:     version.NoDataState,
+			ExecErrState:    version.ExecErrState,
+			For:             version.For,
+			KeepFiringFor:   version.KeepFiringFor,
+			Annotations:     version.Annotations,
+			Labels:          version.Labels,
+			IsPaused:        version.IsPaused,
+			DependsOn:       version.DependsOn,
+			Record:          version.Record,
+		}
+		return nil
+	})
+	return result, err
+}
+
 // GetAlertRulesGroupByRuleUID is a handler for retrieving a group of alert rules from that database by UID and organisation ID of one of rules that belong to that group.
 func (st DBstore) GetAlertRulesGroupByRuleUID(ctx context.Context, query *ngmodels.GetAlertRulesGroupByRuleUIDQuery) (result []*ngmodels.AlertRule, err error) {
 	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -153,8 +192,11 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				NoDataState:      r.NoDataState,
 				ExecErrState:     r.ExecErrState,
 				For:              r.For,
+				KeepFiringFor:    r.KeepFiringFor,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				DependsOn:        r.DependsOn,
+				Record:           r.Record,
 			})
 		}
 		if len(newRules) > 0 {
@@ -226,8 +268,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				NoDataState:      r.New.NoDataState,
 				ExecErrState:     r.New.ExecErrState,
 				For:              r.New.For,
+				KeepFiringFor:    r.New.KeepFiringFor,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				DependsOn:        r.New.DependsOn,
+				Record:           r.New.Record,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -477,7 +522,7 @@ func (st DBstore) GetNamespaceByTitle(ctx context.Context, namespace string, org
 
 // GetNamespaceByUID is a handler for retrieving a namespace by its UID. Alerting rules follow a Grafana folder-like structure which we call namespaces.
 func (st DBstore) GetNamespaceByUID(ctx context.Context, uid string, orgID int64, user *user.SignedInUser) (*folder.Folder, error) {
-	folder, err := st.FolderService.Get(ctx, &folder.GetFolderQuery{OrgID: orgID, Title: &uid, SignedInUser: user})
+	folder, err := st.FolderService.Get(ctx, &folder.GetFolderQuery{OrgID: orgID, UID: &uid, SignedInUser: user})
 	if err != nil {
 		return nil, err
 	}
@@ -674,5 +719,17 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if err := alertRule.ValidateDependsOn(); err != nil {
+		return err
+	}
+
+	if err := alertRule.ValidateRecord(); err != nil {
+		return err
+	}
 	return nil
 }
@@ -81,6 +81,82 @@ func TestIntegrationUpdateAlertRules(t *testing.T) {
 
 		require.ErrorIs(t, err, ErrOptimisticLock)
 	})
+
+	t.Run("should store recording rule", func(t *testing.T) {
+		rule := createRule(t, store, generator)
+		require.Nil(t, rule.Record)
+
+		newRule := models.CopyRule(rule)
+		models.WithRecord("job:up:sum")(newRule)
+		err := store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+			Existing: rule,
+			New:      *newRule,
+		},
+		})
+		require.NoError(t, err)
+
+		dbrule, err := store.GetAlertRuleByUID(context.Background(), &models.GetAlertRuleByUIDQuery{OrgID: rule.OrgID, UID: rule.UID})
+		require.NoError(t, err)
+		require.Equal(t, newRule.Record, dbrule.Record)
+	})
+}
+
+func TestIntegrationGetAlertRuleVersion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	cfg := setting.NewCfg()
+	cfg.UnifiedAlerting = setting.UnifiedAlertingSettings{BaseInterval: time.Duration(rand.Int63n(100)+1) * time.Second}
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		Cfg:           cfg.UnifiedAlerting,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        &logtest.Fake{},
+	}
+	generator := models.AlertRuleGen(withIntervalMatching(store.Cfg.BaseInterval), models.WithUniqueID())
+
+	rule := createRule(t, store, generator)
+	newRule := models.CopyRule(rule)
+	newRule.Title = util.GenerateShortUID()
+	err := store.UpdateAlertRules(context.Background(), []models.UpdateRule{{
+		Existing: rule,
+		New:      *newRule,
+	},
+	})
+	require.NoError(t, err)
+
+	t.Run("should return the rule at the version", func(t *testing.T) {
+		result, err := store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{
+			UID:     rule.UID,
+			OrgID:   rule.OrgID,
+			Version: rule.Version + 1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, rule.UID, result.UID)
+		require.Equal(t, rule.Version+1, result.Version)
+		require.Equal(t, newRule.Title, result.Title)
+		require.Equal(t, newRule.Condition, result.Condition)
+		require.Equal(t, newRule.IntervalSeconds, result.IntervalSeconds)
+	})
+
+	t.Run("should return ErrAlertRuleNotFound if version does not exist", func(t *testing.T) {
+		_, err := store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{
+			UID:     rule.UID,
+			OrgID:   rule.OrgID,
+			Version: rule.Version + 2,
+		})
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+
+	t.Run("should return ErrAlertRuleNotFound if rule belongs to another organization", func(t *testing.T) {
+		_, err := store.GetAlertRuleVersion(context.Background(), &models.GetAlertRuleVersionQuery{
+			UID:     rule.UID,
+			OrgID:   rule.OrgID + 1,
+			Version: rule.Version + 1,
+		})
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
 }
 
 func TestIntegrationUpdateAlertRulesWithUniqueConstraintViolation(t *testing.T) {
@@ -482,6 +558,41 @@ func TestIntegration_DeleteInFolder(t *testing.T) {
 	require.Equal(t, int64(0), c)
 }
 
+func TestIntegrationGetNamespaceByUID(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore := db.InitTestDB(t)
+	cfg := setting.NewCfg()
+	store := &DBstore{
+		SQLStore:      sqlStore,
+		FolderService: setupFolderService(t, sqlStore, cfg),
+		Logger:        log.New("test-dbstore"),
+	}
+	u := &user.SignedInUser{
+		UserID:         1,
+		OrgID:          1,
+		OrgRole:        org.RoleAdmin,
+		IsGrafanaAdmin: true,
+	}
+	uid := util.GenerateShortUID()
+	title := "folder-" + util.GenerateShortUID()
+	createFolder(t, store, uid, title, 1)
+
+	t.Run("should return the folder with the UID", func(t *testing.T) {
+		actual, err := store.GetNamespaceByUID(context.Background(), uid, 1, u)
+		require.NoError(t, err)
+		require.Equal(t, uid, actual.UID)
+		require.Equal(t, title, actual.Title)
+	})
+
+	t.Run("should not find the folder by its title", func(t *testing.T) {
+		_, err := store.GetNamespaceByUID(context.Background(), title, 1, u)
+		require.Error(t, err)
+	})
+}
+
 func createRule(t *testing.T, store *DBstore, generate func() *models.AlertRule) *models.AlertRule {
 	t.Helper()
 	if generate == nil {
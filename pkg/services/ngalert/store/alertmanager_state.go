@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// AlertmanagerStateStore is a store of the Alertmanager state that is shared between replicas.
+type AlertmanagerStateStore interface {
+	// GetAlertmanagerState returns the state with the key or models.ErrAlertmanagerStateNotFound.
+	GetAlertmanagerState(ctx context.Context, key string) (*models.AlertmanagerState, error)
+	// SaveAlertmanagerState saves the state if it was not changed since it was read with the version of the state,
+	// and increments the version. A state with version 0 is created. It returns models.ErrAlertmanagerStateVersionConflict
+	// if the state was changed or created concurrently.
+	SaveAlertmanagerState(ctx context.Context, state *models.AlertmanagerState) error
+	// HeartbeatAlertmanagerPeer records that the peer with the name is alive at the time.
+	HeartbeatAlertmanagerPeer(ctx context.Context, name string, t time.Time) error
+	// GetAlertmanagerPeers returns the names of the peers whose last heartbeat is not older than the time, sorted by name.
+	GetAlertmanagerPeers(ctx context.Context, since time.Time) ([]string, error)
+	// DeleteAlertmanagerPeersBefore deletes the peers whose last heartbeat is older than the time.
+	DeleteAlertmanagerPeersBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (st DBstore) GetAlertmanagerState(ctx context.Context, key string) (*models.AlertmanagerState, error) {
+	result := &models.AlertmanagerState{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("state_key = ?", key).Get(result)
+		if err != nil {
+			return fmt.Errorf("failed to get alertmanager state: %w", err)
+		}
+		if !exists {
+			return models.ErrAlertmanagerStateNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (st DBstore) SaveAlertmanagerState(ctx context.Context, state *models.AlertmanagerState) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		updated := &models.AlertmanagerState{
+			Key:       state.Key,
+			State:     state.State,
+			Version:   state.Version + 1,
+			UpdatedAt: TimeNow().Unix(),
+		}
+		if state.Version == 0 {
+			if _, err := sess.Insert(updated); err != nil {
+				if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+					return models.ErrAlertmanagerStateVersionConflict
+				}
+				return fmt.Errorf("failed to insert alertmanager state: %w", err)
+			}
+		} else {
+			rows, err := sess.Where("state_key = ? AND version = ?", state.Key, state.Version).
+				Cols("state", "version", "updated_at").
+				Update(updated)
+			if err != nil {
+				return fmt.Errorf("failed to update alertmanager state: %w", err)
+			}
+			if rows == 0 {
+				return models.ErrAlertmanagerStateVersionConflict
+			}
+		}
+		state.Version = updated.Version
+		state.UpdatedAt = updated.UpdatedAt
+		return nil
+	})
+}
+
+func (st DBstore) HeartbeatAlertmanagerPeer(ctx context.Context, name string, t time.Time) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		peer := &models.AlertmanagerPeer{Name: name, HeartbeatAt: t.Unix()}
+		rows, err := sess.Where("name = ?", name).Cols("heartbeat_at").Update(peer)
+		if err != nil {
+			return fmt.Errorf("failed to update alertmanager peer: %w", err)
+		}
+		if rows > 0 {
+			return nil
+		}
+		if _, err := sess.Insert(peer); err != nil {
+			// MySQL does not count rows whose values did not change as affected, so the peer might exist already.
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to insert alertmanager peer: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetAlertmanagerPeers(ctx context.Context, since time.Time) ([]string, error) {
+	var result []string
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(&models.AlertmanagerPeer{}).Where("heartbeat_at >= ?", since.Unix()).Asc("name").Cols("name").Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alertmanager peers: %w", err)
+	}
+	return result, nil
+}
+
+func (st DBstore) DeleteAlertmanagerPeersBefore(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("heartbeat_at < ?", before.Unix()).Delete(&models.AlertmanagerPeer{})
+		if err != nil {
+			return fmt.Errorf("failed to delete alertmanager peers: %w", err)
+		}
+		n = rows
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIntegrationAlertmanagerState(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := &DBstore{
+		SQLStore: db.InitTestDB(t),
+		Logger:   &logtest.Fake{},
+	}
+	ctx := context.Background()
+
+	t.Run("should return not found for a state that was not saved", func(t *testing.T) {
+		_, err := store.GetAlertmanagerState(ctx, "silences:1")
+		require.ErrorIs(t, err, models.ErrAlertmanagerStateNotFound)
+	})
+
+	t.Run("should create and update the state with optimistic versioning", func(t *testing.T) {
+		state := &models.AlertmanagerState{Key: "notificationlog:1", State: "a"}
+		require.NoError(t, store.SaveAlertmanagerState(ctx, state))
+		require.Equal(t, int64(1), state.Version)
+
+		// Another replica that did not read the state yet cannot create it again.
+		err := store.SaveAlertmanagerState(ctx, &models.AlertmanagerState{Key: "notificationlog:1", State: "b"})
+		require.ErrorIs(t, err, models.ErrAlertmanagerStateVersionConflict)
+
+		read, err := store.GetAlertmanagerState(ctx, "notificationlog:1")
+		require.NoError(t, err)
+		require.Equal(t, "a", read.State)
+		require.Equal(t, int64(1), read.Version)
+
+		read.State = "ab"
+		require.NoError(t, store.SaveAlertmanagerState(ctx, read))
+		require.Equal(t, int64(2), read.Version)
+
+		// The first replica has not read the latest version.
+		state.State = "ac"
+		err = store.SaveAlertmanagerState(ctx, state)
+		require.ErrorIs(t, err, models.ErrAlertmanagerStateVersionConflict)
+
+		read, err = store.GetAlertmanagerState(ctx, "notificationlog:1")
+		require.NoError(t, err)
+		require.Equal(t, "ab", read.State)
+	})
+
+	t.Run("should return the peers with a recent heartbeat", func(t *testing.T) {
+		require.NoError(t, store.HeartbeatAlertmanagerPeer(ctx, "peer-b", time.Unix(100, 0)))
+		require.NoError(t, store.HeartbeatAlertmanagerPeer(ctx, "peer-a", time.Unix(100, 0)))
+		require.NoError(t, store.HeartbeatAlertmanagerPeer(ctx, "peer-c", time.Unix(10, 0)))
+		require.NoError(t, store.HeartbeatAlertmanagerPeer(ctx, "peer-a", time.Unix(100, 0)))
+
+		peers, err := store.GetAlertmanagerPeers(ctx, time.Unix(50, 0))
+		require.NoError(t, err)
+		require.Equal(t, []string{"peer-a", "peer-b"}, peers)
+
+		require.NoError(t, store.HeartbeatAlertmanagerPeer(ctx, "peer-c", time.Unix(60, 0)))
+		peers, err = store.GetAlertmanagerPeers(ctx, time.Unix(50, 0))
+		require.NoError(t, err)
+		require.Equal(t, []string{"peer-a", "peer-b", "peer-c"}, peers)
+
+		n, err := store.DeleteAlertmanagerPeersBefore(ctx, time.Unix(70, 0))
+		require.NoError(t, err)
+		require.Equal(t, int64(1), n)
+		peers, err = store.GetAlertmanagerPeers(ctx, time.Unix(0, 0))
+		require.NoError(t, err)
+		require.Equal(t, []string{"peer-a", "peer-b"}, peers)
+	})
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// MaintenanceWindowStore is a store of maintenance windows.
+type MaintenanceWindowStore interface {
+	// ListMaintenanceWindows returns all maintenance windows of the organization sorted by title.
+	ListMaintenanceWindows(ctx context.Context, query *models.ListMaintenanceWindowsQuery) ([]*models.MaintenanceWindow, error)
+	// GetAllMaintenanceWindows returns the maintenance windows of all organizations.
+	GetAllMaintenanceWindows(ctx context.Context) ([]*models.MaintenanceWindow, error)
+	// GetMaintenanceWindow returns the maintenance window with the UID. It returns models.ErrMaintenanceWindowNotFound
+	// if the window does not exist.
+	GetMaintenanceWindow(ctx context.Context, query *models.GetMaintenanceWindowQuery) (*models.MaintenanceWindow, error)
+	// InsertMaintenanceWindow saves a new maintenance window and sets its ID.
+	InsertMaintenanceWindow(ctx context.Context, mw *models.MaintenanceWindow) error
+	// UpdateMaintenanceWindow updates the maintenance window with the same UID.
+	// It returns models.ErrMaintenanceWindowNotFound if the window does not exist.
+	UpdateMaintenanceWindow(ctx context.Context, mw *models.MaintenanceWindow) error
+	// DeleteMaintenanceWindow deletes the maintenance window with the UID. It does nothing if the window does not exist.
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error
+}
+
+func (st DBstore) ListMaintenanceWindows(ctx context.Context, query *models.ListMaintenanceWindowsQuery) ([]*models.MaintenanceWindow, error) {
+	var result []*models.MaintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Where("org_id = ?", query.OrgID).Asc("title", "id").Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	return result, nil
+}
+
+func (st DBstore) GetAllMaintenanceWindows(ctx context.Context) ([]*models.MaintenanceWindow, error) {
+	var result []*models.MaintenanceWindow
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Asc("org_id", "id").Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+	return result, nil
+}
+
+func (st DBstore) GetMaintenanceWindow(ctx context.Context, query *models.GetMaintenanceWindowQuery) (*models.MaintenanceWindow, error) {
+	result := &models.MaintenanceWindow{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND uid = ?", query.OrgID, query.UID).Get(result)
+		if err != nil {
+			return fmt.Errorf("failed to get maintenance window: %w", err)
+		}
+		if !exists {
+			return models.ErrMaintenanceWindowNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (st DBstore) InsertMaintenanceWindow(ctx context.Context, mw *models.MaintenanceWindow) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(mw); err != nil {
+			if st.SQLStore.GetDialect().IsUniqueConstraintViolation(err) {
+				return fmt.Errorf("%w: a maintenance window with UID '%s' already exists", models.ErrMaintenanceWindowFailedValidation, mw.UID)
+			}
+			return fmt.Errorf("failed to insert maintenance window: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) UpdateMaintenanceWindow(ctx context.Context, mw *models.MaintenanceWindow) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		existing := &models.MaintenanceWindow{}
+		exists, err := sess.Where("org_id = ? AND uid = ?", mw.OrgID, mw.UID).Get(existing)
+		if err != nil {
+			return fmt.Errorf("failed to get maintenance window: %w", err)
+		}
+		if !exists {
+			return models.ErrMaintenanceWindowNotFound
+		}
+		mw.ID = existing.ID
+		if _, err := sess.ID(mw.ID).AllCols().Update(mw); err != nil {
+			return fmt.Errorf("failed to update maintenance window: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteMaintenanceWindow(ctx context.Context, orgID int64, uid string) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Where("org_id = ? AND uid = ?", orgID, uid).Delete(&models.MaintenanceWindow{})
+		if err != nil {
+			return fmt.Errorf("failed to delete maintenance window: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIntegrationMaintenanceWindows(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := &DBstore{
+		SQLStore: db.InitTestDB(t),
+		Logger:   &logtest.Fake{},
+	}
+	ctx := context.Background()
+	m, err := labels.NewMatcher(labels.MatchRegexp, "service", "db|cache")
+	require.NoError(t, err)
+	mw := &models.MaintenanceWindow{
+		OrgID:    1,
+		UID:      "backup",
+		Title:    "Backup",
+		Schedule: "0 2 * * *",
+		Timezone: "Europe/Berlin",
+		Duration: 2 * time.Hour,
+		Matchers: labels.Matchers{m},
+		Updated:  time.Unix(1000, 0).UTC(),
+	}
+
+	t.Run("should insert and get a window", func(t *testing.T) {
+		require.NoError(t, store.InsertMaintenanceWindow(ctx, mw))
+		require.NotZero(t, mw.ID)
+
+		stored, err := store.GetMaintenanceWindow(ctx, &models.GetMaintenanceWindowQuery{OrgID: 1, UID: "backup"})
+		require.NoError(t, err)
+		require.Equal(t, mw.Title, stored.Title)
+		require.Equal(t, mw.Duration, stored.Duration)
+		require.Len(t, stored.Matchers, 1)
+		require.Equal(t, m.String(), stored.Matchers[0].String())
+		require.True(t, stored.Matchers[0].Matches("cache"))
+	})
+
+	t.Run("should fail to insert a window with the same UID", func(t *testing.T) {
+		duplicate := *mw
+		duplicate.ID = 0
+		err := store.InsertMaintenanceWindow(ctx, &duplicate)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowFailedValidation)
+	})
+
+	t.Run("should not get a window of another organization", func(t *testing.T) {
+		_, err := store.GetMaintenanceWindow(ctx, &models.GetMaintenanceWindowQuery{OrgID: 2, UID: "backup"})
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+		windows, err := store.ListMaintenanceWindows(ctx, &models.ListMaintenanceWindowsQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Empty(t, windows)
+	})
+
+	t.Run("should update a window", func(t *testing.T) {
+		updated := *mw
+		updated.Title = "Nightly backup"
+		updated.Matchers = nil
+		updated.FolderUID = "folder"
+		require.NoError(t, store.UpdateMaintenanceWindow(ctx, &updated))
+
+		windows, err := store.ListMaintenanceWindows(ctx, &models.ListMaintenanceWindowsQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, "Nightly backup", windows[0].Title)
+		require.Equal(t, "folder", windows[0].FolderUID)
+		require.Empty(t, windows[0].Matchers)
+	})
+
+	t.Run("should fail to update a window that does not exist", func(t *testing.T) {
+		missing := *mw
+		missing.UID = "missing"
+		err := store.UpdateMaintenanceWindow(ctx, &missing)
+		require.ErrorIs(t, err, models.ErrMaintenanceWindowNotFound)
+	})
+
+	t.Run("should delete a window", func(t *testing.T) {
+		require.NoError(t, store.DeleteMaintenanceWindow(ctx, 1, "backup"))
+		windows, err := store.GetAllMaintenanceWindows(ctx)
+		require.NoError(t, err)
+		require.Empty(t, windows)
+	})
+}
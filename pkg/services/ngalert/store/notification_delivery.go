@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// NotificationDeliveryStore is a store of notification delivery attempts.
+type NotificationDeliveryStore interface {
+	// SaveNotificationDelivery saves the delivery attempt and sets its ID.
+	SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	// FindNotificationDeliveries returns the delivery attempts that match the query, the most recent first.
+	FindNotificationDeliveries(ctx context.Context, query models.NotificationDeliveryQuery) ([]*models.NotificationDelivery, error)
+	// DeleteNotificationDeliveriesBefore deletes the delivery attempts that are older than the time.
+	// It returns the number of deleted attempts.
+	DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (st DBstore) SaveNotificationDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(delivery); err != nil {
+			return fmt.Errorf("failed to save notification delivery: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) FindNotificationDeliveries(ctx context.Context, query models.NotificationDeliveryQuery) ([]*models.NotificationDelivery, error) {
+	var result []*models.NotificationDelivery
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(&models.NotificationDelivery{}).Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if !query.From.IsZero() {
+			q = q.And("timestamp_nano >= ?", query.From.UnixNano())
+		}
+		q = q.Desc("timestamp_nano", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification deliveries: %w", err)
+	}
+	return result, nil
+}
+
+func (st DBstore) DeleteNotificationDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("timestamp_nano < ?", before.UnixNano()).Delete(&models.NotificationDelivery{})
+		if err != nil {
+			return fmt.Errorf("failed to delete notification deliveries: %w", err)
+		}
+		n = rows
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
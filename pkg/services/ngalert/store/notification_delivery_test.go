@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIntegrationNotificationDelivery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := &DBstore{
+		SQLStore: db.InitTestDB(t),
+		Logger:   &logtest.Fake{},
+	}
+	ctx := context.Background()
+	at := func(sec int64) time.Time {
+		return time.Unix(sec, 0)
+	}
+	delivery := func(orgID int64, receiver string, sec int64) *models.NotificationDelivery {
+		return &models.NotificationDelivery{
+			OrgID:             orgID,
+			Receiver:          receiver,
+			Integration:       "email",
+			AlertFingerprints: []string{"a", "b"},
+			Status:            models.NotificationDeliverySuccess,
+			TimestampNano:     at(sec).UnixNano(),
+		}
+	}
+	timestamps := func(deliveries []*models.NotificationDelivery) []int64 {
+		result := make([]int64, 0, len(deliveries))
+		for _, d := range deliveries {
+			result = append(result, d.TimestampNano/int64(time.Second))
+		}
+		return result
+	}
+
+	for _, d := range []*models.NotificationDelivery{
+		delivery(1, "ops", 10),
+		delivery(1, "ops", 20),
+		delivery(1, "dev", 30),
+		delivery(2, "ops", 40),
+	} {
+		require.NoError(t, store.SaveNotificationDelivery(ctx, d))
+		require.NotZero(t, d.ID)
+	}
+
+	testCases := []struct {
+		name     string
+		query    models.NotificationDeliveryQuery
+		expected []int64
+	}{
+		{
+			name:     "all deliveries of the organization",
+			query:    models.NotificationDeliveryQuery{OrgID: 1},
+			expected: []int64{30, 20, 10},
+		},
+		{
+			name:     "deliveries of a contact point",
+			query:    models.NotificationDeliveryQuery{OrgID: 1, Receiver: "ops"},
+			expected: []int64{20, 10},
+		},
+		{
+			name:     "deliveries since a time",
+			query:    models.NotificationDeliveryQuery{OrgID: 1, From: at(20)},
+			expected: []int64{30, 20},
+		},
+		{
+			name:     "limit returns the most recent deliveries",
+			query:    models.NotificationDeliveryQuery{OrgID: 1, Limit: 1},
+			expected: []int64{30},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := store.FindNotificationDeliveries(ctx, tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, timestamps(result))
+		})
+	}
+
+	t.Run("should store the alert fingerprints", func(t *testing.T) {
+		result, err := store.FindNotificationDeliveries(ctx, models.NotificationDeliveryQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		require.Equal(t, []string{"a", "b"}, result[0].AlertFingerprints)
+	})
+
+	t.Run("should delete deliveries older than the time", func(t *testing.T) {
+		n, err := store.DeleteNotificationDeliveriesBefore(ctx, at(25))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), n)
+
+		result, err := store.FindNotificationDeliveries(ctx, models.NotificationDeliveryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []int64{30}, timestamps(result))
+	})
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// StateHistoryStore is a store of alert state history.
+type StateHistoryStore interface {
+	// SaveStateHistory saves the entries and their labels, and sets the IDs of the entries.
+	SaveStateHistory(ctx context.Context, entries []*models.StateHistoryEntry) error
+	// FindStateHistory returns the entries that match the query sorted by time. If the query has a limit,
+	// the most recent entries are returned.
+	FindStateHistory(ctx context.Context, query models.HistoryQuery) ([]*models.StateHistoryEntry, error)
+	// DeleteStateHistoryBefore deletes the entries that are older than the time. It returns the number of deleted entries.
+	DeleteStateHistoryBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (st DBstore) SaveStateHistory(ctx context.Context, entries []*models.StateHistoryEntry) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		for _, entry := range entries {
+			if _, err := sess.Insert(entry); err != nil {
+				return fmt.Errorf("failed to save state history entry: %w", err)
+			}
+			if len(entry.Labels) == 0 {
+				continue
+			}
+			labels := make([]*models.StateHistoryLabel, 0, len(entry.Labels))
+			for k, v := range entry.Labels {
+				labels = append(labels, &models.StateHistoryLabel{
+					HistoryID: entry.ID,
+					Key:       k,
+					Value:     v,
+				})
+			}
+			if _, err := sess.Insert(&labels); err != nil {
+				return fmt.Errorf("failed to save state history labels: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (st DBstore) FindStateHistory(ctx context.Context, query models.HistoryQuery) ([]*models.StateHistoryEntry, error) {
+	var result []*models.StateHistoryEntry
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(&models.StateHistoryEntry{}).Where("org_id = ?", query.OrgID)
+		if query.RuleUID != "" {
+			q = q.And("rule_uid = ?", query.RuleUID)
+		}
+		if query.DashboardUID != "" {
+			q = q.And("dashboard_uid = ?", query.DashboardUID)
+		}
+		if query.PanelID != 0 {
+			q = q.And("panel_id = ?", query.PanelID)
+		}
+		if !query.From.IsZero() {
+			q = q.And("timestamp_nano >= ?", query.From.UnixNano())
+		}
+		if !query.To.IsZero() {
+			q = q.And("timestamp_nano <= ?", query.To.UnixNano())
+		}
+		keys := make([]string, 0, len(query.Labels))
+		for k := range query.Labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			q = q.And("EXISTS (SELECT 1 FROM alert_state_history_label AS l WHERE l.history_id = alert_state_history.id AND l.label_key = ? AND l.label_value = ?)", k, query.Labels[k])
+		}
+		q = q.Desc("timestamp_nano", "id")
+		if query.Limit > 0 {
+			q = q.Limit(query.Limit)
+		}
+		return q.Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find state history: %w", err)
+	}
+	// the most recent entries are selected first, so that the limit drops the oldest ones. Return them in chronological order.
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
+
+func (st DBstore) DeleteStateHistoryBefore(ctx context.Context, before time.Time) (int64, error) {
+	var n int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		_, err := sess.Exec("DELETE FROM alert_state_history_label WHERE history_id IN (SELECT id FROM alert_state_history WHERE timestamp_nano < ?)", before.UnixNano())
+		if err != nil {
+			return fmt.Errorf("failed to delete state history labels: %w", err)
+		}
+		rows, err := sess.Where("timestamp_nano < ?", before.UnixNano()).Delete(&models.StateHistoryEntry{})
+		if err != nil {
+			return fmt.Errorf("failed to delete state history: %w", err)
+		}
+		n = rows
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestIntegrationStateHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	store := &DBstore{
+		SQLStore: db.InitTestDB(t),
+		Logger:   &logtest.Fake{},
+	}
+	ctx := context.Background()
+	at := func(sec int64) time.Time {
+		return time.Unix(sec, 0)
+	}
+	entry := func(orgID int64, ruleUID string, sec int64, labels map[string]string) *models.StateHistoryEntry {
+		return &models.StateHistoryEntry{
+			OrgID:         orgID,
+			RuleUID:       ruleUID,
+			Labels:        labels,
+			PreviousState: "Normal",
+			CurrentState:  "Alerting",
+			TimestampNano: at(sec).UnixNano(),
+		}
+	}
+	timestamps := func(entries []*models.StateHistoryEntry) []int64 {
+		result := make([]int64, 0, len(entries))
+		for _, e := range entries {
+			result = append(result, e.TimestampNano/int64(time.Second))
+		}
+		return result
+	}
+
+	err := store.SaveStateHistory(ctx, []*models.StateHistoryEntry{
+		entry(1, "a", 10, map[string]string{"instance": "1", "job": "grafana"}),
+		entry(1, "a", 20, map[string]string{"instance": "2", "job": "grafana"}),
+		entry(1, "b", 30, map[string]string{"instance": "1", "job": "loki"}),
+		entry(1, "b", 40, nil),
+		entry(2, "a", 50, map[string]string{"instance": "1", "job": "grafana"}),
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		query    models.HistoryQuery
+		expected []int64
+	}{
+		{
+			name:     "all entries of the organization",
+			query:    models.HistoryQuery{OrgID: 1},
+			expected: []int64{10, 20, 30, 40},
+		},
+		{
+			name:     "entries of the rule",
+			query:    models.HistoryQuery{OrgID: 1, RuleUID: "b"},
+			expected: []int64{30, 40},
+		},
+		{
+			name:     "entries in the time range",
+			query:    models.HistoryQuery{OrgID: 1, From: at(20), To: at(30)},
+			expected: []int64{20, 30},
+		},
+		{
+			name:     "entries with the label",
+			query:    models.HistoryQuery{OrgID: 1, Labels: map[string]string{"instance": "1"}},
+			expected: []int64{10, 30},
+		},
+		{
+			name:     "entries with all labels",
+			query:    models.HistoryQuery{OrgID: 1, Labels: map[string]string{"instance": "1", "job": "loki"}},
+			expected: []int64{30},
+		},
+		{
+			name:     "most recent entries if limited",
+			query:    models.HistoryQuery{OrgID: 1, Limit: 2},
+			expected: []int64{30, 40},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := store.FindStateHistory(ctx, tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, timestamps(entries))
+		})
+	}
+
+	t.Run("should return the labels of entries", func(t *testing.T) {
+		entries, err := store.FindStateHistory(ctx, models.HistoryQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, map[string]string{"instance": "1", "job": "grafana"}, entries[0].Labels)
+	})
+
+	t.Run("should delete entries and their labels before the time", func(t *testing.T) {
+		deleted, err := store.DeleteStateHistoryBefore(ctx, at(30))
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		entries, err := store.FindStateHistory(ctx, models.HistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []int64{30, 40}, timestamps(entries))
+
+		var labels int64
+		err = store.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+			labels, err = sess.Count(&models.StateHistoryLabel{})
+			return err
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(4), labels)
+	})
+}
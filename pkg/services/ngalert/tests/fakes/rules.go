@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/folder"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/user"
@@ -20,7 +21,9 @@ type RuleStore struct {
 	t   *testing.T
 	mtx sync.Mutex
 	// OrgID -> RuleGroup -> Namespace -> Rules
-	Rules       map[int64][]*models.AlertRule
+	Rules map[int64][]*models.AlertRule
+	// OrgID -> previous versions of rules. Current versions are in Rules.
+	Versions    map[int64][]*models.AlertRule
 	Hook        func(cmd interface{}) error // use Hook if you need to intercept some query and return an error
 	RecordedOps []interface{}
 	Folders     map[int64][]*folder.Folder
@@ -33,8 +36,9 @@ type GenericRecordedQuery struct {
 
 func NewRuleStore(t *testing.T) *RuleStore {
 	return &RuleStore{
-		t:     t,
-		Rules: map[int64][]*models.AlertRule{},
+		t:        t,
+		Rules:    map[int64][]*models.AlertRule{},
+		Versions: map[int64][]*models.AlertRule{},
 		Hook: func(interface{}) error {
 			return nil
 		},
@@ -140,6 +144,23 @@ func (f *RuleStore) GetAlertRuleByUID(_ context.Context, q *models.GetAlertRuleB
 	return nil, nil
 }
 
+func (f *RuleStore) GetAlertRuleVersion(_ context.Context, q *models.GetAlertRuleVersionQuery) (*models.AlertRule, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.RecordedOps = append(f.RecordedOps, *q)
+	if err := f.Hook(*q); err != nil {
+		return nil, err
+	}
+	for _, rules := range [][]*models.AlertRule{f.Rules[q.OrgID], f.Versions[q.OrgID]} {
+		for _, rule := range rules {
+			if rule.UID == q.UID && rule.Version == q.Version {
+				return rule, nil
+			}
+		}
+	}
+	return nil, models.ErrAlertRuleNotFound
+}
+
 func (f *RuleStore) GetAlertRulesGroupByRuleUID(_ context.Context, q *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error) {
 	f.mtx.Lock()
 	defer f.mtx.Unlock()
@@ -267,7 +288,7 @@ func (f *RuleStore) GetNamespaceByUID(_ context.Context, uid string, orgID int64
 			return folder, nil
 		}
 	}
-	return nil, fmt.Errorf("not found")
+	return nil, dashboards.ErrFolderNotFound
 }
 
 func (f *RuleStore) UpdateAlertRules(_ context.Context, q []models.UpdateRule) error {
@@ -0,0 +1,198 @@
+// Package writer writes the results of recording rules as metrics.
+package writer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const metricNameLabel = "__name__"
+
+var ErrNoTargetDatasource = errors.New("recording rule does not specify the target data source and no default one is configured")
+
+// DatasourceService is the part of datasources.DataSourceService that is needed to write to a data source.
+type DatasourceService interface {
+	GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error)
+	DecryptedBasicAuthPassword(ctx context.Context, ds *datasources.DataSource) (string, error)
+	CustomHeaders(ctx context.Context, ds *datasources.DataSource) (map[string]string, error)
+}
+
+// PrometheusWriter writes the results of recording rules to Prometheus-compatible data sources
+// by using the Prometheus remote write protocol.
+type PrometheusWriter struct {
+	datasources         DatasourceService
+	client              *http.Client
+	targetDatasourceUID string
+	remoteWritePath     string
+	log                 log.Logger
+}
+
+func NewPrometheusWriter(cfg setting.UnifiedAlertingRecordingRuleSettings, datasources DatasourceService, l log.Logger) *PrometheusWriter {
+	return &PrometheusWriter{
+		datasources:         datasources,
+		client:              &http.Client{Timeout: cfg.RemoteWriteTimeout},
+		targetDatasourceUID: cfg.TargetDatasourceUID,
+		remoteWritePath:     cfg.RemoteWritePath,
+		log:                 l,
+	}
+}
+
+// Write writes the frames as series of the metric of the recording rule. The series get the labels of the rule in
+// addition to their own labels. Samples of frames without a time field get the timestamp t.
+func (w *PrometheusWriter) Write(ctx context.Context, rule *models.AlertRule, t time.Time, frames data.Frames) error {
+	if !rule.IsRecordingRule() {
+		return fmt.Errorf("rule %s is not a recording rule", rule.UID)
+	}
+	series := SeriesFromFrames(rule.Record.Metric, rule.Labels, t, frames)
+	if len(series) == 0 {
+		return nil
+	}
+	b, err := remotewrite.TimeSeriesToBytes(series)
+	if err != nil {
+		return fmt.Errorf("failed to encode series: %w", err)
+	}
+
+	uid := rule.Record.TargetDatasourceUID
+	if uid == "" {
+		uid = w.targetDatasourceUID
+	}
+	if uid == "" {
+		return ErrNoTargetDatasource
+	}
+	ds, err := w.datasources.GetDataSource(ctx, &datasources.GetDataSourceQuery{UID: uid, OrgID: rule.OrgID})
+	if err != nil {
+		return fmt.Errorf("failed to get target data source %s: %w", uid, err)
+	}
+	return w.send(ctx, ds, b)
+}
+
+func (w *PrometheusWriter) send(ctx context.Context, ds *datasources.DataSource, b []byte) error {
+	if ds.Type != datasources.DS_PROMETHEUS {
+		return fmt.Errorf("target data source %s is not a Prometheus data source", ds.UID)
+	}
+	u, err := url.Parse(ds.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse URL of target data source %s: %w", ds.UID, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.JoinPath(w.remoteWritePath).String(), bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+
+	headers, err := w.datasources.CustomHeaders(ctx, ds)
+	if err != nil {
+		return fmt.Errorf("failed to get headers of target data source %s: %w", ds.UID, err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if ds.BasicAuth {
+		password, err := w.datasources.DecryptedBasicAuthPassword(ctx, ds)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt basic auth password of target data source %s: %w", ds.UID, err)
+		}
+		req.SetBasicAuth(ds.BasicAuthUser, password)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote write request: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			w.log.Warn("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		byt, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("received a non-200 response to remote write request, status: %d, body: %s", resp.StatusCode, string(byt))
+	}
+	return nil
+}
+
+// SeriesFromFrames converts the numeric fields of the frames to series of the metric. The series get the extra labels
+// in addition to the labels of the fields. The extra labels take precedence. Frames with a time field are time series
+// and their samples get the timestamps of the time field. Samples of other frames get the timestamp t.
+// Null values are skipped.
+func SeriesFromFrames(metric string, extraLabels map[string]string, t time.Time, frames data.Frames) []prompb.TimeSeries {
+	var result []prompb.TimeSeries
+	for _, frame := range frames {
+		timeField := -1
+		for i, field := range frame.Fields {
+			if field.Type().Time() {
+				timeField = i
+				break
+			}
+		}
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+			samples := make([]prompb.Sample, 0, field.Len())
+			for i := 0; i < field.Len(); i++ {
+				v, err := field.NullableFloatAt(i)
+				if err != nil || v == nil {
+					continue
+				}
+				ts := t
+				if timeField >= 0 {
+					tv, ok := frame.Fields[timeField].ConcreteAt(i)
+					if !ok {
+						continue
+					}
+					ts = tv.(time.Time)
+				}
+				samples = append(samples, prompb.Sample{Value: *v, Timestamp: ts.UnixMilli()})
+			}
+			if len(samples) == 0 {
+				continue
+			}
+			result = append(result, prompb.TimeSeries{
+				Labels:  seriesLabels(metric, field.Labels, extraLabels),
+				Samples: samples,
+			})
+		}
+	}
+	return result
+}
+
+func seriesLabels(metric string, labels data.Labels, extraLabels map[string]string) []prompb.Label {
+	merged := make(map[string]string, len(labels)+len(extraLabels)+1)
+	for k, v := range labels {
+		merged[k] = v
+	}
+	for k, v := range extraLabels {
+		merged[k] = v
+	}
+	merged[metricNameLabel] = metric
+
+	result := make([]prompb.Label, 0, len(merged))
+	for k, v := range merged {
+		result = append(result, prompb.Label{Name: k, Value: v})
+	}
+	// Remote write requires the labels to be sorted by name.
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,168 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+type fakeDatasourceService struct {
+	datasources []*datasources.DataSource
+}
+
+func (f *fakeDatasourceService) GetDataSource(_ context.Context, query *datasources.GetDataSourceQuery) (*datasources.DataSource, error) {
+	for _, ds := range f.datasources {
+		if ds.UID == query.UID && ds.OrgID == query.OrgID {
+			return ds, nil
+		}
+	}
+	return nil, datasources.ErrDataSourceNotFound
+}
+
+func (f *fakeDatasourceService) DecryptedBasicAuthPassword(_ context.Context, _ *datasources.DataSource) (string, error) {
+	return "password", nil
+}
+
+func (f *fakeDatasourceService) CustomHeaders(_ context.Context, _ *datasources.DataSource) (map[string]string, error) {
+	return map[string]string{"X-Scope-OrgID": "tenant"}, nil
+}
+
+func TestSeriesFromFrames(t *testing.T) {
+	now := time.UnixMilli(1000000)
+
+	t.Run("numeric frames get the evaluation time", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("",
+				data.NewField("", data.Labels{"job": "api"}, []float64{3}),
+			),
+			data.NewFrame("",
+				data.NewField("", data.Labels{"job": "db"}, []*float64{nil}),
+			),
+		}
+		series := SeriesFromFrames("job:up:sum", map[string]string{"team": "a"}, now, frames)
+		require.Equal(t, []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "job:up:sum"},
+					{Name: "job", Value: "api"},
+					{Name: "team", Value: "a"},
+				},
+				Samples: []prompb.Sample{{Value: 3, Timestamp: now.UnixMilli()}},
+			},
+		}, series)
+	})
+
+	t.Run("time series frames keep their timestamps", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{time.UnixMilli(1000), time.UnixMilli(2000)}),
+				data.NewField("value", data.Labels{"job": "api", "team": "b"}, []int64{1, 2}),
+			),
+		}
+		series := SeriesFromFrames("job:up:sum", map[string]string{"team": "a"}, now, frames)
+		require.Equal(t, []prompb.TimeSeries{
+			{
+				Labels: []prompb.Label{
+					{Name: "__name__", Value: "job:up:sum"},
+					{Name: "job", Value: "api"},
+					{Name: "team", Value: "a"},
+				},
+				Samples: []prompb.Sample{{Value: 1, Timestamp: 1000}, {Value: 2, Timestamp: 2000}},
+			},
+		}, series)
+	})
+}
+
+func TestPrometheusWriter(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+
+	dsService := &fakeDatasourceService{datasources: []*datasources.DataSource{
+		{UID: "prom", OrgID: 1, Type: datasources.DS_PROMETHEUS, URL: srv.URL + "/prometheus", BasicAuth: true, BasicAuthUser: "user"},
+		{UID: "loki", OrgID: 1, Type: datasources.DS_LOKI, URL: srv.URL},
+	}}
+	cfg := setting.UnifiedAlertingRecordingRuleSettings{
+		TargetDatasourceUID: "prom",
+		RemoteWritePath:     "/api/v1/write",
+		RemoteWriteTimeout:  time.Second,
+	}
+	w := NewPrometheusWriter(cfg, dsService, log.NewNopLogger())
+	frames := data.Frames{data.NewFrame("", data.NewField("", nil, []float64{1}))}
+	rule := models.AlertRuleGen(models.WithOrgID(1), models.WithRecord("job:up:sum"), func(rule *models.AlertRule) {
+		rule.Labels = nil
+	})()
+
+	t.Run("writes series to the default data source", func(t *testing.T) {
+		err := w.Write(context.Background(), rule, time.UnixMilli(1000), frames)
+		require.NoError(t, err)
+
+		require.Equal(t, "/prometheus/api/v1/write", received.URL.Path)
+		require.Equal(t, "snappy", received.Header.Get("Content-Encoding"))
+		require.Equal(t, "application/x-protobuf", received.Header.Get("Content-Type"))
+		require.Equal(t, "tenant", received.Header.Get("X-Scope-OrgID"))
+		user, password, ok := received.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "password", password)
+
+		decoded, err := snappy.Decode(nil, body)
+		require.NoError(t, err)
+		var req prompb.WriteRequest
+		require.NoError(t, proto.Unmarshal(decoded, &req))
+		require.Equal(t, []prompb.TimeSeries{{
+			Labels:  []prompb.Label{{Name: "__name__", Value: "job:up:sum"}},
+			Samples: []prompb.Sample{{Value: 1, Timestamp: 1000}},
+		}}, req.Timeseries)
+	})
+
+	t.Run("fails if the target data source is not Prometheus", func(t *testing.T) {
+		r := models.CopyRule(rule)
+		r.Record.TargetDatasourceUID = "loki"
+		err := w.Write(context.Background(), r, time.UnixMilli(1000), frames)
+		require.ErrorContains(t, err, "not a Prometheus data source")
+	})
+
+	t.Run("fails if the target data source does not exist", func(t *testing.T) {
+		r := models.CopyRule(rule)
+		r.Record.TargetDatasourceUID = util.GenerateShortUID()
+		err := w.Write(context.Background(), r, time.UnixMilli(1000), frames)
+		require.ErrorIs(t, err, datasources.ErrDataSourceNotFound)
+	})
+
+	t.Run("fails if there is no target data source", func(t *testing.T) {
+		w := NewPrometheusWriter(setting.UnifiedAlertingRecordingRuleSettings{}, dsService, log.NewNopLogger())
+		err := w.Write(context.Background(), rule, time.UnixMilli(1000), frames)
+		require.ErrorIs(t, err, ErrNoTargetDatasource)
+	})
+
+	t.Run("fails if the data source responds with an error", func(t *testing.T) {
+		status = http.StatusBadRequest
+		t.Cleanup(func() { status = http.StatusNoContent })
+		err := w.Write(context.Background(), rule, time.UnixMilli(1000), frames)
+		require.ErrorContains(t, err, "status: 400")
+	})
+}
@@ -3,6 +3,7 @@ package alerting
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -33,6 +34,9 @@ const (
 	testFileCorrectProperties_t         = "./testdata/templates/correct-properties"
 	testFileCorrectPropertiesWithOrg_t  = "./testdata/templates/correct-properties-with-org"
 	testFileMultipleTs                  = "./testdata/templates/multiple-templates"
+	testFileCorrectProperties_mw        = "./testdata/maintenance_windows/correct-properties"
+	testFileCorrectPropertiesWithOrg_mw = "./testdata/maintenance_windows/correct-properties-with-org"
+	testFileMissingUID_mw               = "./testdata/maintenance_windows/missing-uid"
 )
 
 func TestConfigReader(t *testing.T) {
@@ -159,4 +163,27 @@ func TestConfigReader(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, file[0].Templates, 2)
 	})
+	t.Run("a maintenance windows file with correct properties should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectProperties_mw)
+		require.NoError(t, err)
+		require.Len(t, file[0].MaintenanceWindows, 1)
+		mw := file[0].MaintenanceWindows[0]
+		require.Equal(t, int64(1), mw.OrgID)
+		require.Equal(t, "nightly-backup", mw.MaintenanceWindow.UID)
+		require.Equal(t, "0 2 * * *", mw.MaintenanceWindow.Schedule)
+		require.Equal(t, 2*time.Hour, time.Duration(mw.MaintenanceWindow.Duration))
+		require.Len(t, mw.MaintenanceWindow.Matchers, 1)
+		require.Equal(t, []DeleteMaintenanceWindow{{OrgID: 1, UID: "old-window"}}, file[0].DeleteMaintenanceWindows)
+	})
+	t.Run("a maintenance windows file with correct properties and specific org should not error", func(t *testing.T) {
+		file, err := configReader.readConfig(ctx, testFileCorrectPropertiesWithOrg_mw)
+		require.NoError(t, err)
+		t.Run("when an organization is set it should not overwrite it with the default of 1", func(t *testing.T) {
+			require.Equal(t, int64(1337), file[0].MaintenanceWindows[0].OrgID)
+		})
+	})
+	t.Run("a maintenance windows file without uid should error", func(t *testing.T) {
+		_, err := configReader.readConfig(ctx, testFileMissingUID_mw)
+		require.ErrorContains(t, err, "maintenance window missing uid")
+	})
 }
@@ -61,18 +61,27 @@ func (ruleGroupV1 *AlertRuleGroupV1) MapToModel() (models.AlertRuleGroupWithFold
 }
 
 type AlertRuleV1 struct {
-	UID          values.StringValue    `json:"uid" yaml:"uid"`
-	Title        values.StringValue    `json:"title" yaml:"title"`
-	Condition    values.StringValue    `json:"condition" yaml:"condition"`
-	Data         []QueryV1             `json:"data" yaml:"data"`
-	DashboardUID values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
-	PanelID      values.Int64Value     `json:"panelId" yaml:"panelId"`
-	NoDataState  values.StringValue    `json:"noDataState" yaml:"noDataState"`
-	ExecErrState values.StringValue    `json:"execErrState" yaml:"execErrState"`
-	For          values.StringValue    `json:"for" yaml:"for"`
-	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
-	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
-	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	UID           values.StringValue    `json:"uid" yaml:"uid"`
+	Title         values.StringValue    `json:"title" yaml:"title"`
+	Condition     values.StringValue    `json:"condition" yaml:"condition"`
+	Data          []QueryV1             `json:"data" yaml:"data"`
+	DashboardUID  values.StringValue    `json:"dasboardUid" yaml:"dashboardUid"`
+	PanelID       values.Int64Value     `json:"panelId" yaml:"panelId"`
+	NoDataState   values.StringValue    `json:"noDataState" yaml:"noDataState"`
+	ExecErrState  values.StringValue    `json:"execErrState" yaml:"execErrState"`
+	For           values.StringValue    `json:"for" yaml:"for"`
+	KeepFiringFor values.StringValue    `json:"keepFiringFor" yaml:"keepFiringFor"`
+	Annotations   values.StringMapValue `json:"annotations" yaml:"annotations"`
+	Labels        values.StringMapValue `json:"labels" yaml:"labels"`
+	IsPaused      values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	DependsOn     []values.StringValue  `json:"dependsOn" yaml:"dependsOn"`
+	Record        *RecordV1             `json:"record" yaml:"record"`
+}
+
+type RecordV1 struct {
+	Metric              values.StringValue `json:"metric" yaml:"metric"`
+	From                values.StringValue `json:"from" yaml:"from"`
+	TargetDatasourceUID values.StringValue `json:"targetDatasourceUid" yaml:"targetDatasourceUid"`
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -91,6 +100,13 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
 	}
 	alertRule.For = time.Duration(duration)
+	if keepFiringFor := rule.KeepFiringFor.Value(); keepFiringFor != "" {
+		duration, err = model.ParseDuration(keepFiringFor)
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.KeepFiringFor = time.Duration(duration)
+	}
 	dashboardUID := rule.DashboardUID.Value()
 	alertRule.DashboardUID = &dashboardUID
 	panelID := rule.PanelID.Value()
@@ -114,6 +130,17 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 	}
 	alertRule.NoDataState = noDataState
 	alertRule.Condition = rule.Condition.Value()
+	if rule.Record != nil {
+		alertRule.Record = &models.Record{
+			Metric:              rule.Record.Metric.Value(),
+			From:                rule.Record.From.Value(),
+			TargetDatasourceUID: rule.Record.TargetDatasourceUID.Value(),
+		}
+		// The condition of a recording rule is the query or expression it records.
+		if alertRule.Condition == "" {
+			alertRule.Condition = alertRule.Record.From
+		}
+	}
 	if alertRule.Condition == "" {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no condition set", alertRule.Title)
 	}
@@ -130,6 +157,9 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	for _, uid := range rule.DependsOn {
+		alertRule.DependsOn = append(alertRule.DependsOn, uid.Value())
+	}
 	return alertRule, nil
 }
 
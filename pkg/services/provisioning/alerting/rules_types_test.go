@@ -125,12 +125,49 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, 48*time.Hour, ruleMapped.For)
 	})
+	t.Run("a rule with out a keep firing for duration should default to zero", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Zero(t, ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with an invalid keep firing for duration should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("10x"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with a keep firing for duration should work", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("5m"), &keepFiringFor)
+		rule.KeepFiringFor = keepFiringFor
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Minute, ruleMapped.KeepFiringFor)
+	})
 	t.Run("a rule with out a condition should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Condition = values.StringValue{}
 		_, err := rule.mapToModel(1)
 		require.Error(t, err)
 	})
+	t.Run("a recording rule with out a condition should use the recorded query", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		record := RecordV1{}
+		err := yaml.Unmarshal([]byte("metric: job:up:sum\nfrom: A"), &record)
+		require.NoError(t, err)
+		rule.Record = &record
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, "A", ruleMapped.Condition)
+		require.Equal(t, &models.Record{Metric: "job:up:sum", From: "A"}, ruleMapped.Record)
+	})
 	t.Run("a rule with out data should error", func(t *testing.T) {
 		rule := validRuleV1(t)
 		rule.Data = []QueryV1{}
@@ -18,6 +18,7 @@ var (
 	oldVersion            = "./testdata/test-configs/version-0"
 	brokenConfigs         = "./testdata/test-configs/broken-configs"
 	appliedDefaults       = "./testdata/test-configs/applied-defaults"
+	templatedConfigs      = "./testdata/test-configs/templates"
 )
 
 func TestDashboardsAsConfig(t *testing.T) {
@@ -54,6 +55,23 @@ func TestDashboardsAsConfig(t *testing.T) {
 			validateDashboardAsConfig(t, cfg)
 		})
 
+		t.Run("Can read templates from config file version 1 format", func(t *testing.T) {
+			t.Setenv("TEST_VAR", "prom-prod")
+			cfgProvider := configReader{path: templatedConfigs, log: logger, orgService: orgFake}
+			cfg, err := cfgProvider.readConfig(context.Background())
+			require.NoError(t, err)
+
+			require.Len(t, cfg, 1)
+			require.Equal(t, &templatesConfig{
+				LeftDelim:  "[[",
+				RightDelim: "]]",
+				Instances: []*templateInstance{
+					{Name: "prod", Variables: map[string]interface{}{"cluster": "prod", "datasource": "prom-prod"}},
+					{Name: "dev", Variables: map[string]interface{}{"cluster": "dev", "datasource": "prom-dev"}},
+				},
+			}, cfg[0].Templates)
+		})
+
 		t.Run("Can read config file in version 0 format", func(t *testing.T) {
 			cfgProvider := configReader{path: oldVersion, log: logger, orgService: orgFake}
 			cfg, err := cfgProvider.readConfig(context.Background())
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 // DashboardProvisioner is responsible for syncing dashboard from disk to
@@ -20,6 +22,7 @@ type DashboardProvisioner interface {
 	GetProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 	CleanUpOrphanedDashboards(ctx context.Context)
+	CommitDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, usr *user.SignedInUser) (string, error)
 }
 
 // DashboardProvisionerFactory creates DashboardProvisioners based on input
@@ -126,6 +129,40 @@ func (provider *Provisioner) GetAllowUIUpdatesFromConfig(name string) bool {
 	return false
 }
 
+// CommitDashboard writes a dashboard saved from the UI back to the git repository it was provisioned from, and pushes
+// the commit. It returns the hash of the commit, or an empty string if the provisioner of the dashboard is not
+// configured to commit UI saves.
+func (provider *Provisioner) CommitDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, usr *user.SignedInUser) (string, error) {
+	for _, reader := range provider.fileReaders {
+		if reader.Cfg.Name != provisioning.Name {
+			continue
+		}
+		if reader.git == nil || !reader.git.commitOnSave {
+			return "", nil
+		}
+		if reader.Cfg.Templates != nil {
+			return "", fmt.Errorf("dashboards rendered from templates cannot be committed to the repository")
+		}
+
+		data, err := dash.Data.Map()
+		if err != nil {
+			return "", err
+		}
+		body := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			body[k] = v
+		}
+		// The ID is specific to this instance of Grafana, so it is not stored in the repository.
+		delete(body, "id")
+		b, err := simplejson.NewFromAny(body).EncodePretty()
+		if err != nil {
+			return "", err
+		}
+		return reader.git.commit(ctx, provisioning.ExternalID, b, message, usr)
+	}
+	return "", nil
+}
+
 func getFileReaders(
 	configs []*config, logger log.Logger, service dashboards.DashboardProvisioningService, store utils.DashboardStore,
 ) ([]*FileReader, error) {
@@ -139,6 +176,12 @@ func getFileReaders(
 				return nil, fmt.Errorf("failed to create file reader for config %v: %w", config.Name, err)
 			}
 			readers = append(readers, fileReader)
+		case "git":
+			gitReader, err := NewDashboardGitReader(config, logger.New("type", config.Type, "name", config.Name), service, store)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create git reader for config %v: %w", config.Name, err)
+			}
+			readers = append(readers, gitReader)
 		default:
 			return nil, fmt.Errorf("type %s is not supported", config.Type)
 		}
@@ -1,6 +1,11 @@
 package dashboards
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+)
 
 // Calls is a mock implementation of the provisioner interface
 type calls struct {
@@ -67,3 +72,8 @@ func (dpm *ProvisionerMock) GetAllowUIUpdatesFromConfig(name string) bool {
 
 // CleanUpOrphanedDashboards not implemented for mocks
 func (dpm *ProvisionerMock) CleanUpOrphanedDashboards(ctx context.Context) {}
+
+// CommitDashboard not implemented for mocks
+func (dpm *ProvisionerMock) CommitDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, usr *user.SignedInUser) (string, error) {
+	return "", nil
+}
@@ -34,6 +34,8 @@ type FileReader struct {
 	dashboardProvisioningService dashboards.DashboardProvisioningService
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
+	// git is the repository the dashboards are read from, if the reader is backed by git.
+	git *gitRepository
 
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
@@ -53,6 +55,10 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
 	}
 
+	return newFileReader(cfg, path, log, service, dashboardStore)
+}
+
+func newFileReader(cfg *config, path string, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
 	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
 	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
 		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
@@ -87,6 +93,12 @@ func (fr *FileReader) pollChanges(ctx context.Context) {
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
 // and applies any change to the database.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	if fr.git != nil {
+		if err := fr.git.sync(ctx); err != nil {
+			return err
+		}
+	}
+
 	fr.log.Debug("Start walking disk", "path", fr.Path)
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
@@ -147,13 +159,15 @@ func (fr *FileReader) storeDashboardsInFolder(ctx context.Context, filesFoundOnD
 
 	// save dashboards based on json files
 	for path, fileInfo := range filesFoundOnDisk {
-		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, fileInfo, dashboardRefs)
-		if err != nil {
-			fr.log.Error("failed to save dashboard", "file", path, "error", err)
-			continue
-		}
+		for _, instance := range fr.templateInstances() {
+			provisioningMetadata, err := fr.saveDashboard(ctx, path, instance, folderID, fileInfo, dashboardRefs)
+			if err != nil {
+				fr.log.Error("failed to save dashboard", "file", path, "error", err)
+				continue
+			}
 
-		usageTracker.track(provisioningMetadata)
+			usageTracker.track(provisioningMetadata)
+		}
 	}
 	return nil
 }
@@ -175,10 +189,12 @@ func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Cont
 			return fmt.Errorf("can't provision folder %q from file system structure: %w", folderName, err)
 		}
 
-		provisioningMetadata, err := fr.saveDashboard(ctx, path, folderID, fileInfo, dashboardRefs)
-		usageTracker.track(provisioningMetadata)
-		if err != nil {
-			fr.log.Error("failed to save dashboard", "file", path, "error", err)
+		for _, instance := range fr.templateInstances() {
+			provisioningMetadata, err := fr.saveDashboard(ctx, path, instance, folderID, fileInfo, dashboardRefs)
+			usageTracker.track(provisioningMetadata)
+			if err != nil {
+				fr.log.Error("failed to save dashboard", "file", path, "error", err)
+			}
 		}
 	}
 	return nil
@@ -187,10 +203,17 @@ func (fr *FileReader) storeDashboardsInFoldersFromFileStructure(ctx context.Cont
 // handleMissingDashboardFiles will unprovision or delete dashboards which are missing on disk.
 func (fr *FileReader) handleMissingDashboardFiles(ctx context.Context, provisionedDashboardRefs map[string]*dashboards.DashboardProvisioning,
 	filesFoundOnDisk map[string]os.FileInfo) {
+	provisionedFromDisk := make(map[string]struct{}, len(filesFoundOnDisk))
+	for path := range filesFoundOnDisk {
+		for _, instance := range fr.templateInstances() {
+			provisionedFromDisk[fr.externalID(path, instance)] = struct{}{}
+		}
+	}
+
 	// find dashboards to delete since json file is missing
 	var dashboardsToDelete []int64
-	for path, provisioningData := range provisionedDashboardRefs {
-		_, existsOnDisk := filesFoundOnDisk[path]
+	for externalID, provisioningData := range provisionedDashboardRefs {
+		_, existsOnDisk := provisionedFromDisk[externalID]
 		if !existsOnDisk {
 			dashboardsToDelete = append(dashboardsToDelete, provisioningData.DashboardID)
 		}
@@ -218,8 +241,9 @@ func (fr *FileReader) handleMissingDashboardFiles(ctx context.Context, provision
 	}
 }
 
-// saveDashboard saves or updates the dashboard provisioning file at path.
-func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID int64, fileInfo os.FileInfo,
+// saveDashboard saves or updates the dashboard provisioning file at path. If the file is a template, it saves the
+// dashboard rendered with the template instance.
+func (fr *FileReader) saveDashboard(ctx context.Context, path string, instance *templateInstance, folderID int64, fileInfo os.FileInfo,
 	provisionedDashboardRefs map[string]*dashboards.DashboardProvisioning) (provisioningMetadata, error) {
 	provisioningMetadata := provisioningMetadata{}
 	resolvedFileInfo, err := resolveSymlink(fileInfo, path)
@@ -227,9 +251,10 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 		return provisioningMetadata, err
 	}
 
-	provisionedData, alreadyProvisioned := provisionedDashboardRefs[path]
+	externalID := fr.externalID(path, instance)
+	provisionedData, alreadyProvisioned := provisionedDashboardRefs[externalID]
 
-	jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo.ModTime(), folderID)
+	jsonFile, err := fr.readDashboardFromFile(path, instance, resolvedFileInfo.ModTime(), folderID)
 	if err != nil {
 		fr.log.Error("failed to load dashboard from ", "file", path, "error", err)
 		return provisioningMetadata, nil
@@ -261,11 +286,14 @@ func (fr *FileReader) saveDashboard(ctx context.Context, path string, folderID i
 	if !fr.isDatabaseAccessRestricted() {
 		fr.log.Debug("saving new dashboard", "provisioner", fr.Cfg.Name, "file", path, "folderId", dash.Dashboard.FolderID)
 		dp := &dashboards.DashboardProvisioning{
-			ExternalID: path,
+			ExternalID: externalID,
 			Name:       fr.Cfg.Name,
 			Updated:    resolvedFileInfo.ModTime().Unix(),
 			CheckSum:   jsonFile.checkSum,
 		}
+		if fr.git != nil {
+			dp.CommitSHA = fr.git.commitSHA()
+		}
 		_, err := fr.dashboardProvisioningService.SaveProvisionedDashboard(ctx, dash, dp)
 		if err != nil {
 			return provisioningMetadata, err
@@ -387,7 +415,7 @@ type dashboardJSONFile struct {
 	lastModified time.Time
 }
 
-func (fr *FileReader) readDashboardFromFile(path string, lastModified time.Time, folderID int64) (*dashboardJSONFile, error) {
+func (fr *FileReader) readDashboardFromFile(path string, instance *templateInstance, lastModified time.Time, folderID int64) (*dashboardJSONFile, error) {
 	// nolint:gosec
 	// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
 	reader, err := os.Open(path)
@@ -405,6 +433,13 @@ func (fr *FileReader) readDashboardFromFile(path string, lastModified time.Time,
 		return nil, err
 	}
 
+	if instance != nil {
+		all, err = fr.renderTemplate(path, all, instance)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render template with instance %q: %w", instance.Name, err)
+		}
+	}
+
 	checkSum, err := util.Md5SumString(string(all))
 	if err != nil {
 		return nil, err
@@ -415,6 +450,10 @@ func (fr *FileReader) readDashboardFromFile(path string, lastModified time.Time,
 		return nil, err
 	}
 
+	if instance != nil && data.Get("uid").MustString() == "" {
+		data.Set("uid", fr.templateUID(path, instance))
+	}
+
 	dash, err := createDashboardJSON(data, lastModified, fr.Cfg, folderID)
 	if err != nil {
 		return nil, err
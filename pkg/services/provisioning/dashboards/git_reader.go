@@ -0,0 +1,288 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/util/gitutil"
+)
+
+const (
+	defaultGitBranch = "main"
+	gitRemoteName    = "origin"
+)
+
+// NewDashboardGitReader returns a new filereader that provisions the dashboards of a branch of a git repository.
+// The repository is cloned into a local directory, and pulled every time the reader looks for changes.
+func NewDashboardGitReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
+	url, ok := cfg.Options["url"].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("failed to load dashboards, url param is not a string")
+	}
+
+	branch, _ := cfg.Options["branch"].(string)
+	if branch == "" {
+		branch = defaultGitBranch
+	}
+
+	// path is the directory inside the repository that contains the dashboards.
+	path, _ := cfg.Options["path"].(string)
+	if filepath.IsAbs(path) || strings.HasPrefix(filepath.Clean(path), "..") {
+		return nil, fmt.Errorf("failed to load dashboards, path %q must be relative to the root of the repository", path)
+	}
+
+	cloneDir, _ := cfg.Options["cloneDir"].(string)
+	if cloneDir == "" {
+		cloneDir = filepath.Join(os.TempDir(), "grafana", "provisioning", "dashboards", cfg.Name)
+	}
+	cloneDir, err := filepath.Abs(cloneDir)
+	if err != nil {
+		return nil, err
+	}
+
+	commitOnSave, _ := cfg.Options["commitOnSave"].(bool)
+	if commitOnSave && !cfg.AllowUIUpdates {
+		return nil, fmt.Errorf("'commitOnSave' requires 'allowUiUpdates' to be enabled")
+	}
+
+	var auth transport.AuthMethod
+	if token, _ := cfg.Options["accessToken"].(string); token != "" {
+		if strings.HasPrefix(token, "$") {
+			token = os.Getenv(token[1:])
+			if token == "" {
+				return nil, fmt.Errorf("unable to find token environment variable: %s", cfg.Options["accessToken"])
+			}
+		}
+		auth = &githttp.BasicAuth{Username: "grafana", Password: token}
+	}
+
+	fr, err := newFileReader(cfg, filepath.Join(cloneDir, path), log, service, dashboardStore)
+	if err != nil {
+		return nil, err
+	}
+	fr.git = &gitRepository{
+		url:          url,
+		branch:       branch,
+		dir:          cloneDir,
+		auth:         auth,
+		commitOnSave: commitOnSave,
+		log:          log,
+	}
+	return fr, nil
+}
+
+// gitRepository is a local clone of a git repository that tracks a branch of its remote.
+type gitRepository struct {
+	url          string
+	branch       string
+	dir          string
+	auth         transport.AuthMethod
+	commitOnSave bool
+	log          log.Logger
+
+	mux  sync.Mutex
+	repo *git.Repository
+	head string
+}
+
+// sync clones the repository if needed and resets the local branch to the latest commit of the remote branch.
+// Local changes that are not pushed are discarded.
+func (r *gitRepository) sync(ctx context.Context) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if err := r.open(ctx); err != nil {
+		return err
+	}
+	return r.reset(ctx)
+}
+
+// reset fetches the remote branch and resets the local branch to its latest commit.
+func (r *gitRepository) reset(ctx context.Context) error {
+	err := r.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []gitconfig.RefSpec{r.refSpec()},
+		Auth:       r.auth,
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch branch %s from %s: %w", r.branch, r.url, err)
+	}
+
+	remoteRef, err := r.repo.Reference(plumbing.NewRemoteReferenceName(gitRemoteName, r.branch), true)
+	if err != nil {
+		return fmt.Errorf("failed to find branch %s: %w", r.branch, err)
+	}
+
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	checkout := &git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(r.branch), Force: true}
+	_, err = r.repo.Reference(checkout.Branch, false)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		checkout.Hash = remoteRef.Hash()
+		checkout.Create = true
+	} else if err != nil {
+		return err
+	}
+	if err := w.Checkout(checkout); err != nil {
+		return fmt.Errorf("failed to checkout branch %s: %w", r.branch, err)
+	}
+	if err := w.Reset(&git.ResetOptions{Commit: remoteRef.Hash(), Mode: git.HardReset}); err != nil {
+		return fmt.Errorf("failed to reset branch %s: %w", r.branch, err)
+	}
+
+	if head := remoteRef.Hash().String(); head != r.head {
+		r.log.Info("Synced git repository", "url", r.url, "branch", r.branch, "commit", head)
+		r.head = head
+	}
+	return nil
+}
+
+func (r *gitRepository) open(ctx context.Context) error {
+	if r.repo != nil {
+		return nil
+	}
+
+	repo, err := git.PlainOpen(r.dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		r.log.Info("Cloning git repository", "url", r.url, "branch", r.branch, "dir", r.dir)
+		repo, err = git.PlainCloneContext(ctx, r.dir, false, &git.CloneOptions{
+			URL:           r.url,
+			ReferenceName: plumbing.NewBranchReferenceName(r.branch),
+			SingleBranch:  true,
+			Auth:          r.auth,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open git repository %s: %w", r.url, err)
+	}
+
+	r.repo = repo
+	return nil
+}
+
+func (r *gitRepository) refSpec() gitconfig.RefSpec {
+	return gitconfig.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", r.branch, gitRemoteName, r.branch))
+}
+
+// commitSHA returns the commit the working tree was last synced to.
+func (r *gitRepository) commitSHA() string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	return r.head
+}
+
+// commit writes the file at path, commits it to the branch and pushes the branch to the remote.
+// The commit is made on top of the latest commit of the remote branch. If the branch changes before the commit is
+// pushed, the commit is made again on top of the new commit once. It returns the hash of the pushed commit.
+func (r *gitRepository) commit(ctx context.Context, path string, body []byte, message string, usr *user.SignedInUser) (string, error) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	if r.repo == nil {
+		return "", fmt.Errorf("git repository %s is not synced", r.url)
+	}
+
+	root, err := filepath.EvalSymlinks(r.dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("file %s is not in git repository %s", path, r.url)
+	}
+
+	if message == "" {
+		message = "changes from grafana ui"
+	}
+
+	for retried := false; ; retried = true {
+		if err := r.reset(ctx); err != nil {
+			return "", err
+		}
+		hash, err := r.commitFile(path, rel, body, message, usr)
+		if err != nil {
+			return "", err
+		}
+
+		err = r.push(ctx)
+		if err == nil {
+			r.head = hash
+			r.log.Info("Committed dashboard to git repository", "url", r.url, "branch", r.branch, "file", rel, "commit", r.head)
+			return r.head, nil
+		}
+
+		// Drop the commit, so that the next sync does not try to build on top of it.
+		if resetErr := r.resetWorktree(); resetErr != nil {
+			r.log.Warn("Failed to reset branch after push failure", "branch", r.branch, "error", resetErr)
+		}
+		if retried || !gitutil.IsNonFastForward(err) {
+			return "", fmt.Errorf("failed to push branch %s to %s: %w", r.branch, r.url, err)
+		}
+		r.log.Info("Branch changed before the dashboard was pushed, committing it again", "url", r.url, "branch", r.branch, "file", rel)
+	}
+}
+
+// commitFile writes the file and commits it to the local branch.
+func (r *gitRepository) commitFile(path string, rel string, body []byte, message string, usr *user.SignedInUser) (string, error) {
+	if err := os.WriteFile(path, body, 0600); err != nil {
+		return "", err
+	}
+
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Add(filepath.ToSlash(rel)); err != nil {
+		return "", err
+	}
+
+	hash, err := w.Commit(message, &git.CommitOptions{Author: gitutil.Signature(usr, time.Now())})
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// push pushes the local branch to the remote.
+func (r *gitRepository) push(ctx context.Context) error {
+	branchRef := plumbing.NewBranchReferenceName(r.branch)
+	err := r.repo.PushContext(ctx, &git.PushOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []gitconfig.RefSpec{gitconfig.RefSpec(fmt.Sprintf("%s:%s", branchRef, branchRef))},
+		Auth:       r.auth,
+	})
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+// resetWorktree resets the local branch to the commit the working tree was last synced to.
+func (r *gitRepository) resetWorktree() error {
+	w, err := r.repo.Worktree()
+	if err != nil {
+		return err
+	}
+	return w.Reset(&git.ResetOptions{Commit: plumbing.NewHash(r.head), Mode: git.HardReset})
+}
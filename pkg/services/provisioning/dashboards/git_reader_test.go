@@ -0,0 +1,235 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestCreatingNewDashboardGitReader(t *testing.T) {
+	setup := func() *config {
+		return &config{
+			Name:    "Default",
+			Type:    "git",
+			OrgID:   1,
+			Options: map[string]interface{}{"url": "https://example.com/dashboards.git"},
+		}
+	}
+
+	t.Run("using url and path", func(t *testing.T) {
+		cfg := setup()
+		cfg.Options["path"] = "dashboards"
+		cfg.Options["cloneDir"] = "/var/lib/grafana/git"
+		reader, err := NewDashboardGitReader(cfg, log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join("/var/lib/grafana/git", "dashboards"), reader.Path)
+		require.Equal(t, defaultGitBranch, reader.git.branch)
+	})
+
+	t.Run("without url", func(t *testing.T) {
+		cfg := setup()
+		delete(cfg.Options, "url")
+		_, err := NewDashboardGitReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("with path outside of the repository", func(t *testing.T) {
+		cfg := setup()
+		cfg.Options["path"] = "../dashboards"
+		_, err := NewDashboardGitReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("committing UI saves without allowing UI updates", func(t *testing.T) {
+		cfg := setup()
+		cfg.Options["commitOnSave"] = true
+		_, err := NewDashboardGitReader(cfg, log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestDashboardGitReader(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is required for the local transport")
+	}
+
+	remote := newTestGitRemote(t)
+	firstCommit := remote.commit(t, "dashboards/dashboard1.json", `{"title": "Grafana", "uid": "git-dash"}`)
+	remote.commit(t, "README.md", "not a dashboard")
+
+	cfg := &config{
+		Name:           configName,
+		Type:           "git",
+		OrgID:          1,
+		AllowUIUpdates: true,
+		Options: map[string]interface{}{
+			"url":          remote.bareDir,
+			"branch":       "main",
+			"path":         "dashboards",
+			"cloneDir":     filepath.Join(t.TempDir(), "clone"),
+			"commitOnSave": true,
+		},
+	}
+
+	fakeService := &dashboards.FakeDashboardProvisioning{}
+	defer fakeService.AssertExpectations(t)
+
+	reader, err := NewDashboardGitReader(cfg, log.New("test-logger"), fakeService, &fakeDashboardStore{})
+	require.NoError(t, err)
+
+	var saved *dashboards.DashboardProvisioning
+	fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+	fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+		Return(&dashboards.Dashboard{ID: 1}, nil).Once().
+		Run(func(args mock.Arguments) {
+			saved = args.Get(2).(*dashboards.DashboardProvisioning)
+		})
+
+	require.NoError(t, reader.walkDisk(context.Background()))
+	require.NotNil(t, saved)
+	require.Equal(t, remote.head(t), saved.CommitSHA)
+	require.NotEqual(t, firstCommit, saved.CommitSHA)
+	require.Equal(t, "dashboard1.json", filepath.Base(saved.ExternalID))
+
+	t.Run("should provision changes pushed to the branch", func(t *testing.T) {
+		provisioned := *saved
+		provisioned.DashboardID = 1
+		commit := remote.commit(t, "dashboards/dashboard1.json", `{"title": "Grafana v2", "uid": "git-dash"}`)
+
+		var updated *dashboards.DashboardProvisioning
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).
+			Return([]*dashboards.DashboardProvisioning{&provisioned}, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&dashboards.Dashboard{ID: 1}, nil).Once().
+			Run(func(args mock.Arguments) {
+				require.Equal(t, "Grafana v2", args.Get(1).(*dashboards.SaveDashboardDTO).Dashboard.Title)
+				updated = args.Get(2).(*dashboards.DashboardProvisioning)
+			})
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.NotNil(t, updated)
+		require.Equal(t, commit, updated.CommitSHA)
+		saved = updated
+	})
+
+	t.Run("should commit UI saves back to the branch", func(t *testing.T) {
+		provisioner := &Provisioner{fileReaders: []*FileReader{reader}, configs: []*config{cfg}}
+		// The branch changes after the last sync, so the UI save must be committed on top of the new commit.
+		parent := remote.commit(t, "README.md", "changed after the last sync")
+		dash := dashboards.NewDashboardFromJson(simplejson.NewFromAny(map[string]interface{}{
+			"id":    1,
+			"uid":   "git-dash",
+			"title": "Saved from the UI",
+		}))
+
+		hash, err := provisioner.CommitDashboard(context.Background(), saved, dash, "rename dashboard", &user.SignedInUser{Login: "admin", Email: "admin@localhost"})
+		require.NoError(t, err)
+		require.Equal(t, remote.head(t), hash)
+
+		commit := remote.commitObject(t)
+		require.Equal(t, "rename dashboard", commit.Message)
+		require.Equal(t, "admin", commit.Author.Name)
+		require.Equal(t, []plumbing.Hash{plumbing.NewHash(parent)}, commit.ParentHashes)
+
+		file, err := commit.File("dashboards/dashboard1.json")
+		require.NoError(t, err)
+		contents, err := file.Contents()
+		require.NoError(t, err)
+		data, err := simplejson.NewJson([]byte(contents))
+		require.NoError(t, err)
+		require.Equal(t, "Saved from the UI", data.Get("title").MustString())
+		_, hasID := data.CheckGet("id")
+		require.False(t, hasID)
+	})
+
+	t.Run("should not commit UI saves of other provisioners", func(t *testing.T) {
+		provisioner := &Provisioner{fileReaders: []*FileReader{reader}, configs: []*config{cfg}}
+		head := remote.head(t)
+
+		hash, err := provisioner.CommitDashboard(context.Background(), &dashboards.DashboardProvisioning{Name: "other"}, dashboards.NewDashboard("other"), "", nil)
+		require.NoError(t, err)
+		require.Empty(t, hash)
+		require.Equal(t, head, remote.head(t))
+	})
+}
+
+// testGitRemote is a bare repository with a main branch, and a working copy used to push commits to it.
+type testGitRemote struct {
+	bareDir string
+	workDir string
+	repo    *git.Repository
+}
+
+func newTestGitRemote(t *testing.T) *testGitRemote {
+	t.Helper()
+
+	r := &testGitRemote{
+		bareDir: filepath.Join(t.TempDir(), "remote.git"),
+		workDir: filepath.Join(t.TempDir(), "work"),
+	}
+	_, err := git.PlainInit(r.bareDir, true)
+	require.NoError(t, err)
+
+	r.repo, err = git.PlainInit(r.workDir, false)
+	require.NoError(t, err)
+	_, err = r.repo.CreateRemote(&gitconfig.RemoteConfig{Name: gitRemoteName, URLs: []string{r.bareDir}})
+	require.NoError(t, err)
+	return r
+}
+
+// commit writes the file to the working copy and pushes it to the main branch of the bare repository.
+func (r *testGitRemote) commit(t *testing.T, path, contents string) string {
+	t.Helper()
+
+	fullPath := filepath.Join(r.workDir, path)
+	require.NoError(t, os.MkdirAll(filepath.Dir(fullPath), 0750))
+	require.NoError(t, os.WriteFile(fullPath, []byte(contents), 0600))
+
+	w, err := r.repo.Worktree()
+	require.NoError(t, err)
+	_, err = w.Add(path)
+	require.NoError(t, err)
+	hash, err := w.Commit("update "+path, &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@localhost", When: time.Now()},
+	})
+	require.NoError(t, err)
+
+	err = r.repo.Push(&git.PushOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs:   []gitconfig.RefSpec{"refs/heads/master:refs/heads/main"},
+	})
+	require.NoError(t, err)
+	return hash.String()
+}
+
+func (r *testGitRemote) commitObject(t *testing.T) *object.Commit {
+	t.Helper()
+
+	bare, err := git.PlainOpen(r.bareDir)
+	require.NoError(t, err)
+	ref, err := bare.Reference(plumbing.NewBranchReferenceName("main"), true)
+	require.NoError(t, err)
+	commit, err := bare.CommitObject(ref.Hash())
+	require.NoError(t, err)
+	return commit
+}
+
+func (r *testGitRemote) head(t *testing.T) string {
+	t.Helper()
+	return r.commitObject(t).Hash.String()
+}
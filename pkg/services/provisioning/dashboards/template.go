@@ -0,0 +1,61 @@
+package dashboards
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"text/template"
+)
+
+// templateInstances returns the instances the dashboard files are rendered with. It returns a single nil instance
+// if the dashboard files are not templates.
+func (fr *FileReader) templateInstances() []*templateInstance {
+	if fr.Cfg.Templates == nil {
+		return []*templateInstance{nil}
+	}
+	return fr.Cfg.Templates.Instances
+}
+
+// externalID returns the ID of the dashboard provisioned from the file at path with the template instance.
+func (fr *FileReader) externalID(path string, instance *templateInstance) string {
+	if instance == nil {
+		return path
+	}
+	return path + "#" + instance.Name
+}
+
+// renderTemplate renders the dashboard template read from the file at path with the variables of the instance.
+// Variables that are not defined by the instance are an error.
+func (fr *FileReader) renderTemplate(path string, body []byte, instance *templateInstance) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(path)).
+		Delims(fr.Cfg.Templates.LeftDelim, fr.Cfg.Templates.RightDelim).
+		Option("missingkey=error").
+		Parse(string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, instance.Variables); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// templateUID returns the UID of the dashboard rendered from the template at path with the instance, for templates
+// that do not set one. The UID only depends on the provider, the path relative to the provider and the name of the
+// instance, so it is the same every time the dashboard is provisioned.
+func (fr *FileReader) templateUID(path string, instance *templateInstance) string {
+	rel, err := filepath.Rel(fr.resolvedPath(), path)
+	if err != nil {
+		rel = path
+	}
+
+	h := sha256.New()
+	for _, s := range []string{fr.Cfg.Name, filepath.ToSlash(rel), instance.Name} {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)[:12])
+}
@@ -0,0 +1,146 @@
+package dashboards
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+)
+
+const templatedDashboards = "testdata/test-dashboards/templated"
+
+func TestTemplatedDashboards(t *testing.T) {
+	logger := log.New("test-logger")
+	templates := &templatesConfig{
+		Instances: []*templateInstance{
+			{Name: "prod", Variables: map[string]interface{}{"cluster": "prod", "datasource": "prom-prod"}},
+			{Name: "dev", Variables: map[string]interface{}{"cluster": "dev", "datasource": "prom-dev"}},
+		},
+	}
+	setup := func(t *testing.T) (*FileReader, *dashboards.FakeDashboardProvisioning) {
+		cfg := &config{
+			Name:      configName,
+			Type:      "file",
+			OrgID:     1,
+			Options:   map[string]interface{}{"path": templatedDashboards},
+			Templates: templates,
+		}
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		t.Cleanup(func() { fakeService.AssertExpectations(t) })
+
+		reader, err := NewDashboardFileReader(cfg, logger, fakeService, &fakeDashboardStore{})
+		require.NoError(t, err)
+		return reader, fakeService
+	}
+
+	absPath, err := filepath.Abs(filepath.Join(templatedDashboards, "cluster.json"))
+	require.NoError(t, err)
+
+	t.Run("should provision a dashboard per template instance", func(t *testing.T) {
+		reader, fakeService := setup(t)
+
+		saved := map[string]*dashboards.SaveDashboardDTO{}
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&dashboards.Dashboard{}, nil).Times(2).
+			Run(func(args mock.Arguments) {
+				saved[args.Get(2).(*dashboards.DashboardProvisioning).ExternalID] = args.Get(1).(*dashboards.SaveDashboardDTO)
+			})
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+		require.Len(t, saved, 2)
+
+		prod := saved[absPath+"#prod"]
+		require.NotNil(t, prod)
+		require.Equal(t, "Cluster prod", prod.Dashboard.Title)
+		require.Equal(t, "prom-prod", prod.Dashboard.Data.Get("panels").GetIndex(0).GetPath("datasource", "uid").MustString())
+		require.Equal(t, "{{instance}}", prod.Dashboard.Data.Get("panels").GetIndex(0).Get("targets").GetIndex(0).Get("legendFormat").MustString())
+
+		dev := saved[absPath+"#dev"]
+		require.NotNil(t, dev)
+		require.Equal(t, "Cluster dev", dev.Dashboard.Title)
+
+		require.Len(t, prod.Dashboard.UID, 24)
+		require.NotEqual(t, prod.Dashboard.UID, dev.Dashboard.UID)
+		require.Equal(t, reader.templateUID(absPath, templates.Instances[0]), prod.Dashboard.UID)
+	})
+
+	t.Run("should delete dashboards of removed template instances", func(t *testing.T) {
+		reader, fakeService := setup(t)
+
+		provisioned := []*dashboards.DashboardProvisioning{
+			{DashboardID: 1, Name: configName, ExternalID: absPath + "#prod"},
+			{DashboardID: 2, Name: configName, ExternalID: absPath + "#staging"},
+		}
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(provisioned, nil).Once()
+		fakeService.On("DeleteProvisionedDashboard", mock.Anything, int64(2), int64(1)).Return(nil).Once()
+		fakeService.On("SaveProvisionedDashboard", mock.Anything, mock.Anything, mock.Anything).
+			Return(&dashboards.Dashboard{}, nil).Times(2)
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+
+	t.Run("should not provision dashboards with undefined variables", func(t *testing.T) {
+		reader, fakeService := setup(t)
+		reader.Cfg.Templates = &templatesConfig{
+			Instances: []*templateInstance{{Name: "prod", Variables: map[string]interface{}{"cluster": "prod"}}},
+		}
+
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, configName).Return(nil, nil).Once()
+
+		require.NoError(t, reader.walkDisk(context.Background()))
+	})
+}
+
+func TestMapToTemplatesConfig(t *testing.T) {
+	testCases := []struct {
+		name  string
+		yaml  string
+		valid bool
+	}{
+		{
+			name:  "with instances",
+			yaml:  "instances: [{name: prod, variables: {cluster: prod}}, {name: dev}]",
+			valid: true,
+		},
+		{
+			name:  "with delimiters",
+			yaml:  "{delimiters: ['[[', ']]'], instances: [{name: prod}]}",
+			valid: true,
+		},
+		{
+			name: "without instances",
+			yaml: "instances: []",
+		},
+		{
+			name: "with an instance without name",
+			yaml: "instances: [{variables: {cluster: prod}}]",
+		},
+		{
+			name: "with instances with the same name",
+			yaml: "instances: [{name: prod}, {name: prod}]",
+		},
+		{
+			name: "with a single delimiter",
+			yaml: "{delimiters: ['[['], instances: [{name: prod}]}",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var templates templatesV1
+			require.NoError(t, yaml.Unmarshal([]byte(tc.yaml), &templates))
+			_, err := templates.mapToTemplatesConfig()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -20,6 +20,20 @@ type config struct {
 	DisableDeletion       bool
 	UpdateIntervalSeconds int64
 	AllowUIUpdates        bool
+	// Templates makes the reader render every dashboard file as a Go template, once per template instance.
+	Templates *templatesConfig
+}
+
+type templatesConfig struct {
+	LeftDelim  string
+	RightDelim string
+	Instances  []*templateInstance
+}
+
+// templateInstance is a named set of variables that dashboard templates are rendered with.
+type templateInstance struct {
+	Name      string
+	Variables map[string]interface{}
 }
 
 type configV0 struct {
@@ -54,6 +68,17 @@ type configs struct {
 	DisableDeletion       values.BoolValue   `json:"disableDeletion" yaml:"disableDeletion"`
 	UpdateIntervalSeconds values.Int64Value  `json:"updateIntervalSeconds" yaml:"updateIntervalSeconds"`
 	AllowUIUpdates        values.BoolValue   `json:"allowUiUpdates" yaml:"allowUiUpdates"`
+	Templates             *templatesV1       `json:"templates" yaml:"templates"`
+}
+
+type templatesV1 struct {
+	Delimiters []values.StringValue  `json:"delimiters" yaml:"delimiters"`
+	Instances  []*templateInstanceV1 `json:"instances" yaml:"instances"`
+}
+
+type templateInstanceV1 struct {
+	Name      values.StringValue `json:"name" yaml:"name"`
+	Variables values.JSONValue   `json:"variables" yaml:"variables"`
 }
 
 func createDashboardJSON(data *simplejson.Json, lastModified time.Time, cfg *config, folderID int64) (*dashboards.SaveDashboardDTO, error) {
@@ -109,6 +134,11 @@ func (dc *configV1) mapToDashboardsAsConfig() ([]*config, error) {
 		}
 		seen[v.Name.Value()] = true
 
+		templates, err := v.Templates.mapToTemplatesConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid templates of dashboard provider %q: %w", v.Name.Value(), err)
+		}
+
 		r = append(r, &config{
 			Name:                  v.Name.Value(),
 			Type:                  v.Type.Value(),
@@ -120,8 +150,46 @@ func (dc *configV1) mapToDashboardsAsConfig() ([]*config, error) {
 			DisableDeletion:       v.DisableDeletion.Value(),
 			UpdateIntervalSeconds: v.UpdateIntervalSeconds.Value(),
 			AllowUIUpdates:        v.AllowUIUpdates.Value(),
+			Templates:             templates,
 		})
 	}
 
 	return r, nil
 }
+
+func (t *templatesV1) mapToTemplatesConfig() (*templatesConfig, error) {
+	if t == nil {
+		return nil, nil
+	}
+
+	result := &templatesConfig{}
+	switch len(t.Delimiters) {
+	case 0:
+	case 2:
+		result.LeftDelim = t.Delimiters[0].Value()
+		result.RightDelim = t.Delimiters[1].Value()
+	default:
+		return nil, fmt.Errorf("delimiters must have a left and a right delimiter")
+	}
+
+	if len(t.Instances) == 0 {
+		return nil, fmt.Errorf("at least one instance is required")
+	}
+	seen := make(map[string]bool, len(t.Instances))
+	for _, instance := range t.Instances {
+		name := instance.Name.Value()
+		if name == "" {
+			return nil, fmt.Errorf("instance name is missing")
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("instance name %q is not unique", name)
+		}
+		seen[name] = true
+
+		result.Instances = append(result.Instances, &templateInstance{
+			Name:      name,
+			Variables: instance.Variables.Value(),
+		})
+	}
+	return result, nil
+}
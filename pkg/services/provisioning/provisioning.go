@@ -32,6 +32,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -93,6 +94,7 @@ type ProvisioningService interface {
 	ProvisionAlerting(ctx context.Context) error
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
+	CommitProvisionedDashboard(ctx context.Context, provisioning *dashboardservice.DashboardProvisioning, dash *dashboardservice.Dashboard, message string, usr *user.SignedInUser) (string, error)
 	RunInitProvisioners(ctx context.Context) error
 }
 
@@ -294,6 +296,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
 	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
 	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
+	maintenanceWindowService := provisioning.NewMaintenanceWindowService(st, st, &st, ps.log)
 	cfg := prov_alerting.ProvisionerConfig{
 		Path:                       alertingPath,
 		RuleService:                *ruleService,
@@ -303,6 +306,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
+		MaintenanceWindowService:   *maintenanceWindowService,
 	}
 	return ps.provisionAlerting(ctx, cfg)
 }
@@ -315,6 +319,10 @@ func (ps *ProvisioningServiceImpl) GetAllowUIUpdatesFromConfig(name string) bool
 	return ps.dashboardProvisioner.GetAllowUIUpdatesFromConfig(name)
 }
 
+func (ps *ProvisioningServiceImpl) CommitProvisionedDashboard(ctx context.Context, provisioning *dashboardservice.DashboardProvisioning, dash *dashboardservice.Dashboard, message string, usr *user.SignedInUser) (string, error) {
+	return ps.dashboardProvisioner.CommitDashboard(ctx, provisioning, dash, message, usr)
+}
+
 func (ps *ProvisioningServiceImpl) cancelPolling() {
 	if ps.pollingCtxCancel != nil {
 		ps.log.Debug("Stop polling for dashboard changes")
@@ -6,6 +6,8 @@ import (
 	"github.com/grafana/dskit/services"
 
 	"github.com/grafana/grafana/pkg/modules"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/user"
 )
 
 type Calls struct {
@@ -17,6 +19,7 @@ type Calls struct {
 	ProvisionAlerting                   []interface{}
 	GetDashboardProvisionerResolvedPath []interface{}
 	GetAllowUIUpdatesFromConfig         []interface{}
+	CommitProvisionedDashboard          []interface{}
 	Run                                 []interface{}
 }
 
@@ -30,6 +33,7 @@ type ProvisioningServiceMock struct {
 	ProvisionDashboardsFunc                 func() error
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
+	CommitProvisionedDashboardFunc          func(provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard) (string, error)
 	RunFunc                                 func(ctx context.Context) error
 }
 
@@ -102,6 +106,14 @@ func (mock *ProvisioningServiceMock) GetAllowUIUpdatesFromConfig(name string) bo
 	return false
 }
 
+func (mock *ProvisioningServiceMock) CommitProvisionedDashboard(ctx context.Context, provisioning *dashboards.DashboardProvisioning, dash *dashboards.Dashboard, message string, usr *user.SignedInUser) (string, error) {
+	mock.Calls.CommitProvisionedDashboard = append(mock.Calls.CommitProvisionedDashboard, provisioning)
+	if mock.CommitProvisionedDashboardFunc != nil {
+		return mock.CommitProvisionedDashboardFunc(provisioning, dash)
+	}
+	return "", nil
+}
+
 func (mock *ProvisioningServiceMock) Run(ctx context.Context) error {
 	mock.Calls.Run = append(mock.Calls.Run, nil)
 	if mock.RunFunc != nil {
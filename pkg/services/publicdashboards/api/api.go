@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/usage"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -23,6 +24,7 @@ type Api struct {
 	RouteRegister          routing.RouteRegister
 	AccessControl          accesscontrol.AccessControl
 	Features               *featuremgmt.FeatureManager
+	Usage                  *usage.Service
 	Log                    log.Logger
 }
 
@@ -31,12 +33,14 @@ func ProvideApi(
 	rr routing.RouteRegister,
 	ac accesscontrol.AccessControl,
 	features *featuremgmt.FeatureManager,
+	usageService *usage.Service,
 ) *Api {
 	api := &Api{
 		PublicDashboardService: pd,
 		RouteRegister:          rr,
 		AccessControl:          ac,
 		Features:               features,
+		Usage:                  usageService,
 		Log:                    log.New("publicdashboards.api"),
 	}
 
@@ -55,9 +59,10 @@ func (api *Api) RegisterAPIEndpoints() {
 	// because it is deeply dependent on the HTTPServer.Index() method and would result in a
 	// circular dependency
 
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.ViewPublicDashboard))
-	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", routing.Wrap(api.QueryPublicDashboard))
-	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", routing.Wrap(api.GetAnnotations))
+	rateLimit := RateLimitPublicDashboardQueries(api.Usage)
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken", routing.Wrap(api.recordUsage(usage.KindView, api.ViewPublicDashboard)))
+	api.RouteRegister.Post("/api/public/dashboards/:accessToken/panels/:panelId/query", rateLimit, routing.Wrap(api.recordUsage(usage.KindQuery, api.QueryPublicDashboard)))
+	api.RouteRegister.Get("/api/public/dashboards/:accessToken/annotations", rateLimit, routing.Wrap(api.recordUsage(usage.KindQuery, api.GetAnnotations)))
 
 	// Auth endpoints
 	auth := accesscontrol.Middleware(api.AccessControl)
@@ -66,6 +71,16 @@ func (api *Api) RegisterAPIEndpoints() {
 	// List public dashboards for org
 	api.RouteRegister.Get("/api/dashboards/public-dashboards", middleware.ReqSignedIn, routing.Wrap(api.ListPublicDashboards))
 
+	// Usage of the access tokens of the public dashboards of the org
+	api.RouteRegister.Get("/api/dashboards/public-dashboards/usage",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite)),
+		routing.Wrap(api.ListPublicDashboardUsage))
+
+	// Revoke the access token of a public dashboard
+	api.RouteRegister.Post("/api/dashboards/public-dashboards/usage/:accessToken/revoke",
+		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite)),
+		routing.Wrap(api.RevokePublicDashboardAccessToken))
+
 	// Get public dashboard
 	api.RouteRegister.Get("/api/dashboards/uid/:dashboardUid/public-dashboards",
 		auth(accesscontrol.EvalPermission(dashboards.ActionDashboardsRead, uidScope)),
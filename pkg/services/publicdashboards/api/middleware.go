@@ -6,6 +6,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/metrics"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/usage"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -67,3 +68,20 @@ func CountPublicDashboardRequest() func(c *contextmodel.ReqContext) {
 		metrics.MPublicDashboardRequestCount.Inc()
 	}
 }
+
+// RateLimitPublicDashboardQueries Middleware to reject the queries of a public dashboard that exceed the query rate
+// limit of its access token
+func RateLimitPublicDashboardQueries(usageService *usage.Service) func(c *contextmodel.ReqContext) {
+	return func(c *contextmodel.ReqContext) {
+		accessToken := web.Params(c.Req)[":accessToken"]
+		if !validation.IsValidAccessToken(accessToken) {
+			return
+		}
+
+		if !usageService.Allow(accessToken) {
+			usageService.RecordRateLimited(accessToken)
+			c.JsonApiErr(http.StatusTooManyRequests, "Too many queries for public dashboard", nil)
+			return
+		}
+	}
+}
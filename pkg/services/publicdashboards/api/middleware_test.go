@@ -11,7 +11,9 @@ import (
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/publicdashboards"
 	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/usage"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -152,6 +154,20 @@ func TestSetPublicDashboardFlag(t *testing.T) {
 	})
 }
 
+func TestRateLimitPublicDashboardQueries(t *testing.T) {
+	cfg := setting.NewCfg()
+	cfg.PublicDashboardsQueryRateLimit = 0.001
+	cfg.PublicDashboardsQueryRateLimitBurst = 1
+	mw := RateLimitPublicDashboardQueries(usage.ProvideService(cfg, nil))
+	params := map[string]string{":accessToken": validAccessToken}
+
+	_, resp := runMw(t, nil, "POST", "/api/public/dashboards/myAccesstoken/panels/1/query", params, mw)
+	require.Equal(t, http.StatusOK, resp.Code)
+
+	_, resp = runMw(t, nil, "POST", "/api/public/dashboards/myAccesstoken/panels/1/query", params, mw)
+	require.Equal(t, http.StatusTooManyRequests, resp.Code)
+}
+
 // This is a helper to test middleware. It handles creating a
 // proper contextmodel.ReqContext, setting web parameters, executing middleware, and
 // returning a response. Response will default to result of
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/usage"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// ListPublicDashboardUsage Gets the usage of the access tokens of the public dashboards of the org that the user can
+// write
+// GET /api/dashboards/public-dashboards/usage
+func (api *Api) ListPublicDashboardUsage(c *contextmodel.ReqContext) response.Response {
+	usages, err := api.Usage.FindAll(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return response.Err(err)
+	}
+
+	filtered := make([]*usage.PublicDashboardUsageDTO, 0, len(usages))
+	for _, u := range usages {
+		ok, err := api.canWritePublicDashboard(c, u.DashboardUID)
+		if err != nil {
+			return response.Err(ErrInternalServerError.Errorf("ListPublicDashboardUsage: failed to evaluate permissions: %w", err))
+		}
+		if ok {
+			filtered = append(filtered, u)
+		}
+	}
+	return response.JSON(http.StatusOK, filtered)
+}
+
+// RevokePublicDashboardAccessToken Replaces the access token of a public dashboard and pauses the public dashboard
+// POST /api/dashboards/public-dashboards/usage/:accessToken/revoke
+func (api *Api) RevokePublicDashboardAccessToken(c *contextmodel.ReqContext) response.Response {
+	accessToken := web.Params(c.Req)[":accessToken"]
+	if !validation.IsValidAccessToken(accessToken) {
+		return response.Err(ErrInvalidAccessToken.Errorf("RevokePublicDashboardAccessToken: invalid access token"))
+	}
+
+	pd, err := api.PublicDashboardService.FindByAccessToken(c.Req.Context(), accessToken)
+	if err != nil {
+		return response.Err(err)
+	}
+	if pd.OrgId != c.OrgID {
+		return response.Err(ErrPublicDashboardNotFound.Errorf("RevokePublicDashboardAccessToken: public dashboard not found"))
+	}
+
+	ok, err := api.canWritePublicDashboard(c, pd.DashboardUid)
+	if err != nil {
+		return response.Err(ErrInternalServerError.Errorf("RevokePublicDashboardAccessToken: failed to evaluate permissions: %w", err))
+	}
+	if !ok {
+		return response.Error(http.StatusForbidden, "Permission denied", nil)
+	}
+
+	revoked, err := api.Usage.Revoke(c.Req.Context(), c.OrgID, accessToken)
+	if err != nil {
+		return response.Err(err)
+	}
+	return response.JSON(http.StatusOK, revoked)
+}
+
+// canWritePublicDashboard returns true if the user can write the public dashboard of the dashboard with the UID.
+func (api *Api) canWritePublicDashboard(c *contextmodel.ReqContext, dashboardUID string) (bool, error) {
+	scope := dashboards.ScopeDashboardsProvider.GetResourceScopeUID(dashboardUID)
+	return api.AccessControl.Evaluate(c.Req.Context(), c.SignedInUser, accesscontrol.EvalPermission(dashboards.ActionDashboardsPublicWrite, scope))
+}
+
+// recordUsage wraps a handler of the public dashboard endpoints to record the request in the usage of the access
+// token once its response is written.
+func (api *Api) recordUsage(kind usage.Kind, handler func(c *contextmodel.ReqContext) response.Response) func(c *contextmodel.ReqContext) response.Response {
+	return func(c *contextmodel.ReqContext) response.Response {
+		res := handler(c)
+		if res == nil {
+			return nil
+		}
+		return &usageResponse{Response: res, usage: api.Usage, kind: kind}
+	}
+}
+
+// usageResponse records the size and status of the response in the usage of the access token of the request.
+type usageResponse struct {
+	response.Response
+	usage *usage.Service
+	kind  usage.Kind
+}
+
+func (r *usageResponse) WriteTo(c *contextmodel.ReqContext) {
+	before := c.Resp.Size()
+	r.Response.WriteTo(c)
+	size := c.Resp.Size() - before
+
+	status := c.Resp.Status()
+	if status == 0 {
+		status = r.Response.Status()
+	}
+	r.usage.Record(web.Params(c.Req)[":accessToken"], r.kind, int64(size), status >= http.StatusBadRequest)
+}
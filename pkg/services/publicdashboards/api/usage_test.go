@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/publicdashboards"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/usage"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestIntegrationAPIPublicDashboardUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	const (
+		dashboardUid      = "abc1234"
+		otherDashboardUid = "other1234"
+		accessToken       = "e71950f3e370451ab63c2ae1b47a1e2c"
+		otherAccessToken  = "0fbd3f7a21dd4b1e9b1ec1b2a9f5a6f4"
+	)
+	userEditorPublicDashboard := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleEditor, Login: "testEditorUser", Permissions: map[int64]map[string][]string{1: {dashboards.ActionDashboardsPublicWrite: {fmt.Sprintf("dashboards:uid:%s", dashboardUid)}}}}
+	userEditorAnotherPublicDashboard := &user.SignedInUser{UserID: 4, OrgID: 1, OrgRole: org.RoleEditor, Login: "testEditorUser", Permissions: map[int64]map[string][]string{1: {dashboards.ActionDashboardsPublicWrite: {fmt.Sprintf("dashboards:uid:%s", otherDashboardUid)}}}}
+
+	setup := func(t *testing.T) db.DB {
+		t.Helper()
+		store := db.InitTestDB(t)
+		now := time.Now()
+		err := store.WithDbSession(context.Background(), func(sess *db.Session) error {
+			if _, err := sess.Insert(&PublicDashboard{Uid: "pubdash1", DashboardUid: dashboardUid, OrgId: 1, AccessToken: accessToken, IsEnabled: true, CreatedAt: now, UpdatedAt: now}); err != nil {
+				return err
+			}
+			_, err := sess.Insert(
+				&usage.PublicDashboardUsage{AccessToken: accessToken, PublicDashboardUID: "pubdash1", DashboardUID: dashboardUid, OrgID: 1, Views: 1, Created: now, Updated: now},
+				&usage.PublicDashboardUsage{AccessToken: otherAccessToken, PublicDashboardUID: "pubdash2", DashboardUID: otherDashboardUid, OrgID: 1, Views: 1, Created: now, Updated: now},
+			)
+			return err
+		})
+		require.NoError(t, err)
+		return store
+	}
+
+	t.Run("usage only includes the public dashboards the user can write", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		testServer := setupTestServer(t, setting.NewCfg(), featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, setup(t), userEditorPublicDashboard)
+
+		response := callAPI(testServer, http.MethodGet, "/api/dashboards/public-dashboards/usage", nil, t)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var usages []*usage.PublicDashboardUsageDTO
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &usages))
+		require.Len(t, usages, 1)
+		assert.Equal(t, dashboardUid, usages[0].DashboardUID)
+	})
+
+	t.Run("viewer cannot list the usage", func(t *testing.T) {
+		service := publicdashboards.NewFakePublicDashboardService(t)
+		testServer := setupTestServer(t, setting.NewCfg(), featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, setup(t), userViewer)
+
+		response := callAPI(testServer, http.MethodGet, "/api/dashboards/public-dashboards/usage", nil, t)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+	})
+
+	testCases := []struct {
+		Name                 string
+		User                 *user.SignedInUser
+		OrgID                int64
+		ExpectedHttpResponse int
+		ShouldFindPubdash    bool
+	}{
+		{
+			Name:                 "User viewer cannot revoke the access token",
+			User:                 userViewer,
+			ExpectedHttpResponse: http.StatusForbidden,
+		},
+		{
+			Name:                 "User editor without specific dashboard access cannot revoke the access token",
+			User:                 userEditorAnotherPublicDashboard,
+			OrgID:                1,
+			ExpectedHttpResponse: http.StatusForbidden,
+			ShouldFindPubdash:    true,
+		},
+		{
+			Name:                 "Access token of another org is not found",
+			User:                 userEditorPublicDashboard,
+			OrgID:                2,
+			ExpectedHttpResponse: http.StatusNotFound,
+			ShouldFindPubdash:    true,
+		},
+		{
+			Name:                 "User editor with dashboard access can revoke the access token",
+			User:                 userEditorPublicDashboard,
+			OrgID:                1,
+			ExpectedHttpResponse: http.StatusOK,
+			ShouldFindPubdash:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.Name, func(t *testing.T) {
+			service := publicdashboards.NewFakePublicDashboardService(t)
+			if test.ShouldFindPubdash {
+				service.On("FindByAccessToken", mock.Anything, accessToken).
+					Return(&PublicDashboard{Uid: "pubdash1", DashboardUid: dashboardUid, OrgId: test.OrgID, AccessToken: accessToken}, nil)
+			}
+			testServer := setupTestServer(t, setting.NewCfg(), featuremgmt.WithFeatures(featuremgmt.FlagPublicDashboards), service, setup(t), test.User)
+
+			response := callAPI(testServer, http.MethodPost, fmt.Sprintf("/api/dashboards/public-dashboards/usage/%s/revoke", accessToken), nil, t)
+			assert.Equal(t, test.ExpectedHttpResponse, response.Code)
+
+			if test.ExpectedHttpResponse == http.StatusOK {
+				var pd PublicDashboard
+				require.NoError(t, json.Unmarshal(response.Body.Bytes(), &pd))
+				assert.NotEqual(t, accessToken, pd.AccessToken)
+				assert.False(t, pd.IsEnabled)
+			}
+		})
+	}
+}
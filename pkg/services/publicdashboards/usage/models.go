@@ -0,0 +1,54 @@
+package usage
+
+import (
+	"time"
+)
+
+// Kind is the kind of a request made with the access token of a public dashboard.
+type Kind int
+
+const (
+	// KindView is a request for the dashboard.
+	KindView Kind = iota
+	// KindQuery is a request for the data of a panel or for the annotations of the dashboard.
+	KindQuery
+)
+
+// PublicDashboardUsage is the usage of the access token of a public dashboard. The usage of an access token is kept
+// after the access token is revoked.
+type PublicDashboardUsage struct {
+	ID                 int64      `json:"-" xorm:"pk autoincr 'id'"`
+	AccessToken        string     `json:"accessToken" xorm:"access_token"`
+	PublicDashboardUID string     `json:"publicDashboardUid" xorm:"public_dashboard_uid"`
+	DashboardUID       string     `json:"dashboardUid" xorm:"dashboard_uid"`
+	OrgID              int64      `json:"-" xorm:"org_id"`
+	Views              int64      `json:"views" xorm:"views"`
+	Queries            int64      `json:"queries" xorm:"queries"`
+	Bytes              int64      `json:"bytes" xorm:"bytes"`
+	Errors             int64      `json:"errors" xorm:"errors"`
+	RateLimited        int64      `json:"rateLimited" xorm:"rate_limited"`
+	Revoked            bool       `json:"revoked" xorm:"revoked"`
+	LastRequestAt      *time.Time `json:"lastRequestAt" xorm:"last_request_at"`
+	Created            time.Time  `json:"created" xorm:"created"`
+	Updated            time.Time  `json:"updated" xorm:"updated"`
+}
+
+func (u PublicDashboardUsage) TableName() string {
+	return "dashboard_public_usage"
+}
+
+// PublicDashboardUsageDTO is the usage of an access token with the title of its dashboard.
+type PublicDashboardUsageDTO struct {
+	PublicDashboardUsage `xorm:"extends"`
+	Title                string `json:"title" xorm:"title"`
+}
+
+// counters are the usage of an access token that is not saved yet.
+type counters struct {
+	views       int64
+	queries     int64
+	bytes       int64
+	errors      int64
+	rateLimited int64
+	lastRequest time.Time
+}
@@ -0,0 +1,195 @@
+package usage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/validation"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// flushInterval is how often the usage recorded by the instance is saved.
+	flushInterval = 15 * time.Second
+	// flushTimeout is how long saving the usage on shutdown can take at most.
+	flushTimeout = 5 * time.Second
+	// maxTokens is the maximum number of access tokens whose usage is recorded, or whose queries are rate limited,
+	// between two flushes. It bounds the memory used by requests with made up access tokens.
+	maxTokens = 10000
+)
+
+// Service records the usage of the access tokens of public dashboards, and limits the rate of their queries. The
+// usage is counted in memory and saved periodically, so that the requests of public dashboards do not write to the
+// database. The rate limit applies to each instance separately.
+type Service struct {
+	log   log.Logger
+	store store
+
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	pending  map[string]*counters
+	limiters map[string]*rate.Limiter
+}
+
+func ProvideService(cfg *setting.Cfg, database db.DB) *Service {
+	return &Service{
+		log:      log.New("publicdashboards.usage"),
+		store:    &sqlStore{db: database},
+		limit:    rate.Limit(cfg.PublicDashboardsQueryRateLimit),
+		burst:    cfg.PublicDashboardsQueryRateLimitBurst,
+		pending:  make(map[string]*counters),
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// Allow reports whether a query can be made with the access token now. All queries are allowed if no rate limit is
+// configured.
+func (s *Service) Allow(accessToken string) bool {
+	if s.limit <= 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limiter, ok := s.limiters[accessToken]
+	if !ok {
+		if len(s.limiters) >= maxTokens {
+			s.evictIdleLimiters()
+		}
+		if len(s.limiters) >= maxTokens {
+			s.log.Warn("Too many public dashboard access tokens to rate limit")
+			return true
+		}
+		limiter = rate.NewLimiter(s.limit, s.burst)
+		s.limiters[accessToken] = limiter
+	}
+	return limiter.Allow()
+}
+
+// evictIdleLimiters removes the limiters that are full again. They behave the same as new limiters.
+func (s *Service) evictIdleLimiters() {
+	for accessToken, limiter := range s.limiters {
+		if limiter.Tokens() >= float64(s.burst) {
+			delete(s.limiters, accessToken)
+		}
+	}
+}
+
+// Record counts a request made with the access token. bytes is the size of the response, and failed whether the
+// request failed.
+func (s *Service) Record(accessToken string, kind Kind, bytes int64, failed bool) {
+	s.record(accessToken, func(c *counters) {
+		switch kind {
+		case KindView:
+			c.views++
+		case KindQuery:
+			c.queries++
+		}
+		c.bytes += bytes
+		if failed {
+			c.errors++
+		}
+	})
+}
+
+// RecordRateLimited counts a query made with the access token that was rejected by the rate limit.
+func (s *Service) RecordRateLimited(accessToken string) {
+	s.record(accessToken, func(c *counters) {
+		c.rateLimited++
+	})
+}
+
+func (s *Service) record(accessToken string, update func(c *counters)) {
+	if !validation.IsValidAccessToken(accessToken) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.pending[accessToken]
+	if !ok {
+		if len(s.pending) >= maxTokens {
+			return
+		}
+		c = &counters{}
+		s.pending[accessToken] = c
+	}
+	update(c)
+	c.lastRequest = time.Now()
+}
+
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.flush(ctx)
+		case <-ctx.Done():
+			// save the usage recorded since the last flush before shutting down
+			flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			s.flush(flushCtx)
+			cancel()
+			return ctx.Err()
+		}
+	}
+}
+
+// flush saves the usage recorded since the last flush. The usage of access tokens that do not belong to a public
+// dashboard is discarded.
+func (s *Service) flush(ctx context.Context) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*counters)
+	s.evictIdleLimiters()
+	s.mu.Unlock()
+
+	for accessToken, c := range pending {
+		if _, err := s.store.Add(ctx, accessToken, c); err != nil {
+			s.log.Error("Failed to save public dashboard usage", "error", err)
+		}
+	}
+}
+
+// FindAll returns the usage of the access tokens of the public dashboards of the organization. The usage recorded
+// since the last flush is not included.
+func (s *Service) FindAll(ctx context.Context, orgID int64) ([]*PublicDashboardUsageDTO, error) {
+	return s.store.FindAll(ctx, orgID)
+}
+
+// Revoke replaces the access token of a public dashboard with a new one and pauses the public dashboard, so that
+// the dashboard is no longer available with the revoked access token. The public dashboard has to be resumed to
+// share it with the new access token.
+func (s *Service) Revoke(ctx context.Context, orgID int64, accessToken string) (*PublicDashboard, error) {
+	if !validation.IsValidAccessToken(accessToken) {
+		return nil, ErrInvalidAccessToken.Errorf("Revoke: invalid access token")
+	}
+
+	newAccessToken, err := service.GenerateAccessToken()
+	if err != nil {
+		return nil, ErrInternalServerError.Errorf("Revoke: failed to generate access token: %w", err)
+	}
+
+	pd, err := s.store.Revoke(ctx, orgID, accessToken, newAccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	delete(s.limiters, accessToken)
+	s.mu.Unlock()
+
+	return pd, nil
+}
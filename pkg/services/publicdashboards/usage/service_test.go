@@ -0,0 +1,151 @@
+package usage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	dashboardsDB "github.com/grafana/grafana/pkg/services/dashboards/database"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/database"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+	"github.com/grafana/grafana/pkg/services/publicdashboards/service"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+	"github.com/grafana/grafana/pkg/services/tag/tagimpl"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+func TestAllow(t *testing.T) {
+	validAccessToken, err := service.GenerateAccessToken()
+	require.NoError(t, err)
+	otherAccessToken, err := service.GenerateAccessToken()
+	require.NoError(t, err)
+
+	t.Run("allows all queries without rate limit", func(t *testing.T) {
+		s := ProvideService(setting.NewCfg(), nil)
+		for i := 0; i < 100; i++ {
+			require.True(t, s.Allow(validAccessToken))
+		}
+	})
+
+	t.Run("limits the queries of each access token", func(t *testing.T) {
+		cfg := setting.NewCfg()
+		cfg.PublicDashboardsQueryRateLimit = 0.001
+		cfg.PublicDashboardsQueryRateLimitBurst = 2
+		s := ProvideService(cfg, nil)
+
+		assert.True(t, s.Allow(validAccessToken))
+		assert.True(t, s.Allow(validAccessToken))
+		assert.False(t, s.Allow(validAccessToken))
+		assert.True(t, s.Allow(otherAccessToken))
+	})
+}
+
+func TestIntegrationUsage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	sqlStore, cfg := db.InitTestDBwithCfg(t)
+	dashboardStore, err := dashboardsDB.ProvideDashboardStore(sqlStore, cfg, featuremgmt.WithFeatures(), tagimpl.ProvideService(sqlStore, cfg), quotatest.New(false, nil))
+	require.NoError(t, err)
+	publicdashboardStore := database.ProvideStore(sqlStore, cfg, featuremgmt.WithFeatures())
+
+	dash, err := dashboardStore.SaveDashboard(context.Background(), dashboards.SaveDashboardCommand{
+		OrgID:     1,
+		Dashboard: simplejson.NewFromAny(map[string]interface{}{"title": "usage"}),
+	})
+	require.NoError(t, err)
+
+	accessToken, err := service.GenerateAccessToken()
+	require.NoError(t, err)
+	uid := util.GenerateShortUID()
+	_, err = publicdashboardStore.Create(context.Background(), SavePublicDashboardCommand{
+		PublicDashboard: PublicDashboard{
+			Uid:          uid,
+			DashboardUid: dash.UID,
+			OrgId:        1,
+			IsEnabled:    true,
+			TimeSettings: &TimeSettings{},
+			CreatedBy:    1,
+			CreatedAt:    time.Now(),
+			AccessToken:  accessToken,
+			Share:        PublicShareType,
+		},
+	})
+	require.NoError(t, err)
+
+	s := ProvideService(cfg, sqlStore)
+
+	t.Run("saves the usage of access tokens of public dashboards", func(t *testing.T) {
+		unknownAccessToken, err := service.GenerateAccessToken()
+		require.NoError(t, err)
+
+		s.Record(accessToken, KindView, 100, false)
+		s.Record(accessToken, KindQuery, 200, false)
+		s.Record(accessToken, KindQuery, 50, true)
+		s.RecordRateLimited(accessToken)
+		s.Record(unknownAccessToken, KindView, 100, false)
+		s.Record("invalid", KindView, 100, false)
+		s.flush(context.Background())
+
+		s.Record(accessToken, KindQuery, 10, false)
+		s.flush(context.Background())
+
+		usages, err := s.FindAll(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, usages, 1)
+		u := usages[0]
+		assert.Equal(t, accessToken, u.AccessToken)
+		assert.Equal(t, uid, u.PublicDashboardUID)
+		assert.Equal(t, dash.UID, u.DashboardUID)
+		assert.Equal(t, "usage", u.Title)
+		assert.EqualValues(t, 1, u.Views)
+		assert.EqualValues(t, 3, u.Queries)
+		assert.EqualValues(t, 360, u.Bytes)
+		assert.EqualValues(t, 1, u.Errors)
+		assert.EqualValues(t, 1, u.RateLimited)
+		assert.False(t, u.Revoked)
+		assert.NotNil(t, u.LastRequestAt)
+
+		usages, err = s.FindAll(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Empty(t, usages)
+	})
+
+	t.Run("revoking an access token of another org fails", func(t *testing.T) {
+		_, err := s.Revoke(context.Background(), 2, accessToken)
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+
+	t.Run("revoking an access token replaces it and pauses the public dashboard", func(t *testing.T) {
+		pd, err := s.Revoke(context.Background(), 1, accessToken)
+		require.NoError(t, err)
+		assert.NotEqual(t, accessToken, pd.AccessToken)
+		assert.False(t, pd.IsEnabled)
+
+		exists, err := publicdashboardStore.ExistsEnabledByAccessToken(context.Background(), accessToken)
+		require.NoError(t, err)
+		assert.False(t, exists)
+		found, err := publicdashboardStore.Find(context.Background(), uid)
+		require.NoError(t, err)
+		assert.Equal(t, pd.AccessToken, found.AccessToken)
+		assert.False(t, found.IsEnabled)
+
+		usages, err := s.FindAll(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, usages, 1)
+		assert.True(t, usages[0].Revoked)
+		assert.EqualValues(t, 3, usages[0].Queries)
+
+		_, err = s.Revoke(context.Background(), 1, accessToken)
+		require.ErrorIs(t, err, ErrPublicDashboardNotFound)
+	})
+}
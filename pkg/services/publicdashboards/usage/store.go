@@ -0,0 +1,136 @@
+package usage
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	. "github.com/grafana/grafana/pkg/services/publicdashboards/models"
+)
+
+type store interface {
+	// Add adds the counters to the usage of the access token. It returns false if no public dashboard has the access
+	// token.
+	Add(ctx context.Context, accessToken string, c *counters) (bool, error)
+	// FindAll returns the usage of the access tokens of the organization, most queried first.
+	FindAll(ctx context.Context, orgID int64) ([]*PublicDashboardUsageDTO, error)
+	// Revoke replaces the access token of the public dashboard with the new one, pauses the public dashboard, and
+	// marks the usage of the access token as revoked.
+	Revoke(ctx context.Context, orgID int64, accessToken, newAccessToken string) (*PublicDashboard, error)
+}
+
+type sqlStore struct {
+	db db.DB
+}
+
+func (s *sqlStore) Add(ctx context.Context, accessToken string, c *counters) (bool, error) {
+	added, err := s.update(ctx, accessToken, c)
+	if err != nil || added {
+		return added, err
+	}
+
+	var pd PublicDashboard
+	err = s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("access_token=?", accessToken).Get(&pd)
+		if err != nil || !exists {
+			return err
+		}
+		now := time.Now()
+		lastRequest := c.lastRequest
+		_, err = sess.Insert(&PublicDashboardUsage{
+			AccessToken:        accessToken,
+			PublicDashboardUID: pd.Uid,
+			DashboardUID:       pd.DashboardUid,
+			OrgID:              pd.OrgId,
+			Views:              c.views,
+			Queries:            c.queries,
+			Bytes:              c.bytes,
+			Errors:             c.errors,
+			RateLimited:        c.rateLimited,
+			LastRequestAt:      &lastRequest,
+			Created:            now,
+			Updated:            now,
+		})
+		if err == nil {
+			added = true
+		}
+		return err
+	})
+	if err != nil {
+		// the usage may have been inserted by another instance in the meantime
+		return s.update(ctx, accessToken, c)
+	}
+	return added, nil
+}
+
+func (s *sqlStore) update(ctx context.Context, accessToken string, c *counters) (bool, error) {
+	var affected int64
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		res, err := sess.Exec(
+			"UPDATE dashboard_public_usage SET views=views+?, queries=queries+?, bytes=bytes+?, errors=errors+?, "+
+				"rate_limited=rate_limited+?, last_request_at=?, updated=? WHERE access_token=?",
+			c.views, c.queries, c.bytes, c.errors, c.rateLimited, c.lastRequest, time.Now(), accessToken,
+		)
+		if err != nil {
+			return err
+		}
+		affected, err = res.RowsAffected()
+		return err
+	})
+	return affected > 0, err
+}
+
+func (s *sqlStore) FindAll(ctx context.Context, orgID int64) ([]*PublicDashboardUsageDTO, error) {
+	usages := make([]*PublicDashboardUsageDTO, 0)
+	err := s.db.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("dashboard_public_usage").
+			Select("dashboard_public_usage.*, dashboard.title").
+			Join("LEFT", "dashboard", "dashboard.uid = dashboard_public_usage.dashboard_uid AND dashboard.org_id = dashboard_public_usage.org_id").
+			Where("dashboard_public_usage.org_id = ?", orgID).
+			Desc("dashboard_public_usage.queries").
+			Asc("dashboard_public_usage.id").
+			Find(&usages)
+	})
+	return usages, err
+}
+
+func (s *sqlStore) Revoke(ctx context.Context, orgID int64, accessToken, newAccessToken string) (*PublicDashboard, error) {
+	var pd PublicDashboard
+	err := s.db.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id=? AND access_token=?", orgID, accessToken).Get(&pd)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrPublicDashboardNotFound.Errorf("Revoke: public dashboard not found")
+		}
+
+		now := time.Now()
+		pd.AccessToken = newAccessToken
+		pd.IsEnabled = false
+		pd.UpdatedAt = now
+		if _, err := sess.Where("uid=?", pd.Uid).Cols("access_token", "is_enabled", "updated_at").Update(&pd); err != nil {
+			return err
+		}
+
+		affected, err := sess.Where("access_token=?", accessToken).Cols("revoked", "updated").
+			Update(&PublicDashboardUsage{Revoked: true, Updated: now})
+		if err != nil || affected > 0 {
+			return err
+		}
+		_, err = sess.Insert(&PublicDashboardUsage{
+			AccessToken:        accessToken,
+			PublicDashboardUID: pd.Uid,
+			DashboardUID:       pd.DashboardUid,
+			OrgID:              pd.OrgId,
+			Revoked:            true,
+			Created:            now,
+			Updated:            now,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pd, nil
+}
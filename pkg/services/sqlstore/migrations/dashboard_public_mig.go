@@ -96,4 +96,32 @@ func addPublicDashboardMigration(mg *Migrator) {
 	mg.AddMigration("backfill empty share column fields with default of public", NewRawSQLMigration(
 		"UPDATE dashboard_public SET share='public' WHERE share=''",
 	))
+
+	// usage of the access tokens of public dashboards
+	dashboardPublicUsageV1 := Table{
+		Name: "dashboard_public_usage",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "access_token", Type: DB_NVarchar, Length: 32, Nullable: false},
+			{Name: "public_dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "views", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "queries", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "bytes", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "errors", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "rate_limited", Type: DB_BigInt, Nullable: false, Default: "0"},
+			{Name: "revoked", Type: DB_Bool, Nullable: false, Default: "0"},
+			{Name: "last_request_at", Type: DB_DateTime, Nullable: true},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"access_token"}, Type: UniqueIndex},
+			{Cols: []string{"org_id"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard public usage table v1", NewAddTableMigration(dashboardPublicUsageV1))
+	addTableIndicesMigrations(mg, "v1", dashboardPublicUsageV1)
 }
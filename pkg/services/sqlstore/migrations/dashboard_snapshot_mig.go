@@ -71,4 +71,38 @@ func addDashboardSnapshotMigrations(mg *Migrator) {
 
 	mg.AddMigration("Change dashboard_encrypted column to MEDIUMBLOB", NewRawSQLMigration("").
 		Mysql("ALTER TABLE dashboard_snapshot MODIFY dashboard_encrypted MEDIUMBLOB;"))
+
+	mg.AddMigration("Add column schedule_id to dashboard_snapshot table", NewAddColumnMigration(snapshotV5, &Column{
+		Name: "schedule_id", Type: DB_BigInt, Nullable: true,
+	}))
+
+	mg.AddMigration("Add index for schedule_id in dashboard_snapshot table", NewAddIndexMigration(snapshotV5, &Index{
+		Cols: []string{"schedule_id"},
+	}))
+
+	snapshotScheduleV1 := Table{
+		Name: "dashboard_snapshot_schedule",
+		Columns: []*Column{
+			{Name: "id", Type: DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "org_id", Type: DB_BigInt, Nullable: false},
+			{Name: "dashboard_uid", Type: DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "name", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "schedule", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "expires", Type: DB_BigInt, Nullable: false},
+			{Name: "retention", Type: DB_BigInt, Nullable: false},
+			{Name: "external_url", Type: DB_NVarchar, Length: 255, Nullable: false},
+			{Name: "user_id", Type: DB_BigInt, Nullable: false},
+			{Name: "next_run", Type: DB_DateTime, Nullable: false},
+			{Name: "created", Type: DB_DateTime, Nullable: false},
+			{Name: "updated", Type: DB_DateTime, Nullable: false},
+		},
+		Indices: []*Index{
+			{Cols: []string{"org_id", "uid"}, Type: UniqueIndex},
+			{Cols: []string{"org_id", "dashboard_uid"}},
+		},
+	}
+
+	mg.AddMigration("create dashboard_snapshot_schedule table v1", NewAddTableMigration(snapshotScheduleV1))
+	addTableIndicesMigrations(mg, "v1", snapshotScheduleV1)
 }
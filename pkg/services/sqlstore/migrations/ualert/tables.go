@@ -24,6 +24,45 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add last_applied column to alert_configuration_history", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_configuration_history"}, &migrator.Column{
 		Name: "last_applied", Type: migrator.DB_Int, Nullable: false, Default: "0",
 	}))
+
+	mg.AddMigration("add keep_firing_for column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add keep_firing_for column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "keep_firing_for", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+	}))
+
+	mg.AddMigration("add depends_on column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "depends_on", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add depends_on column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "depends_on", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add result_fingerprint column to alert_instance", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_instance"}, &migrator.Column{
+		Name: "result_fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: true,
+	}))
+
+	addMaintenanceWindowMigrations(mg)
+	addStateHistoryMigrations(mg)
+
+	mg.AddMigration("add record column to alert_rule", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule"}, &migrator.Column{
+		Name: "record", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	mg.AddMigration("add record column to alert_rule_version", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_rule_version"}, &migrator.Column{
+		Name: "record", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	addNotificationDeliveryMigrations(mg)
+
+	addAlertmanagerStateMigrations(mg)
+
+	mg.AddMigration("add lint_rules column to ngalert_configuration", migrator.NewAddColumnMigration(migrator.Table{Name: "ngalert_configuration"}, &migrator.Column{
+		Name: "lint_rules", Type: migrator.DB_Text, Nullable: true,
+	}))
 	// End of migration log, add new migrations above this line.
 }
 
@@ -568,3 +607,138 @@ func (c extractAlertmanagerConfigurationHistory) Exec(sess *xorm.Session, migrat
 	}
 	return nil
 }
+
+func addMaintenanceWindowMigrations(mg *migrator.Migrator) {
+	maintenanceWindowTable := migrator.Table{
+		Name: "alert_maintenance_window",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "title", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "schedule", Type: migrator.DB_Text, Nullable: false},
+			{Name: "timezone", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "duration", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "matchers", Type: migrator.DB_Text, Nullable: true},
+			{Name: "updated", Type: migrator.DB_DateTime, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "uid"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_maintenance_window table", migrator.NewAddTableMigration(maintenanceWindowTable))
+	mg.AddMigration("add unique index on org_id and uid to alert_maintenance_window table", migrator.NewAddIndexMigration(maintenanceWindowTable, maintenanceWindowTable.Indices[0]))
+}
+
+func addStateHistoryMigrations(mg *migrator.Migrator) {
+	stateHistoryTable := migrator.Table{
+		Name: "alert_state_history",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "rule_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "rule_group", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "folder_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "dashboard_uid", Type: migrator.DB_NVarchar, Length: UIDMaxLength, Nullable: false},
+			{Name: "panel_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "fingerprint", Type: migrator.DB_NVarchar, Length: 16, Nullable: false},
+			{Name: "labels", Type: migrator.DB_Text, Nullable: true},
+			{Name: "previous_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "current_state", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "error_message", Type: migrator.DB_Text, Nullable: true},
+			{Name: "state_values", Type: migrator.DB_Text, Nullable: true},
+			{Name: "rule_condition", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "timestamp_nano", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "rule_uid", "timestamp_nano"}, Type: migrator.IndexType},
+			{Cols: []string{"org_id", "timestamp_nano"}, Type: migrator.IndexType},
+			{Cols: []string{"timestamp_nano"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history table", migrator.NewAddTableMigration(stateHistoryTable))
+	mg.AddMigration("add index on org_id, rule_uid and timestamp_nano to alert_state_history table", migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[0]))
+	mg.AddMigration("add index on org_id and timestamp_nano to alert_state_history table", migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[1]))
+	mg.AddMigration("add index on timestamp_nano to alert_state_history table", migrator.NewAddIndexMigration(stateHistoryTable, stateHistoryTable.Indices[2]))
+
+	stateHistoryLabelTable := migrator.Table{
+		Name: "alert_state_history_label",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "history_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "label_key", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "label_value", Type: migrator.DB_Text, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"history_id"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_state_history_label table", migrator.NewAddTableMigration(stateHistoryLabelTable))
+	mg.AddMigration("add index on history_id to alert_state_history_label table", migrator.NewAddIndexMigration(stateHistoryLabelTable, stateHistoryLabelTable.Indices[0]))
+}
+
+func addNotificationDeliveryMigrations(mg *migrator.Migrator) {
+	notificationDeliveryTable := migrator.Table{
+		Name: "alert_notification_delivery",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "receiver", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "integration_index", Type: migrator.DB_Int, Nullable: false},
+			{Name: "group_key", Type: migrator.DB_Text, Nullable: false},
+			{Name: "alert_fingerprints", Type: migrator.DB_Text, Nullable: true},
+			{Name: "status", Type: migrator.DB_NVarchar, Length: 20, Nullable: false},
+			{Name: "error", Type: migrator.DB_Text, Nullable: true},
+			{Name: "retry", Type: migrator.DB_Bool, Nullable: false},
+			{Name: "duration_ms", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "timestamp_nano", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "receiver", "timestamp_nano"}, Type: migrator.IndexType},
+			{Cols: []string{"timestamp_nano"}, Type: migrator.IndexType},
+		},
+	}
+
+	mg.AddMigration("create alert_notification_delivery table", migrator.NewAddTableMigration(notificationDeliveryTable))
+	mg.AddMigration("add index on org_id, receiver and timestamp_nano to alert_notification_delivery table", migrator.NewAddIndexMigration(notificationDeliveryTable, notificationDeliveryTable.Indices[0]))
+	mg.AddMigration("add index on timestamp_nano to alert_notification_delivery table", migrator.NewAddIndexMigration(notificationDeliveryTable, notificationDeliveryTable.Indices[1]))
+}
+
+func addAlertmanagerStateMigrations(mg *migrator.Migrator) {
+	stateTable := migrator.Table{
+		Name: "alert_notifier_state",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "state_key", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "state", Type: migrator.DB_LongText, Nullable: false},
+			{Name: "version", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "updated_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"state_key"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_notifier_state table", migrator.NewAddTableMigration(stateTable))
+	mg.AddMigration("add unique index on state_key to alert_notifier_state table", migrator.NewAddIndexMigration(stateTable, stateTable.Indices[0]))
+
+	peerTable := migrator.Table{
+		Name: "alert_notifier_peer",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "heartbeat_at", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"name"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_notifier_peer table", migrator.NewAddTableMigration(peerTable))
+	mg.AddMigration("add unique index on name to alert_notifier_peer table", migrator.NewAddIndexMigration(peerTable, peerTable.Indices[0]))
+}
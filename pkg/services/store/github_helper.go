@@ -9,6 +9,8 @@ import (
 
 	"github.com/google/go-github/v45/github"
 	"golang.org/x/oauth2"
+
+	"github.com/grafana/grafana/pkg/util/gitutil"
 )
 
 type githubHelper struct {
@@ -88,9 +90,7 @@ func (g *githubHelper) pushCommit(ctx context.Context, ref *github.Reference, cm
 	// This is not always populated, but is needed.
 	parent.Commit.SHA = parent.SHA
 
-	user := cmd.User
-	name := firstRealString(user.Name, user.Login, user.Email, "?")
-	email := firstRealString(user.Email, user.Login, user.Name, "?")
+	name, email := gitutil.Author(cmd.User)
 
 	// Create the commit using the tree.
 	date := time.Now()
@@ -10,13 +10,12 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"gocloud.dev/blob"
 
 	"github.com/grafana/grafana/pkg/infra/filestorage"
-	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/util/gitutil"
 )
 
 const rootStorageTypeGit = "git"
@@ -339,17 +338,8 @@ func (s *rootStorageGit) Write(ctx context.Context, cmd *WriteValueRequest) (*Wr
 	if msg == "" {
 		msg = "changes from grafana ui"
 	}
-	usr := cmd.User
-	if usr == nil {
-		usr = &user.SignedInUser{}
-	}
-
 	hash, err := w.Commit(msg, &git.CommitOptions{
-		Author: &object.Signature{
-			Name:  firstRealString(usr.Name, usr.Login, usr.Email, "?"),
-			Email: firstRealString(usr.Email, usr.Login, usr.Name, "?"),
-			When:  time.Now(),
-		},
+		Author: gitutil.Signature(cmd.User, time.Now()),
 	})
 	if err != nil {
 		return nil, err
@@ -376,12 +366,3 @@ func (s *rootStorageGit) Sync() error {
 	}
 	return err
 }
-
-func firstRealString(vals ...string) string {
-	for _, v := range vals {
-		if v != "" {
-			return v
-		}
-	}
-	return "?"
-}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"math"
 	"net/http"
 	"net/url"
 	"os"
@@ -398,6 +399,10 @@ type Cfg struct {
 
 	SnapshotPublicMode bool
 
+	// Public dashboards
+	PublicDashboardsQueryRateLimit      float64
+	PublicDashboardsQueryRateLimitBurst int
+
 	ErrTemplateName string
 
 	Env string
@@ -1050,6 +1055,8 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 		return err
 	}
 
+	cfg.readPublicDashboardsSettings(iniFile)
+
 	if err := readGRPCServerSettings(cfg, iniFile); err != nil {
 		return err
 	}
@@ -1844,6 +1851,16 @@ func readSnapshotsSettings(cfg *Cfg, iniFile *ini.File) error {
 	return nil
 }
 
+func (cfg *Cfg) readPublicDashboardsSettings(iniFile *ini.File) {
+	publicDashboards := iniFile.Section("public_dashboards")
+
+	cfg.PublicDashboardsQueryRateLimit = publicDashboards.Key("query_rate_limit").MustFloat64(0)
+	cfg.PublicDashboardsQueryRateLimitBurst = publicDashboards.Key("query_rate_limit_burst").MustInt(0)
+	if cfg.PublicDashboardsQueryRateLimitBurst <= 0 {
+		cfg.PublicDashboardsQueryRateLimitBurst = int(math.Ceil(cfg.PublicDashboardsQueryRateLimit))
+	}
+}
+
 func (cfg *Cfg) readServerSettings(iniFile *ini.File) error {
 	server := iniFile.Section("server")
 	var err error
@@ -15,11 +15,12 @@ import (
 )
 
 const (
-	alertmanagerDefaultClusterAddr        = "0.0.0.0:9094"
-	alertmanagerDefaultPeerTimeout        = 15 * time.Second
-	alertmanagerDefaultGossipInterval     = cluster.DefaultGossipInterval
-	alertmanagerDefaultPushPullInterval   = cluster.DefaultPushPullInterval
-	alertmanagerDefaultConfigPollInterval = time.Minute
+	alertmanagerDefaultClusterAddr          = "0.0.0.0:9094"
+	alertmanagerDefaultPeerTimeout          = 15 * time.Second
+	alertmanagerDefaultGossipInterval       = cluster.DefaultGossipInterval
+	alertmanagerDefaultPushPullInterval     = cluster.DefaultPushPullInterval
+	alertmanagerDefaultDatabaseSyncInterval = 5 * time.Second
+	alertmanagerDefaultConfigPollInterval   = time.Minute
 	// To start, the alertmanager needs at least one route defined.
 	// TODO: we should move this to Grafana settings and define this as the default.
 	alertmanagerDefaultConfiguration = `{
@@ -60,6 +61,19 @@ const (
 	// DefaultRuleEvaluationInterval indicates a default interval of for how long a rule should be evaluated to change state from Pending to Alerting
 	DefaultRuleEvaluationInterval = SchedulerBaseInterval * 6 // == 60 seconds
 	stateHistoryDefaultEnabled    = true
+	stateHistoryDefaultSQLMaxAge  = 30 * 24 * time.Hour
+
+	recordingRulesDefaultRemoteWritePath    = "/api/v1/write"
+	recordingRulesDefaultRemoteWriteTimeout = 30 * time.Second
+
+	notificationDeliveryLogDefaultEnabled = true
+	notificationDeliveryLogDefaultMaxAge  = 7 * 24 * time.Hour
+
+	externalAlertmanagerRetryDefaultEnabled        = true
+	externalAlertmanagerRetryDefaultCapacity       = 10000
+	externalAlertmanagerRetryDefaultInitialBackoff = time.Second
+	externalAlertmanagerRetryDefaultMaxBackoff     = 5 * time.Minute
+	externalAlertmanagerRetryDefaultMaxAttempts    = 10
 )
 
 type UnifiedAlertingSettings struct {
@@ -78,6 +92,8 @@ type UnifiedAlertingSettings struct {
 	HARedisUsername                string
 	HARedisPassword                string
 	HARedisDB                      int
+	HADatabaseSync                 bool
+	HADatabaseSyncInterval         time.Duration
 	MaxAttempts                    int64
 	MinInterval                    time.Duration
 	EvaluationTimeout              time.Duration
@@ -93,8 +109,14 @@ type UnifiedAlertingSettings struct {
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	RecordingRules                UnifiedAlertingRecordingRuleSettings
+	NotificationDeliveryLog       UnifiedAlertingNotificationDeliveryLogSettings
+	ExternalAlertmanagerRetry     UnifiedAlertingExternalAlertmanagerRetrySettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
+	// SlowRuleStateReason determines whether alert instances of rules that regularly take longer to evaluate than their interval
+	// get the state reason SlowEvaluation.
+	SlowRuleStateReason bool
 }
 
 type UnifiedAlertingScreenshotSettings struct {
@@ -122,6 +144,33 @@ type UnifiedAlertingStateHistorySettings struct {
 	MultiPrimary          string
 	MultiSecondaries      []string
 	ExternalLabels        map[string]string
+	// SQLMaxAge is the retention period of state history stored by the "sql" backend. Zero means no limit.
+	SQLMaxAge time.Duration
+}
+
+type UnifiedAlertingRecordingRuleSettings struct {
+	Enabled bool
+	// TargetDatasourceUID is the UID of the data source that recording rules write to if they do not specify one.
+	TargetDatasourceUID string
+	// RemoteWritePath is the path of the remote write endpoint relative to the URL of the data source.
+	RemoteWritePath    string
+	RemoteWriteTimeout time.Duration
+}
+
+type UnifiedAlertingNotificationDeliveryLogSettings struct {
+	Enabled bool
+	// MaxAge is the retention period of the delivery log. Zero means no limit.
+	MaxAge time.Duration
+}
+
+type UnifiedAlertingExternalAlertmanagerRetrySettings struct {
+	Enabled bool
+	// Capacity is the maximum number of alerts queued per external Alertmanager.
+	Capacity       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// MaxAttempts is the number of failed retries after which queued alerts are dropped. Zero means no limit.
+	MaxAttempts int
 }
 
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
@@ -240,6 +289,18 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfg.HARedisUsername = ua.Key("ha_redis_username").MustString("")
 	uaCfg.HARedisPassword = ua.Key("ha_redis_password").MustString("")
 	uaCfg.HARedisDB = ua.Key("ha_redis_db").MustInt(0)
+	uaCfg.HADatabaseSync = ua.Key("ha_database_sync").MustBool(false)
+	uaCfg.HADatabaseSyncInterval, err = gtime.ParseDuration(valueAsString(ua, "ha_database_sync_interval", alertmanagerDefaultDatabaseSyncInterval.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfg.HADatabaseSyncInterval <= 0 {
+		return fmt.Errorf("value of setting 'ha_database_sync_interval' should be greater than 0")
+	}
+	if uaCfg.HADatabaseSync && uaCfg.HADatabaseSyncInterval >= uaCfg.HAPeerTimeout {
+		cfg.Logger.Warn("value of setting 'ha_database_sync_interval' should be lower than 'ha_peer_timeout' to avoid duplicate notifications",
+			"ha_database_sync_interval", uaCfg.HADatabaseSyncInterval, "ha_peer_timeout", uaCfg.HAPeerTimeout)
+	}
 	peers := ua.Key("ha_peers").MustString("")
 	uaCfg.HAPeers = make([]string, 0)
 	if peers != "" {
@@ -352,9 +413,55 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 		MultiSecondaries:      splitTrim(stateHistory.Key("secondaries").MustString(""), ","),
 		ExternalLabels:        stateHistoryLabels.KeysHash(),
 	}
+	uaCfgStateHistory.SQLMaxAge, err = gtime.ParseDuration(valueAsString(stateHistory, "sql_max_age", stateHistoryDefaultSQLMaxAge.String()))
+	if err != nil {
+		return err
+	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	recordingRules := iniFile.Section("unified_alerting.recording_rules")
+	uaCfgRecordingRules := UnifiedAlertingRecordingRuleSettings{
+		Enabled:             recordingRules.Key("enabled").MustBool(false),
+		TargetDatasourceUID: recordingRules.Key("target_datasource_uid").MustString(""),
+		RemoteWritePath:     recordingRules.Key("remote_write_path").MustString(recordingRulesDefaultRemoteWritePath),
+	}
+	uaCfgRecordingRules.RemoteWriteTimeout, err = gtime.ParseDuration(valueAsString(recordingRules, "remote_write_timeout", recordingRulesDefaultRemoteWriteTimeout.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.RecordingRules = uaCfgRecordingRules
+
+	deliveryLog := iniFile.Section("unified_alerting.notification_delivery_log")
+	uaCfgDeliveryLog := UnifiedAlertingNotificationDeliveryLogSettings{
+		Enabled: deliveryLog.Key("enabled").MustBool(notificationDeliveryLogDefaultEnabled),
+	}
+	uaCfgDeliveryLog.MaxAge, err = gtime.ParseDuration(valueAsString(deliveryLog, "max_age", notificationDeliveryLogDefaultMaxAge.String()))
+	if err != nil {
+		return err
+	}
+	uaCfg.NotificationDeliveryLog = uaCfgDeliveryLog
+
+	retryQueue := iniFile.Section("unified_alerting.external_alertmanager_retry_queue")
+	uaCfgRetryQueue := UnifiedAlertingExternalAlertmanagerRetrySettings{
+		Enabled:     retryQueue.Key("enabled").MustBool(externalAlertmanagerRetryDefaultEnabled),
+		Capacity:    retryQueue.Key("capacity").MustInt(externalAlertmanagerRetryDefaultCapacity),
+		MaxAttempts: retryQueue.Key("max_attempts").MustInt(externalAlertmanagerRetryDefaultMaxAttempts),
+	}
+	uaCfgRetryQueue.InitialBackoff, err = gtime.ParseDuration(valueAsString(retryQueue, "initial_backoff", externalAlertmanagerRetryDefaultInitialBackoff.String()))
+	if err != nil {
+		return err
+	}
+	uaCfgRetryQueue.MaxBackoff, err = gtime.ParseDuration(valueAsString(retryQueue, "max_backoff", externalAlertmanagerRetryDefaultMaxBackoff.String()))
+	if err != nil {
+		return err
+	}
+	if uaCfgRetryQueue.Capacity <= 0 {
+		return fmt.Errorf("the capacity of the external Alertmanager retry queue must be greater than 0")
+	}
+	uaCfg.ExternalAlertmanagerRetry = uaCfgRetryQueue
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
+	uaCfg.SlowRuleStateReason = ua.Key("slow_rule_state_reason").MustBool(false)
 
 	cfg.UnifiedAlerting = uaCfg
 	return nil
@@ -39,6 +39,16 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 3)
 		require.ElementsMatch(t, []string{"hostname1:9090", "hostname2:9090", "hostname3:9090"}, cfg.UnifiedAlerting.HAPeers)
 	}
+
+	// With a database sync interval that is not positive, it fails.
+	{
+		s := cfg.Raw.Section("unified_alerting")
+		for _, interval := range []string{"0s", "-5s"} {
+			_, err = s.NewKey("ha_database_sync_interval", interval)
+			require.NoError(t, err)
+			require.ErrorContains(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw), "ha_database_sync_interval")
+		}
+	}
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
@@ -0,0 +1,40 @@
+// Package gitutil contains helpers for the features of Grafana that commit to git repositories.
+package gitutil
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Author returns the name and email of the author of commits made on behalf of the user.
+func Author(usr *user.SignedInUser) (name string, email string) {
+	if usr == nil {
+		usr = &user.SignedInUser{}
+	}
+	return firstRealString(usr.Name, usr.Login, usr.Email), firstRealString(usr.Email, usr.Login, usr.Name)
+}
+
+// Signature returns the signature of commits made on behalf of the user.
+func Signature(usr *user.SignedInUser, when time.Time) *object.Signature {
+	name, email := Author(usr)
+	return &object.Signature{Name: name, Email: email, When: when}
+}
+
+// IsNonFastForward returns true if the error is returned by a push that was rejected because the remote branch has
+// commits that are not in the pushed branch.
+func IsNonFastForward(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "non-fast-forward")
+}
+
+func firstRealString(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return "?"
+}
@@ -0,0 +1,34 @@
+package gitutil
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestAuthor(t *testing.T) {
+	tests := []struct {
+		usr   *user.SignedInUser
+		name  string
+		email string
+	}{
+		{&user.SignedInUser{Name: "Admin", Login: "admin", Email: "admin@localhost"}, "Admin", "admin@localhost"},
+		{&user.SignedInUser{Login: "admin"}, "admin", "admin"},
+		{&user.SignedInUser{}, "?", "?"},
+		{nil, "?", "?"},
+	}
+	for _, tc := range tests {
+		name, email := Author(tc.usr)
+		require.Equal(t, tc.name, name)
+		require.Equal(t, tc.email, email)
+	}
+}
+
+func TestIsNonFastForward(t *testing.T) {
+	require.True(t, IsNonFastForward(errors.New("non-fast-forward update: refs/heads/main")))
+	require.False(t, IsNonFastForward(errors.New("authentication required")))
+	require.False(t, IsNonFastForward(nil))
+}
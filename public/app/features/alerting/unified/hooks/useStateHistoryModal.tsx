@@ -11,6 +11,7 @@ const LokiStateHistory = lazy(() => import('../components/rules/state-history/Lo
 
 enum StateHistoryImplementation {
   Loki = 'loki',
+  SQL = 'sql',
   Annotations = 'annotations',
 }
 
@@ -20,14 +21,16 @@ function useStateHistoryModal() {
 
   const styles = useStyles2(getStyles);
 
-  // can be "loki", "multiple" or "annotations"
+  // can be "loki", "sql", "multiple" or "annotations"
   const stateHistoryBackend = config.unifiedAlerting.alertStateHistoryBackend;
-  // can be "loki" or "annotations"
+  // can be "loki", "sql" or "annotations"
   const stateHistoryPrimary = config.unifiedAlerting.alertStateHistoryPrimary;
 
-  // if "loki" is either the backend or the primary, show the new state history implementation
+  // if "loki" or "sql" is either the backend or the primary, show the new state history implementation
+  // the "sql" backend returns the history in the same format as "loki"
   const usingNewAlertStateHistory = [stateHistoryBackend, stateHistoryPrimary].some(
-    (implementation) => implementation === StateHistoryImplementation.Loki
+    (implementation) =>
+      implementation === StateHistoryImplementation.Loki || implementation === StateHistoryImplementation.SQL
   );
   const implementation = usingNewAlertStateHistory
     ? StateHistoryImplementation.Loki